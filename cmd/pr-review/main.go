@@ -6,11 +6,15 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
+	"pr-review/internal/codehost"
 	"pr-review/internal/config"
-	"pr-review/internal/database/postgres"
+	"pr-review/internal/database"
+	"pr-review/internal/email"
+	"pr-review/internal/models"
 	"pr-review/internal/server/handlers"
 	"pr-review/internal/service"
 
@@ -30,6 +34,12 @@ func main() {
 
 	ctx := context.Background()
 
+	selectors, err := service.NewTeamSelectors(&cfg.Review)
+	if err != nil {
+		log.Error("Failed to setup reviewer selection", "error", err)
+		os.Exit(1)
+	}
+
 	repository, err := setupDatabase(ctx, log, &cfg.Database)
 	if err != nil {
 		log.Error("Failed to setup database", "error", err)
@@ -42,12 +52,50 @@ func main() {
 		}
 	}()
 
-	userService := service.NewUserService(log, repository)
-	teamService := service.NewTeamService(log, repository)
-	prService := service.NewPRService(log, repository)
+	webhookService := service.NewWebhookService(log, repository)
+	codeHostSync := service.NewCodeHostSync(log, repository, setupCodeHostClients(cfg), &cfg.Sync)
+	chatNotifier := service.NewChatNotifier(log, repository, &cfg.Chat)
+	events := service.EventPublishers{webhookService, codeHostSync, chatNotifier}
+
+	var emailNotifier *service.EmailNotifier
+	if cfg.Email.Host != "" {
+		emailNotifier = service.NewEmailNotifier(log, repository, email.NewSMTPSender(&cfg.Email), &cfg.Email)
+		events = append(events, emailNotifier)
+	}
+
+	prService := service.NewPRService(log, repository, selectors, events)
+	userService := service.NewUserService(log, repository, prService, events)
+	teamService := service.NewTeamService(log, repository, prService, selectors, events)
+	poolService := service.NewPoolService(log, repository)
 	statsService := service.NewStatsService(log, repository)
+	githubService := service.NewGitHubService(log, repository, prService, &cfg.GitHub)
+	gitlabService := service.NewGitLabService(log, repository, prService, &cfg.GitLab)
+
+	router := SetupRouter(
+		log, teamService, userService, prService, poolService, statsService,
+		webhookService, githubService, gitlabService, codeHostSync,
+	)
+
+	// проходы обработчиков пишут в базу, поэтому она закрывается только после их остановки
+	var workers sync.WaitGroup
+	defer workers.Wait()
+	schedulerCtx, stopScheduler := context.WithCancel(ctx)
+	defer stopScheduler()
+
+	absenceProcessor := service.NewAbsenceProcessor(log, repository, prService)
+	startEvery(schedulerCtx, &workers, log, "absences", cfg.Availability.CheckInterval, absenceProcessor.ProcessStarted)
+
+	webhookDispatcher := service.NewWebhookDispatcher(log, repository, &cfg.Webhook)
+	startEvery(schedulerCtx, &workers, log, "webhook deliveries", cfg.Webhook.PollInterval, webhookDispatcher.DeliverDue)
 
-	router := SetupRouter(log, teamService, userService, prService, statsService)
+	startEvery(schedulerCtx, &workers, log, "code host sync", cfg.Sync.PollInterval, codeHostSync.SyncDue)
+
+	startEvery(schedulerCtx, &workers, log, "chat messages", cfg.Chat.PollInterval, chatNotifier.SendDue)
+
+	if emailNotifier != nil {
+		startEvery(schedulerCtx, &workers, log, "email digests", cfg.Email.PollInterval, emailNotifier.SendDigests)
+		startEvery(schedulerCtx, &workers, log, "emails", cfg.Email.PollInterval, emailNotifier.SendDue)
+	}
 
 	router.Get("/health", func(w http.ResponseWriter, r *http.Request) {
 		if err := repository.Ping(r.Context()); err != nil {
@@ -86,6 +134,7 @@ func main() {
 
 	<-done
 	log.Info("Stopping server")
+	stopScheduler()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -104,7 +153,12 @@ func SetupRouter(
 	teamService handlers.TeamService,
 	userService handlers.UserService,
 	prService handlers.PRService,
+	poolService handlers.PoolService,
 	statsService handlers.StatsService,
+	webhookService handlers.WebhookService,
+	githubService handlers.GitHubService,
+	gitlabService handlers.GitLabService,
+	syncService handlers.SyncService,
 ) *chi.Mux {
 	router := chi.NewRouter()
 
@@ -117,32 +171,77 @@ func SetupRouter(
 	teamHandler := handlers.NewTeamHandler(logger, teamService)
 	userHandler := handlers.NewUserHandler(logger, userService)
 	prHandler := handlers.NewPRHandler(logger, prService)
+	poolHandler := handlers.NewPoolHandler(logger, poolService)
 	statsHandler := handlers.NewStatsHandler(logger, statsService)
+	webhookHandler := handlers.NewWebhookHandler(logger, webhookService)
+	integrationHandler := handlers.NewIntegrationHandler(logger, githubService, gitlabService, syncService)
 
 	router.Route("/users", func(r chi.Router) {
 		r.Post("/setIsActive", userHandler.SetIsActive)
 		r.Get("/getReview", userHandler.GetReview)
+		r.Post("/setMaxOpenReviews", userHandler.SetMaxOpenReviews)
+		r.Post("/moveTeam", userHandler.MoveTeam)
+		r.Post("/absence", userHandler.AddAbsence)
+		r.Post("/linkAccount", userHandler.LinkAccount)
+		r.Post("/setEmail", userHandler.SetEmail)
 	})
 	router.Route("/team", func(r chi.Router) {
 		r.Post("/add", teamHandler.Add)
 		r.Get("/get", teamHandler.Get)
+		r.Post("/addMember", teamHandler.AddMember)
+		r.Post("/removeMember", teamHandler.RemoveMember)
+		r.Post("/deactivateUsers", teamHandler.DeactivateUsers)
+		r.Post("/rename", teamHandler.Rename)
+		r.Post("/delete", teamHandler.Delete)
+		r.Post("/setMergePolicy", teamHandler.SetMergePolicy)
+		r.Post("/setMaxOpenReviews", teamHandler.SetMaxOpenReviews)
+		r.Post("/setFallbacks", teamHandler.SetFallbacks)
+		r.Post("/setCodeOwners", teamHandler.SetCodeOwners)
+		r.Get("/getCodeOwners", teamHandler.GetCodeOwners)
+		r.Post("/setChat", teamHandler.SetChat)
+		r.Get("/getChat", teamHandler.GetChat)
 	})
 	router.Route("/pullRequest", func(r chi.Router) {
 		r.Post("/create", prHandler.Create)
 		r.Post("/merge", prHandler.Merge)
+		r.Post("/readyForReview", prHandler.ReadyForReview)
+		r.Post("/convertToDraft", prHandler.ConvertToDraft)
+		r.Post("/review", prHandler.Review)
+		r.Get("/mergeability", prHandler.Mergeability)
+		r.Get("/timeline", prHandler.Timeline)
+		r.Post("/close", prHandler.Close)
+		r.Post("/reopen", prHandler.Reopen)
 		r.Post("/reassign", prHandler.Reassign)
 	})
+	router.Route("/pool", func(r chi.Router) {
+		r.Post("/add", poolHandler.Add)
+		r.Get("/get", poolHandler.Get)
+		r.Post("/setMembers", poolHandler.SetMembers)
+	})
 	router.Route("/stats", func(r chi.Router) {
 		r.Get("/user", statsHandler.User)
 		r.Get("/team", statsHandler.Team)
 		r.Get("/total", statsHandler.Total)
 	})
+	router.Route("/webhooks", func(r chi.Router) {
+		r.Post("/", webhookHandler.Create)
+		r.Get("/", webhookHandler.List)
+		r.Post("/delete", webhookHandler.Delete)
+		r.Get("/deliveries", webhookHandler.Deliveries)
+	})
+	router.Route("/integrations", func(r chi.Router) {
+		r.Post("/github/webhook", integrationHandler.GitHub)
+		r.Post("/gitlab/webhook", integrationHandler.GitLab)
+		r.Get("/sync", integrationHandler.SyncTasks)
+		r.Post("/sync/retry", integrationHandler.RetrySync)
+	})
 
 	return router
 }
 
-func setupDatabase(ctx context.Context, log *slog.Logger, dbCfg *config.DatabaseConfig) (*postgres.PostgresRepository, error) {
+func setupDatabase(ctx context.Context, log *slog.Logger, dbCfg *config.DatabaseConfig) (database.Repository, error) {
 	log.Info("Initializing database",
+		"driver", dbCfg.Driver,
 		"path", dbCfg.Path,
 		"init_timeout", dbCfg.InitTimeout,
 		"ping_timeout", dbCfg.PingTimeout,
@@ -151,7 +250,7 @@ func setupDatabase(ctx context.Context, log *slog.Logger, dbCfg *config.Database
 	initCtx, cancel := context.WithTimeout(ctx, dbCfg.InitTimeout)
 	defer cancel()
 
-	repo, err := postgres.New(initCtx, dbCfg)
+	repo, err := database.New(initCtx, dbCfg)
 	if err != nil {
 		return nil, err
 	}
@@ -166,3 +265,16 @@ func setupDatabase(ctx context.Context, log *slog.Logger, dbCfg *config.Database
 	log.Info("Database initialized successfully")
 	return repo, nil
 }
+
+func setupCodeHostClients(cfg *config.Config) map[string]service.CodeHostClient {
+	httpClient := &http.Client{Timeout: cfg.Sync.Timeout}
+
+	clients := make(map[string]service.CodeHostClient)
+	if cfg.GitHub.Token != "" {
+		clients[models.ProviderGitHub] = codehost.NewGitHubClient(&cfg.GitHub, httpClient)
+	}
+	if cfg.GitLab.Token != "" {
+		clients[models.ProviderGitLab] = codehost.NewGitLabClient(&cfg.GitLab, httpClient)
+	}
+	return clients
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+func startEvery(
+	ctx context.Context,
+	workers *sync.WaitGroup,
+	log *slog.Logger,
+	name string,
+	interval time.Duration,
+	run func(ctx context.Context, now time.Time) error,
+) {
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		runEvery(ctx, log, name, interval, run)
+	}()
+}
+
+// runEvery вызывает run сразу после старта, чтобы не ждать интервал после перезапуска.
+// Ошибка прохода только пишется в журнал: следующий проход повторит его работу.
+func runEvery(
+	ctx context.Context,
+	log *slog.Logger,
+	name string,
+	interval time.Duration,
+	run func(ctx context.Context, now time.Time) error,
+) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := run(ctx, time.Now()); err != nil {
+			log.Error("Scheduled run failed", "run", name, "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
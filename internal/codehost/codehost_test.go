@@ -0,0 +1,109 @@
+package codehost_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// apiRequest - запрос, пришедший в API-заглушку.
+type apiRequest struct {
+	header http.Header
+	method string
+	uri    string
+	body   string
+}
+
+// apiResponse - ответ заглушки на очередной запрос.
+type apiResponse struct {
+	body   string
+	status int
+}
+
+// apiServer записывает запросы и отвечает из responses по очереди,
+// а когда они кончились - 200 с пустым объектом.
+type apiServer struct {
+	server    *httptest.Server
+	requests  []apiRequest
+	responses []apiResponse
+	mu        sync.Mutex
+}
+
+func newAPIServer(t *testing.T, responses ...apiResponse) *apiServer {
+	t.Helper()
+
+	s := &apiServer{responses: responses}
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		s.mu.Lock()
+		s.requests = append(s.requests, apiRequest{
+			header: req.Header.Clone(),
+			method: req.Method,
+			uri:    req.URL.RequestURI(),
+			body:   string(body),
+		})
+		resp := apiResponse{status: http.StatusOK, body: "{}"}
+		if len(s.responses) > 0 {
+			resp, s.responses = s.responses[0], s.responses[1:]
+		}
+		s.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(resp.status)
+		_, _ = io.WriteString(w, resp.body)
+	}))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+func (s *apiServer) received() []apiRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]apiRequest(nil), s.requests...)
+}
+
+// wantRequests сверяет запросы в виде "METHOD uri body", тело без пробелов по краям.
+func wantRequests(t *testing.T, s *apiServer, want ...string) []apiRequest {
+	t.Helper()
+
+	requests := s.received()
+	got := make([]string, 0, len(requests))
+	for _, req := range requests {
+		got = append(got, strings.TrimSpace(req.method+" "+req.uri+" "+strings.TrimSpace(req.body)))
+	}
+	equalStrings(t, "requests", got, want)
+	return requests
+}
+
+func noError(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func equal[T comparable](t *testing.T, what string, got, want T) {
+	t.Helper()
+
+	if got != want {
+		t.Fatalf("%s: got %v, want %v", what, got, want)
+	}
+}
+
+func equalStrings(t *testing.T, what string, got, want []string) {
+	t.Helper()
+
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("%s:\ngot  %q\nwant %q", what, got, want)
+	}
+}
@@ -0,0 +1,64 @@
+package codehost
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"pr-review/internal/models"
+)
+
+type FakeCall struct {
+	Method string
+	Body   string
+	Logins []string
+	PR     models.CodeHostPR
+}
+
+// Fake записывает вызовы вместо запросов к код-хостингу, для тестов и демо.
+type Fake struct {
+	err   error
+	calls []FakeCall
+	mu    sync.Mutex
+}
+
+func NewFake() *Fake {
+	return &Fake{}
+}
+
+func (f *Fake) RequestReviewers(_ context.Context, pr models.CodeHostPR, logins []string) error {
+	return f.record(FakeCall{Method: "RequestReviewers", PR: pr, Logins: slices.Clone(logins)})
+}
+
+func (f *Fake) RemoveReviewers(_ context.Context, pr models.CodeHostPR, logins []string) error {
+	return f.record(FakeCall{Method: "RemoveReviewers", PR: pr, Logins: slices.Clone(logins)})
+}
+
+func (f *Fake) Comment(_ context.Context, pr models.CodeHostPR, body string) error {
+	return f.record(FakeCall{Method: "Comment", PR: pr, Body: body})
+}
+
+// FailWith: неудачные вызовы тоже записываются.
+func (f *Fake) FailWith(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.err = err
+}
+
+func (f *Fake) Calls() []FakeCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return slices.Clone(f.calls)
+}
+
+// private methods
+
+func (f *Fake) record(call FakeCall) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, call)
+	return f.err
+}
@@ -0,0 +1,54 @@
+package codehost
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"pr-review/internal/config"
+	"pr-review/internal/models"
+)
+
+type GitHubClient struct {
+	client  *http.Client
+	baseURL string
+	token   string
+}
+
+func NewGitHubClient(cfg *config.GitHubConfig, client *http.Client) *GitHubClient {
+	return &GitHubClient{
+		client:  client,
+		baseURL: strings.TrimSuffix(cfg.APIURL, "/"),
+		token:   cfg.Token,
+	}
+}
+
+// RequestReviewers оставляет уже запрошенных ревьюверов.
+func (c *GitHubClient) RequestReviewers(ctx context.Context, pr models.CodeHostPR, logins []string) error {
+	return c.send(ctx, http.MethodPost, c.pullURL(pr)+"/requested_reviewers", map[string][]string{"reviewers": logins})
+}
+
+func (c *GitHubClient) RemoveReviewers(ctx context.Context, pr models.CodeHostPR, logins []string) error {
+	return c.send(ctx, http.MethodDelete, c.pullURL(pr)+"/requested_reviewers", map[string][]string{"reviewers": logins})
+}
+
+// Comment: у GitHub комментарий PR - это комментарий issue.
+func (c *GitHubClient) Comment(ctx context.Context, pr models.CodeHostPR, body string) error {
+	url := fmt.Sprintf("%s/repos/%s/issues/%d/comments", c.baseURL, pr.Repository, pr.Number)
+	return c.send(ctx, http.MethodPost, url, map[string]string{"body": body})
+}
+
+// private methods
+
+func (c *GitHubClient) pullURL(pr models.CodeHostPR) string {
+	return fmt.Sprintf("%s/repos/%s/pulls/%d", c.baseURL, pr.Repository, pr.Number)
+}
+
+func (c *GitHubClient) send(ctx context.Context, method, url string, in any) error {
+	header := http.Header{}
+	header.Set("Accept", "application/vnd.github+json")
+	header.Set("Authorization", "Bearer "+c.token)
+	header.Set("X-GitHub-Api-Version", "2022-11-28")
+	return send(ctx, c.client, method, url, header, in, nil)
+}
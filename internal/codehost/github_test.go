@@ -0,0 +1,56 @@
+package codehost_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"pr-review/internal/codehost"
+	"pr-review/internal/config"
+	"pr-review/internal/models"
+)
+
+const gitHubToken = "ghp-test"
+
+var gitHubPR = models.CodeHostPR{Repository: "acme/api", Number: 7}
+
+func newGitHubClient(s *apiServer) *codehost.GitHubClient {
+	return codehost.NewGitHubClient(&config.GitHubConfig{Token: gitHubToken, APIURL: s.server.URL + "/"}, s.server.Client())
+}
+
+func TestGitHubRequests(t *testing.T) {
+	s := newAPIServer(t)
+	client := newGitHubClient(s)
+
+	noError(t, client.RequestReviewers(t.Context(), gitHubPR, []string{"bob"}))
+	noError(t, client.RemoveReviewers(t.Context(), gitHubPR, []string{"carol", "dave"}))
+	noError(t, client.Comment(t.Context(), gitHubPR, "Reviewer @carol was replaced by @bob."))
+
+	requests := wantRequests(t, s,
+		`POST /repos/acme/api/pulls/7/requested_reviewers {"reviewers":["bob"]}`,
+		`DELETE /repos/acme/api/pulls/7/requested_reviewers {"reviewers":["carol","dave"]}`,
+		`POST /repos/acme/api/issues/7/comments {"body":"Reviewer @carol was replaced by @bob."}`,
+	)
+	for _, req := range requests {
+		equal(t, "authorization", req.header.Get("Authorization"), "Bearer "+gitHubToken)
+		equal(t, "accept", req.header.Get("Accept"), "application/vnd.github+json")
+		equal(t, "api version", req.header.Get("X-GitHub-Api-Version"), "2022-11-28")
+		equal(t, "content type", req.header.Get("Content-Type"), "application/json")
+	}
+}
+
+// Ответ не 2xx возвращается ошибкой, чтобы задача синхронизации ушла на повтор.
+func TestGitHubErrorStatus(t *testing.T) {
+	s := newAPIServer(t, apiResponse{status: http.StatusUnprocessableEntity, body: `{"message":"Reviews may only be requested from collaborators."}`})
+	client := newGitHubClient(s)
+
+	err := client.RequestReviewers(t.Context(), gitHubPR, []string{"mallory"})
+	if err == nil {
+		t.Fatal("expected error for 422 response")
+	}
+	for _, part := range []string{"POST", "/repos/acme/api/pulls/7/requested_reviewers", "422", "only be requested from collaborators"} {
+		if !strings.Contains(err.Error(), part) {
+			t.Fatalf("error %q does not mention %q", err, part)
+		}
+	}
+}
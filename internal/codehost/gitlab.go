@@ -0,0 +1,121 @@
+package codehost
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"pr-review/internal/config"
+	"pr-review/internal/models"
+)
+
+// GitLabClient: GitLab задаёт ревьюверов MR списком id целиком, поэтому изменения списка
+// читают текущих ревьюверов и записывают их заново.
+type GitLabClient struct {
+	client  *http.Client
+	baseURL string
+	token   string
+}
+
+type gitLabUser struct {
+	Username string `json:"username"`
+	ID       int    `json:"id"`
+}
+
+func NewGitLabClient(cfg *config.GitLabConfig, client *http.Client) *GitLabClient {
+	return &GitLabClient{
+		client:  client,
+		baseURL: strings.TrimSuffix(cfg.APIURL, "/"),
+		token:   cfg.Token,
+	}
+}
+
+func (c *GitLabClient) RequestReviewers(ctx context.Context, pr models.CodeHostPR, logins []string) error {
+	current, err := c.reviewers(ctx, pr)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]int, 0, len(current)+len(logins))
+	for _, user := range current {
+		ids = append(ids, user.ID)
+	}
+	for _, login := range logins {
+		id, err := c.userID(ctx, login)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == len(current) {
+		return nil
+	}
+
+	return c.setReviewers(ctx, pr, ids)
+}
+
+func (c *GitLabClient) RemoveReviewers(ctx context.Context, pr models.CodeHostPR, logins []string) error {
+	current, err := c.reviewers(ctx, pr)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]int, 0, len(current))
+	for _, user := range current {
+		// логины хранятся в нижнем регистре
+		if !slices.Contains(logins, strings.ToLower(user.Username)) {
+			ids = append(ids, user.ID)
+		}
+	}
+	if len(ids) == len(current) {
+		return nil
+	}
+
+	return c.setReviewers(ctx, pr, ids)
+}
+
+func (c *GitLabClient) Comment(ctx context.Context, pr models.CodeHostPR, body string) error {
+	return c.send(ctx, http.MethodPost, c.mergeRequestURL(pr)+"/notes", map[string]string{"body": body}, nil)
+}
+
+// private methods
+
+func (c *GitLabClient) mergeRequestURL(pr models.CodeHostPR) string {
+	return fmt.Sprintf("%s/projects/%s/merge_requests/%d", c.baseURL, url.PathEscape(pr.Repository), pr.Number)
+}
+
+func (c *GitLabClient) reviewers(ctx context.Context, pr models.CodeHostPR) ([]gitLabUser, error) {
+	var mergeRequest struct {
+		Reviewers []gitLabUser `json:"reviewers"`
+	}
+	if err := c.send(ctx, http.MethodGet, c.mergeRequestURL(pr), nil, &mergeRequest); err != nil {
+		return nil, err
+	}
+	return mergeRequest.Reviewers, nil
+}
+
+func (c *GitLabClient) setReviewers(ctx context.Context, pr models.CodeHostPR, ids []int) error {
+	return c.send(ctx, http.MethodPut, c.mergeRequestURL(pr), map[string][]int{"reviewer_ids": ids}, nil)
+}
+
+func (c *GitLabClient) userID(ctx context.Context, login string) (int, error) {
+	var users []gitLabUser
+	if err := c.send(ctx, http.MethodGet, c.baseURL+"/users?username="+url.QueryEscape(login), nil, &users); err != nil {
+		return 0, err
+	}
+	if len(users) == 0 {
+		return 0, fmt.Errorf("gitlab user %q not found", login)
+	}
+	return users[0].ID, nil
+}
+
+func (c *GitLabClient) send(ctx context.Context, method, url string, in, out any) error {
+	header := http.Header{}
+	header.Set("PRIVATE-TOKEN", c.token)
+	return send(ctx, c.client, method, url, header, in, out)
+}
@@ -0,0 +1,115 @@
+package codehost_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"pr-review/internal/codehost"
+	"pr-review/internal/config"
+	"pr-review/internal/models"
+)
+
+const gitLabToken = "glpat-test"
+
+var gitLabPR = models.CodeHostPR{Repository: "platform/api", Number: 12}
+
+func newGitLabClient(s *apiServer) *codehost.GitLabClient {
+	return codehost.NewGitLabClient(&config.GitLabConfig{Token: gitLabToken, APIURL: s.server.URL}, s.server.Client())
+}
+
+func wantGitLabToken(t *testing.T, requests []apiRequest) {
+	t.Helper()
+
+	for _, req := range requests {
+		equal(t, "private token", req.header.Get("PRIVATE-TOKEN"), gitLabToken)
+	}
+}
+
+// Список ревьюверов MR записывается целиком: к текущим добавляются новые.
+func TestGitLabRequestReviewers(t *testing.T) {
+	s := newAPIServer(t,
+		apiResponse{status: http.StatusOK, body: `{"iid":12,"reviewers":[{"id":5,"username":"Carol"}]}`},
+		apiResponse{status: http.StatusOK, body: `[{"id":8,"username":"bob"}]`},
+	)
+	client := newGitLabClient(s)
+
+	noError(t, client.RequestReviewers(t.Context(), gitLabPR, []string{"bob"}))
+
+	requests := wantRequests(t, s,
+		`GET /projects/platform%2Fapi/merge_requests/12`,
+		`GET /users?username=bob`,
+		`PUT /projects/platform%2Fapi/merge_requests/12 {"reviewer_ids":[5,8]}`,
+	)
+	wantGitLabToken(t, requests)
+	equal(t, "content type", requests[2].header.Get("Content-Type"), "application/json")
+}
+
+// Уже назначенный ревьювер не записывается повторно.
+func TestGitLabRequestAssignedReviewer(t *testing.T) {
+	s := newAPIServer(t,
+		apiResponse{status: http.StatusOK, body: `{"reviewers":[{"id":8,"username":"bob"}]}`},
+		apiResponse{status: http.StatusOK, body: `[{"id":8,"username":"bob"}]`},
+	)
+	client := newGitLabClient(s)
+
+	noError(t, client.RequestReviewers(t.Context(), gitLabPR, []string{"bob"}))
+	wantRequests(t, s,
+		`GET /projects/platform%2Fapi/merge_requests/12`,
+		`GET /users?username=bob`,
+	)
+}
+
+func TestGitLabRemoveReviewers(t *testing.T) {
+	s := newAPIServer(t,
+		apiResponse{status: http.StatusOK, body: `{"reviewers":[{"id":5,"username":"Carol"},{"id":8,"username":"bob"}]}`},
+	)
+	client := newGitLabClient(s)
+
+	// логины сравниваются без учёта регистра
+	noError(t, client.RemoveReviewers(t.Context(), gitLabPR, []string{"carol"}))
+
+	requests := wantRequests(t, s,
+		`GET /projects/platform%2Fapi/merge_requests/12`,
+		`PUT /projects/platform%2Fapi/merge_requests/12 {"reviewer_ids":[8]}`,
+	)
+	wantGitLabToken(t, requests)
+}
+
+func TestGitLabComment(t *testing.T) {
+	s := newAPIServer(t)
+	client := newGitLabClient(s)
+
+	noError(t, client.Comment(t.Context(), gitLabPR, "Reviewer @carol was replaced by @bob."))
+
+	requests := wantRequests(t, s,
+		`POST /projects/platform%2Fapi/merge_requests/12/notes {"body":"Reviewer @carol was replaced by @bob."}`,
+	)
+	wantGitLabToken(t, requests)
+	equal(t, "content type", requests[0].header.Get("Content-Type"), "application/json")
+}
+
+func TestGitLabErrors(t *testing.T) {
+	s := newAPIServer(t,
+		apiResponse{status: http.StatusOK, body: `{"reviewers":[]}`},
+		apiResponse{status: http.StatusOK, body: `[]`},
+		apiResponse{status: http.StatusBadGateway, body: "upstream unavailable"},
+	)
+	client := newGitLabClient(s)
+
+	err := client.RequestReviewers(t.Context(), gitLabPR, []string{"ghost"})
+	if err == nil || !strings.Contains(err.Error(), `"ghost" not found`) {
+		t.Fatalf("expected unknown user error, got %v", err)
+	}
+
+	// ответ не 2xx возвращается ошибкой, ничего не записывается
+	err = client.RequestReviewers(t.Context(), gitLabPR, []string{"bob"})
+	if err == nil || !strings.Contains(err.Error(), "502") {
+		t.Fatalf("expected error for 502 response, got %v", err)
+	}
+	wantRequests(t, s,
+		`GET /projects/platform%2Fapi/merge_requests/12`,
+		`GET /users?username=ghost`,
+		`GET /projects/platform%2Fapi/merge_requests/12`,
+	)
+}
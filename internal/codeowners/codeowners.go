@@ -0,0 +1,198 @@
+package codeowners
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Rule: владельцы хранятся без ведущего '@': "u1" для пользователя, "org/backend" для команды.
+type Rule struct {
+	re      *regexp.Regexp
+	Pattern string
+	Owners  []string
+	Line    int
+}
+
+type Ruleset struct {
+	Rules []Rule
+}
+
+// Parse пропускает адреса почты в списке владельцев: сопоставить их с пользователями нельзя.
+func Parse(content string) (*Ruleset, error) {
+	ruleset := &Ruleset{}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+
+		line := stripComment(scanner.Text())
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		pattern := strings.ReplaceAll(fields[0], `\#`, "#")
+		if strings.HasPrefix(pattern, "!") {
+			return nil, fmt.Errorf("line %d: negated patterns are not supported", lineNum)
+		}
+
+		re, err := compilePattern(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+
+		var owners []string
+		for _, owner := range fields[1:] {
+			if !strings.HasPrefix(owner, "@") {
+				continue
+			}
+			owner = strings.TrimPrefix(owner, "@")
+			if owner == "" {
+				return nil, fmt.Errorf("line %d: empty owner", lineNum)
+			}
+			owners = append(owners, owner)
+		}
+
+		ruleset.Rules = append(ruleset.Rules, Rule{
+			re:      re,
+			Pattern: pattern,
+			Owners:  owners,
+			Line:    lineNum,
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return ruleset, nil
+}
+
+// Owners: как и на GitHub, побеждает последнее подходящее правило, правило без владельцев снимает владение.
+func (rs *Ruleset) Owners(path string) []string {
+	path = strings.TrimPrefix(path, "/")
+
+	for i := len(rs.Rules) - 1; i >= 0; i-- {
+		if rs.Rules[i].re.MatchString(path) {
+			return rs.Rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// OwnersOf сохраняет порядок первого появления.
+func (rs *Ruleset) OwnersOf(paths []string) []string {
+	seen := make(map[string]bool)
+	var owners []string
+	for _, path := range paths {
+		for _, owner := range rs.Owners(path) {
+			if !seen[owner] {
+				seen[owner] = true
+				owners = append(owners, owner)
+			}
+		}
+	}
+	return owners
+}
+
+func stripComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		if line[i] == '#' && (i == 0 || line[i-1] != '\\') {
+			return line[:i]
+		}
+	}
+	return line
+}
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+
+	// Шаблон со слешем в начале или в середине привязан к корню репозитория,
+	// без слеша - совпадает на любой глубине.
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	if pattern == "" {
+		return nil, fmt.Errorf("empty pattern")
+	}
+
+	var sb strings.Builder
+	sb.WriteString("^")
+	if !anchored && !strings.HasPrefix(pattern, "**") {
+		sb.WriteString("(?:.*/)?")
+	}
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			sb.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated character class in %q", pattern)
+			}
+			class, err := translateClass(pattern[i+1 : i+end])
+			if err != nil {
+				return nil, fmt.Errorf("%w in %q", err, pattern)
+			}
+			sb.WriteString(class)
+			i += end
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	lastSegment := pattern[strings.LastIndex(pattern, "/")+1:]
+	switch {
+	case dirOnly:
+		sb.WriteString("/.*")
+	case anchored && strings.Contains(lastSegment, "*") && !strings.Contains(lastSegment, "**"):
+		// "docs/*" по правилам GitHub покрывает только файлы прямо в docs
+	default:
+		// Остальные шаблоны покрывают и содержимое совпавших директорий
+		sb.WriteString("(?:/.*)?")
+	}
+	sb.WriteString("$")
+
+	return regexp.Compile(sb.String())
+}
+
+// translateClass экранирует символы, кроме '-' в диапазонах, чтобы синтаксис regexp не просочился в класс.
+func translateClass(class string) (string, error) {
+	var sb strings.Builder
+	sb.WriteString("[")
+	// в glob класс отрицается '!' (или '^'), в regexp - только '^';
+	// как и '*', отрицание не совпадает со слешем
+	if negated, ok := strings.CutPrefix(class, "!"); ok {
+		sb.WriteString("^/")
+		class = negated
+	} else if negated, ok := strings.CutPrefix(class, "^"); ok {
+		sb.WriteString("^/")
+		class = negated
+	}
+	if class == "" {
+		return "", fmt.Errorf("empty character class")
+	}
+
+	for _, r := range class {
+		if r == '-' {
+			sb.WriteRune(r)
+			continue
+		}
+		sb.WriteString(regexp.QuoteMeta(string(r)))
+	}
+	sb.WriteString("]")
+	return sb.String(), nil
+}
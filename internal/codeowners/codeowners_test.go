@@ -0,0 +1,131 @@
+package codeowners
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseOwners(t *testing.T) {
+	ruleset, err := Parse("# общий владелец\n* @all @org/platform owner@example.com\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ruleset.Rules) != 1 {
+		t.Fatalf("got %d rules, want 1", len(ruleset.Rules))
+	}
+
+	// почта пропускается, у команды остаётся org/
+	rule := ruleset.Rules[0]
+	if want := []string{"all", "org/platform"}; !slices.Equal(rule.Owners, want) {
+		t.Errorf("owners = %v, want %v", rule.Owners, want)
+	}
+	if rule.Line != 2 {
+		t.Errorf("line = %d, want 2", rule.Line)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{content: "!*.go @u1", want: "line 1: negated patterns are not supported"},
+		{content: "*.go @u1\n/src/[abc @u2", want: "line 2: unterminated character class"},
+		{content: "/src/[]x @u1", want: "line 1: empty character class"},
+		{content: "/src/[!]x @u1", want: "line 1: empty character class"},
+		{content: "*.go @", want: "line 1: empty owner"},
+		{content: "/ @u1", want: "line 1: empty pattern"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.content)
+		if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+			t.Errorf("Parse(%q) error = %v, want %q", tt.content, err, tt.want)
+		}
+	}
+}
+
+func TestOwners(t *testing.T) {
+	ruleset, err := Parse(strings.Join([]string{
+		`*                @all`,
+		`*.js             @js`,
+		`build            @build`,
+		`/docs/           @docs`,
+		`/guides/*        @guides`,
+		`apps/            @apps`,
+		`**/logs          @logs`,
+		`/config/**       @config`,
+		`/a/**/b          @ab`,
+		`/src/[!t]*.go    @src`,
+		`/lib/v[0-9].go   @lib`,
+		`/esc/[\d.].txt   @esc`,
+		`/docs/generated/`,
+		`\#notes          @notes`,
+	}, "\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		// без слеша шаблон совпадает на любой глубине
+		{path: "main.go", want: []string{"all"}},
+		{path: "app.js", want: []string{"js"}},
+		{path: "web/src/app.js", want: []string{"js"}},
+		{path: "build", want: []string{"build"}},
+		{path: "tools/build/out.bin", want: []string{"build"}},
+		{path: "/app.js", want: []string{"js"}},
+
+		// со слешем в начале привязан к корню, "dir/" покрывает всё содержимое
+		{path: "docs/readme.md", want: []string{"docs"}},
+		{path: "docs/api/v1.md", want: []string{"docs"}},
+		{path: "src/docs/readme.md", want: []string{"all"}},
+		{path: "apps/web/main.go", want: []string{"apps"}},
+		{path: "services/apps/main.go", want: []string{"apps"}},
+
+		// "dir/*" - только файлы прямо в директории
+		{path: "guides/intro.md", want: []string{"guides"}},
+		{path: "guides/advanced/tips.md", want: []string{"all"}},
+
+		// "**" в начале, в середине и в конце
+		{path: "logs", want: []string{"logs"}},
+		{path: "var/logs/app.log", want: []string{"logs"}},
+		{path: "config/app.yml", want: []string{"config"}},
+		{path: "config/prod/db.yml", want: []string{"config"}},
+		{path: "a/b", want: []string{"ab"}},
+		{path: "a/x/y/b", want: []string{"ab"}},
+		{path: "a/x/c", want: []string{"all"}},
+
+		// классы символов, включая отрицание и экранирование синтаксиса regexp
+		{path: "src/main.go", want: []string{"src"}},
+		{path: "src/test.go", want: []string{"all"}},
+		{path: "lib/v1.go", want: []string{"lib"}},
+		{path: "lib/vx.go", want: []string{"all"}},
+		{path: "esc/d.txt", want: []string{"esc"}},
+		{path: "esc/..txt", want: []string{"esc"}},
+		{path: "esc/1.txt", want: []string{"all"}},
+
+		// побеждает последнее правило, правило без владельцев снимает владение
+		{path: "docs/generated/api.md", want: nil},
+		{path: "#notes", want: []string{"notes"}},
+	}
+	for _, tt := range tests {
+		if got := ruleset.Owners(tt.path); !slices.Equal(got, tt.want) {
+			t.Errorf("Owners(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestOwnersOf(t *testing.T) {
+	ruleset, err := Parse("*.go @u1 @u2\n*.sql @u2 @u3\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := ruleset.OwnersOf([]string{"main.go", "schema.sql", "README.md"})
+	if want := []string{"u1", "u2", "u3"}; !slices.Equal(got, want) {
+		t.Errorf("OwnersOf = %v, want %v", got, want)
+	}
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"log"
 	"log/slog"
 	"os"
@@ -17,10 +18,18 @@ const (
 )
 
 type Config struct {
-	Env        string `env:"ENV" env-default:"local"`
-	LogLevel   string `env:"LOG_LEVEL" env-default:"info"`
-	HTTPServer HTTPServerConfig
-	Database   DatabaseConfig
+	Env          string `env:"ENV" env-default:"local"`
+	LogLevel     string `env:"LOG_LEVEL" env-default:"info"`
+	GitHub       GitHubConfig
+	GitLab       GitLabConfig
+	Review       ReviewConfig
+	HTTPServer   HTTPServerConfig
+	Database     DatabaseConfig
+	Email        EmailConfig
+	Availability AvailabilityConfig
+	Webhook      OutboxConfig `env-prefix:"WEBHOOK_"`
+	Sync         OutboxConfig `env-prefix:"SYNC_"`
+	Chat         OutboxConfig `env-prefix:"CHAT_"`
 }
 
 type HTTPServerConfig struct {
@@ -30,6 +39,8 @@ type HTTPServerConfig struct {
 }
 
 type DatabaseConfig struct {
+	// Driver - бэкенд хранилища: postgres, sqlite (файл DB_PATH) или memory (без сохранения между запусками)
+	Driver          string        `env:"DB_DRIVER" env-default:"postgres"`
 	MigrationsPath  string        `env:"DB_MIGRATIONS_PATH" env-default:"./migrations"`
 	Port            string        `env:"DB_PORT" env-default:"5432"`
 	User            string        `env:"DB_USER" env-default:"pr_review_user"`
@@ -45,6 +56,54 @@ type DatabaseConfig struct {
 	PingTimeout     time.Duration `env:"DB_PING_TIMEOUT" env-default:"5s"`
 }
 
+type ReviewConfig struct {
+	TeamStrategies map[string]string `env:"REVIEW_TEAM_STRATEGIES"`
+	Weights        map[string]int    `env:"REVIEW_WEIGHTS"`
+	Strategy       string            `env:"REVIEW_STRATEGY" env-default:"random"`
+	TieBreak       string            `env:"REVIEW_TIE_BREAK" env-default:"random"`
+	AtCapacity     string            `env:"REVIEW_AT_CAPACITY" env-default:"assign-fewer"`
+}
+
+type AvailabilityConfig struct {
+	CheckInterval time.Duration `env:"ABSENCE_CHECK_INTERVAL" env-default:"1m"`
+}
+
+// OutboxConfig: переменные получают префикс очереди, например WEBHOOK_TIMEOUT,
+// значения по умолчанию задаются в MustLoad.
+type OutboxConfig struct {
+	PollInterval time.Duration `env:"POLL_INTERVAL"`
+	Timeout      time.Duration `env:"TIMEOUT"`
+	BackoffBase  time.Duration `env:"BACKOFF_BASE"`
+	BackoffMax   time.Duration `env:"BACKOFF_MAX"`
+	MaxAttempts  int           `env:"MAX_ATTEMPTS"`
+	BatchSize    int           `env:"BATCH_SIZE"`
+}
+
+type GitHubConfig struct {
+	WebhookSecret string `env:"GITHUB_WEBHOOK_SECRET" env-default:""`
+	Token         string `env:"GITHUB_TOKEN" env-default:""`
+	APIURL        string `env:"GITHUB_API_URL" env-default:"https://api.github.com"`
+}
+
+type GitLabConfig struct {
+	// ProjectTeams, например `group/api:backend,group/web:frontend`
+	ProjectTeams map[string]string `env:"GITLAB_PROJECT_TEAMS"`
+	WebhookToken string            `env:"GITLAB_WEBHOOK_TOKEN" env-default:""`
+	Token        string            `env:"GITLAB_TOKEN" env-default:""`
+	APIURL       string            `env:"GITLAB_API_URL" env-default:"https://gitlab.com/api/v4"`
+}
+
+type EmailConfig struct {
+	Host string `env:"SMTP_HOST" env-default:""`
+	// Username и Password передаются только после STARTTLS
+	Username     string `env:"SMTP_USERNAME" env-default:""`
+	Password     string `env:"SMTP_PASSWORD" env-default:""`
+	From         string `env:"SMTP_FROM" env-default:"pr-review@localhost"`
+	OutboxConfig `env-prefix:"EMAIL_"`
+	Port         int `env:"SMTP_PORT" env-default:"587"`
+	DigestHour   int `env:"EMAIL_DIGEST_HOUR" env-default:"9"`
+}
+
 func MustLoad() *Config {
 	if _, err := os.Stat(".env-default"); err == nil {
 		if err := godotenv.Load(".env-default"); err != nil {
@@ -58,12 +117,51 @@ func MustLoad() *Config {
 		}
 	}
 
-	var cfg Config
+	cfg := Config{
+		Webhook: OutboxConfig{
+			PollInterval: 2 * time.Second,
+			Timeout:      5 * time.Second,
+			BackoffBase:  10 * time.Second,
+			BackoffMax:   time.Hour,
+			MaxAttempts:  8,
+			BatchSize:    20,
+		},
+		Sync: OutboxConfig{
+			PollInterval: 2 * time.Second,
+			Timeout:      10 * time.Second,
+			BackoffBase:  30 * time.Second,
+			BackoffMax:   time.Hour,
+			MaxAttempts:  6,
+			BatchSize:    20,
+		},
+		Chat: OutboxConfig{
+			PollInterval: 2 * time.Second,
+			Timeout:      5 * time.Second,
+			BackoffBase:  10 * time.Second,
+			BackoffMax:   30 * time.Minute,
+			MaxAttempts:  5,
+			BatchSize:    20,
+		},
+		Email: EmailConfig{
+			OutboxConfig: OutboxConfig{
+				PollInterval: 5 * time.Second,
+				Timeout:      30 * time.Second,
+				BackoffBase:  time.Minute,
+				BackoffMax:   time.Hour,
+				MaxAttempts:  5,
+				BatchSize:    20,
+			},
+		},
+	}
 
 	if err := cleanenv.ReadEnv(&cfg); err != nil {
 		log.Fatalf("cannot read config from environment: %s", err)
 	}
 
+	if err := cfg.validate(); err != nil {
+		log.Fatalf("invalid config: %s", err)
+	}
+
 	return &cfg
 }
 
@@ -79,3 +177,39 @@ func (c *Config) GetSlogLevel() slog.Level {
 		return slog.LevelInfo
 	}
 }
+
+// private methods
+
+func (c *Config) validate() error {
+	queues := []struct {
+		cfg    *OutboxConfig
+		prefix string
+	}{
+		{cfg: &c.Webhook, prefix: "WEBHOOK_"},
+		{cfg: &c.Sync, prefix: "SYNC_"},
+		{cfg: &c.Chat, prefix: "CHAT_"},
+		{cfg: &c.Email.OutboxConfig, prefix: "EMAIL_"},
+	}
+	for _, queue := range queues {
+		if err := queue.cfg.validate(); err != nil {
+			return fmt.Errorf("%s%w", queue.prefix, err)
+		}
+	}
+	return nil
+}
+
+func (c *OutboxConfig) validate() error {
+	if c.PollInterval <= 0 {
+		return fmt.Errorf("POLL_INTERVAL must be positive, got %s", c.PollInterval)
+	}
+	if c.BatchSize <= 0 {
+		return fmt.Errorf("BATCH_SIZE must be positive, got %d", c.BatchSize)
+	}
+	if c.Timeout <= 0 {
+		return fmt.Errorf("TIMEOUT must be positive, got %s", c.Timeout)
+	}
+	if c.MaxAttempts <= 0 {
+		return fmt.Errorf("MAX_ATTEMPTS must be positive, got %d", c.MaxAttempts)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"pr-review/internal/config"
+	"pr-review/internal/database/memory"
+	"pr-review/internal/database/postgres"
+	"pr-review/internal/database/sqlite"
+	"pr-review/internal/service"
+)
+
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	DriverMemory   = "memory"
+)
+
+type Repository interface {
+	service.PRRepository
+	service.TeamRepository
+	service.UserRepository
+	service.PoolRepository
+	service.StatsRepository
+	service.AbsenceRepository
+	service.WebhookRepository
+	service.AccountRepository
+	service.SyncRepository
+	service.ChatRepository
+	service.EmailRepository
+
+	Ping(ctx context.Context) error
+	Close() error
+}
+
+// New: хранилище в памяти миграций не требует.
+func New(ctx context.Context, cfg *config.DatabaseConfig) (Repository, error) {
+	const op = "database.New"
+
+	var (
+		repo Repository
+		err  error
+	)
+	switch cfg.Driver {
+	case DriverPostgres:
+		repo, err = postgres.New(ctx, cfg)
+	case DriverSQLite:
+		repo, err = sqlite.New(ctx, cfg)
+	case DriverMemory:
+		repo = memory.New()
+	default:
+		return nil, fmt.Errorf("%s: unknown database driver %q", op, cfg.Driver)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
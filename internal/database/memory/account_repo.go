@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+type accountKey struct {
+	provider string
+	login    string
+}
+
+func (r *MemoryRepository) LinkUserAccount(_ context.Context, account *models.UserAccount) error {
+	const op = "Memory.LinkUserAccount"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[account.UserID]; !ok {
+		return errors.WrapError(op, errors.ErrUserNotFound)
+	}
+
+	key := accountKey{provider: account.Provider, login: account.Login}
+	if userID, ok := r.accounts[key]; ok && userID != account.UserID {
+		return errors.WrapError(op, errors.ErrLoginTaken)
+	}
+
+	for linked, userID := range r.accounts {
+		if linked.provider == account.Provider && userID == account.UserID {
+			delete(r.accounts, linked)
+		}
+	}
+	r.accounts[key] = account.UserID
+	return nil
+}
+
+func (r *MemoryRepository) GetUserIDByLogin(_ context.Context, provider, login string) (string, error) {
+	const op = "Memory.GetUserIDByLogin"
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	userID, ok := r.accounts[accountKey{provider: provider, login: login}]
+	if !ok {
+		return "", errors.WrapError(op, errors.ErrAccountNotLinked)
+	}
+	return userID, nil
+}
+
+func (r *MemoryRepository) GetUserLogin(_ context.Context, provider, userID string) (string, error) {
+	const op = "Memory.GetUserLogin"
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for key, linked := range r.accounts {
+		if key.provider == provider && linked == userID {
+			return key.login, nil
+		}
+	}
+	return "", errors.WrapError(op, errors.ErrAccountNotLinked)
+}
+
+// private methods
+
+func (r *MemoryRepository) unlinkAccounts(userID string) {
+	for key, linked := range r.accounts {
+		if linked == userID {
+			delete(r.accounts, key)
+		}
+	}
+}
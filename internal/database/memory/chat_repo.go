@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"pr-review/internal/models"
+)
+
+func (r *MemoryRepository) EnqueueChatMessage(_ context.Context, message *models.ChatMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastChatMessageID++
+	r.chatMessages = append(r.chatMessages, &models.ChatMessage{
+		CreatedAt:     message.CreatedAt,
+		NextAttemptAt: message.NextAttemptAt,
+		TeamName:      message.TeamName,
+		Event:         message.Event,
+		URL:           message.URL,
+		Status:        models.ChatPending,
+		Payload:       slices.Clone(message.Payload),
+		ID:            r.lastChatMessageID,
+	})
+	return nil
+}
+
+func (r *MemoryRepository) ClaimDueChatMessages(
+	_ context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]models.ChatMessage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var due []*models.ChatMessage
+	for _, message := range r.chatMessages {
+		if message.Status == models.ChatPending && !message.NextAttemptAt.After(now) {
+			due = append(due, message)
+		}
+	}
+	slices.SortStableFunc(due, func(a, b *models.ChatMessage) int {
+		return a.NextAttemptAt.Compare(b.NextAttemptAt)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	var messages []models.ChatMessage
+	for _, message := range due {
+		message.NextAttemptAt = now.Add(lease)
+		messages = append(messages, copyChatMessage(message))
+	}
+
+	return messages, nil
+}
+
+func (r *MemoryRepository) SaveChatAttempt(_ context.Context, message *models.ChatMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, found := slices.BinarySearchFunc(r.chatMessages, message.ID, func(stored *models.ChatMessage, id int64) int {
+		return cmp.Compare(stored.ID, id)
+	})
+	if !found {
+		return nil
+	}
+
+	stored := r.chatMessages[i]
+	stored.Status = message.Status
+	stored.Attempts = message.Attempts
+	stored.LastError = message.LastError
+	stored.NextAttemptAt = message.NextAttemptAt
+	stored.SentAt = nil
+	if message.SentAt != nil {
+		stored.SentAt = copyTime(*message.SentAt)
+	}
+	return nil
+}
+
+// private methods
+
+func copyChatMessage(message *models.ChatMessage) models.ChatMessage {
+	result := *message
+	result.Payload = slices.Clone(message.Payload)
+	if message.SentAt != nil {
+		result.SentAt = copyTime(*message.SentAt)
+	}
+	return result
+}
@@ -0,0 +1,154 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+// SetUserEmail сохраняет день последней сводки, чтобы смена адреса не вызвала повторную.
+func (r *MemoryRepository) SetUserEmail(_ context.Context, settings *models.EmailSettings) error {
+	const op = "Memory.SetUserEmail"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[settings.UserID]; !ok {
+		return errors.WrapError(op, errors.ErrUserNotFound)
+	}
+
+	if settings.Email == "" {
+		delete(r.emails, settings.UserID)
+		return nil
+	}
+	stored := *settings
+	r.emails[settings.UserID] = &stored
+	return nil
+}
+
+func (r *MemoryRepository) GetUserEmail(_ context.Context, userID string) (*models.EmailSettings, error) {
+	const op = "Memory.GetUserEmail"
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, ok := r.users[userID]; !ok {
+		return nil, errors.WrapError(op, errors.ErrUserNotFound)
+	}
+	settings, ok := r.emails[userID]
+	if !ok {
+		return nil, errors.WrapError(op, errors.ErrEmailNotFound)
+	}
+
+	result := *settings
+	return &result, nil
+}
+
+func (r *MemoryRepository) ClaimDigestRecipients(_ context.Context, day time.Time) ([]models.EmailSettings, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var recipients []models.EmailSettings
+	for userID, settings := range r.emails {
+		if settings.Mode != models.EmailDigest || !r.users[userID].IsActive {
+			continue
+		}
+		if sentAt, ok := r.digestSentAt[userID]; ok && !sentAt.Before(day) {
+			continue
+		}
+		r.digestSentAt[userID] = day
+		recipients = append(recipients, *settings)
+	}
+	slices.SortFunc(recipients, func(a, b models.EmailSettings) int {
+		return cmp.Compare(a.UserID, b.UserID)
+	})
+
+	return recipients, nil
+}
+
+func (r *MemoryRepository) EnqueueEmail(_ context.Context, message *models.EmailMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastEmailMessageID++
+	r.emailMessages = append(r.emailMessages, &models.EmailMessage{
+		CreatedAt:     message.CreatedAt,
+		NextAttemptAt: message.NextAttemptAt,
+		UserID:        message.UserID,
+		Recipient:     message.Recipient,
+		Subject:       message.Subject,
+		Status:        models.EmailPending,
+		Message:       slices.Clone(message.Message),
+		ID:            r.lastEmailMessageID,
+	})
+	return nil
+}
+
+func (r *MemoryRepository) ClaimDueEmails(
+	_ context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]models.EmailMessage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var due []*models.EmailMessage
+	for _, message := range r.emailMessages {
+		if message.Status == models.EmailPending && !message.NextAttemptAt.After(now) {
+			due = append(due, message)
+		}
+	}
+	slices.SortStableFunc(due, func(a, b *models.EmailMessage) int {
+		return a.NextAttemptAt.Compare(b.NextAttemptAt)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	var messages []models.EmailMessage
+	for _, message := range due {
+		message.NextAttemptAt = now.Add(lease)
+		messages = append(messages, copyEmailMessage(message))
+	}
+
+	return messages, nil
+}
+
+func (r *MemoryRepository) SaveEmailAttempt(_ context.Context, message *models.EmailMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, found := slices.BinarySearchFunc(r.emailMessages, message.ID, func(stored *models.EmailMessage, id int64) int {
+		return cmp.Compare(stored.ID, id)
+	})
+	if !found {
+		return nil
+	}
+
+	stored := r.emailMessages[i]
+	stored.Status = message.Status
+	stored.Attempts = message.Attempts
+	stored.LastError = message.LastError
+	stored.NextAttemptAt = message.NextAttemptAt
+	stored.SentAt = nil
+	if message.SentAt != nil {
+		stored.SentAt = copyTime(*message.SentAt)
+	}
+	return nil
+}
+
+// private methods
+
+func copyEmailMessage(message *models.EmailMessage) models.EmailMessage {
+	result := *message
+	result.Message = slices.Clone(message.Message)
+	if message.SentAt != nil {
+		result.SentAt = copyTime(*message.SentAt)
+	}
+	return result
+}
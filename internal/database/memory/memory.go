@@ -0,0 +1,266 @@
+package memory
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+// MemoryRepository выполняет каждый метод целиком под одной блокировкой, поэтому проверки
+// и запись в нём атомарны, как транзакция в SQL-бэкендах.
+type MemoryRepository struct {
+	// teams хранит команды без Members и Fallbacks, они собираются при чтении
+	teams        map[string]*models.Team
+	users        map[string]*models.User
+	prs          map[string]*models.PullRequest
+	pools        map[string][]string
+	fallbacks    map[string][]models.ReviewerFallback
+	codeOwners   map[string]*models.CodeOwners
+	chats        map[string]*models.TeamChat
+	absences     map[string][]*models.Absence
+	events       map[string][]models.PREvent
+	webhooks     map[string]*models.Webhook
+	accounts     map[accountKey]string
+	emails       map[string]*models.EmailSettings
+	digestSentAt map[string]time.Time
+	// deliveries, syncTasks, chatMessages и emailMessages упорядочены по id
+	deliveries         []*models.WebhookDelivery
+	syncTasks          []*models.SyncTask
+	chatMessages       []*models.ChatMessage
+	emailMessages      []*models.EmailMessage
+	lastEventID        int64
+	lastDeliveryID     int64
+	lastSyncTaskID     int64
+	lastChatMessageID  int64
+	lastEmailMessageID int64
+	mu                 sync.RWMutex
+}
+
+func New() *MemoryRepository {
+	return &MemoryRepository{
+		teams:        make(map[string]*models.Team),
+		users:        make(map[string]*models.User),
+		prs:          make(map[string]*models.PullRequest),
+		pools:        make(map[string][]string),
+		fallbacks:    make(map[string][]models.ReviewerFallback),
+		codeOwners:   make(map[string]*models.CodeOwners),
+		chats:        make(map[string]*models.TeamChat),
+		absences:     make(map[string][]*models.Absence),
+		events:       make(map[string][]models.PREvent),
+		webhooks:     make(map[string]*models.Webhook),
+		accounts:     make(map[accountKey]string),
+		emails:       make(map[string]*models.EmailSettings),
+		digestSentAt: make(map[string]time.Time),
+	}
+}
+
+func (r *MemoryRepository) Close() error {
+	return nil
+}
+
+func (r *MemoryRepository) Ping(_ context.Context) error {
+	return nil
+}
+
+// private methods
+
+func (r *MemoryRepository) addEvents(events ...models.PREvent) {
+	for _, event := range events {
+		r.lastEventID++
+		event.ID = r.lastEventID
+		r.events[event.PullRequestID] = append(r.events[event.PullRequestID], event)
+	}
+}
+
+func reassignEvents(prID, oldUserID, newUserID, reason string, at time.Time) []models.PREvent {
+	return []models.PREvent{
+		{
+			PullRequestID: prID, Type: models.PREventReviewerRemoved,
+			UserID: oldUserID, RelatedUserID: newUserID, Reason: reason, CreatedAt: at,
+		},
+		{
+			PullRequestID: prID, Type: models.PREventReviewerAssigned,
+			UserID: newUserID, RelatedUserID: oldUserID, Reason: reason, CreatedAt: at,
+		},
+	}
+}
+
+// copyPR выводит ревьюверов из назначений, как в SQL-бэкендах.
+func copyPR(pr *models.PullRequest) *models.PullRequest {
+	result := *pr
+	result.ReviewersCount = copyInt(pr.ReviewersCount)
+	result.ChangedFiles = slices.Clone(pr.ChangedFiles)
+	result.Reviews = slices.Clone(pr.Reviews)
+	result.AssignedReviewers = nil
+	for _, review := range pr.Reviews {
+		result.AssignedReviewers = append(result.AssignedReviewers, review.UserID)
+	}
+	return &result
+}
+
+func copyInt(value *int) *int {
+	if value == nil {
+		return nil
+	}
+	result := *value
+	return &result
+}
+
+func copyTime(value time.Time) *time.Time {
+	return &value
+}
+
+func reviewIndex(reviews []models.Review, userID string) (int, bool) {
+	return slices.BinarySearchFunc(reviews, userID, func(review models.Review, userID string) int {
+		switch {
+		case review.UserID < userID:
+			return -1
+		case review.UserID > userID:
+			return 1
+		default:
+			return 0
+		}
+	})
+}
+
+// assignReviewer не меняет исходный срез, поэтому изменения можно собрать и применить разом.
+func assignReviewer(reviews []models.Review, userID string, assignedAt time.Time) ([]models.Review, bool) {
+	i, found := reviewIndex(reviews, userID)
+	if found {
+		return reviews, false
+	}
+	return slices.Insert(slices.Clone(reviews), i, models.Review{
+		AssignedAt: copyTime(assignedAt),
+		UserID:     userID,
+		State:      models.ReviewStatePending,
+	}), true
+}
+
+func unassignReviewer(reviews []models.Review, userID string) ([]models.Review, bool) {
+	i, found := reviewIndex(reviews, userID)
+	if !found {
+		return reviews, false
+	}
+	return slices.Delete(slices.Clone(reviews), i, i+1), true
+}
+
+func (r *MemoryRepository) isAbsent(userID string, at time.Time) bool {
+	for _, absence := range r.absences[userID] {
+		if !absence.StartsAt.After(at) && absence.EndsAt.After(at) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkAvailable: added - назначения этой же операции, ещё не записанные в r.prs.
+func (r *MemoryRepository) checkAvailable(userIDs []string, at time.Time, added map[string]int) error {
+	for _, userID := range userIDs {
+		user, ok := r.users[userID]
+		if !ok || !user.IsActive || r.isAbsent(userID, at) {
+			return errors.ErrReviewerUnavailable
+		}
+		candidate := r.candidate(user)
+		if candidate.MaxOpenReviews != nil && candidate.OpenReviews+added[userID] >= *candidate.MaxOpenReviews {
+			return errors.ErrReviewerUnavailable
+		}
+	}
+	return nil
+}
+
+// stageReassignments ничего не записывает. Уходящих этой же операцией (leaving) назначить нельзя.
+func (r *MemoryRepository) stageReassignments(
+	reassignments []models.Reassignment,
+	leaving []string,
+	now time.Time,
+) (map[string][]models.Review, error) {
+	reviews := make(map[string][]models.Review)
+	added := make(map[string]int)
+	for _, reassignment := range reassignments {
+		prReviews, staged := reviews[reassignment.PullRequestID]
+		if !staged {
+			pr, ok := r.prs[reassignment.PullRequestID]
+			if !ok {
+				return nil, errors.ErrNotAssigned
+			}
+			if pr.Status != models.PRStatusOpen {
+				return nil, errors.ErrReviewerUnavailable
+			}
+			prReviews = pr.Reviews
+		}
+		if slices.Contains(leaving, reassignment.NewUserID) {
+			return nil, errors.ErrReviewerUnavailable
+		}
+		if err := r.checkAvailable([]string{reassignment.NewUserID}, now, added); err != nil {
+			return nil, err
+		}
+		added[reassignment.NewUserID]++
+
+		prReviews, err := reassign(prReviews, reassignment.OldUserID, reassignment.NewUserID, now)
+		if err != nil {
+			return nil, err
+		}
+		reviews[reassignment.PullRequestID] = prReviews
+	}
+	return reviews, nil
+}
+
+func (r *MemoryRepository) applyReassignments(
+	reviews map[string][]models.Review,
+	reassignments []models.Reassignment,
+	reason string,
+	now time.Time,
+) {
+	for prID, prReviews := range reviews {
+		r.prs[prID].Reviews = prReviews
+	}
+	for _, reassignment := range reassignments {
+		r.addEvents(reassignEvents(
+			reassignment.PullRequestID, reassignment.OldUserID, reassignment.NewUserID, reason, now,
+		)...)
+	}
+}
+
+// candidate считает открытые ревью только на PR не в черновике.
+func (r *MemoryRepository) candidate(user *models.User) models.ReviewerCandidate {
+	candidate := models.ReviewerCandidate{
+		MaxOpenReviews: copyInt(user.MaxOpenReviews),
+		UserID:         user.UserID,
+	}
+	if candidate.MaxOpenReviews == nil {
+		if team, ok := r.teams[user.TeamName]; ok {
+			candidate.MaxOpenReviews = copyInt(team.MaxOpenReviews)
+		}
+	}
+
+	for _, pr := range r.prs {
+		i, found := reviewIndex(pr.Reviews, user.UserID)
+		if !found {
+			continue
+		}
+		if pr.Status == models.PRStatusOpen && !pr.IsDraft {
+			candidate.OpenReviews++
+		}
+		assignedAt := pr.Reviews[i].AssignedAt
+		if assignedAt != nil && (candidate.LastAssignedAt == nil || assignedAt.After(*candidate.LastAssignedAt)) {
+			candidate.LastAssignedAt = copyTime(*assignedAt)
+		}
+	}
+	return candidate
+}
+
+func avgReviewers(prs []*models.PullRequest) float64 {
+	if len(prs) == 0 {
+		return 0
+	}
+	reviewers := 0
+	for _, pr := range prs {
+		reviewers += len(pr.Reviews)
+	}
+	return math.Round(float64(reviewers)/float64(len(prs))*100) / 100
+}
@@ -0,0 +1,195 @@
+package memory_test
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"pr-review/internal/config"
+	"pr-review/internal/database"
+	"pr-review/internal/database/memory"
+	"pr-review/internal/database/repotest"
+	serviceErrors "pr-review/internal/errors"
+	"pr-review/internal/models"
+	"pr-review/internal/service"
+)
+
+func TestRepository(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) database.Repository { return memory.New() })
+}
+
+func TestCreatePRExists(t *testing.T) {
+	repo := newRepo(t, "u1", "u2", "u3")
+
+	noError(t, repo.CreatePR(t.Context(), newPR("pr-1", "u2")))
+	wantError(t, repo.CreatePR(t.Context(), newPR("pr-1", "u3")), serviceErrors.ErrPRExists)
+
+	// повторное создание не меняет PR
+	pr, err := repo.GetPRByID(t.Context(), "pr-1")
+	noError(t, err)
+	equal(t, "reviewers", fmt.Sprint(pr.AssignedReviewers), "[u2]")
+}
+
+// Из параллельных созданий одного PR успешно только одно, остальные получают ErrPRExists.
+func TestCreatePRConcurrent(t *testing.T) {
+	repo := newRepo(t, "u1", "u2", "u3")
+
+	errs := runConcurrently(10, func(i int) error {
+		return repo.CreatePR(t.Context(), newPR("pr-1", []string{"u2", "u3"}[i%2]))
+	})
+
+	created := 0
+	for _, err := range errs {
+		if err == nil {
+			created++
+			continue
+		}
+		wantError(t, err, serviceErrors.ErrPRExists)
+	}
+	equal(t, "created", created, 1)
+
+	events, err := repo.GetPREvents(t.Context(), "pr-1")
+	noError(t, err)
+	equal(t, "events", len(events), 2)
+}
+
+// Параллельные замены одного ревьювера: заменить его успевает только одна,
+// остальные видят, что он уже не назначен, и PR не получает лишних ревьюверов.
+func TestReassignReviewerConcurrent(t *testing.T) {
+	repo := newRepo(t, "u1", "u2", "u3", "u4", "u5", "u6")
+	noError(t, repo.CreatePR(t.Context(), newPR("pr-1", "u2")))
+
+	newUserIDs := []string{"u3", "u4", "u5", "u6"}
+	errs := runConcurrently(len(newUserIDs), func(i int) error {
+		return repo.ReassignReviewer(t.Context(), "pr-1", "u2", newUserIDs[i], models.ReasonManual)
+	})
+
+	reassigned := 0
+	for _, err := range errs {
+		if err == nil {
+			reassigned++
+			continue
+		}
+		wantError(t, err, serviceErrors.ErrNotAssigned)
+	}
+	equal(t, "reassigned", reassigned, 1)
+
+	pr, err := repo.GetPRByID(t.Context(), "pr-1")
+	noError(t, err)
+	equal(t, "reviewers", len(pr.AssignedReviewers), 1)
+	equal(t, "old removed", pr.AssignedReviewers[0] != "u2", true)
+}
+
+// Два разных ревьювера заменяются на одного и того же: второй раз он уже назначен.
+func TestReassignReviewerConcurrentSameTarget(t *testing.T) {
+	repo := newRepo(t, "u1", "u2", "u3", "u4")
+	noError(t, repo.CreatePR(t.Context(), newPR("pr-1", "u2", "u3")))
+
+	oldUserIDs := []string{"u2", "u3"}
+	errs := runConcurrently(len(oldUserIDs), func(i int) error {
+		return repo.ReassignReviewer(t.Context(), "pr-1", oldUserIDs[i], "u4", models.ReasonManual)
+	})
+
+	reassigned := 0
+	for _, err := range errs {
+		if err == nil {
+			reassigned++
+			continue
+		}
+		wantError(t, err, serviceErrors.ErrReviewerUnavailable)
+	}
+	equal(t, "reassigned", reassigned, 1)
+
+	pr, err := repo.GetPRByID(t.Context(), "pr-1")
+	noError(t, err)
+	equal(t, "reviewers", len(pr.AssignedReviewers), 2)
+}
+
+// Сервис поверх хранилища в памяти возвращает ErrNoCandidate, когда заменить
+// ревьювера некем, и не меняет назначения.
+func TestReassignReviewerNoCandidate(t *testing.T) {
+	repo := newRepo(t, "u1", "u2", "u3")
+	selectors, err := service.NewTeamSelectors(&config.ReviewConfig{
+		Strategy:   service.StrategyRoundRobin,
+		TieBreak:   service.TieBreakLastAssigned,
+		AtCapacity: service.AtCapacityAssignFewer,
+	})
+	noError(t, err)
+	prs := service.NewPRService(slog.New(slog.DiscardHandler), repo, selectors, service.EventPublishers{})
+
+	_, err = prs.CreatePR(t.Context(), &models.PullRequestShort{ID: "pr-1", Name: "PR", AuthorID: "u1"}, models.CreatePROptions{})
+	noError(t, err)
+
+	_, _, err = prs.ReassignReviewer(t.Context(), "pr-1", "u2", models.ReasonManual)
+	wantError(t, err, serviceErrors.ErrNoCandidate)
+
+	pr, err := repo.GetPRByID(t.Context(), "pr-1")
+	noError(t, err)
+	equal(t, "reviewers", fmt.Sprint(pr.AssignedReviewers), "[u2 u3]")
+}
+
+// newRepo создаёт хранилище с командой backend из активных участников.
+func newRepo(t *testing.T, userIDs ...string) *memory.MemoryRepository {
+	t.Helper()
+
+	repo := memory.New()
+	team := &models.Team{Name: "backend", RequiredReviewers: models.DefaultRequiredReviewers}
+	for _, userID := range userIDs {
+		team.Members = append(team.Members, models.TeamMember{UserID: userID, Username: userID + "-name", IsActive: true})
+	}
+	noError(t, repo.CreateTeam(t.Context(), team))
+	return repo
+}
+
+func newPR(id string, reviewers ...string) *models.PullRequest {
+	return &models.PullRequest{
+		PullRequestShort:  models.PullRequestShort{ID: id, Name: "PR " + id, AuthorID: "u1"},
+		AssignedReviewers: reviewers,
+	}
+}
+
+// runConcurrently запускает n вызовов run одновременно и возвращает их ошибки.
+func runConcurrently(n int, run func(i int) error) []error {
+	errs := make([]error, n)
+	start := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			errs[i] = run(i)
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	return errs
+}
+
+func noError(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func wantError(t *testing.T, err, target error) {
+	t.Helper()
+
+	if !errors.Is(err, target) {
+		t.Fatalf("got error %v, want %v", err, target)
+	}
+}
+
+func equal[T comparable](t *testing.T, what string, got, want T) {
+	t.Helper()
+
+	if got != want {
+		t.Fatalf("%s: got %v, want %v", what, got, want)
+	}
+}
@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+func (r *MemoryRepository) CreateReviewerPool(_ context.Context, pool *models.ReviewerPool) error {
+	const op = "Memory.CreateReviewerPool"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.pools[pool.Name]; ok {
+		return errors.WrapError(op, errors.ErrPoolExists)
+	}
+
+	members, err := r.poolMembers(pool)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	r.pools[pool.Name] = members
+	return nil
+}
+
+func (r *MemoryRepository) GetReviewerPool(_ context.Context, name string) (*models.ReviewerPool, error) {
+	const op = "Memory.GetReviewerPool"
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	members, ok := r.pools[name]
+	if !ok {
+		return nil, errors.WrapError(op, errors.ErrPoolNotFound)
+	}
+
+	return &models.ReviewerPool{Name: name, Members: slices.Clone(members)}, nil
+}
+
+func (r *MemoryRepository) SetReviewerPoolMembers(_ context.Context, pool *models.ReviewerPool) error {
+	const op = "Memory.SetReviewerPoolMembers"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.pools[pool.Name]; !ok {
+		return errors.WrapError(op, errors.ErrPoolNotFound)
+	}
+
+	members, err := r.poolMembers(pool)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	r.pools[pool.Name] = members
+	return nil
+}
+
+func (r *MemoryRepository) PoolExists(_ context.Context, name string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.pools[name]
+	return ok, nil
+}
+
+// private methods
+
+func (r *MemoryRepository) poolMembers(pool *models.ReviewerPool) ([]string, error) {
+	const op = "Memory.poolMembers"
+
+	for _, userID := range pool.Members {
+		if _, ok := r.users[userID]; !ok {
+			return nil, errors.WrapError(op, errors.ErrUserNotFound)
+		}
+	}
+
+	members := slices.Clone(pool.Members)
+	slices.Sort(members)
+	return slices.Compact(members), nil
+}
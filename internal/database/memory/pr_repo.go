@@ -0,0 +1,416 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+func (r *MemoryRepository) CreatePR(_ context.Context, pr *models.PullRequest) error {
+	const op = "Memory.CreatePR"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.prs[pr.ID]; ok {
+		return errors.WrapError(op, errors.ErrPRExists)
+	}
+	if _, ok := r.users[pr.AuthorID]; !ok {
+		return errors.WrapError(op, errors.ErrUserNotFound)
+	}
+
+	now := time.Now()
+	if err := r.checkAvailable(pr.AssignedReviewers, now, nil); err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	created := &models.PullRequest{
+		CreatedAt:      now,
+		ReviewersCount: copyInt(pr.ReviewersCount),
+		Provider:       pr.Provider,
+		PullRequestShort: models.PullRequestShort{
+			ID:       pr.ID,
+			Name:     pr.Name,
+			AuthorID: pr.AuthorID,
+			Status:   models.PRStatusOpen,
+			IsDraft:  pr.IsDraft,
+		},
+	}
+
+	for _, userID := range pr.AssignedReviewers {
+		var ok bool
+		created.Reviews, ok = assignReviewer(created.Reviews, userID, now)
+		if !ok {
+			return fmt.Errorf("%s: reviewer %s is assigned twice", op, userID)
+		}
+	}
+
+	for _, path := range pr.ChangedFiles {
+		i, found := slices.BinarySearch(created.ChangedFiles, path)
+		if found {
+			return fmt.Errorf("%s: file %s is listed twice", op, path)
+		}
+		created.ChangedFiles = slices.Insert(created.ChangedFiles, i, path)
+	}
+
+	r.prs[pr.ID] = created
+	r.addEvents(models.PREvent{PullRequestID: pr.ID, Type: models.PREventCreated, UserID: pr.AuthorID, CreatedAt: now})
+	for _, userID := range pr.AssignedReviewers {
+		r.addEvents(models.PREvent{PullRequestID: pr.ID, Type: models.PREventReviewerAssigned, UserID: userID, CreatedAt: now})
+	}
+	return nil
+}
+
+func (r *MemoryRepository) GetPRByID(_ context.Context, id string) (*models.PullRequest, error) {
+	const op = "Memory.GetPRByID"
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	pr, ok := r.prs[id]
+	if !ok {
+		return nil, errors.WrapError(op, errors.ErrPRNotFound)
+	}
+
+	return copyPR(pr), nil
+}
+
+func (r *MemoryRepository) MergePR(_ context.Context, prID string, mergedAt time.Time, guard func(pr *models.PullRequest) error) error {
+	const op = "Memory.MergePR"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pr, ok := r.prs[prID]
+	if !ok {
+		return errors.WrapError(op, errors.ErrPRNotFound)
+	}
+
+	switch pr.Status {
+	case models.PRStatusMerged:
+		return nil
+	case models.PRStatusClosed:
+		return errors.WrapError(op, errors.ErrPRClosed)
+	}
+	if guard != nil {
+		if err := guard(copyPR(pr)); err != nil {
+			return errors.WrapError(op, err)
+		}
+	}
+
+	pr.Status = models.PRStatusMerged
+	pr.MergedAt = copyTime(mergedAt)
+	r.addEvents(models.PREvent{PullRequestID: prID, Type: models.PREventMerged, CreatedAt: mergedAt})
+	return nil
+}
+
+func (r *MemoryRepository) ClosePR(_ context.Context, prID string, closedAt time.Time) error {
+	const op = "Memory.ClosePR"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pr, ok := r.prs[prID]
+	if !ok || pr.Status != models.PRStatusOpen {
+		return errors.WrapError(op, errors.ErrPRNotFound)
+	}
+
+	pr.Status = models.PRStatusClosed
+	pr.ClosedAt = copyTime(closedAt)
+	r.addEvents(models.PREvent{PullRequestID: prID, Type: models.PREventClosed, CreatedAt: closedAt})
+	return nil
+}
+
+func (r *MemoryRepository) ReopenPR(_ context.Context, prID string, removed, added []string) error {
+	const op = "Memory.ReopenPR"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pr, ok := r.prs[prID]
+	if !ok || pr.Status != models.PRStatusClosed {
+		return errors.WrapError(op, errors.ErrPRNotFound)
+	}
+
+	now := time.Now()
+	if err := r.checkAvailable(added, now, nil); err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	reviews := pr.Reviews
+	for _, userID := range removed {
+		reviews, _ = unassignReviewer(reviews, userID)
+	}
+	for _, userID := range added {
+		var ok bool
+		reviews, ok = assignReviewer(reviews, userID, now)
+		if !ok {
+			return fmt.Errorf("%s: reviewer %s is already assigned", op, userID)
+		}
+	}
+
+	pr.Status = models.PRStatusOpen
+	pr.ClosedAt = nil
+	pr.Reviews = reviews
+	r.addEvents(models.PREvent{PullRequestID: prID, Type: models.PREventReopened, CreatedAt: now})
+	for _, userID := range removed {
+		r.addEvents(models.PREvent{
+			PullRequestID: prID, Type: models.PREventReviewerRemoved, UserID: userID, Reason: models.ReasonUnavailable, CreatedAt: now,
+		})
+	}
+	for _, userID := range added {
+		r.addEvents(models.PREvent{PullRequestID: prID, Type: models.PREventReviewerAssigned, UserID: userID, CreatedAt: now})
+	}
+	return nil
+}
+
+func (r *MemoryRepository) MarkReadyForReview(_ context.Context, prID string, reviewers []string) error {
+	const op = "Memory.MarkReadyForReview"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pr, ok := r.prs[prID]
+	if !ok || pr.Status != models.PRStatusOpen || !pr.IsDraft {
+		return errors.WrapError(op, errors.ErrPRNotFound)
+	}
+
+	now := time.Now()
+	if err := r.checkAvailable(reviewers, now, nil); err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	reviews := pr.Reviews
+	for _, userID := range reviewers {
+		var ok bool
+		reviews, ok = assignReviewer(reviews, userID, now)
+		if !ok {
+			return fmt.Errorf("%s: reviewer %s is already assigned", op, userID)
+		}
+	}
+
+	pr.IsDraft = false
+	pr.Reviews = reviews
+	r.addEvents(models.PREvent{PullRequestID: prID, Type: models.PREventReadyForReview, CreatedAt: now})
+	for _, userID := range reviewers {
+		r.addEvents(models.PREvent{PullRequestID: prID, Type: models.PREventReviewerAssigned, UserID: userID, CreatedAt: now})
+	}
+	return nil
+}
+
+// MarkDraft оставляет назначенных ревьюверов.
+func (r *MemoryRepository) MarkDraft(_ context.Context, prID string) error {
+	const op = "Memory.MarkDraft"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pr, ok := r.prs[prID]
+	if !ok || pr.Status != models.PRStatusOpen || pr.IsDraft {
+		return errors.WrapError(op, errors.ErrPRNotFound)
+	}
+
+	pr.IsDraft = true
+	r.addEvents(models.PREvent{PullRequestID: prID, Type: models.PREventConvertedToDraft, CreatedAt: time.Now()})
+	return nil
+}
+
+func (r *MemoryRepository) SubmitReview(_ context.Context, prID, userID string, state models.ReviewState, reviewedAt time.Time) error {
+	const op = "Memory.SubmitReview"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pr, ok := r.prs[prID]
+	if !ok {
+		return errors.WrapError(op, errors.ErrNotAssigned)
+	}
+	i, found := reviewIndex(pr.Reviews, userID)
+	if !found {
+		return errors.WrapError(op, errors.ErrNotAssigned)
+	}
+
+	reviews := slices.Clone(pr.Reviews)
+	reviews[i].State = state
+	reviews[i].ReviewedAt = copyTime(reviewedAt)
+	pr.Reviews = reviews
+	r.addEvents(models.PREvent{
+		PullRequestID: prID, Type: models.PREventReviewSubmitted, UserID: userID, State: state, CreatedAt: reviewedAt,
+	})
+	return nil
+}
+
+// ReassignReviewer отклоняет с ErrReviewerUnavailable замену, которую успели деактивировать или назначить на этот PR.
+func (r *MemoryRepository) ReassignReviewer(_ context.Context, prID, oldUserID, newUserID, reason string) error {
+	const op = "Memory.ReassignReviewer"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pr, ok := r.prs[prID]
+	if !ok {
+		return errors.WrapError(op, errors.ErrPRNotFound)
+	}
+	switch pr.Status {
+	case models.PRStatusMerged:
+		return errors.WrapError(op, errors.ErrPRMerged)
+	case models.PRStatusClosed:
+		return errors.WrapError(op, errors.ErrPRClosed)
+	}
+
+	now := time.Now()
+	if _, found := reviewIndex(pr.Reviews, oldUserID); !found {
+		return errors.WrapError(op, errors.ErrNotAssigned)
+	}
+	if _, found := reviewIndex(pr.Reviews, newUserID); found {
+		return errors.WrapError(op, errors.ErrReviewerUnavailable)
+	}
+	if err := r.checkAvailable([]string{newUserID}, now, nil); err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	reviews, err := reassign(pr.Reviews, oldUserID, newUserID, now)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	pr.Reviews = reviews
+	r.addEvents(reassignEvents(prID, oldUserID, newUserID, reason, now)...)
+	return nil
+}
+
+func (r *MemoryRepository) GetPREvents(_ context.Context, prID string) ([]models.PREvent, error) {
+	const op = "Memory.GetPREvents"
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, ok := r.prs[prID]; !ok {
+		return nil, errors.WrapError(op, errors.ErrPRNotFound)
+	}
+
+	return slices.Clone(r.events[prID]), nil
+}
+
+func (r *MemoryRepository) PRExists(_ context.Context, prID string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.prs[prID]
+	return ok, nil
+}
+
+func (r *MemoryRepository) IsReviewerAssigned(_ context.Context, prID, userID string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	pr, ok := r.prs[prID]
+	if !ok {
+		return false, nil
+	}
+	_, found := reviewIndex(pr.Reviews, userID)
+	return found, nil
+}
+
+func (r *MemoryRepository) GetTotalStats(_ context.Context) (*models.TotalStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stats := &models.TotalStats{
+		TotalTeams: len(r.teams),
+		TotalUsers: len(r.users),
+		TotalPRs:   len(r.prs),
+	}
+	for _, user := range r.users {
+		if user.IsActive {
+			stats.ActiveUsers++
+		}
+	}
+
+	prs := make([]*models.PullRequest, 0, len(r.prs))
+	for _, pr := range r.prs {
+		prs = append(prs, pr)
+		switch {
+		case pr.Status == models.PRStatusOpen && pr.IsDraft:
+			stats.DraftPRs++
+		case pr.Status == models.PRStatusOpen:
+			stats.OpenPRs++
+		case pr.Status == models.PRStatusMerged:
+			stats.MergedPRs++
+		case pr.Status == models.PRStatusClosed:
+			stats.ClosedPRs++
+		}
+	}
+	stats.AvgReviewersPerPR = avgReviewers(prs)
+
+	return stats, nil
+}
+
+func (r *MemoryRepository) GetReviewerCandidates(_ context.Context, teamName string) ([]models.ReviewerCandidate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	var candidates []models.ReviewerCandidate
+	for _, user := range r.users {
+		if user.TeamName != teamName || !user.IsActive || r.isAbsent(user.UserID, now) {
+			continue
+		}
+		candidates = append(candidates, r.candidate(user))
+	}
+	sortCandidates(candidates)
+
+	return candidates, nil
+}
+
+func (r *MemoryRepository) GetReviewerCandidatesByIDs(_ context.Context, userIDs []string) ([]models.ReviewerCandidate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	var candidates []models.ReviewerCandidate
+	for _, userID := range userIDs {
+		user, ok := r.users[userID]
+		if !ok || !user.IsActive || r.isAbsent(userID, now) {
+			continue
+		}
+		if slices.ContainsFunc(candidates, func(c models.ReviewerCandidate) bool { return c.UserID == userID }) {
+			continue
+		}
+		candidates = append(candidates, r.candidate(user))
+	}
+	sortCandidates(candidates)
+
+	return candidates, nil
+}
+
+// private methods
+
+func reassign(reviews []models.Review, oldUserID, newUserID string, assignedAt time.Time) ([]models.Review, error) {
+	reviews, ok := unassignReviewer(reviews, oldUserID)
+	if !ok {
+		return nil, errors.ErrNotAssigned
+	}
+	reviews, ok = assignReviewer(reviews, newUserID, assignedAt)
+	if !ok {
+		return nil, errors.ErrReviewerUnavailable
+	}
+	return reviews, nil
+}
+
+func sortCandidates(candidates []models.ReviewerCandidate) {
+	slices.SortFunc(candidates, func(a, b models.ReviewerCandidate) int {
+		switch {
+		case a.UserID < b.UserID:
+			return -1
+		case a.UserID > b.UserID:
+			return 1
+		default:
+			return 0
+		}
+	})
+}
@@ -0,0 +1,140 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+func (r *MemoryRepository) EnqueueSyncTasks(_ context.Context, tasks []models.SyncTask) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, task := range tasks {
+		r.lastSyncTaskID++
+		stored := task
+		stored.ID = r.lastSyncTaskID
+		stored.Status = models.SyncPending
+		stored.Attempts = 0
+		stored.LastError = ""
+		stored.CompletedAt = nil
+		r.syncTasks = append(r.syncTasks, &stored)
+	}
+	return nil
+}
+
+// ClaimDueSyncTasks выдаёт для каждого PR только самую раннюю ожидающую задачу.
+func (r *MemoryRepository) ClaimDueSyncTasks(
+	_ context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]models.SyncTask, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var due []*models.SyncTask
+	blocked := make(map[string]bool)
+	for _, task := range r.syncTasks {
+		if task.Status != models.SyncPending || blocked[task.PullRequestID] {
+			continue
+		}
+		blocked[task.PullRequestID] = true
+		if !task.NextAttemptAt.After(now) {
+			due = append(due, task)
+		}
+	}
+	slices.SortStableFunc(due, func(a, b *models.SyncTask) int {
+		return a.NextAttemptAt.Compare(b.NextAttemptAt)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	var tasks []models.SyncTask
+	for _, task := range due {
+		task.NextAttemptAt = now.Add(lease)
+		tasks = append(tasks, copySyncTask(task))
+	}
+	slices.SortFunc(tasks, func(a, b models.SyncTask) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return tasks, nil
+}
+
+func (r *MemoryRepository) SaveSyncAttempt(_ context.Context, task *models.SyncTask) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.syncTask(task.ID)
+	if !ok {
+		return nil
+	}
+
+	stored.Status = task.Status
+	stored.Attempts = task.Attempts
+	stored.LastError = task.LastError
+	stored.NextAttemptAt = task.NextAttemptAt
+	stored.CompletedAt = nil
+	if task.CompletedAt != nil {
+		stored.CompletedAt = copyTime(*task.CompletedAt)
+	}
+	return nil
+}
+
+func (r *MemoryRepository) GetSyncTasks(_ context.Context, status string, limit int) ([]models.SyncTask, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var tasks []models.SyncTask
+	for i := len(r.syncTasks) - 1; i >= 0 && len(tasks) < limit; i-- {
+		if status == "" || r.syncTasks[i].Status == status {
+			tasks = append(tasks, copySyncTask(r.syncTasks[i]))
+		}
+	}
+
+	return tasks, nil
+}
+
+func (r *MemoryRepository) RetrySyncTask(_ context.Context, id int64, at time.Time) error {
+	const op = "Memory.RetrySyncTask"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	task, ok := r.syncTask(id)
+	if !ok || task.Status != models.SyncFailed {
+		return errors.WrapError(op, errors.ErrSyncTaskNotFound)
+	}
+
+	task.Status = models.SyncPending
+	task.Attempts = 0
+	task.LastError = ""
+	task.NextAttemptAt = at
+	return nil
+}
+
+// private methods
+
+func (r *MemoryRepository) syncTask(id int64) (*models.SyncTask, bool) {
+	i, found := slices.BinarySearchFunc(r.syncTasks, id, func(stored *models.SyncTask, id int64) int {
+		return cmp.Compare(stored.ID, id)
+	})
+	if !found {
+		return nil, false
+	}
+	return r.syncTasks[i], true
+}
+
+func copySyncTask(task *models.SyncTask) models.SyncTask {
+	result := *task
+	if task.CompletedAt != nil {
+		result.CompletedAt = copyTime(*task.CompletedAt)
+	}
+	return result
+}
@@ -0,0 +1,462 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+func (r *MemoryRepository) CreateTeam(_ context.Context, team *models.Team) error {
+	const op = "Memory.CreateTeam"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.teams[team.Name]; ok {
+		return errors.WrapError(op, errors.ErrTeamExists)
+	}
+
+	for i, member := range team.Members {
+		if r.userExists(member.UserID, member.Username) {
+			return errors.WrapError(op, errors.ErrUserExists)
+		}
+		// Повтор внутри самой команды нарушил бы уникальность так же, как существующий пользователь
+		for _, other := range team.Members[:i] {
+			if other.UserID == member.UserID || other.Username == member.Username {
+				return errors.WrapError(op, errors.ErrUserExists)
+			}
+		}
+	}
+
+	r.teams[team.Name] = &models.Team{
+		MaxOpenReviews:    copyInt(team.MaxOpenReviews),
+		Name:              team.Name,
+		MergePolicy:       team.MergePolicy,
+		RequiredReviewers: team.RequiredReviewers,
+	}
+	for _, member := range team.Members {
+		r.users[member.UserID] = &models.User{
+			TeamName:   team.Name,
+			TeamMember: member,
+		}
+	}
+
+	return nil
+}
+
+func (r *MemoryRepository) GetTeamByName(_ context.Context, name string) (*models.Team, error) {
+	const op = "Memory.GetTeamByName"
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored, ok := r.teams[name]
+	if !ok {
+		return nil, errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+
+	team := *stored
+	team.MaxOpenReviews = copyInt(stored.MaxOpenReviews)
+	if stored.ArchivedAt != nil {
+		team.ArchivedAt = copyTime(*stored.ArchivedAt)
+	}
+
+	for _, user := range r.users {
+		if user.TeamName == name {
+			team.Members = append(team.Members, user.TeamMember)
+		}
+	}
+	slices.SortFunc(team.Members, func(a, b models.TeamMember) int {
+		return strings.Compare(a.Username, b.Username)
+	})
+
+	team.Fallbacks = slices.Clone(r.fallbacks[name])
+
+	return &team, nil
+}
+
+// AddTeamMember возвращает ранее исключённого из команды пользователя с новыми данными.
+func (r *MemoryRepository) AddTeamMember(_ context.Context, teamName string, member *models.TeamMember) error {
+	const op = "Memory.AddTeamMember"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.teams[teamName]; !ok {
+		return errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+
+	user, ok := r.users[member.UserID]
+	if ok && user.TeamName != "" {
+		return errors.WrapError(op, errors.ErrUserExists)
+	}
+
+	for _, other := range r.users {
+		if other.Username == member.Username && other.UserID != member.UserID {
+			return errors.WrapError(op, errors.ErrUserExists)
+		}
+	}
+
+	if !ok {
+		user = &models.User{}
+		r.users[member.UserID] = user
+	}
+	user.TeamMember = *member
+	user.TeamName = teamName
+
+	return nil
+}
+
+// RemoveTeamMember оставляет пользователя, чтобы не потерять историю его PR и ревью.
+func (r *MemoryRepository) RemoveTeamMember(_ context.Context, teamName, userID string, reassignments []models.Reassignment) error {
+	const op = "Memory.RemoveTeamMember"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.teams[teamName]; !ok {
+		return errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+
+	user, ok := r.users[userID]
+	if !ok {
+		return errors.WrapError(op, errors.ErrUserNotFound)
+	}
+	if user.TeamName != teamName {
+		return errors.WrapError(op, errors.ErrNotMember)
+	}
+
+	now := time.Now()
+	reviews, err := r.stageReassignments(reassignments, []string{userID}, now)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	user.TeamName = ""
+	r.applyReassignments(reviews, reassignments, models.ReasonLeftTeam, now)
+	return nil
+}
+
+func (r *MemoryRepository) RenameTeam(_ context.Context, oldName, newName string) error {
+	const op = "Memory.RenameTeam"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	team, ok := r.teams[oldName]
+	if !ok {
+		return errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+	if _, ok := r.teams[newName]; ok {
+		return errors.WrapError(op, errors.ErrTeamExists)
+	}
+
+	delete(r.teams, oldName)
+	team.Name = newName
+	r.teams[newName] = team
+
+	for _, user := range r.users {
+		if user.TeamName == oldName {
+			user.TeamName = newName
+		}
+	}
+
+	if codeOwners, ok := r.codeOwners[oldName]; ok {
+		delete(r.codeOwners, oldName)
+		codeOwners.TeamName = newName
+		r.codeOwners[newName] = codeOwners
+	}
+	if chat, ok := r.chats[oldName]; ok {
+		delete(r.chats, oldName)
+		chat.TeamName = newName
+		r.chats[newName] = chat
+	}
+
+	if fallbacks, ok := r.fallbacks[oldName]; ok {
+		delete(r.fallbacks, oldName)
+		r.fallbacks[newName] = fallbacks
+	}
+	for teamName, fallbacks := range r.fallbacks {
+		renamed := slices.Clone(fallbacks)
+		for i := range renamed {
+			if renamed[i].Kind == models.FallbackTeam && renamed[i].Name == oldName {
+				renamed[i].Name = newName
+			}
+		}
+		r.fallbacks[teamName] = renamed
+	}
+
+	return nil
+}
+
+func (r *MemoryRepository) GetOpenPRIDsByTeam(_ context.Context, teamName string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var prIDs []string
+	for _, pr := range r.prs {
+		if pr.Status == models.PRStatusOpen && r.isTeamMember(pr.AuthorID, teamName) {
+			prIDs = append(prIDs, pr.ID)
+		}
+	}
+	slices.Sort(prIDs)
+
+	return prIDs, nil
+}
+
+func (r *MemoryRepository) TeamHasHistory(_ context.Context, teamName string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, pr := range r.prs {
+		if r.isTeamMember(pr.AuthorID, teamName) {
+			return true, nil
+		}
+		for _, review := range pr.Reviews {
+			if r.isTeamMember(review.UserID, teamName) {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+func (r *MemoryRepository) ArchiveTeam(_ context.Context, teamName string, archivedAt time.Time, closePRs []string) error {
+	const op = "Memory.ArchiveTeam"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	team, ok := r.teams[teamName]
+	if !ok {
+		return errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+	team.ArchivedAt = copyTime(archivedAt)
+
+	for _, user := range r.users {
+		if user.TeamName == teamName {
+			user.IsActive = false
+		}
+	}
+
+	for _, prID := range closePRs {
+		if pr, ok := r.prs[prID]; ok && pr.Status == models.PRStatusOpen {
+			pr.Status = models.PRStatusClosed
+			pr.ClosedAt = copyTime(archivedAt)
+			r.addEvents(models.PREvent{
+				PullRequestID: prID, Type: models.PREventClosed, Reason: models.ReasonTeamArchived, CreatedAt: archivedAt,
+			})
+		}
+	}
+
+	return nil
+}
+
+// DeleteTeam вызывается только для команды без истории PR, см. TeamHasHistory.
+func (r *MemoryRepository) DeleteTeam(_ context.Context, teamName string) error {
+	const op = "Memory.DeleteTeam"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.teams[teamName]; !ok {
+		return errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+
+	delete(r.codeOwners, teamName)
+	delete(r.chats, teamName)
+	delete(r.fallbacks, teamName)
+	for name, fallbacks := range r.fallbacks {
+		r.fallbacks[name] = slices.DeleteFunc(slices.Clone(fallbacks), func(fallback models.ReviewerFallback) bool {
+			return fallback.Kind == models.FallbackTeam && fallback.Name == teamName
+		})
+	}
+
+	for userID, user := range r.users {
+		if user.TeamName != teamName {
+			continue
+		}
+		for name, members := range r.pools {
+			r.pools[name] = slices.DeleteFunc(slices.Clone(members), func(memberID string) bool { return memberID == userID })
+		}
+		delete(r.absences, userID)
+		r.unlinkAccounts(userID)
+		delete(r.emails, userID)
+		delete(r.digestSentAt, userID)
+		delete(r.users, userID)
+	}
+
+	delete(r.teams, teamName)
+	return nil
+}
+
+func (r *MemoryRepository) SetTeamMergePolicy(_ context.Context, teamName string, policy *models.MergePolicy) error {
+	const op = "Memory.SetTeamMergePolicy"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	team, ok := r.teams[teamName]
+	if !ok {
+		return errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+
+	team.MergePolicy = *policy
+	return nil
+}
+
+func (r *MemoryRepository) SetTeamMaxOpenReviews(_ context.Context, teamName string, limit *int) error {
+	const op = "Memory.SetTeamMaxOpenReviews"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	team, ok := r.teams[teamName]
+	if !ok {
+		return errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+
+	team.MaxOpenReviews = copyInt(limit)
+	return nil
+}
+
+func (r *MemoryRepository) GetTeamFallbacks(_ context.Context, teamName string) ([]models.ReviewerFallback, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return slices.Clone(r.fallbacks[teamName]), nil
+}
+
+func (r *MemoryRepository) SetTeamFallbacks(_ context.Context, teamName string, fallbacks []models.ReviewerFallback) error {
+	const op = "Memory.SetTeamFallbacks"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.teams[teamName]; !ok {
+		return errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+
+	if len(fallbacks) == 0 {
+		delete(r.fallbacks, teamName)
+		return nil
+	}
+	r.fallbacks[teamName] = slices.Clone(fallbacks)
+	return nil
+}
+
+func (r *MemoryRepository) SetTeamCodeOwners(_ context.Context, codeOwners *models.CodeOwners) error {
+	const op = "Memory.SetTeamCodeOwners"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.teams[codeOwners.TeamName]; !ok {
+		return errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+
+	stored := *codeOwners
+	r.codeOwners[codeOwners.TeamName] = &stored
+	return nil
+}
+
+func (r *MemoryRepository) GetTeamCodeOwners(_ context.Context, teamName string) (*models.CodeOwners, error) {
+	const op = "Memory.GetTeamCodeOwners"
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	codeOwners, ok := r.codeOwners[teamName]
+	if !ok {
+		return nil, errors.WrapError(op, errors.ErrCodeOwnersNotFound)
+	}
+
+	result := *codeOwners
+	return &result, nil
+}
+
+func (r *MemoryRepository) SetTeamChat(_ context.Context, chat *models.TeamChat) error {
+	const op = "Memory.SetTeamChat"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.teams[chat.TeamName]; !ok {
+		return errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+
+	stored := *chat
+	r.chats[chat.TeamName] = &stored
+	return nil
+}
+
+func (r *MemoryRepository) GetTeamChat(_ context.Context, teamName string) (*models.TeamChat, error) {
+	const op = "Memory.GetTeamChat"
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	chat, ok := r.chats[teamName]
+	if !ok {
+		return nil, errors.WrapError(op, errors.ErrChatNotFound)
+	}
+
+	result := *chat
+	return &result, nil
+}
+
+func (r *MemoryRepository) TeamExists(_ context.Context, teamName string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.teams[teamName]
+	return ok, nil
+}
+
+func (r *MemoryRepository) GetPRsCntByTeam(_ context.Context, teamName string) (int, error) {
+	const op = "Memory.GetPRsCntByTeam"
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, ok := r.teams[teamName]; !ok {
+		return 0, errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+
+	return len(r.teamPRs(teamName)), nil
+}
+
+func (r *MemoryRepository) GetAvgReviewersPerPR(_ context.Context, teamName string) (float64, error) {
+	const op = "Memory.GetAvgReviewersPerPR"
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, ok := r.teams[teamName]; !ok {
+		return 0, errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+
+	return avgReviewers(r.teamPRs(teamName)), nil
+}
+
+// private methods
+
+func (r *MemoryRepository) isTeamMember(userID, teamName string) bool {
+	user, ok := r.users[userID]
+	return ok && user.TeamName == teamName
+}
+
+func (r *MemoryRepository) teamPRs(teamName string) []*models.PullRequest {
+	var prs []*models.PullRequest
+	for _, pr := range r.prs {
+		if r.isTeamMember(pr.AuthorID, teamName) {
+			prs = append(prs, pr)
+		}
+	}
+	return prs
+}
@@ -0,0 +1,262 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+func (r *MemoryRepository) GetUserByID(_ context.Context, id string) (*models.User, error) {
+	const op = "Memory.GetUserByID"
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, errors.WrapError(op, errors.ErrUserNotFound)
+	}
+
+	return copyUser(user), nil
+}
+
+func (r *MemoryRepository) GetUserByUsername(_ context.Context, username string) (*models.User, error) {
+	const op = "Memory.GetUserByUsername"
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users {
+		if user.Username == username {
+			return copyUser(user), nil
+		}
+	}
+
+	return nil, errors.WrapError(op, errors.ErrUserNotFound)
+}
+
+func (r *MemoryRepository) SetUserActive(_ context.Context, userID string, isActive bool) error {
+	const op = "Memory.SetUserActive"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[userID]
+	if !ok {
+		return errors.WrapError(op, errors.ErrUserNotFound)
+	}
+
+	user.IsActive = isActive
+	return nil
+}
+
+func (r *MemoryRepository) SetUserTeam(_ context.Context, userID, teamName string) error {
+	const op = "Memory.SetUserTeam"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.teams[teamName]; !ok {
+		return errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+
+	user, ok := r.users[userID]
+	if !ok {
+		return errors.WrapError(op, errors.ErrUserNotFound)
+	}
+
+	user.TeamName = teamName
+	return nil
+}
+
+func (r *MemoryRepository) SetUserMaxOpenReviews(_ context.Context, userID string, limit *int) error {
+	const op = "Memory.SetUserMaxOpenReviews"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[userID]
+	if !ok {
+		return errors.WrapError(op, errors.ErrUserNotFound)
+	}
+
+	user.MaxOpenReviews = copyInt(limit)
+	return nil
+}
+
+// DeactivateUsers применяет изменения, только если весь план собран без ошибок.
+func (r *MemoryRepository) DeactivateUsers(_ context.Context, userIDs []string, reassignments []models.Reassignment) error {
+	const op = "Memory.DeactivateUsers"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, userID := range userIDs {
+		if _, ok := r.users[userID]; !ok {
+			return errors.WrapError(op, errors.ErrUserNotFound)
+		}
+	}
+
+	now := time.Now()
+	reviews, err := r.stageReassignments(reassignments, userIDs, now)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	for _, userID := range userIDs {
+		r.users[userID].IsActive = false
+	}
+	r.applyReassignments(reviews, reassignments, models.ReasonDeactivated, now)
+
+	return nil
+}
+
+// AddAbsence: повторная запись с тем же началом снова ставит период в очередь планировщика.
+func (r *MemoryRepository) AddAbsence(_ context.Context, absence *models.Absence) error {
+	const op = "Memory.AddAbsence"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[absence.UserID]; !ok {
+		return errors.WrapError(op, errors.ErrUserNotFound)
+	}
+
+	for _, stored := range r.absences[absence.UserID] {
+		if stored.StartsAt.Equal(absence.StartsAt) {
+			stored.EndsAt = absence.EndsAt.UTC()
+			stored.ProcessedAt = nil
+			return nil
+		}
+	}
+
+	r.absences[absence.UserID] = append(r.absences[absence.UserID], &models.Absence{
+		StartsAt: absence.StartsAt.UTC(),
+		EndsAt:   absence.EndsAt.UTC(),
+		UserID:   absence.UserID,
+	})
+	return nil
+}
+
+func (r *MemoryRepository) GetUsersWithStartedAbsences(_ context.Context, at time.Time) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var userIDs []string
+	for userID, absences := range r.absences {
+		for _, absence := range absences {
+			if absence.ProcessedAt == nil && !absence.StartsAt.After(at) && absence.EndsAt.After(at) {
+				userIDs = append(userIDs, userID)
+				break
+			}
+		}
+	}
+	slices.Sort(userIDs)
+
+	return userIDs, nil
+}
+
+func (r *MemoryRepository) MarkAbsencesProcessed(_ context.Context, userID string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, absence := range r.absences[userID] {
+		if absence.ProcessedAt == nil && !absence.StartsAt.After(at) {
+			absence.ProcessedAt = copyTime(at.UTC())
+		}
+	}
+
+	return nil
+}
+
+func (r *MemoryRepository) GetPRsByReviewer(_ context.Context, userID string) ([]*models.ReviewAssignment, error) {
+	const op = "Memory.GetPRsByReviewer"
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, ok := r.users[userID]; !ok {
+		return nil, errors.WrapError(op, errors.ErrUserNotFound)
+	}
+
+	var prs []*models.PullRequest
+	for _, pr := range r.prs {
+		if _, found := reviewIndex(pr.Reviews, userID); found && !pr.IsDraft {
+			prs = append(prs, pr)
+		}
+	}
+	slices.SortFunc(prs, func(a, b *models.PullRequest) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	assignments := make([]*models.ReviewAssignment, 0, len(prs))
+	for _, pr := range prs {
+		i, _ := reviewIndex(pr.Reviews, userID)
+		review := pr.Reviews[i]
+		assignments = append(assignments, &models.ReviewAssignment{
+			AssignedAt:       review.AssignedAt,
+			ReviewedAt:       review.ReviewedAt,
+			State:            review.State,
+			PullRequestShort: pr.PullRequestShort,
+		})
+	}
+
+	return assignments, nil
+}
+
+func (r *MemoryRepository) GetPRsCntByAuthor(_ context.Context, userID string) (int, error) {
+	const op = "Memory.GetPRsCntByAuthor"
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, ok := r.users[userID]; !ok {
+		return 0, errors.WrapError(op, errors.ErrUserNotFound)
+	}
+
+	count := 0
+	for _, pr := range r.prs {
+		if pr.AuthorID == userID {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *MemoryRepository) UserExists(_ context.Context, userID string, username ...string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.userExists(userID, username...), nil
+}
+
+// private methods
+
+func (r *MemoryRepository) userExists(userID string, username ...string) bool {
+	if _, ok := r.users[userID]; ok {
+		return true
+	}
+	if len(username) == 0 {
+		return false
+	}
+	for _, user := range r.users {
+		if user.Username == username[0] {
+			return true
+		}
+	}
+	return false
+}
+
+func copyUser(user *models.User) *models.User {
+	result := *user
+	result.MaxOpenReviews = copyInt(user.MaxOpenReviews)
+	return &result
+}
@@ -0,0 +1,188 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+func (r *MemoryRepository) CreateWebhook(_ context.Context, webhook *models.Webhook) error {
+	const op = "Memory.CreateWebhook"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.webhooks[webhook.ID]; ok {
+		return errors.WrapError(op, fmt.Errorf("webhook %q already exists", webhook.ID))
+	}
+
+	stored := *webhook
+	stored.Events = slices.Clone(webhook.Events)
+	slices.Sort(stored.Events)
+	r.webhooks[webhook.ID] = &stored
+	return nil
+}
+
+func (r *MemoryRepository) GetWebhooks(_ context.Context) ([]models.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	webhooks := make([]models.Webhook, 0, len(r.webhooks))
+	for _, webhook := range r.webhooks {
+		result := *webhook
+		result.Events = slices.Clone(webhook.Events)
+		webhooks = append(webhooks, result)
+	}
+	slices.SortFunc(webhooks, func(a, b models.Webhook) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	if len(webhooks) == 0 {
+		return nil, nil
+	}
+	return webhooks, nil
+}
+
+func (r *MemoryRepository) DeleteWebhook(_ context.Context, id string) error {
+	const op = "Memory.DeleteWebhook"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.webhooks[id]; !ok {
+		return errors.WrapError(op, errors.ErrWebhookNotFound)
+	}
+
+	delete(r.webhooks, id)
+	r.deliveries = slices.DeleteFunc(r.deliveries, func(delivery *models.WebhookDelivery) bool {
+		return delivery.WebhookID == id
+	})
+	return nil
+}
+
+func (r *MemoryRepository) EnqueueDeliveries(_ context.Context, event string, payload []byte, at time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	webhooks := make([]*models.Webhook, 0, len(r.webhooks))
+	for _, webhook := range r.webhooks {
+		if len(webhook.Events) == 0 || slices.Contains(webhook.Events, event) {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	slices.SortFunc(webhooks, func(a, b *models.Webhook) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	for _, webhook := range webhooks {
+		r.lastDeliveryID++
+		r.deliveries = append(r.deliveries, &models.WebhookDelivery{
+			CreatedAt:     at,
+			NextAttemptAt: at,
+			WebhookID:     webhook.ID,
+			Event:         event,
+			Status:        models.DeliveryPending,
+			Payload:       slices.Clone(payload),
+			ID:            r.lastDeliveryID,
+		})
+	}
+
+	return len(webhooks), nil
+}
+
+func (r *MemoryRepository) ClaimDueDeliveries(
+	_ context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]models.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var due []*models.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.Status == models.DeliveryPending && !delivery.NextAttemptAt.After(now) {
+			due = append(due, delivery)
+		}
+	}
+	slices.SortStableFunc(due, func(a, b *models.WebhookDelivery) int {
+		return a.NextAttemptAt.Compare(b.NextAttemptAt)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	var deliveries []models.WebhookDelivery
+	for _, delivery := range due {
+		delivery.NextAttemptAt = now.Add(lease)
+
+		webhook := r.webhooks[delivery.WebhookID]
+		result := copyDelivery(delivery)
+		result.URL = webhook.URL
+		result.Secret = webhook.Secret
+		deliveries = append(deliveries, result)
+	}
+
+	return deliveries, nil
+}
+
+// SaveDeliveryAttempt ничего не записывает, если подписку за это время удалили.
+func (r *MemoryRepository) SaveDeliveryAttempt(_ context.Context, delivery *models.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, found := slices.BinarySearchFunc(r.deliveries, delivery.ID, func(stored *models.WebhookDelivery, id int64) int {
+		return cmp.Compare(stored.ID, id)
+	})
+	if !found {
+		return nil
+	}
+
+	stored := r.deliveries[i]
+	stored.Status = delivery.Status
+	stored.Attempts = delivery.Attempts
+	stored.ResponseStatus = delivery.ResponseStatus
+	stored.LastError = delivery.LastError
+	stored.NextAttemptAt = delivery.NextAttemptAt
+	stored.DeliveredAt = nil
+	if delivery.DeliveredAt != nil {
+		stored.DeliveredAt = copyTime(*delivery.DeliveredAt)
+	}
+	return nil
+}
+
+func (r *MemoryRepository) GetWebhookDeliveries(_ context.Context, webhookID string, limit int) ([]models.WebhookDelivery, error) {
+	const op = "Memory.GetWebhookDeliveries"
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, ok := r.webhooks[webhookID]; !ok {
+		return nil, errors.WrapError(op, errors.ErrWebhookNotFound)
+	}
+
+	var deliveries []models.WebhookDelivery
+	for i := len(r.deliveries) - 1; i >= 0 && len(deliveries) < limit; i-- {
+		if r.deliveries[i].WebhookID == webhookID {
+			deliveries = append(deliveries, copyDelivery(r.deliveries[i]))
+		}
+	}
+
+	return deliveries, nil
+}
+
+// private methods
+
+func copyDelivery(delivery *models.WebhookDelivery) models.WebhookDelivery {
+	result := *delivery
+	result.Payload = slices.Clone(delivery.Payload)
+	if delivery.DeliveredAt != nil {
+		result.DeliveredAt = copyTime(*delivery.DeliveredAt)
+	}
+	return result
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+func (r *PostgresRepository) LinkUserAccount(ctx context.Context, account *models.UserAccount) error {
+	const op = "Postgres.LinkUserAccount"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			return
+		}
+	}()
+
+	var exists int
+	err = tx.QueryRowContext(ctx, `SELECT 1 FROM users WHERE user_id = $1 FOR UPDATE`, account.UserID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return errors.WrapError(op, errors.ErrUserNotFound)
+	}
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM user_accounts WHERE provider = $1 AND user_id = $2`, account.Provider, account.UserID)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	query := `INSERT INTO user_accounts (provider, login, user_id) VALUES ($1, $2, $3)`
+	_, err = tx.ExecContext(ctx, query, account.Provider, account.Login, account.UserID)
+	if isUniqueViolation(err) {
+		return errors.WrapError(op, errors.ErrLoginTaken)
+	}
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) GetUserIDByLogin(ctx context.Context, provider, login string) (string, error) {
+	const op = "Postgres.GetUserIDByLogin"
+
+	var userID string
+	query := `SELECT user_id FROM user_accounts WHERE provider = $1 AND login = $2`
+	err := r.db.QueryRowContext(ctx, query, provider, login).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return "", errors.WrapError(op, errors.ErrAccountNotLinked)
+	}
+	if err != nil {
+		return "", errors.WrapError(op, err)
+	}
+	return userID, nil
+}
+
+func (r *PostgresRepository) GetUserLogin(ctx context.Context, provider, userID string) (string, error) {
+	const op = "Postgres.GetUserLogin"
+
+	var login string
+	query := `SELECT login FROM user_accounts WHERE provider = $1 AND user_id = $2`
+	err := r.db.QueryRowContext(ctx, query, provider, userID).Scan(&login)
+	if err == sql.ErrNoRows {
+		return "", errors.WrapError(op, errors.ErrAccountNotLinked)
+	}
+	if err != nil {
+		return "", errors.WrapError(op, err)
+	}
+	return login, nil
+}
@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+func (r *PostgresRepository) EnqueueChatMessage(ctx context.Context, message *models.ChatMessage) error {
+	const op = "Postgres.EnqueueChatMessage"
+
+	query := `
+		INSERT INTO chat_messages (team_name, event, url, payload, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		message.TeamName, message.Event, message.URL, string(message.Payload), message.NextAttemptAt, message.CreatedAt,
+	)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	return nil
+}
+
+// ClaimDueChatMessages берёт сообщения через SKIP LOCKED.
+func (r *PostgresRepository) ClaimDueChatMessages(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]models.ChatMessage, error) {
+	const op = "Postgres.ClaimDueChatMessages"
+
+	query := `
+		WITH due AS (
+			SELECT id
+			FROM chat_messages
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at, id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE chat_messages m
+		SET next_attempt_at = $2
+		FROM due
+		WHERE m.id = due.id
+		RETURNING m.id, m.team_name, m.event, m.url, m.payload, m.status, m.attempts, m.next_attempt_at, m.created_at
+	`
+	rows, err := r.db.QueryContext(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			return
+		}
+	}()
+
+	var messages []models.ChatMessage
+	for rows.Next() {
+		var message models.ChatMessage
+		err := rows.Scan(
+			&message.ID, &message.TeamName, &message.Event, &message.URL, &message.Payload, &message.Status,
+			&message.Attempts, &message.NextAttemptAt, &message.CreatedAt,
+		)
+		if err != nil {
+			return nil, errors.WrapError(op, err)
+		}
+		messages = append(messages, message)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	// RETURNING не сохраняет порядок постановки в очередь
+	slices.SortFunc(messages, func(a, b models.ChatMessage) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return messages, nil
+}
+
+func (r *PostgresRepository) SaveChatAttempt(ctx context.Context, message *models.ChatMessage) error {
+	const op = "Postgres.SaveChatAttempt"
+
+	query := `
+		UPDATE chat_messages
+		SET status = $2, attempts = $3, last_error = NULLIF($4, ''), next_attempt_at = $5, sent_at = $6
+		WHERE id = $1
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		message.ID, message.Status, message.Attempts, message.LastError, message.NextAttemptAt, message.SentAt,
+	)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	return nil
+}
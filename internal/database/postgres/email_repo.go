@@ -0,0 +1,183 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"slices"
+	"time"
+
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+func (r *PostgresRepository) SetUserEmail(ctx context.Context, settings *models.EmailSettings) error {
+	const op = "Postgres.SetUserEmail"
+
+	query := `UPDATE users SET email = NULLIF($2, ''), email_mode = NULLIF($3, '') WHERE user_id = $1`
+	result, err := r.db.ExecContext(ctx, query, settings.UserID, settings.Email, settings.Mode)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if rowsAffected == 0 {
+		return errors.WrapError(op, errors.ErrUserNotFound)
+	}
+
+	return nil
+}
+
+func (r *PostgresRepository) GetUserEmail(ctx context.Context, userID string) (*models.EmailSettings, error) {
+	const op = "Postgres.GetUserEmail"
+
+	var email, mode sql.NullString
+	query := `SELECT email, email_mode FROM users WHERE user_id = $1`
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&email, &mode)
+	if err == sql.ErrNoRows {
+		return nil, errors.WrapError(op, errors.ErrUserNotFound)
+	}
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	if !email.Valid {
+		return nil, errors.WrapError(op, errors.ErrEmailNotFound)
+	}
+
+	return &models.EmailSettings{UserID: userID, Email: email.String, Mode: mode.String}, nil
+}
+
+// ClaimDigestRecipients отмечает пользователей одним UPDATE: параллельный вызов
+// перепроверяет условие после блокировки строки и не выдаст их повторно.
+func (r *PostgresRepository) ClaimDigestRecipients(ctx context.Context, day time.Time) ([]models.EmailSettings, error) {
+	const op = "Postgres.ClaimDigestRecipients"
+
+	query := `
+		UPDATE users
+		SET digest_sent_at = $1
+		WHERE email IS NOT NULL AND email_mode = 'digest' AND is_active = TRUE
+			AND (digest_sent_at IS NULL OR digest_sent_at < $1)
+		RETURNING user_id, email, email_mode
+	`
+	rows, err := r.db.QueryContext(ctx, query, day)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			return
+		}
+	}()
+
+	var recipients []models.EmailSettings
+	for rows.Next() {
+		var recipient models.EmailSettings
+		if err := rows.Scan(&recipient.UserID, &recipient.Email, &recipient.Mode); err != nil {
+			return nil, errors.WrapError(op, err)
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	slices.SortFunc(recipients, func(a, b models.EmailSettings) int {
+		return cmp.Compare(a.UserID, b.UserID)
+	})
+	return recipients, nil
+}
+
+func (r *PostgresRepository) EnqueueEmail(ctx context.Context, message *models.EmailMessage) error {
+	const op = "Postgres.EnqueueEmail"
+
+	query := `
+		INSERT INTO email_messages (user_id, recipient, subject, message, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		message.UserID, message.Recipient, message.Subject, string(message.Message), message.NextAttemptAt, message.CreatedAt,
+	)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	return nil
+}
+
+// ClaimDueEmails берёт письма через SKIP LOCKED.
+func (r *PostgresRepository) ClaimDueEmails(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]models.EmailMessage, error) {
+	const op = "Postgres.ClaimDueEmails"
+
+	query := `
+		WITH due AS (
+			SELECT id
+			FROM email_messages
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at, id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE email_messages m
+		SET next_attempt_at = $2
+		FROM due
+		WHERE m.id = due.id
+		RETURNING m.id, m.user_id, m.recipient, m.subject, m.message, m.status, m.attempts, m.next_attempt_at, m.created_at
+	`
+	rows, err := r.db.QueryContext(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			return
+		}
+	}()
+
+	var messages []models.EmailMessage
+	for rows.Next() {
+		var message models.EmailMessage
+		err := rows.Scan(
+			&message.ID, &message.UserID, &message.Recipient, &message.Subject, &message.Message, &message.Status,
+			&message.Attempts, &message.NextAttemptAt, &message.CreatedAt,
+		)
+		if err != nil {
+			return nil, errors.WrapError(op, err)
+		}
+		messages = append(messages, message)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	// RETURNING не сохраняет порядок постановки в очередь
+	slices.SortFunc(messages, func(a, b models.EmailMessage) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return messages, nil
+}
+
+func (r *PostgresRepository) SaveEmailAttempt(ctx context.Context, message *models.EmailMessage) error {
+	const op = "Postgres.SaveEmailAttempt"
+
+	query := `
+		UPDATE email_messages
+		SET status = $2, attempts = $3, last_error = NULLIF($4, ''), next_attempt_at = $5, sent_at = $6
+		WHERE id = $1
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		message.ID, message.Status, message.Attempts, message.LastError, message.NextAttemptAt, message.SentAt,
+	)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+func (r *PostgresRepository) CreateReviewerPool(ctx context.Context, pool *models.ReviewerPool) error {
+	const op = "Postgres.CreateReviewerPool"
+
+	exists, err := r.PoolExists(ctx, pool.Name)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if exists {
+		return errors.WrapError(op, errors.ErrPoolExists)
+	}
+	if err := r.checkPoolMembers(ctx, pool); err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			return
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO reviewer_pools (name) VALUES ($1)`, pool.Name)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	if err := r.insertPoolMembers(ctx, tx, pool); err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) GetReviewerPool(ctx context.Context, name string) (*models.ReviewerPool, error) {
+	const op = "Postgres.GetReviewerPool"
+
+	exists, err := r.PoolExists(ctx, name)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	if !exists {
+		return nil, errors.WrapError(op, errors.ErrPoolNotFound)
+	}
+
+	query := `SELECT user_id FROM reviewer_pool_members WHERE pool_name = $1 ORDER BY user_id`
+	rows, err := r.db.QueryContext(ctx, query, name)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			return
+		}
+	}()
+
+	pool := &models.ReviewerPool{Name: name}
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, errors.WrapError(op, err)
+		}
+		pool.Members = append(pool.Members, userID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	return pool, nil
+}
+
+func (r *PostgresRepository) SetReviewerPoolMembers(ctx context.Context, pool *models.ReviewerPool) error {
+	const op = "Postgres.SetReviewerPoolMembers"
+
+	exists, err := r.PoolExists(ctx, pool.Name)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if !exists {
+		return errors.WrapError(op, errors.ErrPoolNotFound)
+	}
+	if err := r.checkPoolMembers(ctx, pool); err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			return
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM reviewer_pool_members WHERE pool_name = $1`, pool.Name)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	if err := r.insertPoolMembers(ctx, tx, pool); err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) PoolExists(ctx context.Context, name string) (bool, error) {
+	const op = "Postgres.PoolExists"
+
+	query := `SELECT 1 FROM reviewer_pools WHERE name = $1`
+	row := r.db.QueryRowContext(ctx, query, name)
+
+	var exists int
+	err := row.Scan(&exists)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.WrapError(op, err)
+	}
+
+	return true, nil
+}
+
+// private methods
+func (r *PostgresRepository) checkPoolMembers(ctx context.Context, pool *models.ReviewerPool) error {
+	const op = "Postgres.checkPoolMembers"
+
+	for _, userID := range pool.Members {
+		exists, err := r.UserExists(ctx, userID)
+		if err != nil {
+			return errors.WrapError(op, err)
+		}
+		if !exists {
+			return errors.WrapError(op, errors.ErrUserNotFound)
+		}
+	}
+	return nil
+}
+
+func (r *PostgresRepository) insertPoolMembers(ctx context.Context, tx *sql.Tx, pool *models.ReviewerPool) error {
+	const op = "Postgres.insertPoolMembers"
+
+	query := `INSERT INTO reviewer_pool_members (pool_name, user_id) VALUES ($1, $2)`
+	for _, userID := range pool.Members {
+		if _, err := tx.ExecContext(ctx, query, pool.Name, userID); err != nil {
+			return errors.WrapError(op, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package postgres_test
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+
+	"pr-review/internal/config"
+	"pr-review/internal/database"
+	"pr-review/internal/database/postgres"
+	"pr-review/internal/database/repotest"
+)
+
+// TestRepository запускается, только если задан TEST_DB_NAME: перед каждой проверкой
+// схема public этой базы удаляется целиком, поэтому рабочую базу указывать нельзя.
+// Остальные параметры подключения берутся из DB_* со значениями по умолчанию docker-compose.
+// В CI без TEST_DB_NAME тест падает, чтобы проверки Postgres не пропускались молча.
+func TestRepository(t *testing.T) {
+	name := os.Getenv("TEST_DB_NAME")
+	if name == "" {
+		if os.Getenv("CI") != "" {
+			t.Fatal("TEST_DB_NAME is not set in CI, Postgres repository checks would be skipped")
+		}
+		t.Skip("TEST_DB_NAME is not set, Postgres repository checks skipped (run make test-postgres)")
+	}
+
+	cfg := &config.DatabaseConfig{
+		MigrationsPath: "../../../migrations",
+		Host:           env("DB_HOST", "localhost"),
+		Port:           env("DB_PORT", "5432"),
+		User:           env("DB_USER", "pr_review_user"),
+		Password:       env("DB_PASSWORD", "pr_review_password"),
+		Database:       name,
+		SSLMode:        env("DB_SSL_MODE", "disable"),
+		MaxOpenConns:   25,
+		MaxIdleConns:   5,
+	}
+
+	repotest.Run(t, func(t *testing.T) database.Repository {
+		resetSchema(t, cfg)
+
+		repo, err := postgres.New(t.Context(), cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = repo.Close() })
+		return repo
+	})
+}
+
+func resetSchema(t *testing.T, cfg *config.DatabaseConfig) {
+	t.Helper()
+
+	db, err := sql.Open("postgres", fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Database, cfg.SSLMode,
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = db.Close() }()
+
+	if _, err := db.ExecContext(t.Context(), `DROP SCHEMA public CASCADE; CREATE SCHEMA public`); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func env(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
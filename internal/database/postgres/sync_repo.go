@@ -0,0 +1,182 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"slices"
+	"time"
+
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+func (r *PostgresRepository) EnqueueSyncTasks(ctx context.Context, tasks []models.SyncTask) error {
+	const op = "Postgres.EnqueueSyncTasks"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			return
+		}
+	}()
+
+	query := `
+		INSERT INTO sync_tasks (pr_id, provider, action, user_id, body, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), $6, $7)
+	`
+	for _, task := range tasks {
+		_, err = tx.ExecContext(ctx, query,
+			task.PullRequestID, task.Provider, task.Action, task.UserID, task.Body, task.NextAttemptAt, task.CreatedAt,
+		)
+		if err != nil {
+			return errors.WrapError(op, err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	return nil
+}
+
+// ClaimDueSyncTasks выдаёт задачу PR, только если перед ней нет более ранних ожидающих задач того же PR.
+func (r *PostgresRepository) ClaimDueSyncTasks(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]models.SyncTask, error) {
+	const op = "Postgres.ClaimDueSyncTasks"
+
+	query := `
+		WITH due AS (
+			SELECT s.id
+			FROM sync_tasks s
+			WHERE s.status = 'pending' AND s.next_attempt_at <= $1
+				AND NOT EXISTS (
+					SELECT 1 FROM sync_tasks o
+					WHERE o.pr_id = s.pr_id AND o.status = 'pending' AND o.id < s.id
+				)
+			ORDER BY s.next_attempt_at, s.id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE sync_tasks t
+		SET next_attempt_at = $2
+		FROM due
+		WHERE t.id = due.id
+		RETURNING ` + syncTaskColumns
+	rows, err := r.db.QueryContext(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	tasks, err := scanSyncTasks(rows)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	// RETURNING не сохраняет порядок выборки
+	slices.SortFunc(tasks, func(a, b models.SyncTask) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return tasks, nil
+}
+
+func (r *PostgresRepository) SaveSyncAttempt(ctx context.Context, task *models.SyncTask) error {
+	const op = "Postgres.SaveSyncAttempt"
+
+	query := `
+		UPDATE sync_tasks
+		SET status = $2, attempts = $3, last_error = NULLIF($4, ''), next_attempt_at = $5, completed_at = $6
+		WHERE id = $1
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		task.ID, task.Status, task.Attempts, task.LastError, task.NextAttemptAt, task.CompletedAt,
+	)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) GetSyncTasks(ctx context.Context, status string, limit int) ([]models.SyncTask, error) {
+	const op = "Postgres.GetSyncTasks"
+
+	query := `
+		SELECT ` + syncTaskColumns + `
+		FROM sync_tasks
+		WHERE $1::text = '' OR status = $1::text
+		ORDER BY id DESC
+		LIMIT $2
+	`
+	rows, err := r.db.QueryContext(ctx, query, status, limit)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	tasks, err := scanSyncTasks(rows)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	return tasks, nil
+}
+
+func (r *PostgresRepository) RetrySyncTask(ctx context.Context, id int64, at time.Time) error {
+	const op = "Postgres.RetrySyncTask"
+
+	query := `
+		UPDATE sync_tasks
+		SET status = 'pending', attempts = 0, last_error = NULL, next_attempt_at = $2
+		WHERE id = $1 AND status = 'failed'
+	`
+	result, err := r.db.ExecContext(ctx, query, id, at)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if rowsAffected == 0 {
+		return errors.WrapError(op, errors.ErrSyncTaskNotFound)
+	}
+	return nil
+}
+
+// private methods
+
+const syncTaskColumns = `id, pr_id, provider, action, COALESCE(user_id, ''), COALESCE(body, ''), status, attempts,
+	COALESCE(last_error, ''), next_attempt_at, completed_at, created_at`
+
+func scanSyncTasks(rows *sql.Rows) ([]models.SyncTask, error) {
+	defer func() {
+		if err := rows.Close(); err != nil {
+			return
+		}
+	}()
+
+	var tasks []models.SyncTask
+	for rows.Next() {
+		var task models.SyncTask
+		err := rows.Scan(
+			&task.ID, &task.PullRequestID, &task.Provider, &task.Action, &task.UserID, &task.Body, &task.Status,
+			&task.Attempts, &task.LastError, &task.NextAttemptAt, &task.CompletedAt, &task.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
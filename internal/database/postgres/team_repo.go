@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"pr-review/internal/errors"
 	"pr-review/internal/models"
@@ -39,8 +40,16 @@ func (r *PostgresRepository) CreateTeam(ctx context.Context, team *models.Team)
 		}
 	}()
 
-	query := `INSERT INTO teams (name) VALUES ($1)`
-	_, err = tx.ExecContext(ctx, query, team.Name)
+	policy := team.MergePolicy
+	query := `
+		INSERT INTO teams (
+			name, required_reviewers, required_approvals,
+			block_on_changes_requested, forbid_self_approval, require_owner_approval, max_open_reviews
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err = tx.ExecContext(ctx, query, team.Name, team.RequiredReviewers, policy.RequiredApprovals,
+		policy.BlockOnChangesRequested, policy.ForbidSelfApproval, policy.RequireOwnerApproval, team.MaxOpenReviews)
 	if err != nil {
 		return errors.WrapError(op, err)
 	}
@@ -63,12 +72,38 @@ func (r *PostgresRepository) CreateTeam(ctx context.Context, team *models.Team)
 func (r *PostgresRepository) GetTeamByName(ctx context.Context, name string) (*models.Team, error) {
 	const op = "Postgres.GetTeamByName"
 
-	exists, err := r.TeamExists(ctx, name)
+	team := &models.Team{Name: name}
+
+	teamQuery := `
+		SELECT required_reviewers, required_approvals,
+			block_on_changes_requested, forbid_self_approval, require_owner_approval, archived_at,
+			max_open_reviews
+		FROM teams
+		WHERE name = $1
+	`
+	var archivedAt sql.NullTime
+	var maxOpenReviews sql.NullInt64
+	err := r.db.QueryRowContext(ctx, teamQuery, name).Scan(
+		&team.RequiredReviewers,
+		&team.MergePolicy.RequiredApprovals,
+		&team.MergePolicy.BlockOnChangesRequested,
+		&team.MergePolicy.ForbidSelfApproval,
+		&team.MergePolicy.RequireOwnerApproval,
+		&archivedAt,
+		&maxOpenReviews,
+	)
+	if err == sql.ErrNoRows {
+		return nil, errors.WrapError(op, errors.ErrTeamNotFound)
+	}
 	if err != nil {
 		return nil, errors.WrapError(op, err)
 	}
-	if !exists {
-		return nil, errors.WrapError(op, errors.ErrTeamNotFound)
+	if archivedAt.Valid {
+		team.ArchivedAt = &archivedAt.Time
+	}
+	if maxOpenReviews.Valid {
+		limit := int(maxOpenReviews.Int64)
+		team.MaxOpenReviews = &limit
 	}
 
 	query := `
@@ -101,14 +136,550 @@ func (r *PostgresRepository) GetTeamByName(ctx context.Context, name string) (*m
 		return nil, errors.WrapError(op, err)
 	}
 
-	team := &models.Team{
-		Name:    name,
-		Members: members,
+	team.Members = members
+
+	team.Fallbacks, err = r.GetTeamFallbacks(ctx, name)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
 	}
 
 	return team, nil
 }
 
+// AddTeamMember возвращает ранее исключённого из команды (team_name = NULL) с новыми данными.
+func (r *PostgresRepository) AddTeamMember(ctx context.Context, teamName string, member *models.TeamMember) error {
+	const op = "Postgres.AddTeamMember"
+
+	exists, err := r.TeamExists(ctx, teamName)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if !exists {
+		return errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+
+	var currentTeam sql.NullString
+	query := `INSERT INTO users (username, is_active, team_name, user_id) VALUES ($1, $2, $3, $4)`
+	err = r.db.QueryRowContext(ctx, `SELECT team_name FROM users WHERE user_id = $1`, member.UserID).Scan(&currentTeam)
+	switch {
+	case err == sql.ErrNoRows:
+	case err != nil:
+		return errors.WrapError(op, err)
+	case currentTeam.Valid:
+		return errors.WrapError(op, errors.ErrUserExists)
+	default:
+		query = `UPDATE users SET username = $1, is_active = $2, team_name = $3 WHERE user_id = $4`
+	}
+
+	var taken int
+	err = r.db.QueryRowContext(ctx, `SELECT 1 FROM users WHERE username = $1 AND user_id <> $2`,
+		member.Username, member.UserID).Scan(&taken)
+	if err == nil {
+		return errors.WrapError(op, errors.ErrUserExists)
+	}
+	if err != sql.ErrNoRows {
+		return errors.WrapError(op, err)
+	}
+
+	_, err = r.db.ExecContext(ctx, query, member.Username, member.IsActive, teamName, member.UserID)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	return nil
+}
+
+// RemoveTeamMember оставляет строку пользователя, чтобы не потерять историю его PR и ревью.
+func (r *PostgresRepository) RemoveTeamMember(ctx context.Context, teamName, userID string, reassignments []models.Reassignment) error {
+	const op = "Postgres.RemoveTeamMember"
+
+	exists, err := r.TeamExists(ctx, teamName)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if !exists {
+		return errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+
+	exists, err = r.UserExists(ctx, userID)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if !exists {
+		return errors.WrapError(op, errors.ErrUserNotFound)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			return
+		}
+	}()
+
+	if err := lockReassignments(ctx, tx, reassignments); err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	query := `UPDATE users SET team_name = NULL WHERE user_id = $1 AND team_name = $2`
+	result, err := tx.ExecContext(ctx, query, userID, teamName)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if rowsAffected == 0 {
+		return errors.WrapError(op, errors.ErrNotMember)
+	}
+
+	if err := applyReassignments(ctx, tx, reassignments, models.ReasonLeftTeam); err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	return nil
+}
+
+// RenameTeam: внешние ключи не каскадируют обновление, поэтому создаётся строка с новым именем,
+// на неё переводятся участники и CODEOWNERS, а старая строка удаляется.
+func (r *PostgresRepository) RenameTeam(ctx context.Context, oldName, newName string) error {
+	const op = "Postgres.RenameTeam"
+
+	exists, err := r.TeamExists(ctx, oldName)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if !exists {
+		return errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+
+	exists, err = r.TeamExists(ctx, newName)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if exists {
+		return errors.WrapError(op, errors.ErrTeamExists)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			return
+		}
+	}()
+
+	queries := []string{
+		`INSERT INTO teams (
+			name, required_reviewers, required_approvals,
+			block_on_changes_requested, forbid_self_approval, require_owner_approval, archived_at,
+			max_open_reviews
+		)
+		SELECT $1, required_reviewers, required_approvals,
+			block_on_changes_requested, forbid_self_approval, require_owner_approval, archived_at,
+			max_open_reviews
+		FROM teams
+		WHERE name = $2`,
+		`UPDATE users SET team_name = $1 WHERE team_name = $2`,
+		`UPDATE team_codeowners SET team_name = $1 WHERE team_name = $2`,
+		`UPDATE team_chats SET team_name = $1 WHERE team_name = $2`,
+		`UPDATE team_fallbacks SET team_name = $1 WHERE team_name = $2`,
+		`UPDATE team_fallbacks SET source_name = $1 WHERE kind = 'team' AND source_name = $2`,
+	}
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query, newName, oldName); err != nil {
+			return errors.WrapError(op, err)
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM teams WHERE name = $1`, oldName)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) GetOpenPRIDsByTeam(ctx context.Context, teamName string) ([]string, error) {
+	const op = "Postgres.GetOpenPRIDsByTeam"
+
+	query := `
+		SELECT pr.id
+		FROM pull_requests pr
+		JOIN users u ON u.user_id = pr.author_id
+		WHERE u.team_name = $1 AND pr.status = 'OPEN'
+		ORDER BY pr.id
+	`
+	rows, err := r.db.QueryContext(ctx, query, teamName)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			return
+		}
+	}()
+
+	var prIDs []string
+	for rows.Next() {
+		var prID string
+		if err := rows.Scan(&prID); err != nil {
+			return nil, errors.WrapError(op, err)
+		}
+		prIDs = append(prIDs, prID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	return prIDs, nil
+}
+
+// TeamHasHistory: такие PR и ревью потерялись бы при каскадном удалении.
+func (r *PostgresRepository) TeamHasHistory(ctx context.Context, teamName string) (bool, error) {
+	const op = "Postgres.TeamHasHistory"
+
+	query := `
+		SELECT 1
+		FROM users u
+		WHERE u.team_name = $1 AND (
+			EXISTS (SELECT 1 FROM pull_requests pr WHERE pr.author_id = u.user_id)
+			OR EXISTS (SELECT 1 FROM pr_reviewers prr WHERE prr.user_id = u.user_id)
+		)
+		LIMIT 1
+	`
+	var found int
+	err := r.db.QueryRowContext(ctx, query, teamName).Scan(&found)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.WrapError(op, err)
+	}
+
+	return true, nil
+}
+
+func (r *PostgresRepository) ArchiveTeam(ctx context.Context, teamName string, archivedAt time.Time, closePRs []string) error {
+	const op = "Postgres.ArchiveTeam"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			return
+		}
+	}()
+
+	result, err := tx.ExecContext(ctx, `UPDATE teams SET archived_at = $1 WHERE name = $2`, archivedAt, teamName)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if rowsAffected == 0 {
+		return errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE users SET is_active = FALSE WHERE team_name = $1`, teamName)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	for _, prID := range closePRs {
+		if _, err := closePR(ctx, tx, prID, archivedAt, models.ReasonTeamArchived); err != nil {
+			return errors.WrapError(op, err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	return nil
+}
+
+// DeleteTeam вызывается только для команды без истории PR, см. TeamHasHistory.
+func (r *PostgresRepository) DeleteTeam(ctx context.Context, teamName string) error {
+	const op = "Postgres.DeleteTeam"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			return
+		}
+	}()
+
+	queries := []string{
+		`DELETE FROM team_codeowners WHERE team_name = $1`,
+		`DELETE FROM team_chats WHERE team_name = $1`,
+		`DELETE FROM team_fallbacks WHERE team_name = $1`,
+		`DELETE FROM team_fallbacks WHERE kind = 'team' AND source_name = $1`,
+		`DELETE FROM reviewer_pool_members WHERE user_id IN (SELECT user_id FROM users WHERE team_name = $1)`,
+		`DELETE FROM users WHERE team_name = $1`,
+	}
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query, teamName); err != nil {
+			return errors.WrapError(op, err)
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM teams WHERE name = $1`, teamName)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if rowsAffected == 0 {
+		return errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) SetTeamMergePolicy(ctx context.Context, teamName string, policy *models.MergePolicy) error {
+	const op = "Postgres.SetTeamMergePolicy"
+
+	query := `
+		UPDATE teams
+		SET required_approvals = $1, block_on_changes_requested = $2,
+			forbid_self_approval = $3, require_owner_approval = $4
+		WHERE name = $5
+	`
+	result, err := r.db.ExecContext(ctx, query, policy.RequiredApprovals, policy.BlockOnChangesRequested,
+		policy.ForbidSelfApproval, policy.RequireOwnerApproval, teamName)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if rowsAffected == 0 {
+		return errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+
+	return nil
+}
+
+func (r *PostgresRepository) SetTeamMaxOpenReviews(ctx context.Context, teamName string, limit *int) error {
+	const op = "Postgres.SetTeamMaxOpenReviews"
+
+	query := `UPDATE teams SET max_open_reviews = $1 WHERE name = $2`
+	result, err := r.db.ExecContext(ctx, query, limit, teamName)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if rowsAffected == 0 {
+		return errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+
+	return nil
+}
+
+func (r *PostgresRepository) GetTeamFallbacks(ctx context.Context, teamName string) ([]models.ReviewerFallback, error) {
+	const op = "Postgres.GetTeamFallbacks"
+
+	query := `SELECT kind, source_name FROM team_fallbacks WHERE team_name = $1 ORDER BY position`
+	rows, err := r.db.QueryContext(ctx, query, teamName)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			return
+		}
+	}()
+
+	var fallbacks []models.ReviewerFallback
+	for rows.Next() {
+		var fallback models.ReviewerFallback
+		if err := rows.Scan(&fallback.Kind, &fallback.Name); err != nil {
+			return nil, errors.WrapError(op, err)
+		}
+		fallbacks = append(fallbacks, fallback)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	return fallbacks, nil
+}
+
+func (r *PostgresRepository) SetTeamFallbacks(ctx context.Context, teamName string, fallbacks []models.ReviewerFallback) error {
+	const op = "Postgres.SetTeamFallbacks"
+
+	exists, err := r.TeamExists(ctx, teamName)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if !exists {
+		return errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			return
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM team_fallbacks WHERE team_name = $1`, teamName)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	query := `INSERT INTO team_fallbacks (team_name, position, kind, source_name) VALUES ($1, $2, $3, $4)`
+	for i, fallback := range fallbacks {
+		if _, err := tx.ExecContext(ctx, query, teamName, i, fallback.Kind, fallback.Name); err != nil {
+			return errors.WrapError(op, err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) SetTeamCodeOwners(ctx context.Context, codeOwners *models.CodeOwners) error {
+	const op = "Postgres.SetTeamCodeOwners"
+
+	exists, err := r.TeamExists(ctx, codeOwners.TeamName)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if !exists {
+		return errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+
+	query := `
+		INSERT INTO team_codeowners (team_name, content, mode, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (team_name) DO UPDATE
+		SET content = excluded.content, mode = excluded.mode, updated_at = excluded.updated_at
+	`
+	_, err = r.db.ExecContext(ctx, query, codeOwners.TeamName, codeOwners.Content, codeOwners.Mode, codeOwners.UpdatedAt)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	return nil
+}
+
+func (r *PostgresRepository) GetTeamCodeOwners(ctx context.Context, teamName string) (*models.CodeOwners, error) {
+	const op = "Postgres.GetTeamCodeOwners"
+
+	query := `SELECT team_name, content, mode, updated_at FROM team_codeowners WHERE team_name = $1`
+	row := r.db.QueryRowContext(ctx, query, teamName)
+
+	var codeOwners models.CodeOwners
+	err := row.Scan(&codeOwners.TeamName, &codeOwners.Content, &codeOwners.Mode, &codeOwners.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, errors.WrapError(op, errors.ErrCodeOwnersNotFound)
+	}
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	return &codeOwners, nil
+}
+
+func (r *PostgresRepository) SetTeamChat(ctx context.Context, chat *models.TeamChat) error {
+	const op = "Postgres.SetTeamChat"
+
+	exists, err := r.TeamExists(ctx, chat.TeamName)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if !exists {
+		return errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+
+	query := `
+		INSERT INTO team_chats (team_name, url, format, assigned_template, reassigned_template, merged_template, updated_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), $7)
+		ON CONFLICT (team_name) DO UPDATE
+		SET url = excluded.url, format = excluded.format, assigned_template = excluded.assigned_template,
+			reassigned_template = excluded.reassigned_template, merged_template = excluded.merged_template,
+			updated_at = excluded.updated_at
+	`
+	_, err = r.db.ExecContext(ctx, query,
+		chat.TeamName, chat.URL, chat.Format,
+		chat.Templates.ReviewerAssigned, chat.Templates.ReviewerReassigned, chat.Templates.PRMerged, chat.UpdatedAt,
+	)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	return nil
+}
+
+func (r *PostgresRepository) GetTeamChat(ctx context.Context, teamName string) (*models.TeamChat, error) {
+	const op = "Postgres.GetTeamChat"
+
+	query := `
+		SELECT team_name, url, format, COALESCE(assigned_template, ''), COALESCE(reassigned_template, ''),
+			COALESCE(merged_template, ''), updated_at
+		FROM team_chats
+		WHERE team_name = $1
+	`
+	row := r.db.QueryRowContext(ctx, query, teamName)
+
+	var chat models.TeamChat
+	err := row.Scan(
+		&chat.TeamName, &chat.URL, &chat.Format, &chat.Templates.ReviewerAssigned,
+		&chat.Templates.ReviewerReassigned, &chat.Templates.PRMerged, &chat.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, errors.WrapError(op, errors.ErrChatNotFound)
+	}
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	return &chat, nil
+}
+
 func (r *PostgresRepository) TeamExists(ctx context.Context, teamName string) (bool, error) {
 	const op = "Postgres.TeamExists"
 
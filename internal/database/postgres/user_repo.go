@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"pr-review/internal/errors"
 	"pr-review/internal/models"
@@ -19,17 +20,22 @@ func (r *PostgresRepository) GetUserByID(ctx context.Context, id string) (*model
 		return nil, errors.WrapError(op, errors.ErrUserNotFound)
 	}
 
-	query := `SELECT user_id, username, is_active, team_name FROM users WHERE user_id = $1`
+	query := `SELECT user_id, username, is_active, COALESCE(team_name, ''), max_open_reviews FROM users WHERE user_id = $1`
 	row := r.db.QueryRowContext(ctx, query, id)
 
 	var user models.User
-	err = row.Scan(&user.UserID, &user.Username, &user.IsActive, &user.TeamName)
+	var maxOpenReviews sql.NullInt64
+	err = row.Scan(&user.UserID, &user.Username, &user.IsActive, &user.TeamName, &maxOpenReviews)
 	if err == sql.ErrNoRows {
 		return nil, errors.WrapError(op, errors.ErrUserNotFound)
 	}
 	if err != nil {
 		return nil, errors.WrapError(op, err)
 	}
+	if maxOpenReviews.Valid {
+		limit := int(maxOpenReviews.Int64)
+		user.MaxOpenReviews = &limit
+	}
 
 	return &user, nil
 }
@@ -37,17 +43,22 @@ func (r *PostgresRepository) GetUserByID(ctx context.Context, id string) (*model
 func (r *PostgresRepository) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
 	const op = "Postgres.GetUserByUsername"
 
-	query := `SELECT user_id, username, is_active, team_name FROM users WHERE username = $1`
+	query := `SELECT user_id, username, is_active, COALESCE(team_name, ''), max_open_reviews FROM users WHERE username = $1`
 	row := r.db.QueryRowContext(ctx, query, username)
 
 	var user models.User
-	err := row.Scan(&user.UserID, &user.Username, &user.IsActive, &user.TeamName)
+	var maxOpenReviews sql.NullInt64
+	err := row.Scan(&user.UserID, &user.Username, &user.IsActive, &user.TeamName, &maxOpenReviews)
 	if err == sql.ErrNoRows {
 		return nil, errors.WrapError(op, errors.ErrUserNotFound)
 	}
 	if err != nil {
 		return nil, errors.WrapError(op, err)
 	}
+	if maxOpenReviews.Valid {
+		limit := int(maxOpenReviews.Int64)
+		user.MaxOpenReviews = &limit
+	}
 
 	return &user, nil
 }
@@ -80,7 +91,176 @@ func (r *PostgresRepository) SetUserActive(ctx context.Context, userID string, i
 	return nil
 }
 
-func (r *PostgresRepository) GetPRsByReviewer(ctx context.Context, userID string) ([]*models.PullRequestShort, error) {
+func (r *PostgresRepository) SetUserTeam(ctx context.Context, userID, teamName string) error {
+	const op = "Postgres.SetUserTeam"
+
+	exists, err := r.TeamExists(ctx, teamName)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if !exists {
+		return errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+
+	query := `UPDATE users SET team_name = $1 WHERE user_id = $2`
+	result, err := r.db.ExecContext(ctx, query, teamName, userID)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if rowsAffected == 0 {
+		return errors.WrapError(op, errors.ErrUserNotFound)
+	}
+
+	return nil
+}
+
+func (r *PostgresRepository) SetUserMaxOpenReviews(ctx context.Context, userID string, limit *int) error {
+	const op = "Postgres.SetUserMaxOpenReviews"
+
+	query := `UPDATE users SET max_open_reviews = $1 WHERE user_id = $2`
+	result, err := r.db.ExecContext(ctx, query, limit, userID)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if rowsAffected == 0 {
+		return errors.WrapError(op, errors.ErrUserNotFound)
+	}
+
+	return nil
+}
+
+func (r *PostgresRepository) DeactivateUsers(ctx context.Context, userIDs []string, reassignments []models.Reassignment) error {
+	const op = "Postgres.DeactivateUsers"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			return
+		}
+	}()
+
+	if err := lockReassignments(ctx, tx, reassignments); err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	deactivateQuery := `UPDATE users SET is_active = FALSE WHERE user_id = $1`
+	for _, userID := range userIDs {
+		result, err := tx.ExecContext(ctx, deactivateQuery, userID)
+		if err != nil {
+			return errors.WrapError(op, err)
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return errors.WrapError(op, err)
+		}
+		if rowsAffected == 0 {
+			return errors.WrapError(op, errors.ErrUserNotFound)
+		}
+	}
+
+	// деактивированные этой же транзакцией тоже недоступны
+	if err := applyReassignments(ctx, tx, reassignments, models.ReasonDeactivated); err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	return nil
+}
+
+// AddAbsence: повторная запись с тем же началом снова ставит период в очередь планировщика.
+func (r *PostgresRepository) AddAbsence(ctx context.Context, absence *models.Absence) error {
+	const op = "Postgres.AddAbsence"
+
+	exists, err := r.UserExists(ctx, absence.UserID)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if !exists {
+		return errors.WrapError(op, errors.ErrUserNotFound)
+	}
+
+	query := `
+		INSERT INTO user_absences (user_id, starts_at, ends_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, starts_at) DO UPDATE
+		SET ends_at = excluded.ends_at, processed_at = NULL
+	`
+	_, err = r.db.ExecContext(ctx, query, absence.UserID, absence.StartsAt, absence.EndsAt)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	return nil
+}
+
+func (r *PostgresRepository) GetUsersWithStartedAbsences(ctx context.Context, at time.Time) ([]string, error) {
+	const op = "Postgres.GetUsersWithStartedAbsences"
+
+	query := `
+		SELECT DISTINCT user_id
+		FROM user_absences
+		WHERE processed_at IS NULL AND starts_at <= $1 AND ends_at > $1
+		ORDER BY user_id
+	`
+	rows, err := r.db.QueryContext(ctx, query, at)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			return
+		}
+	}()
+
+	var userIDs []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, errors.WrapError(op, err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	return userIDs, nil
+}
+
+func (r *PostgresRepository) MarkAbsencesProcessed(ctx context.Context, userID string, at time.Time) error {
+	const op = "Postgres.MarkAbsencesProcessed"
+
+	query := `
+		UPDATE user_absences
+		SET processed_at = $1
+		WHERE user_id = $2 AND processed_at IS NULL AND starts_at <= $1
+	`
+	_, err := r.db.ExecContext(ctx, query, at, userID)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	return nil
+}
+
+func (r *PostgresRepository) GetPRsByReviewer(ctx context.Context, userID string) ([]*models.ReviewAssignment, error) {
 	const op = "Postgres.GetPRsByReviewer"
 
 	exists, err := r.UserExists(ctx, userID)
@@ -92,11 +272,11 @@ func (r *PostgresRepository) GetPRsByReviewer(ctx context.Context, userID string
 	}
 
 	query := `
-		SELECT pr.id, pr.name, pr.author_id, pr.status, pr.created_at
+		SELECT pr.id, pr.name, pr.author_id, pr.status, prr.state, prr.assigned_at, prr.reviewed_at
 		FROM pull_requests pr
 		JOIN pr_reviewers prr ON pr.id = prr.pr_id
-		WHERE prr.user_id = $1
-		ORDER BY pr.created_at DESC
+		WHERE prr.user_id = $1 AND pr.is_draft = FALSE
+		ORDER BY pr.created_at DESC, pr.id
 	`
 
 	rows, err := r.db.QueryContext(ctx, query, userID)
@@ -109,14 +289,20 @@ func (r *PostgresRepository) GetPRsByReviewer(ctx context.Context, userID string
 		}
 	}()
 
-	var prs []*models.PullRequestShort
+	var prs []*models.ReviewAssignment
 	for rows.Next() {
-		var pr models.PullRequestShort
-		var createdAt string
-		err := rows.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &createdAt)
+		var pr models.ReviewAssignment
+		var assignedAt, reviewedAt sql.NullTime
+		err := rows.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.State, &assignedAt, &reviewedAt)
 		if err != nil {
 			return nil, errors.WrapError(op, err)
 		}
+		if assignedAt.Valid {
+			pr.AssignedAt = &assignedAt.Time
+		}
+		if reviewedAt.Valid {
+			pr.ReviewedAt = &reviewedAt.Time
+		}
 		prs = append(prs, &pr)
 	}
 
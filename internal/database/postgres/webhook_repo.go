@@ -0,0 +1,271 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"slices"
+	"time"
+
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+func (r *PostgresRepository) CreateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	const op = "Postgres.CreateWebhook"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			return
+		}
+	}()
+
+	query := `INSERT INTO webhooks (id, url, secret, created_at) VALUES ($1, $2, $3, $4)`
+	_, err = tx.ExecContext(ctx, query, webhook.ID, webhook.URL, webhook.Secret, webhook.CreatedAt)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	for _, event := range webhook.Events {
+		_, err = tx.ExecContext(ctx, `INSERT INTO webhook_events (webhook_id, event) VALUES ($1, $2)`, webhook.ID, event)
+		if err != nil {
+			return errors.WrapError(op, err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) GetWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	const op = "Postgres.GetWebhooks"
+
+	query := `
+		SELECT w.id, w.url, w.secret, w.created_at, COALESCE(e.event, '')
+		FROM webhooks w
+		LEFT JOIN webhook_events e ON e.webhook_id = w.id
+		ORDER BY w.created_at, w.id, e.event
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			return
+		}
+	}()
+
+	var webhooks []models.Webhook
+	for rows.Next() {
+		var (
+			webhook models.Webhook
+			event   string
+		)
+		if err := rows.Scan(&webhook.ID, &webhook.URL, &webhook.Secret, &webhook.CreatedAt, &event); err != nil {
+			return nil, errors.WrapError(op, err)
+		}
+
+		// строки одной подписки идут подряд, по одной на событие
+		if n := len(webhooks); n == 0 || webhooks[n-1].ID != webhook.ID {
+			webhooks = append(webhooks, webhook)
+		}
+		if event != "" {
+			last := &webhooks[len(webhooks)-1]
+			last.Events = append(last.Events, event)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	return webhooks, nil
+}
+
+// DeleteWebhook: события и доставки подписки удаляются каскадно.
+func (r *PostgresRepository) DeleteWebhook(ctx context.Context, id string) error {
+	const op = "Postgres.DeleteWebhook"
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if rowsAffected == 0 {
+		return errors.WrapError(op, errors.ErrWebhookNotFound)
+	}
+	return nil
+}
+
+// EnqueueDeliveries ставит доставки одним запросом, поэтому подписка, удалённая в это же время,
+// либо получит событие целиком, либо нет.
+func (r *PostgresRepository) EnqueueDeliveries(ctx context.Context, event string, payload []byte, at time.Time) (int, error) {
+	const op = "Postgres.EnqueueDeliveries"
+
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event, payload, next_attempt_at, created_at)
+		SELECT w.id, $1, $2, $3, $3
+		FROM webhooks w
+		WHERE NOT EXISTS (SELECT 1 FROM webhook_events e WHERE e.webhook_id = w.id)
+			OR EXISTS (SELECT 1 FROM webhook_events e WHERE e.webhook_id = w.id AND e.event = $1)
+		ORDER BY w.created_at, w.id
+	`
+	result, err := r.db.ExecContext(ctx, query, event, string(payload), at)
+	if err != nil {
+		return 0, errors.WrapError(op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.WrapError(op, err)
+	}
+	return int(rowsAffected), nil
+}
+
+// ClaimDueDeliveries берёт доставки через SKIP LOCKED, поэтому параллельные обработчики не ждут друг друга.
+func (r *PostgresRepository) ClaimDueDeliveries(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]models.WebhookDelivery, error) {
+	const op = "Postgres.ClaimDueDeliveries"
+
+	query := `
+		WITH due AS (
+			SELECT id
+			FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at, id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = $2
+		FROM due, webhooks w
+		WHERE d.id = due.id AND w.id = d.webhook_id
+		RETURNING d.id, d.webhook_id, d.event, d.payload, d.status, d.attempts,
+			d.next_attempt_at, d.created_at, w.url, w.secret
+	`
+	rows, err := r.db.QueryContext(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			return
+		}
+	}()
+
+	var deliveries []models.WebhookDelivery
+	for rows.Next() {
+		var delivery models.WebhookDelivery
+		err := rows.Scan(
+			&delivery.ID, &delivery.WebhookID, &delivery.Event, &delivery.Payload, &delivery.Status,
+			&delivery.Attempts, &delivery.NextAttemptAt, &delivery.CreatedAt, &delivery.URL, &delivery.Secret,
+		)
+		if err != nil {
+			return nil, errors.WrapError(op, err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	sortDeliveries(deliveries)
+	return deliveries, nil
+}
+
+// SaveDeliveryAttempt ничего не записывает, если подписку за это время удалили.
+func (r *PostgresRepository) SaveDeliveryAttempt(ctx context.Context, delivery *models.WebhookDelivery) error {
+	const op = "Postgres.SaveDeliveryAttempt"
+
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, response_status = NULLIF($4, 0), last_error = NULLIF($5, ''),
+			next_attempt_at = $6, delivered_at = $7
+		WHERE id = $1
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		delivery.ID, delivery.Status, delivery.Attempts, delivery.ResponseStatus, delivery.LastError,
+		delivery.NextAttemptAt, delivery.DeliveredAt,
+	)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) GetWebhookDeliveries(ctx context.Context, webhookID string, limit int) ([]models.WebhookDelivery, error) {
+	const op = "Postgres.GetWebhookDeliveries"
+
+	var exists int
+	err := r.db.QueryRowContext(ctx, `SELECT 1 FROM webhooks WHERE id = $1`, webhookID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return nil, errors.WrapError(op, errors.ErrWebhookNotFound)
+	}
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	query := `
+		SELECT id, webhook_id, event, payload, status, attempts, COALESCE(response_status, 0),
+			COALESCE(last_error, ''), next_attempt_at, delivered_at, created_at
+		FROM webhook_deliveries
+		WHERE webhook_id = $1
+		ORDER BY id DESC
+		LIMIT $2
+	`
+	rows, err := r.db.QueryContext(ctx, query, webhookID, limit)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			return
+		}
+	}()
+
+	var deliveries []models.WebhookDelivery
+	for rows.Next() {
+		var delivery models.WebhookDelivery
+		err := rows.Scan(
+			&delivery.ID, &delivery.WebhookID, &delivery.Event, &delivery.Payload, &delivery.Status,
+			&delivery.Attempts, &delivery.ResponseStatus, &delivery.LastError, &delivery.NextAttemptAt,
+			&delivery.DeliveredAt, &delivery.CreatedAt,
+		)
+		if err != nil {
+			return nil, errors.WrapError(op, err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	return deliveries, nil
+}
+
+// private methods
+
+// sortDeliveries восстанавливает порядок постановки в очередь: RETURNING его не сохраняет.
+func sortDeliveries(deliveries []models.WebhookDelivery) {
+	slices.SortFunc(deliveries, func(a, b models.WebhookDelivery) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+}
@@ -0,0 +1,67 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+
+	"pr-review/internal/database"
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+var accountChecks = []check{
+	{name: "Link", run: testLinkUserAccount},
+	{name: "DeleteTeam", run: testAccountsDeleteTeam},
+}
+
+func testLinkUserAccount(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2")
+
+	noError(t, linkAccount(ctx, repo, "u1", "alice"))
+	equal(t, "linked", lookupLogin(ctx, t, repo, "alice"), "u1")
+
+	// повторная привязка того же логина ничего не меняет
+	noError(t, linkAccount(ctx, repo, "u1", "alice"))
+	wantError(t, linkAccount(ctx, repo, "u2", "alice"), errors.ErrLoginTaken)
+	wantError(t, linkAccount(ctx, repo, "missing", "bob"), errors.ErrUserNotFound)
+
+	// новый логин заменяет прежний
+	noError(t, linkAccount(ctx, repo, "u1", "alice-new"))
+	equal(t, "relinked", lookupLogin(ctx, t, repo, "alice-new"), "u1")
+	_, err := repo.GetUserIDByLogin(ctx, models.ProviderGitHub, "alice")
+	wantError(t, err, errors.ErrAccountNotLinked)
+
+	noError(t, linkAccount(ctx, repo, "u2", "alice"))
+	equal(t, "freed login", lookupLogin(ctx, t, repo, "alice"), "u2")
+
+	_, err = repo.GetUserIDByLogin(ctx, "gitlab", "alice")
+	wantError(t, err, errors.ErrAccountNotLinked)
+}
+
+func testAccountsDeleteTeam(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1")
+	noError(t, linkAccount(ctx, repo, "u1", "alice"))
+
+	noError(t, repo.DeleteTeam(ctx, "backend"))
+	_, err := repo.GetUserIDByLogin(ctx, models.ProviderGitHub, "alice")
+	wantError(t, err, errors.ErrAccountNotLinked)
+
+	// пользователь с тем же id не получает чужую учётную запись
+	seedTeam(ctx, t, repo, "frontend", "u1")
+	_, err = repo.GetUserIDByLogin(ctx, models.ProviderGitHub, "alice")
+	wantError(t, err, errors.ErrAccountNotLinked)
+}
+
+// private methods
+
+func linkAccount(ctx context.Context, repo database.Repository, userID, login string) error {
+	return repo.LinkUserAccount(ctx, &models.UserAccount{Provider: models.ProviderGitHub, Login: login, UserID: userID})
+}
+
+func lookupLogin(ctx context.Context, t *testing.T, repo database.Repository, login string) string {
+	t.Helper()
+
+	userID, err := repo.GetUserIDByLogin(ctx, models.ProviderGitHub, login)
+	noError(t, err)
+	return userID
+}
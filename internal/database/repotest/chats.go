@@ -0,0 +1,146 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"pr-review/internal/database"
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+var chatChecks = []check{
+	{name: "SetAndGet", run: testTeamChatSetGet},
+	{name: "RenameAndDelete", run: testTeamChatRenameDelete},
+	{name: "ClaimAndSave", run: testChatClaimSave},
+}
+
+func testTeamChatSetGet(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1")
+
+	_, err := repo.GetTeamChat(ctx, "backend")
+	wantError(t, err, errors.ErrChatNotFound)
+	wantError(t, repo.SetTeamChat(ctx, &models.TeamChat{TeamName: "missing", URL: "http://localhost", Format: models.ChatFormatSlack}),
+		errors.ErrTeamNotFound)
+
+	updatedAt := now()
+	noError(t, repo.SetTeamChat(ctx, &models.TeamChat{
+		UpdatedAt: updatedAt,
+		TeamName:  "backend",
+		URL:       "http://localhost/slack",
+		Format:    models.ChatFormatSlack,
+		Templates: models.ChatTemplates{ReviewerAssigned: "{{.Reviewer}}", PRMerged: "merged"},
+	}))
+	chat, err := repo.GetTeamChat(ctx, "backend")
+	noError(t, err)
+	equal(t, "url", chat.URL, "http://localhost/slack")
+	equal(t, "format", chat.Format, models.ChatFormatSlack)
+	equal(t, "assigned", chat.Templates.ReviewerAssigned, "{{.Reviewer}}")
+	equal(t, "reassigned", chat.Templates.ReviewerReassigned, "")
+	equal(t, "merged", chat.Templates.PRMerged, "merged")
+	equalTime(t, "updated at", &chat.UpdatedAt, updatedAt)
+
+	// новые настройки заменяют прежние целиком
+	noError(t, repo.SetTeamChat(ctx, &models.TeamChat{
+		UpdatedAt: now(),
+		TeamName:  "backend",
+		URL:       "http://localhost/mattermost",
+		Format:    models.ChatFormatMattermost,
+	}))
+	chat, err = repo.GetTeamChat(ctx, "backend")
+	noError(t, err)
+	equal(t, "replaced url", chat.URL, "http://localhost/mattermost")
+	equal(t, "replaced format", chat.Format, models.ChatFormatMattermost)
+	equal(t, "cleared template", chat.Templates.ReviewerAssigned, "")
+}
+
+func testTeamChatRenameDelete(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1")
+	noError(t, repo.SetTeamChat(ctx, &models.TeamChat{
+		UpdatedAt: now(),
+		TeamName:  "backend",
+		URL:       "http://localhost/slack",
+		Format:    models.ChatFormatSlack,
+	}))
+
+	noError(t, repo.RenameTeam(ctx, "backend", "platform"))
+	_, err := repo.GetTeamChat(ctx, "backend")
+	wantError(t, err, errors.ErrChatNotFound)
+	chat, err := repo.GetTeamChat(ctx, "platform")
+	noError(t, err)
+	equal(t, "renamed team", chat.TeamName, "platform")
+
+	noError(t, repo.DeleteTeam(ctx, "platform"))
+	seedTeam(ctx, t, repo, "platform", "u2")
+	_, err = repo.GetTeamChat(ctx, "platform")
+	wantError(t, err, errors.ErrChatNotFound)
+}
+
+func testChatClaimSave(ctx context.Context, t *testing.T, repo database.Repository) {
+	at := now()
+	noError(t, repo.EnqueueChatMessage(ctx, chatMessage(at, `{"text":"first"}`)))
+	noError(t, repo.EnqueueChatMessage(ctx, chatMessage(at.Add(time.Hour), `{"text":"second"}`)))
+
+	messages, err := repo.ClaimDueChatMessages(ctx, at, time.Minute, 10)
+	noError(t, err)
+	equal(t, "due", len(messages), 1)
+	claimed := messages[0]
+	equal(t, "team", claimed.TeamName, "backend")
+	equal(t, "event", claimed.Event, models.WebhookEventPRMerged)
+	equal(t, "url", claimed.URL, "http://localhost/chat")
+	equal(t, "payload", string(claimed.Payload), `{"text":"first"}`)
+	equal(t, "status", claimed.Status, models.ChatPending)
+
+	// выданное сообщение не выдаётся повторно, пока не истёк lease
+	messages, err = repo.ClaimDueChatMessages(ctx, at.Add(30*time.Second), time.Minute, 10)
+	noError(t, err)
+	equal(t, "leased", len(messages), 0)
+
+	retryAt := at.Add(2 * time.Hour)
+	claimed.Attempts = 1
+	claimed.LastError = "chat responded 500"
+	claimed.NextAttemptAt = retryAt
+	noError(t, repo.SaveChatAttempt(ctx, &claimed))
+
+	messages, err = repo.ClaimDueChatMessages(ctx, at.Add(90*time.Minute), time.Minute, 10)
+	noError(t, err)
+	equal(t, "later message", len(messages), 1)
+	equal(t, "later payload", string(messages[0].Payload), `{"text":"second"}`)
+	failed := messages[0]
+	failed.Status = models.ChatFailed
+	failed.Attempts = 1
+	noError(t, repo.SaveChatAttempt(ctx, &failed))
+
+	messages, err = repo.ClaimDueChatMessages(ctx, retryAt, time.Minute, 10)
+	noError(t, err)
+	equal(t, "retry", len(messages), 1)
+	equal(t, "retry attempts", messages[0].Attempts, 1)
+
+	sentAt := now()
+	sent := messages[0]
+	sent.Status = models.ChatSent
+	sent.Attempts = 2
+	sent.LastError = ""
+	sent.SentAt = &sentAt
+	noError(t, repo.SaveChatAttempt(ctx, &sent))
+
+	// отправленные и исчерпавшие попытки больше не выдаются
+	messages, err = repo.ClaimDueChatMessages(ctx, retryAt.Add(24*time.Hour), time.Minute, 10)
+	noError(t, err)
+	equal(t, "after send", len(messages), 0)
+}
+
+// private methods
+
+func chatMessage(at time.Time, payload string) *models.ChatMessage {
+	return &models.ChatMessage{
+		CreatedAt:     at,
+		NextAttemptAt: at,
+		TeamName:      "backend",
+		Event:         models.WebhookEventPRMerged,
+		URL:           "http://localhost/chat",
+		Status:        models.ChatPending,
+		Payload:       []byte(payload),
+	}
+}
@@ -0,0 +1,142 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"pr-review/internal/database"
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+var emailChecks = []check{
+	{name: "SetAndGet", run: testUserEmailSetGet},
+	{name: "DigestRecipients", run: testDigestRecipients},
+	{name: "ClaimAndSave", run: testEmailClaimSave},
+}
+
+func testUserEmailSetGet(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1")
+
+	_, err := repo.GetUserEmail(ctx, "u1")
+	wantError(t, err, errors.ErrEmailNotFound)
+	_, err = repo.GetUserEmail(ctx, "missing")
+	wantError(t, err, errors.ErrUserNotFound)
+	wantError(t, repo.SetUserEmail(ctx, &models.EmailSettings{UserID: "missing", Email: "a@example.com", Mode: models.EmailDigest}),
+		errors.ErrUserNotFound)
+
+	noError(t, repo.SetUserEmail(ctx, &models.EmailSettings{UserID: "u1", Email: "u1@example.com", Mode: models.EmailDigest}))
+	settings, err := repo.GetUserEmail(ctx, "u1")
+	noError(t, err)
+	equal(t, "user", settings.UserID, "u1")
+	equal(t, "email", settings.Email, "u1@example.com")
+	equal(t, "mode", settings.Mode, models.EmailDigest)
+
+	// пустой адрес отключает письма
+	noError(t, repo.SetUserEmail(ctx, &models.EmailSettings{UserID: "u1"}))
+	_, err = repo.GetUserEmail(ctx, "u1")
+	wantError(t, err, errors.ErrEmailNotFound)
+}
+
+func testDigestRecipients(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2", "u3", "u4")
+	noError(t, repo.SetUserEmail(ctx, &models.EmailSettings{UserID: "u2", Email: "u2@example.com", Mode: models.EmailDigest}))
+	noError(t, repo.SetUserEmail(ctx, &models.EmailSettings{UserID: "u1", Email: "u1@example.com", Mode: models.EmailDigest}))
+	noError(t, repo.SetUserEmail(ctx, &models.EmailSettings{UserID: "u3", Email: "u3@example.com", Mode: models.EmailImmediate}))
+	noError(t, repo.SetUserEmail(ctx, &models.EmailSettings{UserID: "u4", Email: "u4@example.com", Mode: models.EmailDigest}))
+	noError(t, repo.SetUserActive(ctx, "u4", false))
+
+	day := now().Truncate(time.Hour)
+	recipients, err := repo.ClaimDigestRecipients(ctx, day)
+	noError(t, err)
+	equal(t, "recipients", len(recipients), 2)
+	equal(t, "first", recipients[0].UserID, "u1")
+	equal(t, "email", recipients[0].Email, "u1@example.com")
+	equal(t, "mode", recipients[0].Mode, models.EmailDigest)
+	equal(t, "second", recipients[1].UserID, "u2")
+
+	// сводка за день выдаётся один раз, и смена адреса этого не меняет
+	noError(t, repo.SetUserEmail(ctx, &models.EmailSettings{UserID: "u1", Email: "new@example.com", Mode: models.EmailDigest}))
+	recipients, err = repo.ClaimDigestRecipients(ctx, day)
+	noError(t, err)
+	equal(t, "same day", len(recipients), 0)
+
+	recipients, err = repo.ClaimDigestRecipients(ctx, day.Add(24*time.Hour))
+	noError(t, err)
+	equal(t, "next day", len(recipients), 2)
+	equal(t, "new email", recipients[0].Email, "new@example.com")
+
+	noError(t, repo.DeleteTeam(ctx, "backend"))
+	seedTeam(ctx, t, repo, "backend", "u1")
+	_, err = repo.GetUserEmail(ctx, "u1")
+	wantError(t, err, errors.ErrEmailNotFound)
+}
+
+func testEmailClaimSave(ctx context.Context, t *testing.T, repo database.Repository) {
+	at := now()
+	noError(t, repo.EnqueueEmail(ctx, emailMessage(at, "first")))
+	noError(t, repo.EnqueueEmail(ctx, emailMessage(at.Add(time.Hour), "second")))
+
+	messages, err := repo.ClaimDueEmails(ctx, at, time.Minute, 10)
+	noError(t, err)
+	equal(t, "due", len(messages), 1)
+	claimed := messages[0]
+	equal(t, "user", claimed.UserID, "u1")
+	equal(t, "recipient", claimed.Recipient, "u1@example.com")
+	equal(t, "subject", claimed.Subject, "first")
+	equal(t, "message", string(claimed.Message), "Subject: first\r\n\r\nbody")
+	equal(t, "status", claimed.Status, models.EmailPending)
+
+	// выданное письмо не выдаётся повторно, пока не истёк lease
+	messages, err = repo.ClaimDueEmails(ctx, at.Add(30*time.Second), time.Minute, 10)
+	noError(t, err)
+	equal(t, "leased", len(messages), 0)
+
+	retryAt := at.Add(2 * time.Hour)
+	claimed.Attempts = 1
+	claimed.LastError = "451 try again later"
+	claimed.NextAttemptAt = retryAt
+	noError(t, repo.SaveEmailAttempt(ctx, &claimed))
+
+	messages, err = repo.ClaimDueEmails(ctx, at.Add(90*time.Minute), time.Minute, 10)
+	noError(t, err)
+	equal(t, "later message", len(messages), 1)
+	equal(t, "later subject", messages[0].Subject, "second")
+	failed := messages[0]
+	failed.Status = models.EmailFailed
+	failed.Attempts = 1
+	noError(t, repo.SaveEmailAttempt(ctx, &failed))
+
+	messages, err = repo.ClaimDueEmails(ctx, retryAt, time.Minute, 10)
+	noError(t, err)
+	equal(t, "retry", len(messages), 1)
+	equal(t, "retry attempts", messages[0].Attempts, 1)
+
+	sentAt := now()
+	sent := messages[0]
+	sent.Status = models.EmailSent
+	sent.Attempts = 2
+	sent.LastError = ""
+	sent.SentAt = &sentAt
+	noError(t, repo.SaveEmailAttempt(ctx, &sent))
+
+	// отправленные и исчерпавшие попытки больше не выдаются
+	messages, err = repo.ClaimDueEmails(ctx, retryAt.Add(24*time.Hour), time.Minute, 10)
+	noError(t, err)
+	equal(t, "after send", len(messages), 0)
+}
+
+// private methods
+
+func emailMessage(at time.Time, subject string) *models.EmailMessage {
+	return &models.EmailMessage{
+		CreatedAt:     at,
+		NextAttemptAt: at,
+		UserID:        "u1",
+		Recipient:     "u1@example.com",
+		Subject:       subject,
+		Status:        models.EmailPending,
+		Message:       []byte("Subject: " + subject + "\r\n\r\nbody"),
+	}
+}
@@ -0,0 +1,163 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+
+	"pr-review/internal/database"
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+var eventChecks = []check{
+	{name: "Lifecycle", run: testEventsLifecycle},
+	{name: "DraftAndReopen", run: testEventsDraftAndReopen},
+	{name: "Deactivate", run: testEventsDeactivate},
+	{name: "Archive", run: testEventsArchive},
+	{name: "FailedChange", run: testEventsFailedChange},
+}
+
+func testEventsLifecycle(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2", "u3", "u4")
+	seedPR(ctx, t, repo, "pr-1", "u1", "u2", "u3")
+	reviewedAt := now()
+	noError(t, repo.SubmitReview(ctx, "pr-1", "u2", models.ReviewStateApproved, reviewedAt))
+	noError(t, repo.ReassignReviewer(ctx, "pr-1", "u3", "u4", models.ReasonManual))
+	mergedAt := now()
+	noError(t, repo.MergePR(ctx, "pr-1", mergedAt, nil))
+	// повторный merge истории не меняет
+	noError(t, repo.MergePR(ctx, "pr-1", mergedAt, nil))
+
+	events := getEvents(ctx, t, repo, "pr-1")
+	equalStrings(t, "types", eventTypes(events), []string{
+		"created", "reviewer_assigned", "reviewer_assigned", "review_submitted",
+		"reviewer_removed", "reviewer_assigned", "merged",
+	})
+	for i := 1; i < len(events); i++ {
+		if events[i].ID <= events[i-1].ID {
+			t.Fatalf("event ids are not increasing: %d after %d", events[i].ID, events[i-1].ID)
+		}
+	}
+
+	equal(t, "pr", events[0].PullRequestID, "pr-1")
+	equal(t, "author", events[0].UserID, "u1")
+	equal(t, "first reviewer", events[1].UserID, "u2")
+	equal(t, "initial reason", events[1].Reason, "")
+
+	equal(t, "reviewer", events[3].UserID, "u2")
+	equal(t, "verdict", events[3].State, models.ReviewStateApproved)
+	equalTime(t, "reviewed at", &events[3].CreatedAt, reviewedAt)
+
+	removed := events[4]
+	equal(t, "removed", removed.UserID, "u3")
+	equal(t, "replaced by", removed.RelatedUserID, "u4")
+	equal(t, "reason", removed.Reason, models.ReasonManual)
+	assigned := events[5]
+	equal(t, "assigned", assigned.UserID, "u4")
+	equal(t, "replaces", assigned.RelatedUserID, "u3")
+	equal(t, "assigned reason", assigned.Reason, models.ReasonManual)
+
+	equalTime(t, "merged at", &events[6].CreatedAt, mergedAt)
+
+	_, err := repo.GetPREvents(ctx, "missing")
+	wantError(t, err, errors.ErrPRNotFound)
+}
+
+func testEventsDraftAndReopen(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2", "u3")
+	seedDraft(ctx, t, repo, "pr-1", "u1")
+	noError(t, repo.MarkReadyForReview(ctx, "pr-1", []string{"u2"}))
+	closedAt := now()
+	noError(t, repo.ClosePR(ctx, "pr-1", closedAt))
+	noError(t, repo.ReopenPR(ctx, "pr-1", []string{"u2"}, []string{"u3"}))
+	noError(t, repo.MarkDraft(ctx, "pr-1"))
+
+	events := getEvents(ctx, t, repo, "pr-1")
+	equalStrings(t, "types", eventTypes(events), []string{
+		"created", "ready_for_review", "reviewer_assigned", "closed",
+		"reopened", "reviewer_removed", "reviewer_assigned", "converted_to_draft",
+	})
+	equalTime(t, "closed at", &events[3].CreatedAt, closedAt)
+	equal(t, "manual close reason", events[3].Reason, "")
+	equal(t, "removed", events[5].UserID, "u2")
+	equal(t, "removed reason", events[5].Reason, models.ReasonUnavailable)
+	equal(t, "assigned", events[6].UserID, "u3")
+}
+
+func testEventsDeactivate(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2", "u3")
+	seedPR(ctx, t, repo, "pr-1", "u1", "u2")
+
+	noError(t, repo.DeactivateUsers(ctx, []string{"u2"}, []models.Reassignment{
+		{PullRequestID: "pr-1", OldUserID: "u2", NewUserID: "u3"},
+	}))
+
+	events := getEvents(ctx, t, repo, "pr-1")
+	equalStrings(t, "types", eventTypes(events), []string{
+		"created", "reviewer_assigned", "reviewer_removed", "reviewer_assigned",
+	})
+	equal(t, "removed", events[2].UserID, "u2")
+	equal(t, "replaced by", events[2].RelatedUserID, "u3")
+	equal(t, "reason", events[2].Reason, models.ReasonDeactivated)
+	equal(t, "assigned reason", events[3].Reason, models.ReasonDeactivated)
+}
+
+func testEventsArchive(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2")
+	seedPR(ctx, t, repo, "pr-1", "u1", "u2")
+	seedPR(ctx, t, repo, "pr-2", "u1")
+	noError(t, repo.MergePR(ctx, "pr-2", now(), nil))
+
+	archivedAt := now()
+	noError(t, repo.ArchiveTeam(ctx, "backend", archivedAt, []string{"pr-1", "pr-2"}))
+
+	events := getEvents(ctx, t, repo, "pr-1")
+	closed := events[len(events)-1]
+	equal(t, "type", closed.Type, models.PREventClosed)
+	equal(t, "reason", closed.Reason, models.ReasonTeamArchived)
+	equalTime(t, "closed at", &closed.CreatedAt, archivedAt)
+
+	// уже влитый PR не закрывается и в истории не появляется закрытие
+	equalStrings(t, "merged PR", eventTypes(getEvents(ctx, t, repo, "pr-2")), []string{"created", "merged"})
+}
+
+func testEventsFailedChange(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2", "u3")
+	seedPR(ctx, t, repo, "pr-1", "u1", "u2")
+	noError(t, repo.SetUserActive(ctx, "u3", false))
+	before := eventTypes(getEvents(ctx, t, repo, "pr-1"))
+
+	wantError(t, repo.ReassignReviewer(ctx, "pr-1", "u2", "u3", models.ReasonManual), errors.ErrReviewerUnavailable)
+	wantError(t, repo.SubmitReview(ctx, "pr-1", "u3", models.ReviewStateApproved, now()), errors.ErrNotAssigned)
+	noError(t, repo.ClosePR(ctx, "pr-1", now()))
+	wantError(t, repo.ClosePR(ctx, "pr-1", now()), errors.ErrPRNotFound)
+	wantError(t, repo.ReopenPR(ctx, "pr-1", nil, []string{"u3"}), errors.ErrReviewerUnavailable)
+
+	equalStrings(t, "types", eventTypes(getEvents(ctx, t, repo, "pr-1")), append(before, "closed"))
+
+	err := repo.CreatePR(ctx, &models.PullRequest{
+		PullRequestShort:  models.PullRequestShort{ID: "pr-2", Name: "PR", AuthorID: "u1"},
+		AssignedReviewers: []string{"u3"},
+	})
+	wantError(t, err, errors.ErrReviewerUnavailable)
+	_, err = repo.GetPREvents(ctx, "pr-2")
+	wantError(t, err, errors.ErrPRNotFound)
+}
+
+// private methods
+
+func getEvents(ctx context.Context, t *testing.T, repo database.Repository, prID string) []models.PREvent {
+	t.Helper()
+
+	events, err := repo.GetPREvents(ctx, prID)
+	noError(t, err)
+	return events
+}
+
+func eventTypes(events []models.PREvent) []string {
+	types := make([]string, 0, len(events))
+	for _, event := range events {
+		types = append(types, string(event.Type))
+	}
+	return types
+}
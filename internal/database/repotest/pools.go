@@ -0,0 +1,74 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+
+	"pr-review/internal/database"
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+var poolChecks = []check{
+	{name: "CreateAndGet", run: testCreatePool},
+	{name: "CreateInvalid", run: testCreatePoolInvalid},
+	{name: "SetMembers", run: testSetPoolMembers},
+}
+
+func testCreatePool(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2")
+	seedTeam(ctx, t, repo, "frontend", "u3")
+
+	noError(t, repo.CreateReviewerPool(ctx, &models.ReviewerPool{Name: "shared", Members: []string{"u3", "u1"}}))
+	noError(t, repo.CreateReviewerPool(ctx, &models.ReviewerPool{Name: "empty"}))
+
+	pool, err := repo.GetReviewerPool(ctx, "shared")
+	noError(t, err)
+	equal(t, "name", pool.Name, "shared")
+	equalStrings(t, "members", pool.Members, []string{"u1", "u3"})
+
+	pool, err = repo.GetReviewerPool(ctx, "empty")
+	noError(t, err)
+	equal(t, "empty members", len(pool.Members), 0)
+
+	_, err = repo.GetReviewerPool(ctx, "missing")
+	wantError(t, err, errors.ErrPoolNotFound)
+}
+
+func testCreatePoolInvalid(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1")
+	noError(t, repo.CreateReviewerPool(ctx, &models.ReviewerPool{Name: "shared", Members: []string{"u1"}}))
+
+	err := repo.CreateReviewerPool(ctx, &models.ReviewerPool{Name: "shared"})
+	wantError(t, err, errors.ErrPoolExists)
+
+	err = repo.CreateReviewerPool(ctx, &models.ReviewerPool{Name: "other", Members: []string{"u1", "missing"}})
+	wantError(t, err, errors.ErrUserNotFound)
+	_, err = repo.GetReviewerPool(ctx, "other")
+	wantError(t, err, errors.ErrPoolNotFound)
+}
+
+func testSetPoolMembers(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2", "u3")
+	noError(t, repo.CreateReviewerPool(ctx, &models.ReviewerPool{Name: "shared", Members: []string{"u1", "u2"}}))
+
+	noError(t, repo.SetReviewerPoolMembers(ctx, &models.ReviewerPool{Name: "shared", Members: []string{"u3", "u2"}}))
+	pool, err := repo.GetReviewerPool(ctx, "shared")
+	noError(t, err)
+	equalStrings(t, "members", pool.Members, []string{"u2", "u3"})
+
+	// неизвестный участник не меняет состав
+	err = repo.SetReviewerPoolMembers(ctx, &models.ReviewerPool{Name: "shared", Members: []string{"u1", "missing"}})
+	wantError(t, err, errors.ErrUserNotFound)
+	pool, err = repo.GetReviewerPool(ctx, "shared")
+	noError(t, err)
+	equalStrings(t, "members after failure", pool.Members, []string{"u2", "u3"})
+
+	noError(t, repo.SetReviewerPoolMembers(ctx, &models.ReviewerPool{Name: "shared"}))
+	pool, err = repo.GetReviewerPool(ctx, "shared")
+	noError(t, err)
+	equal(t, "cleared", len(pool.Members), 0)
+
+	err = repo.SetReviewerPoolMembers(ctx, &models.ReviewerPool{Name: "missing", Members: []string{"u1"}})
+	wantError(t, err, errors.ErrPoolNotFound)
+}
@@ -0,0 +1,417 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"pr-review/internal/database"
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+var prChecks = []check{
+	{name: "CreateAndGet", run: testCreatePR},
+	{name: "CreateDuplicate", run: testCreatePRDuplicate},
+	{name: "CreateInvalid", run: testCreatePRInvalid},
+	{name: "UnavailableReviewers", run: testUnavailableReviewers},
+	{name: "ReviewersAtCapacity", run: testReviewersAtCapacity},
+	{name: "Merge", run: testMergePR},
+	{name: "Close", run: testClosePR},
+	{name: "Reopen", run: testReopenPR},
+	{name: "ReadyForReview", run: testMarkReadyForReview},
+	{name: "MarkDraft", run: testMarkDraft},
+	{name: "SubmitReview", run: testSubmitReview},
+	{name: "Reassign", run: testReassignReviewer},
+}
+
+var candidateChecks = []check{
+	{name: "ByTeam", run: testReviewerCandidates},
+	{name: "ByIDs", run: testReviewerCandidatesByIDs},
+}
+
+func testCreatePR(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2", "u3")
+
+	before := time.Now().Add(-time.Second)
+	noError(t, repo.CreatePR(ctx, &models.PullRequest{
+		ReviewersCount:    intPtr(2),
+		PullRequestShort:  models.PullRequestShort{ID: "pr-1", Name: "Add search", AuthorID: "u1"},
+		AssignedReviewers: []string{"u3", "u2"},
+		ChangedFiles:      []string{"web/app.go", "api/search.go"},
+	}))
+
+	pr := getPR(ctx, t, repo, "pr-1")
+	equal(t, "name", pr.Name, "Add search")
+	equal(t, "author", pr.AuthorID, "u1")
+	equal(t, "status", pr.Status, models.PRStatusOpen)
+	equal(t, "draft", pr.IsDraft, false)
+	equal(t, "created at", pr.CreatedAt.After(before) && pr.CreatedAt.Before(time.Now().Add(time.Second)), true)
+	equal(t, "merged at", pr.MergedAt == nil, true)
+	equal(t, "closed at", pr.ClosedAt == nil, true)
+	equalIntPtr(t, "reviewers count", pr.ReviewersCount, intPtr(2))
+	// ревьюверы упорядочены по user_id, файлы по пути
+	equalStrings(t, "reviewers", pr.AssignedReviewers, []string{"u2", "u3"})
+	equalStrings(t, "files", pr.ChangedFiles, []string{"api/search.go", "web/app.go"})
+
+	review := findReview(t, pr, "u2")
+	equal(t, "review state", review.State, models.ReviewStatePending)
+	equal(t, "assigned at", review.AssignedAt != nil, true)
+	equal(t, "reviewed at", review.ReviewedAt == nil, true)
+
+	seedDraft(ctx, t, repo, "pr-2", "u1")
+	draft := getPR(ctx, t, repo, "pr-2")
+	equal(t, "draft", draft.IsDraft, true)
+	equalIntPtr(t, "draft reviewers count", draft.ReviewersCount, nil)
+	equal(t, "draft reviewers", len(draft.AssignedReviewers), 0)
+	equal(t, "draft files", len(draft.ChangedFiles), 0)
+}
+
+func testCreatePRDuplicate(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2", "u3")
+	seedPR(ctx, t, repo, "pr-1", "u1", "u2")
+
+	err := repo.CreatePR(ctx, &models.PullRequest{
+		PullRequestShort:  models.PullRequestShort{ID: "pr-1", Name: "Other", AuthorID: "u3"},
+		AssignedReviewers: []string{"u1"},
+	})
+	wantError(t, err, errors.ErrPRExists)
+
+	// исходный PR не меняется
+	pr := getPR(ctx, t, repo, "pr-1")
+	equal(t, "author", pr.AuthorID, "u1")
+	equalStrings(t, "reviewers", pr.AssignedReviewers, []string{"u2"})
+}
+
+func testCreatePRInvalid(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2")
+
+	err := repo.CreatePR(ctx, &models.PullRequest{
+		PullRequestShort: models.PullRequestShort{ID: "pr-1", Name: "PR", AuthorID: "missing"},
+	})
+	wantError(t, err, errors.ErrUserNotFound)
+
+	_, err = repo.GetPRByID(ctx, "pr-1")
+	wantError(t, err, errors.ErrPRNotFound)
+
+	// повтор ревьювера или файла отменяет создание целиком
+	err = repo.CreatePR(ctx, &models.PullRequest{
+		PullRequestShort:  models.PullRequestShort{ID: "pr-2", Name: "PR", AuthorID: "u1"},
+		AssignedReviewers: []string{"u2", "u2"},
+	})
+	wantAnyError(t, err)
+	_, err = repo.GetPRByID(ctx, "pr-2")
+	wantError(t, err, errors.ErrPRNotFound)
+
+	err = repo.CreatePR(ctx, &models.PullRequest{
+		PullRequestShort:  models.PullRequestShort{ID: "pr-3", Name: "PR", AuthorID: "u1"},
+		AssignedReviewers: []string{"u2"},
+		ChangedFiles:      []string{"main.go", "main.go"},
+	})
+	wantAnyError(t, err)
+	_, err = repo.GetPRByID(ctx, "pr-3")
+	wantError(t, err, errors.ErrPRNotFound)
+	assignments, err := repo.GetPRsByReviewer(ctx, "u2")
+	noError(t, err)
+	equal(t, "assignments", len(assignments), 0)
+}
+
+func testMergePR(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2")
+	seedPR(ctx, t, repo, "pr-1", "u1", "u2")
+	seedPR(ctx, t, repo, "pr-2", "u1")
+
+	mergedAt := now()
+	noError(t, repo.MergePR(ctx, "pr-1", mergedAt, nil))
+	pr := getPR(ctx, t, repo, "pr-1")
+	equal(t, "status", pr.Status, models.PRStatusMerged)
+	equalTime(t, "merged at", pr.MergedAt, mergedAt)
+
+	// повторный merge ничего не меняет
+	noError(t, repo.MergePR(ctx, "pr-1", mergedAt.Add(time.Hour), nil))
+	equalTime(t, "merged at after repeat", getPR(ctx, t, repo, "pr-1").MergedAt, mergedAt)
+
+	// закрытый PR остаётся закрытым
+	noError(t, repo.ClosePR(ctx, "pr-2", mergedAt))
+	wantError(t, repo.MergePR(ctx, "pr-2", mergedAt, nil), errors.ErrPRClosed)
+	closed := getPR(ctx, t, repo, "pr-2")
+	equal(t, "closed status", closed.Status, models.PRStatusClosed)
+	equal(t, "closed merged at", closed.MergedAt == nil, true)
+
+	wantError(t, repo.MergePR(ctx, "missing", mergedAt, nil), errors.ErrPRNotFound)
+
+	// guard видит вердикты внутри транзакции, и его ошибка отменяет merge
+	seedPR(ctx, t, repo, "pr-3", "u1", "u2")
+	noError(t, repo.SubmitReview(ctx, "pr-3", "u2", models.ReviewStateChangesRequested, mergedAt))
+	var seen *models.PullRequest
+	guard := func(pr *models.PullRequest) error {
+		seen = pr
+		if pr.Reviews[0].State != models.ReviewStateApproved {
+			return errors.ErrMergeBlocked
+		}
+		return nil
+	}
+	wantError(t, repo.MergePR(ctx, "pr-3", mergedAt, guard), errors.ErrMergeBlocked)
+	equal(t, "guarded author", seen.AuthorID, "u1")
+	equalStrings(t, "guarded reviewers", seen.AssignedReviewers, []string{"u2"})
+	equal(t, "blocked status", getPR(ctx, t, repo, "pr-3").Status, models.PRStatusOpen)
+
+	noError(t, repo.SubmitReview(ctx, "pr-3", "u2", models.ReviewStateApproved, mergedAt))
+	noError(t, repo.MergePR(ctx, "pr-3", mergedAt, guard))
+	equal(t, "guarded status", getPR(ctx, t, repo, "pr-3").Status, models.PRStatusMerged)
+}
+
+func testClosePR(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2")
+	seedPR(ctx, t, repo, "pr-1", "u1", "u2")
+	seedPR(ctx, t, repo, "pr-2", "u1")
+
+	closedAt := now()
+	noError(t, repo.ClosePR(ctx, "pr-1", closedAt))
+	pr := getPR(ctx, t, repo, "pr-1")
+	equal(t, "status", pr.Status, models.PRStatusClosed)
+	equalTime(t, "closed at", pr.ClosedAt, closedAt)
+	equalStrings(t, "reviewers", pr.AssignedReviewers, []string{"u2"})
+
+	// закрыть можно только открытый PR
+	wantError(t, repo.ClosePR(ctx, "pr-1", closedAt), errors.ErrPRNotFound)
+	noError(t, repo.MergePR(ctx, "pr-2", closedAt, nil))
+	wantError(t, repo.ClosePR(ctx, "pr-2", closedAt), errors.ErrPRNotFound)
+	wantError(t, repo.ClosePR(ctx, "missing", closedAt), errors.ErrPRNotFound)
+}
+
+func testReopenPR(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2", "u3", "u4")
+	seedPR(ctx, t, repo, "pr-1", "u1", "u2", "u3")
+	seedPR(ctx, t, repo, "pr-2", "u1")
+
+	wantError(t, repo.ReopenPR(ctx, "pr-1", nil, nil), errors.ErrPRNotFound)
+	wantError(t, repo.ReopenPR(ctx, "missing", nil, nil), errors.ErrPRNotFound)
+
+	noError(t, repo.ClosePR(ctx, "pr-1", now()))
+	noError(t, repo.ReopenPR(ctx, "pr-1", []string{"u2"}, []string{"u4"}))
+
+	pr := getPR(ctx, t, repo, "pr-1")
+	equal(t, "status", pr.Status, models.PRStatusOpen)
+	equal(t, "closed at", pr.ClosedAt == nil, true)
+	equalStrings(t, "reviewers", pr.AssignedReviewers, []string{"u3", "u4"})
+
+	// назначение уже назначенного отменяет переоткрытие целиком
+	noError(t, repo.ClosePR(ctx, "pr-1", now()))
+	wantAnyError(t, repo.ReopenPR(ctx, "pr-1", []string{"u3"}, []string{"u4"}))
+	pr = getPR(ctx, t, repo, "pr-1")
+	equal(t, "status after failure", pr.Status, models.PRStatusClosed)
+	equalStrings(t, "reviewers after failure", pr.AssignedReviewers, []string{"u3", "u4"})
+
+	noError(t, repo.MergePR(ctx, "pr-2", now(), nil))
+	wantError(t, repo.ReopenPR(ctx, "pr-2", nil, nil), errors.ErrPRNotFound)
+}
+
+func testMarkReadyForReview(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2", "u3")
+	seedDraft(ctx, t, repo, "pr-1", "u1")
+	seedDraft(ctx, t, repo, "pr-2", "u1")
+	seedPR(ctx, t, repo, "pr-3", "u1")
+
+	noError(t, repo.MarkReadyForReview(ctx, "pr-1", []string{"u3", "u2"}))
+	pr := getPR(ctx, t, repo, "pr-1")
+	equal(t, "draft", pr.IsDraft, false)
+	equalStrings(t, "reviewers", pr.AssignedReviewers, []string{"u2", "u3"})
+
+	wantError(t, repo.MarkReadyForReview(ctx, "pr-1", nil), errors.ErrPRNotFound)
+	wantError(t, repo.MarkReadyForReview(ctx, "pr-3", nil), errors.ErrPRNotFound)
+	wantError(t, repo.MarkReadyForReview(ctx, "missing", nil), errors.ErrPRNotFound)
+
+	// закрытый черновик сначала переоткрывается
+	noError(t, repo.ClosePR(ctx, "pr-2", now()))
+	wantError(t, repo.MarkReadyForReview(ctx, "pr-2", nil), errors.ErrPRNotFound)
+	equal(t, "closed draft", getPR(ctx, t, repo, "pr-2").IsDraft, true)
+}
+
+func testMarkDraft(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2", "u3")
+	seedPR(ctx, t, repo, "pr-1", "u1", "u2")
+	seedPR(ctx, t, repo, "pr-2", "u1")
+
+	noError(t, repo.MarkDraft(ctx, "pr-1"))
+	pr := getPR(ctx, t, repo, "pr-1")
+	equal(t, "draft", pr.IsDraft, true)
+	equalStrings(t, "reviewers", pr.AssignedReviewers, []string{"u2"})
+
+	candidates, err := repo.GetReviewerCandidatesByIDs(ctx, []string{"u2"})
+	noError(t, err)
+	equal(t, "draft open reviews", candidates[0].OpenReviews, 0)
+
+	wantError(t, repo.MarkDraft(ctx, "pr-1"), errors.ErrPRNotFound)
+	wantError(t, repo.MarkDraft(ctx, "missing"), errors.ErrPRNotFound)
+	noError(t, repo.ClosePR(ctx, "pr-2", now()))
+	wantError(t, repo.MarkDraft(ctx, "pr-2"), errors.ErrPRNotFound)
+
+	// назначенные ревьюверы не назначаются повторно при готовности
+	noError(t, repo.MarkReadyForReview(ctx, "pr-1", []string{"u3"}))
+	equalStrings(t, "ready reviewers", getPR(ctx, t, repo, "pr-1").AssignedReviewers, []string{"u2", "u3"})
+}
+
+func testSubmitReview(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2", "u3")
+	seedPR(ctx, t, repo, "pr-1", "u1", "u2", "u3")
+
+	reviewedAt := now()
+	noError(t, repo.SubmitReview(ctx, "pr-1", "u2", models.ReviewStateChangesRequested, reviewedAt))
+	noError(t, repo.SubmitReview(ctx, "pr-1", "u2", models.ReviewStateApproved, reviewedAt.Add(time.Minute)))
+
+	pr := getPR(ctx, t, repo, "pr-1")
+	review := findReview(t, pr, "u2")
+	equal(t, "state", review.State, models.ReviewStateApproved)
+	equalTime(t, "reviewed at", review.ReviewedAt, reviewedAt.Add(time.Minute))
+	equal(t, "other state", findReview(t, pr, "u3").State, models.ReviewStatePending)
+
+	wantError(t, repo.SubmitReview(ctx, "pr-1", "u1", models.ReviewStateApproved, reviewedAt), errors.ErrNotAssigned)
+	wantError(t, repo.SubmitReview(ctx, "missing", "u2", models.ReviewStateApproved, reviewedAt), errors.ErrNotAssigned)
+}
+
+func testReassignReviewer(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2", "u3", "u4")
+	seedPR(ctx, t, repo, "pr-1", "u1", "u2", "u3")
+	noError(t, repo.SubmitReview(ctx, "pr-1", "u2", models.ReviewStateCommented, now()))
+
+	noError(t, repo.ReassignReviewer(ctx, "pr-1", "u2", "u4", models.ReasonManual))
+	pr := getPR(ctx, t, repo, "pr-1")
+	equalStrings(t, "reviewers", pr.AssignedReviewers, []string{"u3", "u4"})
+	// новый ревьювер начинает с чистого листа
+	review := findReview(t, pr, "u4")
+	equal(t, "state", review.State, models.ReviewStatePending)
+	equal(t, "reviewed at", review.ReviewedAt == nil, true)
+
+	wantError(t, repo.ReassignReviewer(ctx, "pr-1", "u2", "u1", models.ReasonManual), errors.ErrNotAssigned)
+	wantError(t, repo.ReassignReviewer(ctx, "pr-1", "u1", "u2", models.ReasonManual), errors.ErrNotAssigned)
+	wantError(t, repo.ReassignReviewer(ctx, "missing", "u3", "u2", models.ReasonManual), errors.ErrPRNotFound)
+
+	// замена на уже назначенного не снимает прежнего
+	wantError(t, repo.ReassignReviewer(ctx, "pr-1", "u3", "u4", models.ReasonManual), errors.ErrReviewerUnavailable)
+	equalStrings(t, "reviewers after failure", getPR(ctx, t, repo, "pr-1").AssignedReviewers, []string{"u3", "u4"})
+
+	seedPR(ctx, t, repo, "pr-2", "u1", "u2")
+	noError(t, repo.MergePR(ctx, "pr-2", now(), nil))
+	wantError(t, repo.ReassignReviewer(ctx, "pr-2", "u2", "u3", models.ReasonManual), errors.ErrPRMerged)
+	seedPR(ctx, t, repo, "pr-3", "u1", "u2")
+	noError(t, repo.ClosePR(ctx, "pr-3", now()))
+	wantError(t, repo.ReassignReviewer(ctx, "pr-3", "u2", "u3", models.ReasonManual), errors.ErrPRClosed)
+}
+
+// testUnavailableReviewers: ревьюверов могли деактивировать между выбором в сервисе и записью.
+func testUnavailableReviewers(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2", "u3", "u4")
+	noError(t, repo.SetUserActive(ctx, "u3", false))
+	at := now()
+	noError(t, repo.AddAbsence(ctx, &models.Absence{StartsAt: at.Add(-time.Hour), EndsAt: at.Add(time.Hour), UserID: "u4"}))
+
+	for _, reviewerID := range []string{"u3", "u4", "missing"} {
+		err := repo.CreatePR(ctx, &models.PullRequest{
+			PullRequestShort:  models.PullRequestShort{ID: "pr-1", Name: "PR", AuthorID: "u1"},
+			AssignedReviewers: []string{"u2", reviewerID},
+		})
+		wantError(t, err, errors.ErrReviewerUnavailable)
+	}
+	_, err := repo.GetPRByID(ctx, "pr-1")
+	wantError(t, err, errors.ErrPRNotFound)
+
+	seedDraft(ctx, t, repo, "pr-2", "u1")
+	wantError(t, repo.MarkReadyForReview(ctx, "pr-2", []string{"u2", "u3"}), errors.ErrReviewerUnavailable)
+	equal(t, "draft after failure", getPR(ctx, t, repo, "pr-2").IsDraft, true)
+
+	seedPR(ctx, t, repo, "pr-3", "u1", "u2")
+	wantError(t, repo.ReassignReviewer(ctx, "pr-3", "u2", "u4", models.ReasonManual), errors.ErrReviewerUnavailable)
+	equalStrings(t, "reviewers after reassign", getPR(ctx, t, repo, "pr-3").AssignedReviewers, []string{"u2"})
+
+	noError(t, repo.ClosePR(ctx, "pr-3", now()))
+	wantError(t, repo.ReopenPR(ctx, "pr-3", []string{"u2"}, []string{"u3"}), errors.ErrReviewerUnavailable)
+	pr := getPR(ctx, t, repo, "pr-3")
+	equal(t, "status after reopen", pr.Status, models.PRStatusClosed)
+	equalStrings(t, "reviewers after reopen", pr.AssignedReviewers, []string{"u2"})
+}
+
+// testReviewersAtCapacity: параллельное назначение могло довести ревьювера до лимита после выбора.
+func testReviewersAtCapacity(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2", "u3", "u4")
+	noError(t, repo.SetTeamMaxOpenReviews(ctx, "backend", intPtr(1)))
+	noError(t, repo.SetUserMaxOpenReviews(ctx, "u4", intPtr(2)))
+	seedPR(ctx, t, repo, "pr-1", "u1", "u2", "u4")
+
+	err := repo.CreatePR(ctx, &models.PullRequest{
+		PullRequestShort:  models.PullRequestShort{ID: "pr-2", Name: "PR", AuthorID: "u1"},
+		AssignedReviewers: []string{"u2", "u3"},
+	})
+	wantError(t, err, errors.ErrReviewerUnavailable)
+
+	// личный лимит u4 выше командного
+	seedPR(ctx, t, repo, "pr-2", "u1", "u3", "u4")
+	wantError(t, repo.ReassignReviewer(ctx, "pr-2", "u3", "u2", models.ReasonManual), errors.ErrReviewerUnavailable)
+
+	seedDraft(ctx, t, repo, "pr-3", "u1")
+	wantError(t, repo.MarkReadyForReview(ctx, "pr-3", []string{"u4"}), errors.ErrReviewerUnavailable)
+
+	// влитый PR освобождает место
+	noError(t, repo.MergePR(ctx, "pr-1", now(), nil))
+	noError(t, repo.MarkReadyForReview(ctx, "pr-3", []string{"u2"}))
+	equalStrings(t, "reviewers", getPR(ctx, t, repo, "pr-3").AssignedReviewers, []string{"u2"})
+}
+
+func testReviewerCandidates(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u3", "u1", "u2", "u4")
+	seedTeam(ctx, t, repo, "frontend", "u5")
+	noError(t, repo.SetUserActive(ctx, "u4", false))
+	noError(t, repo.SetTeamMaxOpenReviews(ctx, "backend", intPtr(3)))
+
+	seedPR(ctx, t, repo, "pr-1", "u5", "u1", "u2")
+	seedPR(ctx, t, repo, "pr-2", "u5", "u1")
+	seedPR(ctx, t, repo, "pr-3", "u5", "u2")
+	noError(t, repo.MergePR(ctx, "pr-3", now(), nil))
+	noError(t, repo.SetUserMaxOpenReviews(ctx, "u2", intPtr(1)))
+	seedDraft(ctx, t, repo, "pr-4", "u5")
+	noError(t, repo.MarkReadyForReview(ctx, "pr-4", []string{"u1"}))
+	noError(t, repo.ClosePR(ctx, "pr-4", now()))
+
+	candidates, err := repo.GetReviewerCandidates(ctx, "backend")
+	noError(t, err)
+	equalStrings(t, "candidates", candidateIDs(candidates), []string{"u1", "u2", "u3"})
+
+	// открытые ревью считаются только по открытым PR, последнее назначение - по всем
+	u1, u2, u3 := candidates[0], candidates[1], candidates[2]
+	equal(t, "u1 open reviews", u1.OpenReviews, 2)
+	equal(t, "u2 open reviews", u2.OpenReviews, 1)
+	equal(t, "u3 open reviews", u3.OpenReviews, 0)
+	pr4 := getPR(ctx, t, repo, "pr-4")
+	equalTime(t, "u1 last assigned", u1.LastAssignedAt, *findReview(t, pr4, "u1").AssignedAt)
+	pr3 := getPR(ctx, t, repo, "pr-3")
+	equalTime(t, "u2 last assigned", u2.LastAssignedAt, *findReview(t, pr3, "u2").AssignedAt)
+	equal(t, "u3 last assigned", u3.LastAssignedAt == nil, true)
+
+	// личный лимит важнее командного
+	equalIntPtr(t, "u1 limit", u1.MaxOpenReviews, intPtr(3))
+	equalIntPtr(t, "u2 limit", u2.MaxOpenReviews, intPtr(1))
+
+	candidates, err = repo.GetReviewerCandidates(ctx, "missing")
+	noError(t, err)
+	equal(t, "missing team candidates", len(candidates), 0)
+}
+
+func testReviewerCandidatesByIDs(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2")
+	seedTeam(ctx, t, repo, "frontend", "u3", "u4")
+	noError(t, repo.SetUserActive(ctx, "u4", false))
+	noError(t, repo.SetTeamMaxOpenReviews(ctx, "frontend", intPtr(2)))
+	seedDraft(ctx, t, repo, "pr-1", "u1")
+	noError(t, repo.MarkReadyForReview(ctx, "pr-1", []string{"u3"}))
+
+	candidates, err := repo.GetReviewerCandidatesByIDs(ctx, []string{"u3", "missing", "u4", "u2", "u3"})
+	noError(t, err)
+	equalStrings(t, "candidates", candidateIDs(candidates), []string{"u2", "u3"})
+	equal(t, "u3 open reviews", candidates[1].OpenReviews, 1)
+	equalIntPtr(t, "u3 limit", candidates[1].MaxOpenReviews, intPtr(2))
+	equalIntPtr(t, "u2 limit", candidates[0].MaxOpenReviews, nil)
+
+	candidates, err = repo.GetReviewerCandidatesByIDs(ctx, nil)
+	noError(t, err)
+	equal(t, "no ids", len(candidates), 0)
+}
@@ -0,0 +1,221 @@
+// Package repotest - общий набор проверок хранилища. Каждый бэкенд прогоняет его
+// против себя, чтобы реализации не расходились в поведении и ошибках.
+//
+// Вызывается из тестов бэкенда во внешнем пакете, например sqlite_test:
+//
+//	func TestRepository(t *testing.T) {
+//		repotest.Run(t, func(t *testing.T) database.Repository {
+//			repo, err := sqlite.New(t.Context(), &config.DatabaseConfig{...})
+//			...
+//			return repo
+//		})
+//	}
+package repotest
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"pr-review/internal/database"
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+// Factory открывает пустое хранилище для одной проверки и сам закрывает его по её окончании.
+type Factory func(t *testing.T) database.Repository
+
+type check struct {
+	run  func(ctx context.Context, t *testing.T, repo database.Repository)
+	name string
+}
+
+// Run прогоняет все проверки, каждую на новом хранилище из newRepo.
+func Run(t *testing.T, newRepo Factory) {
+	groups := []struct {
+		name   string
+		checks []check
+	}{
+		{"Team", teamChecks},
+		{"User", userChecks},
+		{"Absence", absenceChecks},
+		{"PullRequest", prChecks},
+		{"Event", eventChecks},
+		{"Candidate", candidateChecks},
+		{"Pool", poolChecks},
+		{"Stats", statsChecks},
+		{"Account", accountChecks},
+		{"Webhook", webhookChecks},
+		{"Sync", syncChecks},
+		{"Chat", chatChecks},
+		{"Email", emailChecks},
+		{"Concurrency", concurrencyChecks},
+	}
+
+	for _, group := range groups {
+		t.Run(group.name, func(t *testing.T) {
+			for _, c := range group.checks {
+				t.Run(c.name, func(t *testing.T) {
+					repo := newRepo(t)
+					noError(t, repo.Ping(t.Context()))
+					c.run(t.Context(), t, repo)
+				})
+			}
+		})
+	}
+}
+
+// private methods
+
+func member(userID string) models.TeamMember {
+	return models.TeamMember{UserID: userID, Username: userID + "-name", IsActive: true}
+}
+
+func seedTeam(ctx context.Context, t *testing.T, repo database.Repository, name string, userIDs ...string) {
+	t.Helper()
+
+	team := &models.Team{Name: name, RequiredReviewers: models.DefaultRequiredReviewers}
+	for _, userID := range userIDs {
+		team.Members = append(team.Members, member(userID))
+	}
+	noError(t, repo.CreateTeam(ctx, team))
+}
+
+func seedPR(ctx context.Context, t *testing.T, repo database.Repository, id, authorID string, reviewers ...string) {
+	t.Helper()
+
+	pr := &models.PullRequest{
+		PullRequestShort:  models.PullRequestShort{ID: id, Name: "PR " + id, AuthorID: authorID},
+		AssignedReviewers: reviewers,
+	}
+	noError(t, repo.CreatePR(ctx, pr))
+}
+
+func seedDraft(ctx context.Context, t *testing.T, repo database.Repository, id, authorID string) {
+	t.Helper()
+
+	pr := &models.PullRequest{
+		PullRequestShort: models.PullRequestShort{ID: id, Name: "PR " + id, AuthorID: authorID, IsDraft: true},
+	}
+	noError(t, repo.CreatePR(ctx, pr))
+}
+
+func getPR(ctx context.Context, t *testing.T, repo database.Repository, id string) *models.PullRequest {
+	t.Helper()
+
+	pr, err := repo.GetPRByID(ctx, id)
+	noError(t, err)
+	return pr
+}
+
+func getUser(ctx context.Context, t *testing.T, repo database.Repository, id string) *models.User {
+	t.Helper()
+
+	user, err := repo.GetUserByID(ctx, id)
+	noError(t, err)
+	return user
+}
+
+func getTeam(ctx context.Context, t *testing.T, repo database.Repository, name string) *models.Team {
+	t.Helper()
+
+	team, err := repo.GetTeamByName(ctx, name)
+	noError(t, err)
+	return team
+}
+
+func memberIDs(team *models.Team) []string {
+	ids := make([]string, 0, len(team.Members))
+	for _, m := range team.Members {
+		ids = append(ids, m.UserID)
+	}
+	return ids
+}
+
+func candidateIDs(candidates []models.ReviewerCandidate) []string {
+	ids := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		ids = append(ids, candidate.UserID)
+	}
+	return ids
+}
+
+func findReview(t *testing.T, pr *models.PullRequest, userID string) models.Review {
+	t.Helper()
+
+	for _, review := range pr.Reviews {
+		if review.UserID == userID {
+			return review
+		}
+	}
+	t.Fatalf("PR %s: no review of %s in %v", pr.ID, userID, pr.AssignedReviewers)
+	return models.Review{}
+}
+
+// now возвращает время с точностью Postgres, чтобы сохранённое значение совпадало с исходным.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func noError(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func wantError(t *testing.T, err, target error) {
+	t.Helper()
+
+	if !errors.Is(err, target) {
+		t.Fatalf("got error %v, want %v", err, target)
+	}
+}
+
+// wantAnyError - для нарушений ограничений, которые бэкенды сообщают своими ошибками.
+func wantAnyError(t *testing.T, err error) {
+	t.Helper()
+
+	if err == nil {
+		t.Fatal("got no error, want one")
+	}
+}
+
+func equal[T comparable](t *testing.T, what string, got, want T) {
+	t.Helper()
+
+	if got != want {
+		t.Fatalf("%s: got %v, want %v", what, got, want)
+	}
+}
+
+// equalStrings не различает nil и пустой список.
+func equalStrings(t *testing.T, what string, got, want []string) {
+	t.Helper()
+
+	if !slices.Equal(got, want) {
+		t.Fatalf("%s: got %v, want %v", what, got, want)
+	}
+}
+
+func equalTime(t *testing.T, what string, got *time.Time, want time.Time) {
+	t.Helper()
+
+	if got == nil || !got.Equal(want) {
+		t.Fatalf("%s: got %v, want %v", what, got, want)
+	}
+}
+
+func equalIntPtr(t *testing.T, what string, got, want *int) {
+	t.Helper()
+
+	if (got == nil) != (want == nil) || (got != nil && *got != *want) {
+		t.Fatalf("%s: got %v, want %v", what, got, want)
+	}
+}
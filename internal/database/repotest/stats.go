@@ -0,0 +1,94 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+
+	"pr-review/internal/database"
+	"pr-review/internal/models"
+)
+
+var statsChecks = []check{
+	{name: "EmptyDatabase", run: testEmptyStats},
+	{name: "Total", run: testTotalStats},
+	{name: "Team", run: testTeamStats},
+}
+
+func testEmptyStats(ctx context.Context, t *testing.T, repo database.Repository) {
+	stats, err := repo.GetTotalStats(ctx)
+	noError(t, err)
+	equal(t, "stats", *stats, models.TotalStats{})
+}
+
+func testTotalStats(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2", "u3")
+	seedTeam(ctx, t, repo, "frontend", "u4")
+	seedPR(ctx, t, repo, "pr-1", "u1", "u2", "u3")
+	noError(t, repo.SetUserActive(ctx, "u3", false))
+	seedPR(ctx, t, repo, "pr-2", "u1", "u2")
+	seedPR(ctx, t, repo, "pr-3", "u4", "u1")
+	seedDraft(ctx, t, repo, "pr-4", "u4")
+	seedPR(ctx, t, repo, "pr-5", "u2")
+	seedPR(ctx, t, repo, "pr-6", "u2", "u1")
+	noError(t, repo.MergePR(ctx, "pr-2", now(), nil))
+	noError(t, repo.MergePR(ctx, "pr-3", now(), nil))
+	noError(t, repo.ClosePR(ctx, "pr-5", now()))
+
+	stats, err := repo.GetTotalStats(ctx)
+	noError(t, err)
+	equal(t, "stats", *stats, models.TotalStats{
+		TotalTeams:  2,
+		TotalUsers:  4,
+		ActiveUsers: 3,
+		TotalPRs:    6,
+		OpenPRs:     2,
+		DraftPRs:    1,
+		MergedPRs:   2,
+		ClosedPRs:   1,
+		// 5 назначений на 6 PR
+		AvgReviewersPerPR: 0.83,
+	})
+}
+
+func testTeamStats(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2", "u3")
+	seedTeam(ctx, t, repo, "frontend", "u4")
+	seedTeam(ctx, t, repo, "idle", "u5")
+
+	seedPR(ctx, t, repo, "pr-1", "u1", "u2", "u3")
+	seedPR(ctx, t, repo, "pr-2", "u2", "u1")
+	seedDraft(ctx, t, repo, "pr-3", "u3")
+	seedPR(ctx, t, repo, "pr-4", "u4", "u1", "u2", "u3")
+	noError(t, repo.MergePR(ctx, "pr-2", now(), nil))
+
+	for teamName, want := range map[string]struct {
+		prs int
+		avg float64
+	}{
+		// 3 назначения на 3 PR
+		"backend":  {prs: 3, avg: 1},
+		"frontend": {prs: 1, avg: 3},
+		// участники без PR
+		"idle": {prs: 0, avg: 0},
+	} {
+		count, err := repo.GetPRsCntByTeam(ctx, teamName)
+		noError(t, err)
+		equal(t, teamName+" PRs", count, want.prs)
+
+		avg, err := repo.GetAvgReviewersPerPR(ctx, teamName)
+		noError(t, err)
+		equal(t, teamName+" avg reviewers", avg, want.avg)
+	}
+
+	seedPR(ctx, t, repo, "pr-5", "u1", "u2")
+	avg, err := repo.GetAvgReviewersPerPR(ctx, "backend")
+	noError(t, err)
+	// 4 назначения на 4 PR
+	equal(t, "backend avg reviewers", avg, 1)
+
+	seedPR(ctx, t, repo, "pr-6", "u1")
+	seedPR(ctx, t, repo, "pr-7", "u1")
+	avg, err = repo.GetAvgReviewersPerPR(ctx, "backend")
+	noError(t, err)
+	equal(t, "rounded avg reviewers", avg, 0.67)
+}
@@ -0,0 +1,173 @@
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"pr-review/internal/database"
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+const workers = 8
+
+var concurrencyChecks = []check{
+	{name: "CreateSameID", run: testConcurrentCreate},
+	{name: "ReassignSameReviewer", run: testConcurrentReassign},
+	{name: "CreateAtCapacity", run: testConcurrentCreateAtCapacity},
+	{name: "MergeVersusClose", run: testConcurrentMergeClose},
+	{name: "CreateVersusDeactivate", run: testConcurrentCreateDeactivate},
+}
+
+func testConcurrentCreate(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2", "u3")
+
+	errs := parallel(workers, func(i int) error {
+		return repo.CreatePR(ctx, &models.PullRequest{
+			PullRequestShort:  models.PullRequestShort{ID: "pr-1", Name: fmt.Sprintf("PR %d", i), AuthorID: "u1"},
+			AssignedReviewers: []string{"u2", "u3"},
+		})
+	})
+
+	created := 0
+	for _, err := range errs {
+		if err == nil {
+			created++
+			continue
+		}
+		wantError(t, err, errors.ErrPRExists)
+	}
+	equal(t, "created", created, 1)
+	equalStrings(t, "reviewers", getPR(ctx, t, repo, "pr-1").AssignedReviewers, []string{"u2", "u3"})
+}
+
+func testConcurrentReassign(ctx context.Context, t *testing.T, repo database.Repository) {
+	userIDs := []string{"u1", "u2"}
+	for i := range workers {
+		userIDs = append(userIDs, fmt.Sprintf("c%d", i))
+	}
+	seedTeam(ctx, t, repo, "backend", userIDs...)
+	seedPR(ctx, t, repo, "pr-1", "u1", "u2")
+
+	// все снимают одного и того же ревьювера, каждый ставит своего
+	errs := parallel(workers, func(i int) error {
+		return repo.ReassignReviewer(ctx, "pr-1", "u2", fmt.Sprintf("c%d", i), models.ReasonManual)
+	})
+
+	reassigned := 0
+	for _, err := range errs {
+		if err == nil {
+			reassigned++
+			continue
+		}
+		wantError(t, err, errors.ErrNotAssigned)
+	}
+	equal(t, "reassigned", reassigned, 1)
+	equal(t, "reviewers", len(getPR(ctx, t, repo, "pr-1").AssignedReviewers), 1)
+}
+
+func testConcurrentCreateAtCapacity(ctx context.Context, t *testing.T, repo database.Repository) {
+	const limit = 3
+	seedTeam(ctx, t, repo, "backend", "u1", "u2")
+	noError(t, repo.SetUserMaxOpenReviews(ctx, "u2", intPtr(limit)))
+
+	errs := parallel(workers, func(i int) error {
+		return repo.CreatePR(ctx, &models.PullRequest{
+			PullRequestShort:  models.PullRequestShort{ID: fmt.Sprintf("pr-%d", i), Name: "PR", AuthorID: "u1"},
+			AssignedReviewers: []string{"u2"},
+		})
+	})
+
+	created := 0
+	for _, err := range errs {
+		if err == nil {
+			created++
+			continue
+		}
+		wantError(t, err, errors.ErrReviewerUnavailable)
+	}
+	equal(t, "created", created, limit)
+}
+
+func testConcurrentMergeClose(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2")
+
+	for i := range workers {
+		prID := fmt.Sprintf("pr-%d", i)
+		seedPR(ctx, t, repo, prID, "u1", "u2")
+
+		errs := parallel(2, func(j int) error {
+			if j == 0 {
+				return repo.MergePR(ctx, prID, now(), nil)
+			}
+			return repo.ClosePR(ctx, prID, now())
+		})
+
+		pr := getPR(ctx, t, repo, prID)
+		switch pr.Status {
+		case models.PRStatusMerged:
+			noError(t, errs[0])
+			wantError(t, errs[1], errors.ErrPRNotFound)
+			equal(t, "closed at", pr.ClosedAt == nil, true)
+		case models.PRStatusClosed:
+			noError(t, errs[1])
+			wantError(t, errs[0], errors.ErrPRClosed)
+			equal(t, "merged at", pr.MergedAt == nil, true)
+		default:
+			t.Fatalf("%s: status %s after merge and close", prID, pr.Status)
+		}
+	}
+}
+
+func testConcurrentCreateDeactivate(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2")
+
+	var deactivated atomic.Bool
+	errs := parallel(workers+1, func(i int) error {
+		if i == workers {
+			err := repo.DeactivateUsers(ctx, []string{"u2"}, nil)
+			deactivated.Store(true)
+			return err
+		}
+
+		startedAfter := deactivated.Load()
+		err := repo.CreatePR(ctx, &models.PullRequest{
+			PullRequestShort:  models.PullRequestShort{ID: fmt.Sprintf("pr-%d", i), Name: "PR", AuthorID: "u1"},
+			AssignedReviewers: []string{"u2"},
+		})
+		if startedAfter && !errors.Is(err, errors.ErrReviewerUnavailable) {
+			return fmt.Errorf("created after deactivation: %w", err)
+		}
+		if err != nil && !errors.Is(err, errors.ErrReviewerUnavailable) {
+			return err
+		}
+		return nil
+	})
+
+	for _, err := range errs {
+		noError(t, err)
+	}
+	equal(t, "active", getUser(ctx, t, repo, "u2").IsActive, false)
+}
+
+func parallel(n int, fn func(i int) error) []error {
+	errs := make([]error, n)
+	start := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			errs[i] = fn(i)
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	return errs
+}
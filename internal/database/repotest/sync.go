@@ -0,0 +1,174 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"pr-review/internal/database"
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+var syncChecks = []check{
+	{name: "EnqueueAndList", run: testSyncEnqueueList},
+	{name: "ClaimSaveRetry", run: testSyncClaimSaveRetry},
+	{name: "ClaimPerPROrder", run: testSyncClaimPerPROrder},
+	{name: "UserLogin", run: testSyncUserLogin},
+}
+
+func testSyncEnqueueList(ctx context.Context, t *testing.T, repo database.Repository) {
+	at := now()
+	noError(t, repo.EnqueueSyncTasks(ctx, []models.SyncTask{
+		syncTask(at, models.SyncRemoveReviewer, "u1", ""),
+		syncTask(at, models.SyncRequestReviewer, "u2", ""),
+		syncTask(at, models.SyncComment, "", "replaced"),
+	}))
+
+	tasks, err := repo.GetSyncTasks(ctx, "", 10)
+	noError(t, err)
+	equal(t, "tasks", len(tasks), 3)
+	// новые первыми
+	equal(t, "latest action", tasks[0].Action, models.SyncComment)
+	equal(t, "body", tasks[0].Body, "replaced")
+	equal(t, "comment user", tasks[0].UserID, "")
+	equal(t, "first action", tasks[2].Action, models.SyncRemoveReviewer)
+	equal(t, "user", tasks[2].UserID, "u1")
+	equal(t, "pr", tasks[2].PullRequestID, "acme/api#1")
+	equal(t, "provider", tasks[2].Provider, models.ProviderGitHub)
+	equal(t, "status", tasks[2].Status, models.SyncPending)
+	equal(t, "attempts", tasks[2].Attempts, 0)
+	equalTime(t, "next attempt", &tasks[2].NextAttemptAt, at)
+	equal(t, "completed at", tasks[2].CompletedAt == nil, true)
+
+	tasks, err = repo.GetSyncTasks(ctx, models.SyncFailed, 10)
+	noError(t, err)
+	equal(t, "failed", len(tasks), 0)
+
+	tasks, err = repo.GetSyncTasks(ctx, models.SyncPending, 1)
+	noError(t, err)
+	equal(t, "limited", len(tasks), 1)
+}
+
+func testSyncClaimSaveRetry(ctx context.Context, t *testing.T, repo database.Repository) {
+	at := now()
+	noError(t, repo.EnqueueSyncTasks(ctx, []models.SyncTask{syncTask(at, models.SyncRequestReviewer, "u1", "")}))
+	noError(t, repo.EnqueueSyncTasks(ctx, []models.SyncTask{syncTask(at.Add(time.Hour), models.SyncRequestReviewer, "u2", "")}))
+
+	tasks, err := repo.ClaimDueSyncTasks(ctx, at, time.Minute, 10)
+	noError(t, err)
+	equal(t, "due", len(tasks), 1)
+	claimed := tasks[0]
+	equal(t, "claimed user", claimed.UserID, "u1")
+
+	// выданная задача не выдаётся повторно, пока не истёк lease
+	tasks, err = repo.ClaimDueSyncTasks(ctx, at.Add(30*time.Second), time.Minute, 10)
+	noError(t, err)
+	equal(t, "leased", len(tasks), 0)
+
+	claimed.Status = models.SyncFailed
+	claimed.Attempts = 1
+	claimed.LastError = "reviewer u1: account not linked"
+	noError(t, repo.SaveSyncAttempt(ctx, &claimed))
+
+	tasks, err = repo.ClaimDueSyncTasks(ctx, at.Add(2*time.Hour), time.Minute, 10)
+	noError(t, err)
+	equal(t, "later task", len(tasks), 1)
+	equal(t, "later user", tasks[0].UserID, "u2")
+
+	completedAt := now()
+	done := tasks[0]
+	done.Status = models.SyncDone
+	done.Attempts = 1
+	done.CompletedAt = &completedAt
+	noError(t, repo.SaveSyncAttempt(ctx, &done))
+
+	failed, err := repo.GetSyncTasks(ctx, models.SyncFailed, 10)
+	noError(t, err)
+	equal(t, "failed", len(failed), 1)
+	equal(t, "failed error", failed[0].LastError, "reviewer u1: account not linked")
+
+	saved, err := repo.GetSyncTasks(ctx, models.SyncDone, 10)
+	noError(t, err)
+	equal(t, "done", len(saved), 1)
+	equalTime(t, "completed at", saved[0].CompletedAt, completedAt)
+
+	// повторить можно только неудавшуюся задачу
+	wantError(t, repo.RetrySyncTask(ctx, done.ID, at), errors.ErrSyncTaskNotFound)
+	wantError(t, repo.RetrySyncTask(ctx, failed[0].ID+100, at), errors.ErrSyncTaskNotFound)
+
+	retryAt := at.Add(3 * time.Hour)
+	noError(t, repo.RetrySyncTask(ctx, failed[0].ID, retryAt))
+	wantError(t, repo.RetrySyncTask(ctx, failed[0].ID, retryAt), errors.ErrSyncTaskNotFound)
+
+	tasks, err = repo.ClaimDueSyncTasks(ctx, retryAt, time.Minute, 10)
+	noError(t, err)
+	equal(t, "retried", len(tasks), 1)
+	equal(t, "retried id", tasks[0].ID, failed[0].ID)
+	equal(t, "retried attempts", tasks[0].Attempts, 0)
+}
+
+func testSyncClaimPerPROrder(ctx context.Context, t *testing.T, repo database.Repository) {
+	at := now()
+	other := syncTask(at, models.SyncRequestReviewer, "u3", "")
+	other.PullRequestID = "acme/api#2"
+	noError(t, repo.EnqueueSyncTasks(ctx, []models.SyncTask{
+		syncTask(at, models.SyncRemoveReviewer, "u1", ""),
+		syncTask(at, models.SyncRequestReviewer, "u2", ""),
+		other,
+	}))
+
+	// вторая задача PR ждёт первую, задачи других PR не ждут
+	tasks, err := repo.ClaimDueSyncTasks(ctx, at, time.Minute, 10)
+	noError(t, err)
+	equal(t, "due", len(tasks), 2)
+	equal(t, "first pr", tasks[0].UserID, "u1")
+	equal(t, "other pr", tasks[1].UserID, "u3")
+	first := tasks[0]
+
+	// и после истечения lease выдаётся снова первая задача
+	tasks, err = repo.ClaimDueSyncTasks(ctx, at.Add(2*time.Minute), time.Minute, 10)
+	noError(t, err)
+	equal(t, "expired", len(tasks), 2)
+	equal(t, "reclaimed", tasks[0].ID, first.ID)
+
+	completedAt := now()
+	first.Status = models.SyncDone
+	first.Attempts = 1
+	first.CompletedAt = &completedAt
+	noError(t, repo.SaveSyncAttempt(ctx, &first))
+
+	tasks, err = repo.ClaimDueSyncTasks(ctx, at.Add(4*time.Minute), time.Minute, 10)
+	noError(t, err)
+	equal(t, "after first", len(tasks), 2)
+	equal(t, "second", tasks[0].UserID, "u2")
+}
+
+func testSyncUserLogin(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1")
+	noError(t, linkAccount(ctx, repo, "u1", "alice"))
+
+	login, err := repo.GetUserLogin(ctx, models.ProviderGitHub, "u1")
+	noError(t, err)
+	equal(t, "login", login, "alice")
+
+	_, err = repo.GetUserLogin(ctx, models.ProviderGitLab, "u1")
+	wantError(t, err, errors.ErrAccountNotLinked)
+	_, err = repo.GetUserLogin(ctx, models.ProviderGitHub, "missing")
+	wantError(t, err, errors.ErrAccountNotLinked)
+}
+
+// private methods
+
+func syncTask(at time.Time, action, userID, body string) models.SyncTask {
+	return models.SyncTask{
+		CreatedAt:     at,
+		NextAttemptAt: at,
+		PullRequestID: "acme/api#1",
+		Provider:      models.ProviderGitHub,
+		Action:        action,
+		UserID:        userID,
+		Body:          body,
+		Status:        models.SyncPending,
+	}
+}
@@ -0,0 +1,389 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"pr-review/internal/database"
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+var teamChecks = []check{
+	{name: "CreateAndGet", run: testCreateTeam},
+	{name: "CreateDuplicate", run: testCreateTeamDuplicate},
+	{name: "GetMissing", run: testGetMissingTeam},
+	{name: "Empty", run: testEmptyTeam},
+	{name: "AddMember", run: testAddTeamMember},
+	{name: "RemoveMember", run: testRemoveTeamMember},
+	{name: "RemoveMemberReassign", run: testRemoveTeamMemberReassign},
+	{name: "Rename", run: testRenameTeam},
+	{name: "OpenPRsAndHistory", run: testTeamOpenPRs},
+	{name: "Archive", run: testArchiveTeam},
+	{name: "Delete", run: testDeleteTeam},
+	{name: "Settings", run: testTeamSettings},
+	{name: "Fallbacks", run: testTeamFallbacks},
+	{name: "CodeOwners", run: testTeamCodeOwners},
+}
+
+func testCreateTeam(ctx context.Context, t *testing.T, repo database.Repository) {
+	team := &models.Team{
+		MaxOpenReviews:    intPtr(3),
+		Name:              "backend",
+		Members:           []models.TeamMember{member("u2"), member("u1"), {UserID: "u3", Username: "a-first"}},
+		RequiredReviewers: 1,
+		MergePolicy:       models.MergePolicy{RequiredApprovals: 2, BlockOnChangesRequested: true},
+	}
+	noError(t, repo.CreateTeam(ctx, team))
+
+	got := getTeam(ctx, t, repo, "backend")
+	equal(t, "name", got.Name, "backend")
+	equal(t, "required reviewers", got.RequiredReviewers, 1)
+	equal(t, "merge policy", got.MergePolicy, team.MergePolicy)
+	equalIntPtr(t, "max open reviews", got.MaxOpenReviews, intPtr(3))
+	equal(t, "archived", got.ArchivedAt == nil, true)
+	equal(t, "fallbacks", len(got.Fallbacks), 0)
+	// участники упорядочены по username
+	equalStrings(t, "members", memberIDs(got), []string{"u3", "u1", "u2"})
+	equal(t, "inactive member", got.Members[0].IsActive, false)
+
+	user := getUser(ctx, t, repo, "u1")
+	equal(t, "user team", user.TeamName, "backend")
+	equal(t, "username", user.Username, "u1-name")
+	equal(t, "user active", user.IsActive, true)
+	equalIntPtr(t, "user limit", user.MaxOpenReviews, nil)
+}
+
+func testCreateTeamDuplicate(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1")
+
+	err := repo.CreateTeam(ctx, &models.Team{Name: "backend", Members: []models.TeamMember{member("u2")}})
+	wantError(t, err, errors.ErrTeamExists)
+
+	err = repo.CreateTeam(ctx, &models.Team{Name: "frontend", Members: []models.TeamMember{member("u2"), member("u1")}})
+	wantError(t, err, errors.ErrUserExists)
+
+	taken := models.TeamMember{UserID: "u3", Username: "u1-name", IsActive: true}
+	err = repo.CreateTeam(ctx, &models.Team{Name: "frontend", Members: []models.TeamMember{taken}})
+	wantError(t, err, errors.ErrUserExists)
+
+	// неудачное создание не оставляет ни команды, ни участников
+	_, err = repo.GetTeamByName(ctx, "frontend")
+	wantError(t, err, errors.ErrTeamNotFound)
+	_, err = repo.GetUserByID(ctx, "u2")
+	wantError(t, err, errors.ErrUserNotFound)
+	equalStrings(t, "members", memberIDs(getTeam(ctx, t, repo, "backend")), []string{"u1"})
+}
+
+func testGetMissingTeam(ctx context.Context, t *testing.T, repo database.Repository) {
+	_, err := repo.GetTeamByName(ctx, "missing")
+	wantError(t, err, errors.ErrTeamNotFound)
+
+	_, err = repo.GetPRsCntByTeam(ctx, "missing")
+	wantError(t, err, errors.ErrTeamNotFound)
+
+	_, err = repo.GetAvgReviewersPerPR(ctx, "missing")
+	wantError(t, err, errors.ErrTeamNotFound)
+
+	fallbacks, err := repo.GetTeamFallbacks(ctx, "missing")
+	noError(t, err)
+	equal(t, "fallbacks", len(fallbacks), 0)
+
+	prIDs, err := repo.GetOpenPRIDsByTeam(ctx, "missing")
+	noError(t, err)
+	equalStrings(t, "open PRs", prIDs, nil)
+}
+
+func testEmptyTeam(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "empty")
+
+	team := getTeam(ctx, t, repo, "empty")
+	equal(t, "members", len(team.Members), 0)
+
+	count, err := repo.GetPRsCntByTeam(ctx, "empty")
+	noError(t, err)
+	equal(t, "PRs", count, 0)
+
+	avg, err := repo.GetAvgReviewersPerPR(ctx, "empty")
+	noError(t, err)
+	equal(t, "avg reviewers", avg, 0)
+
+	hasHistory, err := repo.TeamHasHistory(ctx, "empty")
+	noError(t, err)
+	equal(t, "history", hasHistory, false)
+
+	candidates, err := repo.GetReviewerCandidates(ctx, "empty")
+	noError(t, err)
+	equal(t, "candidates", len(candidates), 0)
+
+	noError(t, repo.DeleteTeam(ctx, "empty"))
+	_, err = repo.GetTeamByName(ctx, "empty")
+	wantError(t, err, errors.ErrTeamNotFound)
+}
+
+func testAddTeamMember(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1")
+	seedTeam(ctx, t, repo, "frontend", "u2")
+
+	newcomer := member("u3")
+	noError(t, repo.AddTeamMember(ctx, "backend", &newcomer))
+	equal(t, "team", getUser(ctx, t, repo, "u3").TeamName, "backend")
+
+	wantError(t, repo.AddTeamMember(ctx, "missing", &models.TeamMember{UserID: "u4", Username: "u4"}), errors.ErrTeamNotFound)
+
+	// пользователь другой команды и чужой username
+	other := member("u2")
+	wantError(t, repo.AddTeamMember(ctx, "backend", &other), errors.ErrUserExists)
+	taken := models.TeamMember{UserID: "u4", Username: "u1-name"}
+	wantError(t, repo.AddTeamMember(ctx, "backend", &taken), errors.ErrUserExists)
+
+	// исключённый из команды возвращается с новыми данными
+	noError(t, repo.RemoveTeamMember(ctx, "frontend", "u2", nil))
+	returned := models.TeamMember{UserID: "u2", Username: "u2-renamed", IsActive: false}
+	noError(t, repo.AddTeamMember(ctx, "backend", &returned))
+
+	user := getUser(ctx, t, repo, "u2")
+	equal(t, "team", user.TeamName, "backend")
+	equal(t, "username", user.Username, "u2-renamed")
+	equal(t, "active", user.IsActive, false)
+	equalStrings(t, "members", memberIDs(getTeam(ctx, t, repo, "backend")), []string{"u1", "u2", "u3"})
+}
+
+func testRemoveTeamMember(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2")
+	seedTeam(ctx, t, repo, "frontend", "u3")
+	seedPR(ctx, t, repo, "pr-1", "u1", "u2")
+
+	wantError(t, repo.RemoveTeamMember(ctx, "missing", "u1", nil), errors.ErrTeamNotFound)
+	wantError(t, repo.RemoveTeamMember(ctx, "backend", "missing", nil), errors.ErrUserNotFound)
+	wantError(t, repo.RemoveTeamMember(ctx, "backend", "u3", nil), errors.ErrNotMember)
+
+	noError(t, repo.RemoveTeamMember(ctx, "backend", "u2", nil))
+	equal(t, "team", getUser(ctx, t, repo, "u2").TeamName, "")
+	equalStrings(t, "members", memberIDs(getTeam(ctx, t, repo, "backend")), []string{"u1"})
+	// история ревью остаётся
+	equalStrings(t, "reviewers", getPR(ctx, t, repo, "pr-1").AssignedReviewers, []string{"u2"})
+
+	wantError(t, repo.RemoveTeamMember(ctx, "backend", "u2", nil), errors.ErrNotMember)
+}
+
+func testRemoveTeamMemberReassign(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2", "u3", "u4")
+	seedPR(ctx, t, repo, "pr-1", "u1", "u2", "u3")
+	seedPR(ctx, t, repo, "pr-2", "u1", "u2")
+
+	// устаревший план отклоняется целиком: участник остаётся в команде
+	err := repo.RemoveTeamMember(ctx, "backend", "u2", []models.Reassignment{
+		{PullRequestID: "pr-2", OldUserID: "u2", NewUserID: "u4"},
+		{PullRequestID: "pr-1", OldUserID: "u2", NewUserID: "u3"},
+	})
+	wantError(t, err, errors.ErrReviewerUnavailable)
+	equal(t, "team after stale plan", getUser(ctx, t, repo, "u2").TeamName, "backend")
+	equalStrings(t, "reviewers after stale plan", getPR(ctx, t, repo, "pr-2").AssignedReviewers, []string{"u2"})
+
+	noError(t, repo.RemoveTeamMember(ctx, "backend", "u2", []models.Reassignment{
+		{PullRequestID: "pr-1", OldUserID: "u2", NewUserID: "u4"},
+		{PullRequestID: "pr-2", OldUserID: "u2", NewUserID: "u3"},
+	}))
+	equal(t, "team", getUser(ctx, t, repo, "u2").TeamName, "")
+	equalStrings(t, "pr-1 reviewers", getPR(ctx, t, repo, "pr-1").AssignedReviewers, []string{"u3", "u4"})
+	equalStrings(t, "pr-2 reviewers", getPR(ctx, t, repo, "pr-2").AssignedReviewers, []string{"u3"})
+
+	events := getEvents(ctx, t, repo, "pr-2")
+	removed := events[len(events)-2]
+	equal(t, "removed", removed.UserID, "u2")
+	equal(t, "reason", removed.Reason, models.ReasonLeftTeam)
+}
+
+func testRenameTeam(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1")
+	seedTeam(ctx, t, repo, "frontend", "u2")
+	seedTeam(ctx, t, repo, "infra")
+	noError(t, repo.SetTeamMaxOpenReviews(ctx, "backend", intPtr(4)))
+	noError(t, repo.SetTeamFallbacks(ctx, "backend", []models.ReviewerFallback{{Kind: models.FallbackTeam, Name: "infra"}}))
+	noError(t, repo.SetTeamFallbacks(ctx, "frontend", []models.ReviewerFallback{{Kind: models.FallbackTeam, Name: "backend"}}))
+	noError(t, repo.SetTeamCodeOwners(ctx, &models.CodeOwners{UpdatedAt: now(), TeamName: "backend", Content: "* @u1", Mode: models.CodeOwnersPrefer}))
+
+	wantError(t, repo.RenameTeam(ctx, "missing", "core"), errors.ErrTeamNotFound)
+	wantError(t, repo.RenameTeam(ctx, "backend", "frontend"), errors.ErrTeamExists)
+
+	noError(t, repo.RenameTeam(ctx, "backend", "core"))
+
+	_, err := repo.GetTeamByName(ctx, "backend")
+	wantError(t, err, errors.ErrTeamNotFound)
+	team := getTeam(ctx, t, repo, "core")
+	equalStrings(t, "members", memberIDs(team), []string{"u1"})
+	equalIntPtr(t, "max open reviews", team.MaxOpenReviews, intPtr(4))
+	equal(t, "own fallbacks", len(team.Fallbacks), 1)
+	equal(t, "user team", getUser(ctx, t, repo, "u1").TeamName, "core")
+
+	codeOwners, err := repo.GetTeamCodeOwners(ctx, "core")
+	noError(t, err)
+	equal(t, "code owners team", codeOwners.TeamName, "core")
+
+	// ссылки других команд переходят на новое имя
+	fallbacks, err := repo.GetTeamFallbacks(ctx, "frontend")
+	noError(t, err)
+	equal(t, "fallbacks", len(fallbacks), 1)
+	equal(t, "fallback", fallbacks[0], models.ReviewerFallback{Kind: models.FallbackTeam, Name: "core"})
+}
+
+func testTeamOpenPRs(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2")
+	seedTeam(ctx, t, repo, "frontend", "u3")
+	seedTeam(ctx, t, repo, "reviewers", "u4")
+	seedTeam(ctx, t, repo, "idle", "u5")
+	seedPR(ctx, t, repo, "pr-2", "u1")
+	seedPR(ctx, t, repo, "pr-1", "u2")
+	seedDraft(ctx, t, repo, "pr-3", "u1")
+	seedPR(ctx, t, repo, "pr-merged", "u1")
+	seedPR(ctx, t, repo, "pr-closed", "u1")
+	seedPR(ctx, t, repo, "pr-other", "u3", "u4")
+	noError(t, repo.MergePR(ctx, "pr-merged", now(), nil))
+	noError(t, repo.ClosePR(ctx, "pr-closed", now()))
+
+	prIDs, err := repo.GetOpenPRIDsByTeam(ctx, "backend")
+	noError(t, err)
+	equalStrings(t, "open PRs", prIDs, []string{"pr-1", "pr-2", "pr-3"})
+
+	for teamName, want := range map[string]bool{"backend": true, "reviewers": true, "idle": false} {
+		hasHistory, err := repo.TeamHasHistory(ctx, teamName)
+		noError(t, err)
+		equal(t, teamName+" history", hasHistory, want)
+	}
+}
+
+func testArchiveTeam(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2")
+	seedPR(ctx, t, repo, "pr-1", "u1", "u2")
+	seedPR(ctx, t, repo, "pr-2", "u1")
+	noError(t, repo.MergePR(ctx, "pr-2", now(), nil))
+
+	archivedAt := now()
+	wantError(t, repo.ArchiveTeam(ctx, "missing", archivedAt, nil), errors.ErrTeamNotFound)
+	noError(t, repo.ArchiveTeam(ctx, "backend", archivedAt, []string{"pr-1", "pr-2"}))
+
+	team := getTeam(ctx, t, repo, "backend")
+	equalTime(t, "archived at", team.ArchivedAt, archivedAt)
+	for _, m := range team.Members {
+		equal(t, m.UserID+" active", m.IsActive, false)
+	}
+
+	pr := getPR(ctx, t, repo, "pr-1")
+	equal(t, "status", pr.Status, models.PRStatusClosed)
+	equalTime(t, "closed at", pr.ClosedAt, archivedAt)
+	// влитый PR не закрывается
+	equal(t, "merged status", getPR(ctx, t, repo, "pr-2").Status, models.PRStatusMerged)
+}
+
+func testDeleteTeam(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2")
+	seedTeam(ctx, t, repo, "frontend", "u3")
+	noError(t, repo.CreateReviewerPool(ctx, &models.ReviewerPool{Name: "shared", Members: []string{"u1", "u3"}}))
+	noError(t, repo.SetTeamFallbacks(ctx, "frontend", []models.ReviewerFallback{
+		{Kind: models.FallbackTeam, Name: "backend"},
+		{Kind: models.FallbackPool, Name: "shared"},
+	}))
+	noError(t, repo.SetTeamCodeOwners(ctx, &models.CodeOwners{UpdatedAt: now(), TeamName: "backend", Content: "* @u1", Mode: models.CodeOwnersPrefer}))
+	at := now()
+	noError(t, repo.AddAbsence(ctx, &models.Absence{StartsAt: at.Add(-time.Hour), EndsAt: at.Add(time.Hour), UserID: "u1"}))
+	noError(t, linkAccount(ctx, repo, "u1", "alice"))
+
+	wantError(t, repo.DeleteTeam(ctx, "missing"), errors.ErrTeamNotFound)
+	noError(t, repo.DeleteTeam(ctx, "backend"))
+
+	_, err := repo.GetTeamByName(ctx, "backend")
+	wantError(t, err, errors.ErrTeamNotFound)
+	_, err = repo.GetUserByID(ctx, "u1")
+	wantError(t, err, errors.ErrUserNotFound)
+	_, err = repo.GetTeamCodeOwners(ctx, "backend")
+	wantError(t, err, errors.ErrCodeOwnersNotFound)
+
+	pool, err := repo.GetReviewerPool(ctx, "shared")
+	noError(t, err)
+	equalStrings(t, "pool members", pool.Members, []string{"u3"})
+
+	fallbacks, err := repo.GetTeamFallbacks(ctx, "frontend")
+	noError(t, err)
+	equal(t, "fallbacks", len(fallbacks), 1)
+	equal(t, "fallback", fallbacks[0].Name, "shared")
+
+	// имя освобождается, а отсутствия и учётные записи удалённых пользователей удалены каскадом
+	seedTeam(ctx, t, repo, "backend", "u1")
+	absent, err := repo.GetUsersWithStartedAbsences(ctx, at)
+	noError(t, err)
+	equalStrings(t, "absent", absent, nil)
+	_, err = repo.GetUserLogin(ctx, models.ProviderGitHub, "u1")
+	wantError(t, err, errors.ErrAccountNotLinked)
+}
+
+func testTeamSettings(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1")
+
+	policy := &models.MergePolicy{RequiredApprovals: 1, ForbidSelfApproval: true, RequireOwnerApproval: true}
+	noError(t, repo.SetTeamMergePolicy(ctx, "backend", policy))
+	wantError(t, repo.SetTeamMergePolicy(ctx, "missing", policy), errors.ErrTeamNotFound)
+	equal(t, "merge policy", getTeam(ctx, t, repo, "backend").MergePolicy, *policy)
+
+	noError(t, repo.SetTeamMaxOpenReviews(ctx, "backend", intPtr(2)))
+	equalIntPtr(t, "max open reviews", getTeam(ctx, t, repo, "backend").MaxOpenReviews, intPtr(2))
+	noError(t, repo.SetTeamMaxOpenReviews(ctx, "backend", nil))
+	equalIntPtr(t, "reset max open reviews", getTeam(ctx, t, repo, "backend").MaxOpenReviews, nil)
+	wantError(t, repo.SetTeamMaxOpenReviews(ctx, "missing", intPtr(1)), errors.ErrTeamNotFound)
+}
+
+func testTeamFallbacks(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1")
+	seedTeam(ctx, t, repo, "frontend", "u2")
+	noError(t, repo.CreateReviewerPool(ctx, &models.ReviewerPool{Name: "shared", Members: []string{"u2"}}))
+
+	fallbacks := []models.ReviewerFallback{
+		{Kind: models.FallbackPool, Name: "shared"},
+		{Kind: models.FallbackTeam, Name: "frontend"},
+	}
+	noError(t, repo.SetTeamFallbacks(ctx, "backend", fallbacks))
+	wantError(t, repo.SetTeamFallbacks(ctx, "missing", fallbacks), errors.ErrTeamNotFound)
+
+	// порядок сохраняется
+	got, err := repo.GetTeamFallbacks(ctx, "backend")
+	noError(t, err)
+	equal(t, "fallbacks", len(got), 2)
+	equal(t, "first", got[0], fallbacks[0])
+	equal(t, "second", got[1], fallbacks[1])
+	equal(t, "team fallbacks", len(getTeam(ctx, t, repo, "backend").Fallbacks), 2)
+
+	// список заменяется целиком
+	noError(t, repo.SetTeamFallbacks(ctx, "backend", fallbacks[1:]))
+	got, err = repo.GetTeamFallbacks(ctx, "backend")
+	noError(t, err)
+	equal(t, "replaced", len(got), 1)
+	equal(t, "replaced first", got[0], fallbacks[1])
+
+	noError(t, repo.SetTeamFallbacks(ctx, "backend", nil))
+	got, err = repo.GetTeamFallbacks(ctx, "backend")
+	noError(t, err)
+	equal(t, "cleared", len(got), 0)
+}
+
+func testTeamCodeOwners(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1")
+
+	_, err := repo.GetTeamCodeOwners(ctx, "backend")
+	wantError(t, err, errors.ErrCodeOwnersNotFound)
+
+	codeOwners := &models.CodeOwners{UpdatedAt: now(), TeamName: "missing", Content: "* @u1", Mode: models.CodeOwnersPrefer}
+	wantError(t, repo.SetTeamCodeOwners(ctx, codeOwners), errors.ErrTeamNotFound)
+
+	codeOwners.TeamName = "backend"
+	noError(t, repo.SetTeamCodeOwners(ctx, codeOwners))
+
+	updated := &models.CodeOwners{UpdatedAt: now(), TeamName: "backend", Content: "/api/ @u1", Mode: models.CodeOwnersRequire}
+	noError(t, repo.SetTeamCodeOwners(ctx, updated))
+
+	got, err := repo.GetTeamCodeOwners(ctx, "backend")
+	noError(t, err)
+	equal(t, "content", got.Content, updated.Content)
+	equal(t, "mode", got.Mode, updated.Mode)
+	equalTime(t, "updated at", &got.UpdatedAt, updated.UpdatedAt)
+}
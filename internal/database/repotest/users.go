@@ -0,0 +1,258 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"pr-review/internal/database"
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+var userChecks = []check{
+	{name: "Get", run: testGetUser},
+	{name: "SetActive", run: testSetUserActive},
+	{name: "SetTeam", run: testSetUserTeam},
+	{name: "SetMaxOpenReviews", run: testSetUserMaxOpenReviews},
+	{name: "Deactivate", run: testDeactivateUsers},
+	{name: "DeactivateRollback", run: testDeactivateUsersRollback},
+	{name: "DeactivateStalePlan", run: testDeactivateUsersStalePlan},
+	{name: "PRsByReviewer", run: testGetPRsByReviewer},
+	{name: "PRsCntByAuthor", run: testGetPRsCntByAuthor},
+}
+
+var absenceChecks = []check{
+	{name: "Add", run: testAddAbsence},
+	{name: "Started", run: testStartedAbsences},
+}
+
+func testGetUser(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1")
+
+	user := getUser(ctx, t, repo, "u1")
+	equal(t, "user", user.TeamMember, member("u1"))
+	equal(t, "team", user.TeamName, "backend")
+
+	_, err := repo.GetUserByID(ctx, "missing")
+	wantError(t, err, errors.ErrUserNotFound)
+}
+
+func testSetUserActive(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1")
+
+	noError(t, repo.SetUserActive(ctx, "u1", false))
+	equal(t, "deactivated", getUser(ctx, t, repo, "u1").IsActive, false)
+	noError(t, repo.SetUserActive(ctx, "u1", true))
+	equal(t, "activated", getUser(ctx, t, repo, "u1").IsActive, true)
+
+	wantError(t, repo.SetUserActive(ctx, "missing", true), errors.ErrUserNotFound)
+}
+
+func testSetUserTeam(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1")
+	seedTeam(ctx, t, repo, "frontend")
+
+	noError(t, repo.SetUserTeam(ctx, "u1", "frontend"))
+	equal(t, "team", getUser(ctx, t, repo, "u1").TeamName, "frontend")
+	equalStrings(t, "members", memberIDs(getTeam(ctx, t, repo, "frontend")), []string{"u1"})
+
+	wantError(t, repo.SetUserTeam(ctx, "u1", "missing"), errors.ErrTeamNotFound)
+	wantError(t, repo.SetUserTeam(ctx, "missing", "backend"), errors.ErrUserNotFound)
+}
+
+func testSetUserMaxOpenReviews(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1")
+
+	noError(t, repo.SetUserMaxOpenReviews(ctx, "u1", intPtr(0)))
+	equalIntPtr(t, "limit", getUser(ctx, t, repo, "u1").MaxOpenReviews, intPtr(0))
+	noError(t, repo.SetUserMaxOpenReviews(ctx, "u1", nil))
+	equalIntPtr(t, "reset limit", getUser(ctx, t, repo, "u1").MaxOpenReviews, nil)
+
+	wantError(t, repo.SetUserMaxOpenReviews(ctx, "missing", intPtr(1)), errors.ErrUserNotFound)
+}
+
+func testDeactivateUsers(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2", "u3", "u4")
+	seedPR(ctx, t, repo, "pr-1", "u1", "u2", "u3")
+
+	noError(t, repo.DeactivateUsers(ctx, []string{"u2", "u3"}, []models.Reassignment{
+		{PullRequestID: "pr-1", OldUserID: "u2", NewUserID: "u4"},
+	}))
+
+	equal(t, "u2 active", getUser(ctx, t, repo, "u2").IsActive, false)
+	equal(t, "u3 active", getUser(ctx, t, repo, "u3").IsActive, false)
+	equal(t, "u1 active", getUser(ctx, t, repo, "u1").IsActive, true)
+
+	pr := getPR(ctx, t, repo, "pr-1")
+	equalStrings(t, "reviewers", pr.AssignedReviewers, []string{"u3", "u4"})
+	equal(t, "new review state", findReview(t, pr, "u4").State, models.ReviewStatePending)
+
+	noError(t, repo.DeactivateUsers(ctx, nil, nil))
+}
+
+func testDeactivateUsersRollback(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2", "u3")
+	seedPR(ctx, t, repo, "pr-1", "u1", "u2")
+
+	err := repo.DeactivateUsers(ctx, []string{"u2", "missing"}, nil)
+	wantError(t, err, errors.ErrUserNotFound)
+	equal(t, "active after unknown user", getUser(ctx, t, repo, "u2").IsActive, true)
+
+	err = repo.DeactivateUsers(ctx, []string{"u2"}, []models.Reassignment{
+		{PullRequestID: "pr-1", OldUserID: "u2", NewUserID: "u3"},
+		{PullRequestID: "pr-1", OldUserID: "u1", NewUserID: "u3"},
+	})
+	wantError(t, err, errors.ErrNotAssigned)
+
+	// ни одно изменение не применяется
+	equal(t, "active after unassigned", getUser(ctx, t, repo, "u2").IsActive, true)
+	equalStrings(t, "reviewers", getPR(ctx, t, repo, "pr-1").AssignedReviewers, []string{"u2"})
+}
+
+// План замен составляется до транзакции, хранилище перепроверяет его при записи.
+func testDeactivateUsersStalePlan(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2", "u3", "u4")
+	seedPR(ctx, t, repo, "pr-1", "u1", "u2", "u3")
+	seedPR(ctx, t, repo, "pr-2", "u1", "u2")
+
+	// замена уже назначена на этот PR
+	err := repo.DeactivateUsers(ctx, []string{"u2"}, []models.Reassignment{
+		{PullRequestID: "pr-1", OldUserID: "u2", NewUserID: "u3"},
+	})
+	wantError(t, err, errors.ErrReviewerUnavailable)
+
+	// замену деактивировали, в том числе этим же вызовом
+	noError(t, repo.SetUserActive(ctx, "u4", false))
+	err = repo.DeactivateUsers(ctx, []string{"u2"}, []models.Reassignment{
+		{PullRequestID: "pr-2", OldUserID: "u2", NewUserID: "u4"},
+	})
+	wantError(t, err, errors.ErrReviewerUnavailable)
+	noError(t, repo.SetUserActive(ctx, "u4", true))
+	err = repo.DeactivateUsers(ctx, []string{"u2", "u3"}, []models.Reassignment{
+		{PullRequestID: "pr-2", OldUserID: "u2", NewUserID: "u3"},
+	})
+	wantError(t, err, errors.ErrReviewerUnavailable)
+
+	// PR влили после планирования
+	noError(t, repo.MergePR(ctx, "pr-2", now(), nil))
+	err = repo.DeactivateUsers(ctx, []string{"u2"}, []models.Reassignment{
+		{PullRequestID: "pr-2", OldUserID: "u2", NewUserID: "u4"},
+	})
+	wantError(t, err, errors.ErrReviewerUnavailable)
+
+	equal(t, "active after stale plans", getUser(ctx, t, repo, "u2").IsActive, true)
+	equal(t, "replacement active", getUser(ctx, t, repo, "u3").IsActive, true)
+	equalStrings(t, "reviewers", getPR(ctx, t, repo, "pr-1").AssignedReviewers, []string{"u2", "u3"})
+	equalStrings(t, "merged reviewers", getPR(ctx, t, repo, "pr-2").AssignedReviewers, []string{"u2"})
+}
+
+func testGetPRsByReviewer(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2", "u3")
+	seedPR(ctx, t, repo, "pr-1", "u1", "u2")
+	seedPR(ctx, t, repo, "pr-2", "u1", "u2", "u3")
+	seedDraft(ctx, t, repo, "pr-3", "u1")
+	noError(t, repo.MarkReadyForReview(ctx, "pr-3", []string{"u2"}))
+	seedDraft(ctx, t, repo, "pr-4", "u1")
+	noError(t, repo.MergePR(ctx, "pr-1", now(), nil))
+	reviewedAt := now()
+	noError(t, repo.SubmitReview(ctx, "pr-2", "u2", models.ReviewStateApproved, reviewedAt))
+
+	assignments, err := repo.GetPRsByReviewer(ctx, "u2")
+	noError(t, err)
+	ids := make([]string, 0, len(assignments))
+	for _, assignment := range assignments {
+		ids = append(ids, assignment.ID)
+	}
+	// новые первыми
+	equalStrings(t, "PRs", ids, []string{"pr-3", "pr-2", "pr-1"})
+
+	equal(t, "merged status", assignments[2].Status, models.PRStatusMerged)
+	equal(t, "author", assignments[1].AuthorID, "u1")
+	equal(t, "state", assignments[1].State, models.ReviewStateApproved)
+	equalTime(t, "reviewed at", assignments[1].ReviewedAt, reviewedAt)
+	equal(t, "pending state", assignments[0].State, models.ReviewStatePending)
+	equal(t, "assigned at", assignments[0].AssignedAt != nil, true)
+
+	assignments, err = repo.GetPRsByReviewer(ctx, "u1")
+	noError(t, err)
+	equal(t, "author reviews", len(assignments), 0)
+
+	_, err = repo.GetPRsByReviewer(ctx, "missing")
+	wantError(t, err, errors.ErrUserNotFound)
+}
+
+func testGetPRsCntByAuthor(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2")
+	seedPR(ctx, t, repo, "pr-1", "u1")
+	seedDraft(ctx, t, repo, "pr-2", "u1")
+	seedPR(ctx, t, repo, "pr-3", "u2")
+	noError(t, repo.MergePR(ctx, "pr-1", now(), nil))
+
+	count, err := repo.GetPRsCntByAuthor(ctx, "u1")
+	noError(t, err)
+	equal(t, "u1 PRs", count, 2)
+
+	noError(t, repo.SetUserActive(ctx, "u2", false))
+	count, err = repo.GetPRsCntByAuthor(ctx, "u2")
+	noError(t, err)
+	equal(t, "u2 PRs", count, 1)
+
+	_, err = repo.GetPRsCntByAuthor(ctx, "missing")
+	wantError(t, err, errors.ErrUserNotFound)
+}
+
+func testAddAbsence(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1")
+
+	startsAt := now()
+	err := repo.AddAbsence(ctx, &models.Absence{StartsAt: startsAt, EndsAt: startsAt.Add(time.Hour), UserID: "missing"})
+	wantError(t, err, errors.ErrUserNotFound)
+
+	noError(t, repo.AddAbsence(ctx, &models.Absence{StartsAt: startsAt, EndsAt: startsAt.Add(time.Hour), UserID: "u1"}))
+	// запись с тем же началом заменяет окончание
+	noError(t, repo.AddAbsence(ctx, &models.Absence{StartsAt: startsAt, EndsAt: startsAt.Add(2 * time.Hour), UserID: "u1"}))
+
+	userIDs, err := repo.GetUsersWithStartedAbsences(ctx, startsAt.Add(90*time.Minute))
+	noError(t, err)
+	equalStrings(t, "absent", userIDs, []string{"u1"})
+}
+
+func testStartedAbsences(ctx context.Context, t *testing.T, repo database.Repository) {
+	seedTeam(ctx, t, repo, "backend", "u1", "u2", "u3")
+
+	at := now()
+	absences := []*models.Absence{
+		{StartsAt: at.Add(-time.Hour), EndsAt: at.Add(time.Hour), UserID: "u2"},
+		{StartsAt: at.Add(-2 * time.Hour), EndsAt: at.Add(time.Hour), UserID: "u1"},
+		// ещё не началось и уже закончилось
+		{StartsAt: at.Add(time.Hour), EndsAt: at.Add(2 * time.Hour), UserID: "u3"},
+		{StartsAt: at.Add(-2 * time.Hour), EndsAt: at.Add(-time.Hour), UserID: "u3"},
+	}
+	for _, absence := range absences {
+		noError(t, repo.AddAbsence(ctx, absence))
+	}
+
+	userIDs, err := repo.GetUsersWithStartedAbsences(ctx, at)
+	noError(t, err)
+	equalStrings(t, "started", userIDs, []string{"u1", "u2"})
+
+	noError(t, repo.MarkAbsencesProcessed(ctx, "u1", at))
+	userIDs, err = repo.GetUsersWithStartedAbsences(ctx, at)
+	noError(t, err)
+	equalStrings(t, "after processing", userIDs, []string{"u2"})
+
+	// повторная запись снова ставит период в очередь
+	noError(t, repo.AddAbsence(ctx, absences[1]))
+	userIDs, err = repo.GetUsersWithStartedAbsences(ctx, at)
+	noError(t, err)
+	equalStrings(t, "after re-adding", userIDs, []string{"u1", "u2"})
+
+	// отсутствующий не становится кандидатом
+	candidates, err := repo.GetReviewerCandidates(ctx, "backend")
+	noError(t, err)
+	equalStrings(t, "candidates", candidateIDs(candidates), []string{"u3"})
+	candidates, err = repo.GetReviewerCandidatesByIDs(ctx, []string{"u1", "u3"})
+	noError(t, err)
+	equalStrings(t, "candidates by ids", candidateIDs(candidates), []string{"u3"})
+}
@@ -0,0 +1,203 @@
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"pr-review/internal/database"
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+var webhookChecks = []check{
+	{name: "CreateAndDelete", run: testWebhookCreateDelete},
+	{name: "Enqueue", run: testWebhookEnqueue},
+	{name: "ClaimAndSave", run: testWebhookClaimSave},
+	{name: "ConcurrentClaim", run: testWebhookConcurrentClaim},
+}
+
+func testWebhookCreateDelete(ctx context.Context, t *testing.T, repo database.Repository) {
+	createdAt := now()
+	seedWebhook(ctx, t, repo, "w2", createdAt.Add(time.Second))
+	seedWebhook(ctx, t, repo, "w1", createdAt, models.WebhookEventPRMerged, models.WebhookEventPRCreated)
+
+	webhooks, err := repo.GetWebhooks(ctx)
+	noError(t, err)
+	equal(t, "webhooks", len(webhooks), 2)
+	equal(t, "first", webhooks[0].ID, "w1")
+	equal(t, "url", webhooks[0].URL, "http://localhost/w1")
+	equal(t, "secret", webhooks[0].Secret, "secret-w1")
+	equalTime(t, "created at", &webhooks[0].CreatedAt, createdAt)
+	equalStrings(t, "events", webhooks[0].Events, []string{models.WebhookEventPRCreated, models.WebhookEventPRMerged})
+	equalStrings(t, "all events", webhooks[1].Events, nil)
+
+	wantAnyError(t, repo.CreateWebhook(ctx, &models.Webhook{ID: "w1", URL: "http://localhost", Secret: "s", CreatedAt: now()}))
+
+	_, err = repo.EnqueueDeliveries(ctx, models.WebhookEventPRCreated, []byte(`{}`), now())
+	noError(t, err)
+	noError(t, repo.DeleteWebhook(ctx, "w1"))
+	wantError(t, repo.DeleteWebhook(ctx, "w1"), errors.ErrWebhookNotFound)
+	_, err = repo.GetWebhookDeliveries(ctx, "w1", 10)
+	wantError(t, err, errors.ErrWebhookNotFound)
+
+	// доставки удалены каскадом и не достаются новой подписке с тем же id
+	seedWebhook(ctx, t, repo, "w1", createdAt)
+	recreated, err := repo.GetWebhookDeliveries(ctx, "w1", 10)
+	noError(t, err)
+	equal(t, "recreated deliveries", len(recreated), 0)
+	noError(t, repo.DeleteWebhook(ctx, "w1"))
+
+	// доставки удалённой подписки не выдаются на отправку
+	deliveries, err := repo.ClaimDueDeliveries(ctx, now(), time.Minute, 10)
+	noError(t, err)
+	equal(t, "claimed", len(deliveries), 1)
+	equal(t, "claimed webhook", deliveries[0].WebhookID, "w2")
+}
+
+func testWebhookEnqueue(ctx context.Context, t *testing.T, repo database.Repository) {
+	at := now()
+	seedWebhook(ctx, t, repo, "all", at)
+	seedWebhook(ctx, t, repo, "merged", at.Add(time.Second), models.WebhookEventPRMerged)
+
+	count, err := repo.EnqueueDeliveries(ctx, models.WebhookEventPRCreated, []byte(`{"n":1}`), at)
+	noError(t, err)
+	equal(t, "created subscribers", count, 1)
+	count, err = repo.EnqueueDeliveries(ctx, models.WebhookEventPRMerged, []byte(`{"n":2}`), at)
+	noError(t, err)
+	equal(t, "merged subscribers", count, 2)
+
+	deliveries, err := repo.GetWebhookDeliveries(ctx, "all", 10)
+	noError(t, err)
+	equal(t, "deliveries", len(deliveries), 2)
+	// новые первыми
+	equal(t, "latest event", deliveries[0].Event, models.WebhookEventPRMerged)
+	equal(t, "payload", string(deliveries[1].Payload), `{"n":1}`)
+	equal(t, "status", deliveries[1].Status, models.DeliveryPending)
+	equal(t, "attempts", deliveries[1].Attempts, 0)
+	equalTime(t, "next attempt", &deliveries[1].NextAttemptAt, at)
+	equal(t, "delivered at", deliveries[1].DeliveredAt == nil, true)
+
+	deliveries, err = repo.GetWebhookDeliveries(ctx, "merged", 10)
+	noError(t, err)
+	equal(t, "filtered deliveries", len(deliveries), 1)
+
+	deliveries, err = repo.GetWebhookDeliveries(ctx, "all", 1)
+	noError(t, err)
+	equal(t, "limited", len(deliveries), 1)
+}
+
+func testWebhookClaimSave(ctx context.Context, t *testing.T, repo database.Repository) {
+	at := now()
+	seedWebhook(ctx, t, repo, "w1", at)
+	_, err := repo.EnqueueDeliveries(ctx, models.WebhookEventPRCreated, []byte(`{"n":1}`), at)
+	noError(t, err)
+	_, err = repo.EnqueueDeliveries(ctx, models.WebhookEventPRMerged, []byte(`{"n":2}`), at.Add(time.Hour))
+	noError(t, err)
+
+	deliveries, err := repo.ClaimDueDeliveries(ctx, at, time.Minute, 10)
+	noError(t, err)
+	equal(t, "due", len(deliveries), 1)
+	claimed := deliveries[0]
+	equal(t, "url", claimed.URL, "http://localhost/w1")
+	equal(t, "secret", claimed.Secret, "secret-w1")
+	equal(t, "payload", string(claimed.Payload), `{"n":1}`)
+
+	// выданная доставка не выдаётся повторно, пока не истёк lease
+	deliveries, err = repo.ClaimDueDeliveries(ctx, at.Add(30*time.Second), time.Minute, 10)
+	noError(t, err)
+	equal(t, "leased", len(deliveries), 0)
+
+	retryAt := at.Add(2 * time.Hour)
+	claimed.Attempts = 1
+	claimed.ResponseStatus = 500
+	claimed.LastError = "receiver responded 500"
+	claimed.NextAttemptAt = retryAt
+	noError(t, repo.SaveDeliveryAttempt(ctx, &claimed))
+
+	deliveries, err = repo.ClaimDueDeliveries(ctx, at.Add(90*time.Minute), time.Minute, 10)
+	noError(t, err)
+	equal(t, "later event", len(deliveries), 1)
+	equal(t, "later event name", deliveries[0].Event, models.WebhookEventPRMerged)
+	failed := deliveries[0]
+	failed.Status = models.DeliveryFailed
+	failed.Attempts = 1
+	failed.LastError = "connection refused"
+	noError(t, repo.SaveDeliveryAttempt(ctx, &failed))
+
+	deliveries, err = repo.ClaimDueDeliveries(ctx, retryAt, time.Minute, 10)
+	noError(t, err)
+	equal(t, "retry", len(deliveries), 1)
+	equal(t, "retry attempts", deliveries[0].Attempts, 1)
+
+	deliveredAt := now()
+	retry := deliveries[0]
+	retry.Attempts = 2
+	retry.Status = models.DeliveryDelivered
+	retry.ResponseStatus = 204
+	retry.LastError = ""
+	retry.DeliveredAt = &deliveredAt
+	noError(t, repo.SaveDeliveryAttempt(ctx, &retry))
+
+	// доставленные и исчерпавшие попытки больше не выдаются
+	deliveries, err = repo.ClaimDueDeliveries(ctx, retryAt.Add(24*time.Hour), time.Minute, 10)
+	noError(t, err)
+	equal(t, "after delivery", len(deliveries), 0)
+
+	log, err := repo.GetWebhookDeliveries(ctx, "w1", 10)
+	noError(t, err)
+	equal(t, "failed status", log[0].Status, models.DeliveryFailed)
+	equal(t, "failed error", log[0].LastError, "connection refused")
+	equal(t, "failed response", log[0].ResponseStatus, 0)
+
+	saved := log[1]
+	equal(t, "status", saved.Status, models.DeliveryDelivered)
+	equal(t, "attempts", saved.Attempts, 2)
+	equal(t, "response", saved.ResponseStatus, 204)
+	equal(t, "error", saved.LastError, "")
+	equalTime(t, "delivered at", saved.DeliveredAt, deliveredAt)
+}
+
+func testWebhookConcurrentClaim(ctx context.Context, t *testing.T, repo database.Repository) {
+	at := now()
+	seedWebhook(ctx, t, repo, "w1", at)
+	const total = 3 * workers
+	for i := range total {
+		_, err := repo.EnqueueDeliveries(ctx, models.WebhookEventPRCreated, fmt.Appendf(nil, `{"n":%d}`, i), at)
+		noError(t, err)
+	}
+
+	claimed := make([][]models.WebhookDelivery, workers)
+	errs := parallel(workers, func(i int) error {
+		var err error
+		claimed[i], err = repo.ClaimDueDeliveries(ctx, at, time.Minute, 5)
+		return err
+	})
+
+	seen := make(map[int64]bool)
+	for i, err := range errs {
+		noError(t, err)
+		for _, delivery := range claimed[i] {
+			if seen[delivery.ID] {
+				t.Fatalf("delivery %d claimed twice", delivery.ID)
+			}
+			seen[delivery.ID] = true
+		}
+	}
+	equal(t, "claimed", len(seen), total)
+}
+
+// private methods
+
+func seedWebhook(ctx context.Context, t *testing.T, repo database.Repository, id string, createdAt time.Time, events ...string) {
+	t.Helper()
+
+	noError(t, repo.CreateWebhook(ctx, &models.Webhook{
+		CreatedAt: createdAt,
+		ID:        id,
+		URL:       "http://localhost/" + id,
+		Secret:    "secret-" + id,
+		Events:    events,
+	}))
+}
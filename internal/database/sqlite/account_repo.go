@@ -0,0 +1,82 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+func (r *SQLiteRepository) LinkUserAccount(ctx context.Context, account *models.UserAccount) error {
+	const op = "SQLite.LinkUserAccount"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			return
+		}
+	}()
+
+	var exists int
+	err = tx.QueryRowContext(ctx, `SELECT 1 FROM users WHERE user_id = ?`, account.UserID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return errors.WrapError(op, errors.ErrUserNotFound)
+	}
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM user_accounts WHERE provider = ? AND user_id = ?`, account.Provider, account.UserID)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	query := `INSERT INTO user_accounts (provider, login, user_id) VALUES (?, ?, ?)`
+	_, err = tx.ExecContext(ctx, query, account.Provider, account.Login, account.UserID)
+	if isUniqueViolation(err) {
+		return errors.WrapError(op, errors.ErrLoginTaken)
+	}
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) GetUserIDByLogin(ctx context.Context, provider, login string) (string, error) {
+	const op = "SQLite.GetUserIDByLogin"
+
+	var userID string
+	query := `SELECT user_id FROM user_accounts WHERE provider = ? AND login = ?`
+	err := r.db.QueryRowContext(ctx, query, provider, login).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return "", errors.WrapError(op, errors.ErrAccountNotLinked)
+	}
+	if err != nil {
+		return "", errors.WrapError(op, err)
+	}
+	return userID, nil
+}
+
+func (r *SQLiteRepository) GetUserLogin(ctx context.Context, provider, userID string) (string, error) {
+	const op = "SQLite.GetUserLogin"
+
+	var login string
+	query := `SELECT login FROM user_accounts WHERE provider = ? AND user_id = ?`
+	err := r.db.QueryRowContext(ctx, query, provider, userID).Scan(&login)
+	if err == sql.ErrNoRows {
+		return "", errors.WrapError(op, errors.ErrAccountNotLinked)
+	}
+	if err != nil {
+		return "", errors.WrapError(op, err)
+	}
+	return login, nil
+}
@@ -0,0 +1,116 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+func (r *SQLiteRepository) EnqueueChatMessage(ctx context.Context, message *models.ChatMessage) error {
+	const op = "SQLite.EnqueueChatMessage"
+
+	query := `
+		INSERT INTO chat_messages (team_name, event, url, payload, next_attempt_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		message.TeamName, message.Event, message.URL, string(message.Payload),
+		message.NextAttemptAt.UTC(), message.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) ClaimDueChatMessages(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]models.ChatMessage, error) {
+	const op = "SQLite.ClaimDueChatMessages"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			return
+		}
+	}()
+
+	query := `
+		SELECT id, team_name, event, url, payload, status, attempts, created_at
+		FROM chat_messages
+		WHERE status = 'pending' AND next_attempt_at <= ?
+		ORDER BY next_attempt_at, id
+		LIMIT ?
+	`
+	rows, err := tx.QueryContext(ctx, query, now.UTC(), limit)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			return
+		}
+	}()
+
+	var messages []models.ChatMessage
+	for rows.Next() {
+		var message models.ChatMessage
+		err := rows.Scan(
+			&message.ID, &message.TeamName, &message.Event, &message.URL, &message.Payload, &message.Status,
+			&message.Attempts, &message.CreatedAt,
+		)
+		if err != nil {
+			return nil, errors.WrapError(op, err)
+		}
+		messages = append(messages, message)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	leasedUntil := now.Add(lease).UTC()
+	for i := range messages {
+		_, err := tx.ExecContext(ctx, `UPDATE chat_messages SET next_attempt_at = ? WHERE id = ?`, leasedUntil, messages[i].ID)
+		if err != nil {
+			return nil, errors.WrapError(op, err)
+		}
+		messages[i].NextAttemptAt = leasedUntil
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	return messages, nil
+}
+
+func (r *SQLiteRepository) SaveChatAttempt(ctx context.Context, message *models.ChatMessage) error {
+	const op = "SQLite.SaveChatAttempt"
+
+	query := `
+		UPDATE chat_messages
+		SET status = ?, attempts = ?, last_error = NULLIF(?, ''), next_attempt_at = ?, sent_at = ?
+		WHERE id = ?
+	`
+	var sentAt any
+	if message.SentAt != nil {
+		sentAt = message.SentAt.UTC()
+	}
+	_, err := r.db.ExecContext(ctx, query,
+		message.Status, message.Attempts, message.LastError, message.NextAttemptAt.UTC(), sentAt, message.ID,
+	)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+func (r *SQLiteRepository) SetUserEmail(ctx context.Context, settings *models.EmailSettings) error {
+	const op = "SQLite.SetUserEmail"
+
+	query := `UPDATE users SET email = NULLIF(?, ''), email_mode = NULLIF(?, '') WHERE user_id = ?`
+	result, err := r.db.ExecContext(ctx, query, settings.Email, settings.Mode, settings.UserID)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if rowsAffected == 0 {
+		return errors.WrapError(op, errors.ErrUserNotFound)
+	}
+
+	return nil
+}
+
+func (r *SQLiteRepository) GetUserEmail(ctx context.Context, userID string) (*models.EmailSettings, error) {
+	const op = "SQLite.GetUserEmail"
+
+	var email, mode sql.NullString
+	query := `SELECT email, email_mode FROM users WHERE user_id = ?`
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&email, &mode)
+	if err == sql.ErrNoRows {
+		return nil, errors.WrapError(op, errors.ErrUserNotFound)
+	}
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	if !email.Valid {
+		return nil, errors.WrapError(op, errors.ErrEmailNotFound)
+	}
+
+	return &models.EmailSettings{UserID: userID, Email: email.String, Mode: mode.String}, nil
+}
+
+// ClaimDigestRecipients выбирает и отмечает пользователей в одной транзакции на запись.
+func (r *SQLiteRepository) ClaimDigestRecipients(ctx context.Context, day time.Time) ([]models.EmailSettings, error) {
+	const op = "SQLite.ClaimDigestRecipients"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			return
+		}
+	}()
+
+	query := `
+		SELECT user_id, email, email_mode
+		FROM users
+		WHERE email IS NOT NULL AND email_mode = 'digest' AND is_active = TRUE
+			AND (digest_sent_at IS NULL OR digest_sent_at < ?)
+		ORDER BY user_id
+	`
+	rows, err := tx.QueryContext(ctx, query, day.UTC())
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			return
+		}
+	}()
+
+	var recipients []models.EmailSettings
+	for rows.Next() {
+		var recipient models.EmailSettings
+		if err := rows.Scan(&recipient.UserID, &recipient.Email, &recipient.Mode); err != nil {
+			return nil, errors.WrapError(op, err)
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	for i := range recipients {
+		_, err := tx.ExecContext(ctx, `UPDATE users SET digest_sent_at = ? WHERE user_id = ?`, day.UTC(), recipients[i].UserID)
+		if err != nil {
+			return nil, errors.WrapError(op, err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	return recipients, nil
+}
+
+func (r *SQLiteRepository) EnqueueEmail(ctx context.Context, message *models.EmailMessage) error {
+	const op = "SQLite.EnqueueEmail"
+
+	query := `
+		INSERT INTO email_messages (user_id, recipient, subject, message, next_attempt_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		message.UserID, message.Recipient, message.Subject, string(message.Message),
+		message.NextAttemptAt.UTC(), message.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) ClaimDueEmails(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]models.EmailMessage, error) {
+	const op = "SQLite.ClaimDueEmails"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			return
+		}
+	}()
+
+	query := `
+		SELECT id, user_id, recipient, subject, message, status, attempts, created_at
+		FROM email_messages
+		WHERE status = 'pending' AND next_attempt_at <= ?
+		ORDER BY next_attempt_at, id
+		LIMIT ?
+	`
+	rows, err := tx.QueryContext(ctx, query, now.UTC(), limit)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			return
+		}
+	}()
+
+	var messages []models.EmailMessage
+	for rows.Next() {
+		var message models.EmailMessage
+		err := rows.Scan(
+			&message.ID, &message.UserID, &message.Recipient, &message.Subject, &message.Message, &message.Status,
+			&message.Attempts, &message.CreatedAt,
+		)
+		if err != nil {
+			return nil, errors.WrapError(op, err)
+		}
+		messages = append(messages, message)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	leasedUntil := now.Add(lease).UTC()
+	for i := range messages {
+		_, err := tx.ExecContext(ctx, `UPDATE email_messages SET next_attempt_at = ? WHERE id = ?`, leasedUntil, messages[i].ID)
+		if err != nil {
+			return nil, errors.WrapError(op, err)
+		}
+		messages[i].NextAttemptAt = leasedUntil
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	return messages, nil
+}
+
+func (r *SQLiteRepository) SaveEmailAttempt(ctx context.Context, message *models.EmailMessage) error {
+	const op = "SQLite.SaveEmailAttempt"
+
+	query := `
+		UPDATE email_messages
+		SET status = ?, attempts = ?, last_error = NULLIF(?, ''), next_attempt_at = ?, sent_at = ?
+		WHERE id = ?
+	`
+	var sentAt any
+	if message.SentAt != nil {
+		sentAt = message.SentAt.UTC()
+	}
+	_, err := r.db.ExecContext(ctx, query,
+		message.Status, message.Attempts, message.LastError, message.NextAttemptAt.UTC(), sentAt, message.ID,
+	)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	return nil
+}
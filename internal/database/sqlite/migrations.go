@@ -0,0 +1,128 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"pr-review/internal/errors"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+)
+
+// overridesDir - версии миграций для SQLite, где у него нет аналога инструкции PostgreSQL (ALTER COLUMN).
+const overridesDir = "sqlite"
+
+// sqliteDialect: время возвращается драйвером как time.Time только для DATETIME.
+var sqliteDialect = strings.NewReplacer(
+	"TIMESTAMP WITH TIME ZONE", "DATETIME",
+	"BIGSERIAL PRIMARY KEY", "INTEGER PRIMARY KEY AUTOINCREMENT",
+)
+
+func runMigrations(ctx context.Context, dsn, migrationsPath string) error {
+	const op = "SQLiteRepository.runMigrations"
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Printf("%s: failed to close migration connection: %v", op, err)
+		}
+	}()
+
+	if err := db.PingContext(ctx); err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	driver, err := sqlite.WithInstance(db, &sqlite.Config{})
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	src, err := openMigrations(migrationsPath)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	m, err := migrate.NewWithInstance("file", src, "sqlite", driver)
+	if err != nil {
+		return fmt.Errorf("%s: failed to create migration instance: %w", op, err)
+	}
+
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		return errors.WrapError(op, err)
+	}
+
+	log.Println("Migrations applied successfully")
+	return nil
+}
+
+// migrationSource подменяет общие миграции файлами с той же версией из overridesDir.
+type migrationSource struct {
+	source.Driver
+	overrides source.Driver
+}
+
+func openMigrations(migrationsPath string) (*migrationSource, error) {
+	const op = "sqlite.openMigrations"
+
+	base, err := source.Open("file://" + migrationsPath)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	overrides, err := source.Open("file://" + filepath.Join(migrationsPath, overridesDir))
+	if err != nil {
+		_ = base.Close()
+		return nil, errors.WrapError(op, err)
+	}
+
+	return &migrationSource{Driver: base, overrides: overrides}, nil
+}
+
+func (s *migrationSource) Close() error {
+	overridesErr := s.overrides.Close()
+	if err := s.Driver.Close(); err != nil {
+		return err
+	}
+	return overridesErr
+}
+
+func (s *migrationSource) ReadUp(version uint) (io.ReadCloser, string, error) {
+	return s.read(version, source.Driver.ReadUp)
+}
+
+func (s *migrationSource) ReadDown(version uint) (io.ReadCloser, string, error) {
+	return s.read(version, source.Driver.ReadDown)
+}
+
+func (s *migrationSource) read(
+	version uint,
+	read func(source.Driver, uint) (io.ReadCloser, string, error),
+) (io.ReadCloser, string, error) {
+	body, identifier, err := read(s.overrides, version)
+	if errors.Is(err, fs.ErrNotExist) {
+		body, identifier, err = read(s.Driver, version)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	defer body.Close()
+
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return io.NopCloser(strings.NewReader(sqliteDialect.Replace(string(content)))), identifier, nil
+}
@@ -3,70 +3,95 @@ package sqlite
 import (
 	"context"
 	"database/sql"
-	"math/rand"
+	"slices"
+	"strings"
 	"time"
 
 	"pr-review/internal/errors"
 	"pr-review/internal/models"
+
+	sqlitedriver "modernc.org/sqlite"
+	sqlitelib "modernc.org/sqlite/lib"
 )
 
-func (r *SQLiteRepository) CreatePR(ctx context.Context, pr *models.PullRequestShort) error {
+// CreatePR выполняет все проверки в той же транзакции, что и запись, поэтому параллельный запрос
+// не создаст дубль и не назначит ревьювера, которого успели деактивировать.
+func (r *SQLiteRepository) CreatePR(ctx context.Context, pr *models.PullRequest) error {
 	const op = "SQLite.CreatePR"
 
-	exists, err := r.PRExists(ctx, pr.ID)
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return errors.WrapError(op, err)
 	}
-	if exists {
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			return
+		}
+	}()
+
+	var exists int
+	err = tx.QueryRowContext(ctx, `SELECT 1 FROM pull_requests WHERE id = ?`, pr.ID).Scan(&exists)
+	if err == nil {
 		return errors.WrapError(op, errors.ErrPRExists)
 	}
-
-	exists, err = r.UserExists(ctx, pr.AuthorID)
-	if err != nil {
+	if err != sql.ErrNoRows {
 		return errors.WrapError(op, err)
 	}
-	if !exists {
+
+	err = tx.QueryRowContext(ctx, `SELECT 1 FROM users WHERE user_id = ?`, pr.AuthorID).Scan(&exists)
+	if err == sql.ErrNoRows {
 		return errors.WrapError(op, errors.ErrUserNotFound)
 	}
-
-	authorTeam, err := r.getUserTeam(ctx, pr.AuthorID)
 	if err != nil {
 		return errors.WrapError(op, err)
 	}
 
-	availableReviewers, err := r.getActiveTeamMembers(ctx, authorTeam, pr.AuthorID, nil)
-	if err != nil {
+	if err := checkAvailable(ctx, tx, pr.AssignedReviewers); err != nil {
 		return errors.WrapError(op, err)
 	}
 
-	selectedReviewers := selectRandomReviewers(availableReviewers, 2)
+	now := time.Now()
 
-	tx, err := r.db.BeginTx(ctx, nil)
+	query := `
+		INSERT INTO pull_requests (id, name, author_id, status, created_at, is_draft, reviewers_count, provider)
+		VALUES (?, ?, ?, 'OPEN', ?, ?, ?, ?)
+	`
+	_, err = tx.ExecContext(ctx, query, pr.ID, pr.Name, pr.AuthorID, now, pr.IsDraft, pr.ReviewersCount, pr.Provider)
+	if isUniqueViolation(err) {
+		return errors.WrapError(op, errors.ErrPRExists)
+	}
 	if err != nil {
 		return errors.WrapError(op, err)
 	}
-	defer func() {
-		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
-			return
-		}
-	}()
 
-	query := `INSERT INTO pull_requests (id, name, author_id, status, created_at) VALUES (?, ?, ?, 'OPEN', ?)`
-	_, err = tx.ExecContext(ctx, query, pr.ID, pr.Name, pr.AuthorID, time.Now())
-	if err != nil {
-		return errors.WrapError(op, err)
+	events := []models.PREvent{{PullRequestID: pr.ID, Type: models.PREventCreated, UserID: pr.AuthorID, CreatedAt: now}}
+	if len(pr.AssignedReviewers) > 0 {
+		reviewersQuery := `INSERT INTO pr_reviewers (pr_id, user_id, assigned_at) VALUES (?, ?, ?)`
+		for _, reviewerID := range pr.AssignedReviewers {
+			_, err := tx.ExecContext(ctx, reviewersQuery, pr.ID, reviewerID, now)
+			if err != nil {
+				return errors.WrapError(op, err)
+			}
+			events = append(events, models.PREvent{
+				PullRequestID: pr.ID, Type: models.PREventReviewerAssigned, UserID: reviewerID, CreatedAt: now,
+			})
+		}
 	}
 
-	if len(selectedReviewers) > 0 {
-		reviewersQuery := `INSERT INTO pr_reviewers (pr_id, user_id) VALUES (?, ?)`
-		for _, reviewerID := range selectedReviewers {
-			_, err := tx.ExecContext(ctx, reviewersQuery, pr.ID, reviewerID)
+	if len(pr.ChangedFiles) > 0 {
+		filesQuery := `INSERT INTO pr_files (pr_id, path) VALUES (?, ?)`
+		for _, path := range pr.ChangedFiles {
+			_, err := tx.ExecContext(ctx, filesQuery, pr.ID, path)
 			if err != nil {
 				return errors.WrapError(op, err)
 			}
 		}
 	}
 
+	if err := insertEvents(ctx, tx, events...); err != nil {
+		return errors.WrapError(op, err)
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		return errors.WrapError(op, err)
@@ -86,13 +111,18 @@ func (r *SQLiteRepository) GetPRByID(ctx context.Context, id string) (*models.Pu
 		return nil, errors.WrapError(op, errors.ErrPRNotFound)
 	}
 
-	query := `SELECT id, name, author_id, status, created_at, merged_at FROM pull_requests WHERE id = ?`
+	query := `
+		SELECT id, name, author_id, status, is_draft, reviewers_count, created_at, merged_at, closed_at, provider
+		FROM pull_requests
+		WHERE id = ?
+	`
 	row := r.db.QueryRowContext(ctx, query, id)
 
 	var pr models.PullRequest
-	var mergedAt sql.NullTime
+	var reviewersCount sql.NullInt64
+	var mergedAt, closedAt sql.NullTime
 
-	err = row.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &mergedAt)
+	err = row.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.IsDraft, &reviewersCount, &pr.CreatedAt, &mergedAt, &closedAt, &pr.Provider)
 	if err == sql.ErrNoRows {
 		return nil, errors.WrapError(op, errors.ErrPRNotFound)
 	}
@@ -100,119 +130,436 @@ func (r *SQLiteRepository) GetPRByID(ctx context.Context, id string) (*models.Pu
 		return nil, errors.WrapError(op, err)
 	}
 
+	if reviewersCount.Valid {
+		count := int(reviewersCount.Int64)
+		pr.ReviewersCount = &count
+	}
 	if mergedAt.Valid {
 		pr.MergedAt = &mergedAt.Time
 	}
+	if closedAt.Valid {
+		pr.ClosedAt = &closedAt.Time
+	}
+
+	reviews, err := r.getPRReviews(ctx, id)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	pr.Reviews = reviews
+	for _, review := range reviews {
+		pr.AssignedReviewers = append(pr.AssignedReviewers, review.UserID)
+	}
 
-	reviewers, err := r.getPRReviewers(ctx, id)
+	files, err := r.getPRFiles(ctx, id)
 	if err != nil {
 		return nil, errors.WrapError(op, err)
 	}
-	pr.AssignedReviewers = reviewers
+	pr.ChangedFiles = files
 
 	return &pr, nil
 }
 
-func (r *SQLiteRepository) MergePR(ctx context.Context, prID string, mergedAt time.Time) error {
+// MergePR: повторный merge ничего не меняет, закрытый PR не вливается.
+func (r *SQLiteRepository) MergePR(ctx context.Context, prID string, mergedAt time.Time, guard func(pr *models.PullRequest) error) error {
 	const op = "SQLite.MergePR"
 
-	exists, err := r.PRExists(ctx, prID)
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return errors.WrapError(op, err)
 	}
-	if !exists {
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			return
+		}
+	}()
+
+	status, err := prStatus(ctx, tx, prID)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	switch status {
+	case models.PRStatusMerged:
+		return nil
+	case models.PRStatusClosed:
+		return errors.WrapError(op, errors.ErrPRClosed)
+	}
+
+	if guard != nil {
+		pr, err := lockedPR(ctx, tx, prID)
+		if err != nil {
+			return errors.WrapError(op, err)
+		}
+		if err := guard(pr); err != nil {
+			return errors.WrapError(op, err)
+		}
+	}
+
+	query := `UPDATE pull_requests SET status = 'MERGED', merged_at = ? WHERE id = ?`
+	_, err = tx.ExecContext(ctx, query, mergedAt, prID)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	err = insertEvents(ctx, tx, models.PREvent{PullRequestID: prID, Type: models.PREventMerged, CreatedAt: mergedAt})
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	return nil
+}
+
+func (r *SQLiteRepository) ClosePR(ctx context.Context, prID string, closedAt time.Time) error {
+	const op = "SQLite.ClosePR"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			return
+		}
+	}()
+
+	closed, err := closePR(ctx, tx, prID, closedAt, "")
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if !closed {
 		return errors.WrapError(op, errors.ErrPRNotFound)
 	}
 
-	query := `UPDATE pull_requests SET status = 'MERGED', merged_at = ? WHERE id = ? AND status = 'OPEN'`
-	result, err := r.db.ExecContext(ctx, query, mergedAt, prID)
+	if err = tx.Commit(); err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	return nil
+}
+
+func (r *SQLiteRepository) ReopenPR(ctx context.Context, prID string, removed, added []string) error {
+	const op = "SQLite.ReopenPR"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			return
+		}
+	}()
+
+	query := `UPDATE pull_requests SET status = 'OPEN', closed_at = NULL WHERE id = ? AND status = 'CLOSED'`
+	result, err := tx.ExecContext(ctx, query, prID)
 	if err != nil {
 		return errors.WrapError(op, err)
 	}
 
-	_, err = result.RowsAffected()
+	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		return errors.WrapError(op, err)
 	}
+	if rowsAffected == 0 {
+		return errors.WrapError(op, errors.ErrPRNotFound)
+	}
+
+	if err := checkAvailable(ctx, tx, added); err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	now := time.Now()
+	events := []models.PREvent{{PullRequestID: prID, Type: models.PREventReopened, CreatedAt: now}}
+
+	deleteQuery := `DELETE FROM pr_reviewers WHERE pr_id = ? AND user_id = ?`
+	for _, userID := range removed {
+		_, err := tx.ExecContext(ctx, deleteQuery, prID, userID)
+		if err != nil {
+			return errors.WrapError(op, err)
+		}
+		events = append(events, models.PREvent{
+			PullRequestID: prID, Type: models.PREventReviewerRemoved, UserID: userID, Reason: models.ReasonUnavailable, CreatedAt: now,
+		})
+	}
+
+	insertQuery := `INSERT INTO pr_reviewers (pr_id, user_id, assigned_at) VALUES (?, ?, ?)`
+	for _, userID := range added {
+		_, err := tx.ExecContext(ctx, insertQuery, prID, userID, now)
+		if err != nil {
+			return errors.WrapError(op, err)
+		}
+		events = append(events, models.PREvent{
+			PullRequestID: prID, Type: models.PREventReviewerAssigned, UserID: userID, CreatedAt: now,
+		})
+	}
+
+	if err := insertEvents(ctx, tx, events...); err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.WrapError(op, err)
+	}
 
 	return nil
 }
 
-func (r *SQLiteRepository) ReassignReviewer(ctx context.Context, prID, oldUserID string) (*string, error) {
-	const op = "SQLite.ReassignReviewer"
+func (r *SQLiteRepository) MarkReadyForReview(ctx context.Context, prID string, reviewers []string) error {
+	const op = "SQLite.MarkReadyForReview"
 
-	pr, err := r.GetPRByID(ctx, prID)
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, errors.WrapError(op, err)
+		return errors.WrapError(op, err)
 	}
-	if pr.Status == "MERGED" {
-		return nil, errors.WrapError(op, errors.ErrPRMerged)
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			return
+		}
+	}()
+
+	query := `UPDATE pull_requests SET is_draft = 0 WHERE id = ? AND status = 'OPEN' AND is_draft <> 0`
+	result, err := tx.ExecContext(ctx, query, prID)
+	if err != nil {
+		return errors.WrapError(op, err)
 	}
 
-	exists, err := r.UserExists(ctx, oldUserID)
+	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return nil, errors.WrapError(op, err)
+		return errors.WrapError(op, err)
 	}
-	if !exists {
-		return nil, errors.WrapError(op, errors.ErrUserNotFound)
+	if rowsAffected == 0 {
+		return errors.WrapError(op, errors.ErrPRNotFound)
+	}
+
+	if err := checkAvailable(ctx, tx, reviewers); err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	now := time.Now()
+	events := []models.PREvent{{PullRequestID: prID, Type: models.PREventReadyForReview, CreatedAt: now}}
+	insertQuery := `INSERT INTO pr_reviewers (pr_id, user_id, assigned_at) VALUES (?, ?, ?)`
+	for _, userID := range reviewers {
+		_, err := tx.ExecContext(ctx, insertQuery, prID, userID, now)
+		if err != nil {
+			return errors.WrapError(op, err)
+		}
+		events = append(events, models.PREvent{
+			PullRequestID: prID, Type: models.PREventReviewerAssigned, UserID: userID, CreatedAt: now,
+		})
 	}
 
-	isAssigned, err := r.IsReviewerAssigned(ctx, prID, oldUserID)
+	if err := insertEvents(ctx, tx, events...); err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	return nil
+}
+
+// MarkDraft оставляет назначенных ревьюверов.
+func (r *SQLiteRepository) MarkDraft(ctx context.Context, prID string) error {
+	const op = "SQLite.MarkDraft"
+
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, errors.WrapError(op, err)
+		return errors.WrapError(op, err)
 	}
-	if !isAssigned {
-		return nil, errors.WrapError(op, errors.ErrNotAssigned)
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			return
+		}
+	}()
+
+	query := `UPDATE pull_requests SET is_draft = 1 WHERE id = ? AND status = 'OPEN' AND is_draft = 0`
+	result, err := tx.ExecContext(ctx, query, prID)
+	if err != nil {
+		return errors.WrapError(op, err)
 	}
 
-	authorTeam, err := r.getUserTeam(ctx, pr.AuthorID)
+	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return nil, errors.WrapError(op, err)
+		return errors.WrapError(op, err)
+	}
+	if rowsAffected == 0 {
+		return errors.WrapError(op, errors.ErrPRNotFound)
+	}
+
+	event := models.PREvent{PullRequestID: prID, Type: models.PREventConvertedToDraft, CreatedAt: time.Now()}
+	if err := insertEvents(ctx, tx, event); err != nil {
+		return errors.WrapError(op, err)
 	}
 
-	currentReviewers := pr.AssignedReviewers
+	if err = tx.Commit(); err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	return nil
+}
+
+func (r *SQLiteRepository) SubmitReview(ctx context.Context, prID, userID string, state models.ReviewState, reviewedAt time.Time) error {
+	const op = "SQLite.SubmitReview"
 
-	availableReviewers, err := r.getActiveTeamMembers(ctx, authorTeam, oldUserID, currentReviewers)
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, errors.WrapError(op, err)
+		return errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			return
+		}
+	}()
+
+	query := `UPDATE pr_reviewers SET state = ?, reviewed_at = ? WHERE pr_id = ? AND user_id = ?`
+	result, err := tx.ExecContext(ctx, query, state, reviewedAt, prID, userID)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if rowsAffected == 0 {
+		return errors.WrapError(op, errors.ErrNotAssigned)
 	}
 
-	if len(availableReviewers) == 0 {
-		return nil, errors.WrapError(op, errors.ErrNoCandidate)
+	err = insertEvents(ctx, tx, models.PREvent{
+		PullRequestID: prID, Type: models.PREventReviewSubmitted, UserID: userID, State: state, CreatedAt: reviewedAt,
+	})
+	if err != nil {
+		return errors.WrapError(op, err)
 	}
 
-	selectedReviewers := selectRandomReviewers(availableReviewers, 1)
-	if len(selectedReviewers) == 0 {
-		return nil, errors.WrapError(op, errors.ErrNoCandidate)
+	if err = tx.Commit(); err != nil {
+		return errors.WrapError(op, err)
 	}
-	reviewerID := selectedReviewers[0]
+
+	return nil
+}
+
+// ReassignReviewer отклоняет с ErrReviewerUnavailable замену, которую успели деактивировать
+// или назначить на этот PR.
+func (r *SQLiteRepository) ReassignReviewer(ctx context.Context, prID, oldUserID, newUserID, reason string) error {
+	const op = "SQLite.ReassignReviewer"
 
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, errors.WrapError(op, err)
+		return errors.WrapError(op, err)
 	}
 	defer func() {
 		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
 			return
 		}
 	}()
+
+	status, err := prStatus(ctx, tx, prID)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	switch status {
+	case models.PRStatusMerged:
+		return errors.WrapError(op, errors.ErrPRMerged)
+	case models.PRStatusClosed:
+		return errors.WrapError(op, errors.ErrPRClosed)
+	}
+
 	deleteQuery := `DELETE FROM pr_reviewers WHERE pr_id = ? AND user_id = ?`
-	_, err = tx.ExecContext(ctx, deleteQuery, prID, oldUserID)
+	result, err := tx.ExecContext(ctx, deleteQuery, prID, oldUserID)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if rowsAffected == 0 {
+		return errors.WrapError(op, errors.ErrNotAssigned)
+	}
+
+	var assigned int
+	err = tx.QueryRowContext(ctx, `SELECT 1 FROM pr_reviewers WHERE pr_id = ? AND user_id = ?`, prID, newUserID).Scan(&assigned)
+	if err == nil {
+		return errors.WrapError(op, errors.ErrReviewerUnavailable)
+	}
+	if err != sql.ErrNoRows {
+		return errors.WrapError(op, err)
+	}
+
+	if err := checkAvailable(ctx, tx, []string{newUserID}); err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	now := time.Now()
+	insertQuery := `INSERT INTO pr_reviewers (pr_id, user_id, assigned_at) VALUES (?, ?, ?)`
+	_, err = tx.ExecContext(ctx, insertQuery, prID, newUserID, now)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	if err := insertEvents(ctx, tx, reassignEvents(prID, oldUserID, newUserID, reason, now)...); err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	return nil
+}
+
+func (r *SQLiteRepository) GetPREvents(ctx context.Context, prID string) ([]models.PREvent, error) {
+	const op = "SQLite.GetPREvents"
+
+	exists, err := r.PRExists(ctx, prID)
 	if err != nil {
 		return nil, errors.WrapError(op, err)
 	}
+	if !exists {
+		return nil, errors.WrapError(op, errors.ErrPRNotFound)
+	}
 
-	insertQuery := `INSERT INTO pr_reviewers (pr_id, user_id) VALUES (?, ?)`
-	_, err = tx.ExecContext(ctx, insertQuery, prID, reviewerID)
+	query := `
+		SELECT id, pr_id, type, COALESCE(user_id, ''), COALESCE(related_user_id, ''),
+			COALESCE(reason, ''), COALESCE(state, ''), created_at
+		FROM pr_events
+		WHERE pr_id = ?
+		ORDER BY id
+	`
+	rows, err := r.db.QueryContext(ctx, query, prID)
 	if err != nil {
 		return nil, errors.WrapError(op, err)
 	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			return
+		}
+	}()
+
+	var events []models.PREvent
+	for rows.Next() {
+		var event models.PREvent
+		err := rows.Scan(
+			&event.ID, &event.PullRequestID, &event.Type, &event.UserID, &event.RelatedUserID,
+			&event.Reason, &event.State, &event.CreatedAt,
+		)
+		if err != nil {
+			return nil, errors.WrapError(op, err)
+		}
+		events = append(events, event)
+	}
 
-	if err = tx.Commit(); err != nil {
+	if err = rows.Err(); err != nil {
 		return nil, errors.WrapError(op, err)
 	}
 
-	return &reviewerID, nil
+	return events, nil
 }
 
 func (r *SQLiteRepository) PRExists(ctx context.Context, prID string) (bool, error) {
@@ -264,8 +611,10 @@ func (r *SQLiteRepository) GetTotalStats(ctx context.Context) (*models.TotalStat
 			(SELECT COUNT(*) FROM users WHERE is_active = 1) as active_users,
 			-- PR
 			(SELECT COUNT(*) FROM pull_requests) as total_prs,
-			(SELECT COUNT(*) FROM pull_requests WHERE status = 'OPEN') as open_prs,
+			(SELECT COUNT(*) FROM pull_requests WHERE status = 'OPEN' AND is_draft = 0) as open_prs,
+			(SELECT COUNT(*) FROM pull_requests WHERE status = 'OPEN' AND is_draft <> 0) as draft_prs,
 			(SELECT COUNT(*) FROM pull_requests WHERE status = 'MERGED') as merged_prs,
+			(SELECT COUNT(*) FROM pull_requests WHERE status = 'CLOSED') as closed_prs,
 			-- Среднее количество ревьюверов
 			COALESCE(
 				ROUND(
@@ -285,7 +634,9 @@ func (r *SQLiteRepository) GetTotalStats(ctx context.Context) (*models.TotalStat
 		&stats.ActiveUsers,
 		&stats.TotalPRs,
 		&stats.OpenPRs,
+		&stats.DraftPRs,
 		&stats.MergedPRs,
+		&stats.ClosedPRs,
 		&stats.AvgReviewersPerPR,
 	)
 	if err == sql.ErrNoRows {
@@ -298,13 +649,25 @@ func (r *SQLiteRepository) GetTotalStats(ctx context.Context) (*models.TotalStat
 	return stats, nil
 }
 
-// private methods
-
-func (r *SQLiteRepository) getPRReviewers(ctx context.Context, prID string) ([]string, error) {
-	const op = "SQLite.getPRReviewers"
+func (r *SQLiteRepository) GetReviewerCandidates(ctx context.Context, teamName string) ([]models.ReviewerCandidate, error) {
+	const op = "SQLite.GetReviewerCandidates"
 
-	query := `SELECT user_id FROM pr_reviewers WHERE pr_id = ? ORDER BY user_id`
-	rows, err := r.db.QueryContext(ctx, query, prID)
+	query := `
+		SELECT u.user_id, COUNT(pr.id) as open_reviews, MAX(prr.assigned_at) as last_assigned_at,
+			COALESCE(u.max_open_reviews, t.max_open_reviews) as max_open_reviews
+		FROM users u
+		LEFT JOIN teams t ON t.name = u.team_name
+		LEFT JOIN pr_reviewers prr ON u.user_id = prr.user_id
+		LEFT JOIN pull_requests pr ON prr.pr_id = pr.id AND pr.status = 'OPEN' AND pr.is_draft = 0
+		WHERE u.team_name = ? AND u.is_active = 1 AND NOT EXISTS (
+			SELECT 1 FROM user_absences a
+			WHERE a.user_id = u.user_id AND a.starts_at <= ? AND a.ends_at > ?
+		)
+		GROUP BY u.user_id, u.max_open_reviews, t.max_open_reviews
+		ORDER BY u.user_id
+	`
+	now := time.Now().UTC()
+	rows, err := r.db.QueryContext(ctx, query, teamName, now, now)
 	if err != nil {
 		return nil, errors.WrapError(op, err)
 	}
@@ -314,56 +677,116 @@ func (r *SQLiteRepository) getPRReviewers(ctx context.Context, prID string) ([]s
 		}
 	}()
 
-	var reviewers []string
+	var candidates []models.ReviewerCandidate
 	for rows.Next() {
-		var userID string
-		err := rows.Scan(&userID)
+		var candidate models.ReviewerCandidate
+		var lastAssignedAt sql.NullString
+		var maxOpenReviews sql.NullInt64
+		err := rows.Scan(&candidate.UserID, &candidate.OpenReviews, &lastAssignedAt, &maxOpenReviews)
 		if err != nil {
 			return nil, errors.WrapError(op, err)
 		}
-		reviewers = append(reviewers, userID)
+		if maxOpenReviews.Valid {
+			limit := int(maxOpenReviews.Int64)
+			candidate.MaxOpenReviews = &limit
+		}
+		if lastAssignedAt.Valid {
+			t, err := parseTime(lastAssignedAt.String)
+			if err != nil {
+				return nil, errors.WrapError(op, err)
+			}
+			candidate.LastAssignedAt = &t
+		}
+		candidates = append(candidates, candidate)
 	}
 
 	if err = rows.Err(); err != nil {
 		return nil, errors.WrapError(op, err)
 	}
 
-	return reviewers, nil
+	return candidates, nil
 }
 
-func (r *SQLiteRepository) getUserTeam(ctx context.Context, userID string) (string, error) {
-	const op = "SQLite.getUserTeam"
+func (r *SQLiteRepository) GetReviewerCandidatesByIDs(ctx context.Context, userIDs []string) ([]models.ReviewerCandidate, error) {
+	const op = "SQLite.GetReviewerCandidatesByIDs"
 
-	query := `SELECT team_name FROM users WHERE user_id = ?`
-	row := r.db.QueryRowContext(ctx, query, userID)
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
 
-	var teamName string
-	err := row.Scan(&teamName)
-	if err == sql.ErrNoRows {
-		return "", errors.ErrUserNotFound
+	args := make([]any, 0, len(userIDs)+2)
+	for _, userID := range userIDs {
+		args = append(args, userID)
 	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(userIDs)), ", ")
+
+	query := `
+		SELECT u.user_id, COUNT(pr.id) as open_reviews, MAX(prr.assigned_at) as last_assigned_at,
+			COALESCE(u.max_open_reviews, t.max_open_reviews) as max_open_reviews
+		FROM users u
+		LEFT JOIN teams t ON t.name = u.team_name
+		LEFT JOIN pr_reviewers prr ON u.user_id = prr.user_id
+		LEFT JOIN pull_requests pr ON prr.pr_id = pr.id AND pr.status = 'OPEN' AND pr.is_draft = 0
+		WHERE u.user_id IN (` + placeholders + `) AND u.is_active = 1 AND NOT EXISTS (
+			SELECT 1 FROM user_absences a
+			WHERE a.user_id = u.user_id AND a.starts_at <= ? AND a.ends_at > ?
+		)
+		GROUP BY u.user_id, u.max_open_reviews, t.max_open_reviews
+		ORDER BY u.user_id
+	`
+	now := time.Now().UTC()
+	args = append(args, now, now)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return "", errors.WrapError(op, err)
+		return nil, errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			return
+		}
+	}()
+
+	var candidates []models.ReviewerCandidate
+	for rows.Next() {
+		var candidate models.ReviewerCandidate
+		var lastAssignedAt sql.NullString
+		var maxOpenReviews sql.NullInt64
+		err := rows.Scan(&candidate.UserID, &candidate.OpenReviews, &lastAssignedAt, &maxOpenReviews)
+		if err != nil {
+			return nil, errors.WrapError(op, err)
+		}
+		if maxOpenReviews.Valid {
+			limit := int(maxOpenReviews.Int64)
+			candidate.MaxOpenReviews = &limit
+		}
+		if lastAssignedAt.Valid {
+			t, err := parseTime(lastAssignedAt.String)
+			if err != nil {
+				return nil, errors.WrapError(op, err)
+			}
+			candidate.LastAssignedAt = &t
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.WrapError(op, err)
 	}
 
-	return teamName, nil
+	return candidates, nil
 }
 
-func (r *SQLiteRepository) getActiveTeamMembers(ctx context.Context, teamName, excludeUserID string, excludeReviewers []string) ([]string, error) {
-	const op = "SQLite.getActiveTeamMembers"
+// private methods
 
-	excludeMap := make(map[string]bool)
-	for _, reviewer := range excludeReviewers {
-		excludeMap[reviewer] = true
-	}
+func (r *SQLiteRepository) getPRReviews(ctx context.Context, prID string) ([]models.Review, error) {
+	return prReviews(ctx, r.db, prID)
+}
 
-	query := `
-		SELECT user_id 
-		FROM users 
-		WHERE team_name = ? AND is_active = TRUE AND user_id != ?
-		ORDER BY user_id
-	`
-	rows, err := r.db.QueryContext(ctx, query, teamName, excludeUserID)
+func prReviews(ctx context.Context, q queryer, prID string) ([]models.Review, error) {
+	const op = "SQLite.prReviews"
+
+	query := `SELECT user_id, state, assigned_at, reviewed_at FROM pr_reviewers WHERE pr_id = ? ORDER BY user_id`
+	rows, err := q.QueryContext(ctx, query, prID)
 	if err != nil {
 		return nil, errors.WrapError(op, err)
 	}
@@ -373,40 +796,297 @@ func (r *SQLiteRepository) getActiveTeamMembers(ctx context.Context, teamName, e
 		}
 	}()
 
-	var members []string
+	var reviews []models.Review
 	for rows.Next() {
-		var userID string
-		err := rows.Scan(&userID)
+		var review models.Review
+		var assignedAt, reviewedAt sql.NullTime
+		err := rows.Scan(&review.UserID, &review.State, &assignedAt, &reviewedAt)
 		if err != nil {
 			return nil, errors.WrapError(op, err)
 		}
-		if !excludeMap[userID] {
-			members = append(members, userID)
+		if assignedAt.Valid {
+			review.AssignedAt = &assignedAt.Time
+		}
+		if reviewedAt.Valid {
+			review.ReviewedAt = &reviewedAt.Time
 		}
+		reviews = append(reviews, review)
 	}
 
 	if err = rows.Err(); err != nil {
 		return nil, errors.WrapError(op, err)
 	}
 
-	return members, nil
+	return reviews, nil
 }
 
-func selectRandomReviewers(reviewers []string, maxCount int) []string {
-	if len(reviewers) == 0 {
+func (r *SQLiteRepository) getPRFiles(ctx context.Context, prID string) ([]string, error) {
+	const op = "SQLite.getPRFiles"
+
+	query := `SELECT path FROM pr_files WHERE pr_id = ? ORDER BY path`
+	rows, err := r.db.QueryContext(ctx, query, prID)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			return
+		}
+	}()
+
+	var files []string
+	for rows.Next() {
+		var path string
+		err := rows.Scan(&path)
+		if err != nil {
+			return nil, errors.WrapError(op, err)
+		}
+		files = append(files, path)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	return files, nil
+}
+
+// prStatus: транзакции SQLite берут блокировку на запись сразу (см. New), поэтому статус
+// не изменится до её завершения.
+func prStatus(ctx context.Context, tx *sql.Tx, prID string) (models.PRStatus, error) {
+	const op = "SQLite.prStatus"
+
+	var status models.PRStatus
+	err := tx.QueryRowContext(ctx, `SELECT status FROM pull_requests WHERE id = ?`, prID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return "", errors.WrapError(op, errors.ErrPRNotFound)
+	}
+	if err != nil {
+		return "", errors.WrapError(op, err)
+	}
+
+	return status, nil
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// lockedPR вызывается после prStatus, поэтому прочитанное не изменится до коммита.
+func lockedPR(ctx context.Context, tx *sql.Tx, prID string) (*models.PullRequest, error) {
+	const op = "SQLite.lockedPR"
+
+	var pr models.PullRequest
+	query := `SELECT id, name, author_id, status, is_draft FROM pull_requests WHERE id = ?`
+	err := tx.QueryRowContext(ctx, query, prID).Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.IsDraft)
+	if err == sql.ErrNoRows {
+		return nil, errors.WrapError(op, errors.ErrPRNotFound)
+	}
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	pr.Reviews, err = prReviews(ctx, tx, prID)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	for _, review := range pr.Reviews {
+		pr.AssignedReviewers = append(pr.AssignedReviewers, review.UserID)
+	}
+
+	return &pr, nil
+}
+
+func checkAvailable(ctx context.Context, tx *sql.Tx, userIDs []string) error {
+	const op = "SQLite.checkAvailable"
+
+	userIDs = slices.Compact(slices.Sorted(slices.Values(userIDs)))
+	if len(userIDs) == 0 {
 		return nil
 	}
 
-	if len(reviewers) <= maxCount {
-		return reviewers
+	args := make([]any, 0, len(userIDs)+2)
+	for _, userID := range userIDs {
+		args = append(args, userID)
 	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(userIDs)), ", ")
 
-	shuffled := make([]string, len(reviewers))
-	copy(shuffled, reviewers)
-	rand.New(rand.NewSource(time.Now().UnixNano()))
-	rand.Shuffle(len(shuffled), func(i, j int) {
-		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
-	})
+	query := `
+		SELECT COUNT(*)
+		FROM users u
+		WHERE u.user_id IN (` + placeholders + `) AND u.is_active = 1 AND NOT EXISTS (
+			SELECT 1 FROM user_absences a
+			WHERE a.user_id = u.user_id AND a.starts_at <= ? AND a.ends_at > ?
+		)
+	`
+	now := time.Now().UTC()
+	args = append(args, now, now)
+
+	var available int
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&available); err != nil {
+		return errors.WrapError(op, err)
+	}
+	if available != len(userIDs) {
+		return errors.ErrReviewerUnavailable
+	}
+
+	query = `
+		SELECT COUNT(*)
+		FROM users u
+		LEFT JOIN teams t ON t.name = u.team_name
+		WHERE u.user_id IN (` + placeholders + `) AND COALESCE(u.max_open_reviews, t.max_open_reviews) <= (
+			SELECT COUNT(*)
+			FROM pr_reviewers prr
+			JOIN pull_requests pr ON pr.id = prr.pr_id AND pr.status = 'OPEN' AND pr.is_draft = 0
+			WHERE prr.user_id = u.user_id
+		)
+	`
+	var atCapacity int
+	if err := tx.QueryRowContext(ctx, query, args[:len(userIDs)]...).Scan(&atCapacity); err != nil {
+		return errors.WrapError(op, err)
+	}
+	if atCapacity > 0 {
+		return errors.ErrReviewerUnavailable
+	}
+
+	return nil
+}
+
+func isUniqueViolation(err error) bool {
+	var sqliteErr *sqlitedriver.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	code := sqliteErr.Code()
+	return code == sqlitelib.SQLITE_CONSTRAINT_PRIMARYKEY || code == sqlitelib.SQLITE_CONSTRAINT_UNIQUE
+}
+
+// closePR возвращает false, если PR не найден или уже не открыт.
+func closePR(ctx context.Context, tx *sql.Tx, prID string, closedAt time.Time, reason string) (bool, error) {
+	const op = "SQLite.closePR"
+
+	query := `UPDATE pull_requests SET status = 'CLOSED', closed_at = ? WHERE id = ? AND status = 'OPEN'`
+	result, err := tx.ExecContext(ctx, query, closedAt, prID)
+	if err != nil {
+		return false, errors.WrapError(op, err)
+	}
 
-	return shuffled[:maxCount]
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.WrapError(op, err)
+	}
+	if rowsAffected == 0 {
+		return false, nil
+	}
+
+	err = insertEvents(ctx, tx, models.PREvent{PullRequestID: prID, Type: models.PREventClosed, Reason: reason, CreatedAt: closedAt})
+	if err != nil {
+		return false, errors.WrapError(op, err)
+	}
+
+	return true, nil
+}
+
+func insertEvents(ctx context.Context, tx *sql.Tx, events ...models.PREvent) error {
+	const op = "SQLite.insertEvents"
+
+	query := `
+		INSERT INTO pr_events (pr_id, type, user_id, related_user_id, reason, state, created_at)
+		VALUES (?, ?, NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''), ?)
+	`
+	for _, event := range events {
+		_, err := tx.ExecContext(ctx, query,
+			event.PullRequestID, event.Type, event.UserID, event.RelatedUserID, event.Reason, event.State, event.CreatedAt,
+		)
+		if err != nil {
+			return errors.WrapError(op, err)
+		}
+	}
+
+	return nil
+}
+
+// checkReassignments: PR, который успели влить или закрыть, делает план устаревшим,
+// ErrReviewerUnavailable заставляет сервис составить его заново.
+func checkReassignments(ctx context.Context, tx *sql.Tx, reassignments []models.Reassignment) error {
+	const op = "SQLite.checkReassignments"
+
+	prIDs := make([]string, 0, len(reassignments))
+	for _, reassignment := range reassignments {
+		prIDs = append(prIDs, reassignment.PullRequestID)
+	}
+	for _, prID := range slices.Compact(slices.Sorted(slices.Values(prIDs))) {
+		status, err := prStatus(ctx, tx, prID)
+		if errors.Is(err, errors.ErrPRNotFound) {
+			return errors.WrapError(op, errors.ErrNotAssigned)
+		}
+		if err != nil {
+			return errors.WrapError(op, err)
+		}
+		if status != models.PRStatusOpen {
+			return errors.WrapError(op, errors.ErrReviewerUnavailable)
+		}
+	}
+
+	return nil
+}
+
+// applyReassignments: замена, ставшая недоступной или уже назначенной на тот же PR, отклоняет план.
+func applyReassignments(ctx context.Context, tx *sql.Tx, reassignments []models.Reassignment, reason string) error {
+	const op = "SQLite.applyReassignments"
+
+	deleteQuery := `DELETE FROM pr_reviewers WHERE pr_id = ? AND user_id = ?`
+	insertQuery := `INSERT INTO pr_reviewers (pr_id, user_id, assigned_at) VALUES (?, ?, ?)`
+	now := time.Now()
+	for _, reassignment := range reassignments {
+		result, err := tx.ExecContext(ctx, deleteQuery, reassignment.PullRequestID, reassignment.OldUserID)
+		if err != nil {
+			return errors.WrapError(op, err)
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return errors.WrapError(op, err)
+		}
+		if rowsAffected == 0 {
+			return errors.WrapError(op, errors.ErrNotAssigned)
+		}
+
+		var assigned int
+		err = tx.QueryRowContext(ctx, `SELECT 1 FROM pr_reviewers WHERE pr_id = ? AND user_id = ?`,
+			reassignment.PullRequestID, reassignment.NewUserID).Scan(&assigned)
+		if err == nil {
+			return errors.WrapError(op, errors.ErrReviewerUnavailable)
+		}
+		if err != sql.ErrNoRows {
+			return errors.WrapError(op, err)
+		}
+		if err := checkAvailable(ctx, tx, []string{reassignment.NewUserID}); err != nil {
+			return errors.WrapError(op, err)
+		}
+
+		_, err = tx.ExecContext(ctx, insertQuery, reassignment.PullRequestID, reassignment.NewUserID, now)
+		if err != nil {
+			return errors.WrapError(op, err)
+		}
+
+		events := reassignEvents(reassignment.PullRequestID, reassignment.OldUserID, reassignment.NewUserID, reason, now)
+		if err := insertEvents(ctx, tx, events...); err != nil {
+			return errors.WrapError(op, err)
+		}
+	}
+
+	return nil
+}
+
+func reassignEvents(prID, oldUserID, newUserID, reason string, at time.Time) []models.PREvent {
+	return []models.PREvent{
+		{
+			PullRequestID: prID, Type: models.PREventReviewerRemoved,
+			UserID: oldUserID, RelatedUserID: newUserID, Reason: reason, CreatedAt: at,
+		},
+		{
+			PullRequestID: prID, Type: models.PREventReviewerAssigned,
+			UserID: newUserID, RelatedUserID: oldUserID, Reason: reason, CreatedAt: at,
+		},
+	}
 }
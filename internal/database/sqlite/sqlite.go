@@ -4,6 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"log"
+	"strings"
+	"time"
 
 	"pr-review/internal/config"
 	"pr-review/internal/errors"
@@ -22,57 +25,37 @@ type Config struct {
 func New(ctx context.Context, cfg *config.DatabaseConfig) (*SQLiteRepository, error) {
 	const op = "SQLiteRepository.Init"
 
-	db, err := sql.Open("sqlite", cfg.Path)
-	if err != nil {
+	// Пустой путь у modernc - отдельная временная база на каждое соединение пула
+	if cfg.Path == "" {
+		return nil, fmt.Errorf("%s: database path is not set", op)
+	}
+
+	// Транзакции сразу берут блокировку на запись (BEGIN IMMEDIATE): проверки внутри
+	// транзакции не устаревают до коммита, а конкурентные записи ждут busy_timeout, а не падают
+	separator := "?"
+	if strings.Contains(cfg.Path, "?") {
+		separator = "&"
+	}
+	dsn := cfg.Path + separator + "_txlock=immediate&_pragma=busy_timeout(5000)"
+
+	// Миграции пересоздают таблицы (DROP TABLE и RENAME), с внешними ключами удаление
+	// таблицы каскадом стёрло бы ссылающиеся на неё строки. Поэтому ключи включаются
+	// только у соединений, открытых после миграций
+	if err := runMigrations(ctx, dsn, cfg.MigrationsPath); err != nil {
 		return nil, errors.WrapError(op, err)
 	}
 
-	r := &SQLiteRepository{db: db}
-
-	queries := []string{
-		`CREATE TABLE IF NOT EXISTS teams (
-			name TEXT PRIMARY KEY
-		)`,
-
-		`CREATE TABLE IF NOT EXISTS users (
-			user_id TEXT PRIMARY KEY,
-			username TEXT NOT NULL UNIQUE,
-			is_active BOOLEAN NOT NULL DEFAULT TRUE,
-			team_name TEXT NOT NULL,
-			FOREIGN KEY (team_name) REFERENCES teams(name) ON DELETE CASCADE
-		)`,
-
-		`CREATE TABLE IF NOT EXISTS pull_requests (
-			id TEXT PRIMARY KEY,
-			name TEXT NOT NULL,
-			author_id TEXT NOT NULL,
-			status TEXT NOT NULL DEFAULT 'OPEN',
-			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-			merged_at DATETIME DEFAULT NULL,
-			FOREIGN KEY (author_id) REFERENCES users(user_id) ON DELETE CASCADE
-		)`,
-
-		`CREATE TABLE IF NOT EXISTS pr_reviewers (
-			pr_id TEXT NOT NULL,
-			user_id TEXT NOT NULL,
-			PRIMARY KEY (pr_id, user_id),
-			FOREIGN KEY (pr_id) REFERENCES pull_requests(id) ON DELETE CASCADE,
-			FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
-		)`,
-
-		`CREATE INDEX IF NOT EXISTS idx_users_team_active ON users(team_name, is_active)`,
-		`CREATE INDEX IF NOT EXISTS idx_users_active ON users(is_active)`,
-		`CREATE INDEX IF NOT EXISTS idx_prs_author ON pull_requests(author_id)`,
-		`CREATE INDEX IF NOT EXISTS idx_pr_reviewers_user ON pr_reviewers(user_id)`,
+	db, err := sql.Open("sqlite", dsn+"&_pragma=foreign_keys(1)")
+	if err != nil {
+		return nil, errors.WrapError(op, err)
 	}
 
-	for _, query := range queries {
-		if _, err := r.db.ExecContext(ctx, query); err != nil {
-			return nil, errors.WrapError(op, err)
-		}
+	if err := db.PingContext(ctx); err != nil {
+		return nil, errors.WrapError(op, err)
 	}
 
-	return r, nil
+	log.Println("SQLite repository initialized successfully")
+	return &SQLiteRepository{db: db}, nil
 }
 
 func (r *SQLiteRepository) Close() error {
@@ -100,3 +83,27 @@ func (r *SQLiteRepository) Ping(ctx context.Context) error {
 	}
 	return nil
 }
+
+// Агрегаты (MAX и т.п.) теряют тип колонки, поэтому драйвер отдаёт время строкой
+var timeFormats = []string{
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+}
+
+func parseTime(s string) (time.Time, error) {
+	// time.Time.String() дописывает показания монотонных часов
+	if i := strings.Index(s, " m="); i > 0 {
+		s = s[:i]
+	}
+	s = strings.TrimSuffix(s, "Z")
+
+	for _, format := range timeFormats {
+		if t, err := time.Parse(format, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse time %q", s)
+}
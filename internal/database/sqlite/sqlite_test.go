@@ -0,0 +1,25 @@
+package sqlite_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"pr-review/internal/config"
+	"pr-review/internal/database"
+	"pr-review/internal/database/repotest"
+	"pr-review/internal/database/sqlite"
+)
+
+func TestRepository(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) database.Repository {
+		repo, err := sqlite.New(t.Context(), &config.DatabaseConfig{
+			Path:           filepath.Join(t.TempDir(), "db.sqlite"),
+			MigrationsPath: "../../../migrations",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = repo.Close() })
+		return repo
+	})
+}
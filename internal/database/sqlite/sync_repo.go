@@ -0,0 +1,196 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+func (r *SQLiteRepository) EnqueueSyncTasks(ctx context.Context, tasks []models.SyncTask) error {
+	const op = "SQLite.EnqueueSyncTasks"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			return
+		}
+	}()
+
+	query := `
+		INSERT INTO sync_tasks (pr_id, provider, action, user_id, body, next_attempt_at, created_at)
+		VALUES (?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), ?, ?)
+	`
+	for _, task := range tasks {
+		_, err = tx.ExecContext(ctx, query,
+			task.PullRequestID, task.Provider, task.Action, task.UserID, task.Body,
+			task.NextAttemptAt.UTC(), task.CreatedAt.UTC(),
+		)
+		if err != nil {
+			return errors.WrapError(op, err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	return nil
+}
+
+// ClaimDueSyncTasks выдаёт задачу PR, только если перед ней нет более ранних ожидающих задач того же PR.
+func (r *SQLiteRepository) ClaimDueSyncTasks(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]models.SyncTask, error) {
+	const op = "SQLite.ClaimDueSyncTasks"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			return
+		}
+	}()
+
+	query := `
+		SELECT ` + syncTaskColumns + `
+		FROM sync_tasks s
+		WHERE s.status = 'pending' AND s.next_attempt_at <= ?
+			AND NOT EXISTS (
+				SELECT 1 FROM sync_tasks o
+				WHERE o.pr_id = s.pr_id AND o.status = 'pending' AND o.id < s.id
+			)
+		ORDER BY s.next_attempt_at, s.id
+		LIMIT ?
+	`
+	rows, err := tx.QueryContext(ctx, query, now.UTC(), limit)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	tasks, err := scanSyncTasks(rows)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	leasedUntil := now.Add(lease).UTC()
+	for i := range tasks {
+		_, err := tx.ExecContext(ctx, `UPDATE sync_tasks SET next_attempt_at = ? WHERE id = ?`, leasedUntil, tasks[i].ID)
+		if err != nil {
+			return nil, errors.WrapError(op, err)
+		}
+		tasks[i].NextAttemptAt = leasedUntil
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	return tasks, nil
+}
+
+func (r *SQLiteRepository) SaveSyncAttempt(ctx context.Context, task *models.SyncTask) error {
+	const op = "SQLite.SaveSyncAttempt"
+
+	query := `
+		UPDATE sync_tasks
+		SET status = ?, attempts = ?, last_error = NULLIF(?, ''), next_attempt_at = ?, completed_at = ?
+		WHERE id = ?
+	`
+	var completedAt any
+	if task.CompletedAt != nil {
+		completedAt = task.CompletedAt.UTC()
+	}
+	_, err := r.db.ExecContext(ctx, query,
+		task.Status, task.Attempts, task.LastError, task.NextAttemptAt.UTC(), completedAt, task.ID,
+	)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) GetSyncTasks(ctx context.Context, status string, limit int) ([]models.SyncTask, error) {
+	const op = "SQLite.GetSyncTasks"
+
+	query := `
+		SELECT ` + syncTaskColumns + `
+		FROM sync_tasks
+		WHERE ? = '' OR status = ?
+		ORDER BY id DESC
+		LIMIT ?
+	`
+	rows, err := r.db.QueryContext(ctx, query, status, status, limit)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	tasks, err := scanSyncTasks(rows)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	return tasks, nil
+}
+
+func (r *SQLiteRepository) RetrySyncTask(ctx context.Context, id int64, at time.Time) error {
+	const op = "SQLite.RetrySyncTask"
+
+	query := `
+		UPDATE sync_tasks
+		SET status = 'pending', attempts = 0, last_error = NULL, next_attempt_at = ?
+		WHERE id = ? AND status = 'failed'
+	`
+	result, err := r.db.ExecContext(ctx, query, at.UTC(), id)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if rowsAffected == 0 {
+		return errors.WrapError(op, errors.ErrSyncTaskNotFound)
+	}
+	return nil
+}
+
+// private methods
+
+const syncTaskColumns = `id, pr_id, provider, action, COALESCE(user_id, ''), COALESCE(body, ''), status, attempts,
+	COALESCE(last_error, ''), next_attempt_at, completed_at, created_at`
+
+func scanSyncTasks(rows *sql.Rows) ([]models.SyncTask, error) {
+	defer func() {
+		if err := rows.Close(); err != nil {
+			return
+		}
+	}()
+
+	var tasks []models.SyncTask
+	for rows.Next() {
+		var task models.SyncTask
+		err := rows.Scan(
+			&task.ID, &task.PullRequestID, &task.Provider, &task.Action, &task.UserID, &task.Body, &task.Status,
+			&task.Attempts, &task.LastError, &task.NextAttemptAt, &task.CompletedAt, &task.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
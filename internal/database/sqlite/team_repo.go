@@ -3,6 +3,7 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"pr-review/internal/errors"
 	"pr-review/internal/models"
@@ -39,8 +40,16 @@ func (r *SQLiteRepository) CreateTeam(ctx context.Context, team *models.Team) er
 		}
 	}()
 
-	query := `INSERT INTO teams (name) VALUES (?)`
-	_, err = tx.ExecContext(ctx, query, team.Name)
+	policy := team.MergePolicy
+	query := `
+		INSERT INTO teams (
+			name, required_reviewers, required_approvals,
+			block_on_changes_requested, forbid_self_approval, require_owner_approval, max_open_reviews
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err = tx.ExecContext(ctx, query, team.Name, team.RequiredReviewers, policy.RequiredApprovals,
+		policy.BlockOnChangesRequested, policy.ForbidSelfApproval, policy.RequireOwnerApproval, team.MaxOpenReviews)
 	if err != nil {
 		return errors.WrapError(op, err)
 	}
@@ -63,12 +72,38 @@ func (r *SQLiteRepository) CreateTeam(ctx context.Context, team *models.Team) er
 func (r *SQLiteRepository) GetTeamByName(ctx context.Context, name string) (*models.Team, error) {
 	const op = "SQLite.GetTeamByName"
 
-	exists, err := r.TeamExists(ctx, name)
+	team := &models.Team{Name: name}
+
+	teamQuery := `
+		SELECT required_reviewers, required_approvals,
+			block_on_changes_requested, forbid_self_approval, require_owner_approval, archived_at,
+			max_open_reviews
+		FROM teams
+		WHERE name = ?
+	`
+	var archivedAt sql.NullTime
+	var maxOpenReviews sql.NullInt64
+	err := r.db.QueryRowContext(ctx, teamQuery, name).Scan(
+		&team.RequiredReviewers,
+		&team.MergePolicy.RequiredApprovals,
+		&team.MergePolicy.BlockOnChangesRequested,
+		&team.MergePolicy.ForbidSelfApproval,
+		&team.MergePolicy.RequireOwnerApproval,
+		&archivedAt,
+		&maxOpenReviews,
+	)
+	if err == sql.ErrNoRows {
+		return nil, errors.WrapError(op, errors.ErrTeamNotFound)
+	}
 	if err != nil {
 		return nil, errors.WrapError(op, err)
 	}
-	if !exists {
-		return nil, errors.WrapError(op, errors.ErrTeamNotFound)
+	if archivedAt.Valid {
+		team.ArchivedAt = &archivedAt.Time
+	}
+	if maxOpenReviews.Valid {
+		limit := int(maxOpenReviews.Int64)
+		team.MaxOpenReviews = &limit
 	}
 
 	query := `
@@ -101,14 +136,550 @@ func (r *SQLiteRepository) GetTeamByName(ctx context.Context, name string) (*mod
 		return nil, errors.WrapError(op, err)
 	}
 
-	team := &models.Team{
-		Name:    name,
-		Members: members,
+	team.Members = members
+
+	team.Fallbacks, err = r.GetTeamFallbacks(ctx, name)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
 	}
 
 	return team, nil
 }
 
+// AddTeamMember возвращает ранее исключённого из команды (team_name = NULL) с новыми данными.
+func (r *SQLiteRepository) AddTeamMember(ctx context.Context, teamName string, member *models.TeamMember) error {
+	const op = "SQLite.AddTeamMember"
+
+	exists, err := r.TeamExists(ctx, teamName)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if !exists {
+		return errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+
+	var currentTeam sql.NullString
+	query := `INSERT INTO users (username, is_active, team_name, user_id) VALUES (?, ?, ?, ?)`
+	err = r.db.QueryRowContext(ctx, `SELECT team_name FROM users WHERE user_id = ?`, member.UserID).Scan(&currentTeam)
+	switch {
+	case err == sql.ErrNoRows:
+	case err != nil:
+		return errors.WrapError(op, err)
+	case currentTeam.Valid:
+		return errors.WrapError(op, errors.ErrUserExists)
+	default:
+		query = `UPDATE users SET username = ?, is_active = ?, team_name = ? WHERE user_id = ?`
+	}
+
+	var taken int
+	err = r.db.QueryRowContext(ctx, `SELECT 1 FROM users WHERE username = ? AND user_id <> ?`,
+		member.Username, member.UserID).Scan(&taken)
+	if err == nil {
+		return errors.WrapError(op, errors.ErrUserExists)
+	}
+	if err != sql.ErrNoRows {
+		return errors.WrapError(op, err)
+	}
+
+	_, err = r.db.ExecContext(ctx, query, member.Username, member.IsActive, teamName, member.UserID)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	return nil
+}
+
+// RemoveTeamMember оставляет строку пользователя, чтобы не потерять историю его PR и ревью.
+func (r *SQLiteRepository) RemoveTeamMember(ctx context.Context, teamName, userID string, reassignments []models.Reassignment) error {
+	const op = "SQLite.RemoveTeamMember"
+
+	exists, err := r.TeamExists(ctx, teamName)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if !exists {
+		return errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+
+	exists, err = r.UserExists(ctx, userID)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if !exists {
+		return errors.WrapError(op, errors.ErrUserNotFound)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			return
+		}
+	}()
+
+	if err := checkReassignments(ctx, tx, reassignments); err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	query := `UPDATE users SET team_name = NULL WHERE user_id = ? AND team_name = ?`
+	result, err := tx.ExecContext(ctx, query, userID, teamName)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if rowsAffected == 0 {
+		return errors.WrapError(op, errors.ErrNotMember)
+	}
+
+	if err := applyReassignments(ctx, tx, reassignments, models.ReasonLeftTeam); err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	return nil
+}
+
+// RenameTeam: внешние ключи не каскадируют обновление, поэтому создаётся строка с новым именем,
+// на неё переводятся участники и CODEOWNERS, а старая строка удаляется.
+func (r *SQLiteRepository) RenameTeam(ctx context.Context, oldName, newName string) error {
+	const op = "SQLite.RenameTeam"
+
+	exists, err := r.TeamExists(ctx, oldName)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if !exists {
+		return errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+
+	exists, err = r.TeamExists(ctx, newName)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if exists {
+		return errors.WrapError(op, errors.ErrTeamExists)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			return
+		}
+	}()
+
+	queries := []string{
+		`INSERT INTO teams (
+			name, required_reviewers, required_approvals,
+			block_on_changes_requested, forbid_self_approval, require_owner_approval, archived_at,
+			max_open_reviews
+		)
+		SELECT ?, required_reviewers, required_approvals,
+			block_on_changes_requested, forbid_self_approval, require_owner_approval, archived_at,
+			max_open_reviews
+		FROM teams
+		WHERE name = ?`,
+		`UPDATE users SET team_name = ? WHERE team_name = ?`,
+		`UPDATE team_codeowners SET team_name = ? WHERE team_name = ?`,
+		`UPDATE team_chats SET team_name = ? WHERE team_name = ?`,
+		`UPDATE team_fallbacks SET team_name = ? WHERE team_name = ?`,
+		`UPDATE team_fallbacks SET source_name = ? WHERE kind = 'team' AND source_name = ?`,
+	}
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query, newName, oldName); err != nil {
+			return errors.WrapError(op, err)
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM teams WHERE name = ?`, oldName)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) GetOpenPRIDsByTeam(ctx context.Context, teamName string) ([]string, error) {
+	const op = "SQLite.GetOpenPRIDsByTeam"
+
+	query := `
+		SELECT pr.id
+		FROM pull_requests pr
+		JOIN users u ON u.user_id = pr.author_id
+		WHERE u.team_name = ? AND pr.status = 'OPEN'
+		ORDER BY pr.id
+	`
+	rows, err := r.db.QueryContext(ctx, query, teamName)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			return
+		}
+	}()
+
+	var prIDs []string
+	for rows.Next() {
+		var prID string
+		if err := rows.Scan(&prID); err != nil {
+			return nil, errors.WrapError(op, err)
+		}
+		prIDs = append(prIDs, prID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	return prIDs, nil
+}
+
+// TeamHasHistory: такие PR и ревью потерялись бы при каскадном удалении.
+func (r *SQLiteRepository) TeamHasHistory(ctx context.Context, teamName string) (bool, error) {
+	const op = "SQLite.TeamHasHistory"
+
+	query := `
+		SELECT 1
+		FROM users u
+		WHERE u.team_name = ? AND (
+			EXISTS (SELECT 1 FROM pull_requests pr WHERE pr.author_id = u.user_id)
+			OR EXISTS (SELECT 1 FROM pr_reviewers prr WHERE prr.user_id = u.user_id)
+		)
+		LIMIT 1
+	`
+	var found int
+	err := r.db.QueryRowContext(ctx, query, teamName).Scan(&found)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.WrapError(op, err)
+	}
+
+	return true, nil
+}
+
+func (r *SQLiteRepository) ArchiveTeam(ctx context.Context, teamName string, archivedAt time.Time, closePRs []string) error {
+	const op = "SQLite.ArchiveTeam"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			return
+		}
+	}()
+
+	result, err := tx.ExecContext(ctx, `UPDATE teams SET archived_at = ? WHERE name = ?`, archivedAt, teamName)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if rowsAffected == 0 {
+		return errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE users SET is_active = 0 WHERE team_name = ?`, teamName)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	for _, prID := range closePRs {
+		if _, err := closePR(ctx, tx, prID, archivedAt, models.ReasonTeamArchived); err != nil {
+			return errors.WrapError(op, err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	return nil
+}
+
+// DeleteTeam вызывается только для команды без истории PR, см. TeamHasHistory.
+func (r *SQLiteRepository) DeleteTeam(ctx context.Context, teamName string) error {
+	const op = "SQLite.DeleteTeam"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			return
+		}
+	}()
+
+	queries := []string{
+		`DELETE FROM team_codeowners WHERE team_name = ?`,
+		`DELETE FROM team_chats WHERE team_name = ?`,
+		`DELETE FROM team_fallbacks WHERE team_name = ?`,
+		`DELETE FROM team_fallbacks WHERE kind = 'team' AND source_name = ?`,
+		`DELETE FROM reviewer_pool_members WHERE user_id IN (SELECT user_id FROM users WHERE team_name = ?)`,
+		`DELETE FROM users WHERE team_name = ?`,
+	}
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query, teamName); err != nil {
+			return errors.WrapError(op, err)
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM teams WHERE name = ?`, teamName)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if rowsAffected == 0 {
+		return errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) SetTeamMergePolicy(ctx context.Context, teamName string, policy *models.MergePolicy) error {
+	const op = "SQLite.SetTeamMergePolicy"
+
+	query := `
+		UPDATE teams
+		SET required_approvals = ?, block_on_changes_requested = ?,
+			forbid_self_approval = ?, require_owner_approval = ?
+		WHERE name = ?
+	`
+	result, err := r.db.ExecContext(ctx, query, policy.RequiredApprovals, policy.BlockOnChangesRequested,
+		policy.ForbidSelfApproval, policy.RequireOwnerApproval, teamName)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if rowsAffected == 0 {
+		return errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+
+	return nil
+}
+
+func (r *SQLiteRepository) SetTeamMaxOpenReviews(ctx context.Context, teamName string, limit *int) error {
+	const op = "SQLite.SetTeamMaxOpenReviews"
+
+	query := `UPDATE teams SET max_open_reviews = ? WHERE name = ?`
+	result, err := r.db.ExecContext(ctx, query, limit, teamName)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if rowsAffected == 0 {
+		return errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+
+	return nil
+}
+
+func (r *SQLiteRepository) GetTeamFallbacks(ctx context.Context, teamName string) ([]models.ReviewerFallback, error) {
+	const op = "SQLite.GetTeamFallbacks"
+
+	query := `SELECT kind, source_name FROM team_fallbacks WHERE team_name = ? ORDER BY position`
+	rows, err := r.db.QueryContext(ctx, query, teamName)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			return
+		}
+	}()
+
+	var fallbacks []models.ReviewerFallback
+	for rows.Next() {
+		var fallback models.ReviewerFallback
+		if err := rows.Scan(&fallback.Kind, &fallback.Name); err != nil {
+			return nil, errors.WrapError(op, err)
+		}
+		fallbacks = append(fallbacks, fallback)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	return fallbacks, nil
+}
+
+func (r *SQLiteRepository) SetTeamFallbacks(ctx context.Context, teamName string, fallbacks []models.ReviewerFallback) error {
+	const op = "SQLite.SetTeamFallbacks"
+
+	exists, err := r.TeamExists(ctx, teamName)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if !exists {
+		return errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			return
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM team_fallbacks WHERE team_name = ?`, teamName)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	query := `INSERT INTO team_fallbacks (team_name, position, kind, source_name) VALUES (?, ?, ?, ?)`
+	for i, fallback := range fallbacks {
+		if _, err := tx.ExecContext(ctx, query, teamName, i, fallback.Kind, fallback.Name); err != nil {
+			return errors.WrapError(op, err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) SetTeamCodeOwners(ctx context.Context, codeOwners *models.CodeOwners) error {
+	const op = "SQLite.SetTeamCodeOwners"
+
+	exists, err := r.TeamExists(ctx, codeOwners.TeamName)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if !exists {
+		return errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+
+	query := `
+		INSERT INTO team_codeowners (team_name, content, mode, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (team_name) DO UPDATE
+		SET content = excluded.content, mode = excluded.mode, updated_at = excluded.updated_at
+	`
+	_, err = r.db.ExecContext(ctx, query, codeOwners.TeamName, codeOwners.Content, codeOwners.Mode, codeOwners.UpdatedAt)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	return nil
+}
+
+func (r *SQLiteRepository) GetTeamCodeOwners(ctx context.Context, teamName string) (*models.CodeOwners, error) {
+	const op = "SQLite.GetTeamCodeOwners"
+
+	query := `SELECT team_name, content, mode, updated_at FROM team_codeowners WHERE team_name = ?`
+	row := r.db.QueryRowContext(ctx, query, teamName)
+
+	var codeOwners models.CodeOwners
+	err := row.Scan(&codeOwners.TeamName, &codeOwners.Content, &codeOwners.Mode, &codeOwners.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, errors.WrapError(op, errors.ErrCodeOwnersNotFound)
+	}
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	return &codeOwners, nil
+}
+
+func (r *SQLiteRepository) SetTeamChat(ctx context.Context, chat *models.TeamChat) error {
+	const op = "SQLite.SetTeamChat"
+
+	exists, err := r.TeamExists(ctx, chat.TeamName)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if !exists {
+		return errors.WrapError(op, errors.ErrTeamNotFound)
+	}
+
+	query := `
+		INSERT INTO team_chats (team_name, url, format, assigned_template, reassigned_template, merged_template, updated_at)
+		VALUES (?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''), ?)
+		ON CONFLICT (team_name) DO UPDATE
+		SET url = excluded.url, format = excluded.format, assigned_template = excluded.assigned_template,
+			reassigned_template = excluded.reassigned_template, merged_template = excluded.merged_template,
+			updated_at = excluded.updated_at
+	`
+	_, err = r.db.ExecContext(ctx, query,
+		chat.TeamName, chat.URL, chat.Format,
+		chat.Templates.ReviewerAssigned, chat.Templates.ReviewerReassigned, chat.Templates.PRMerged, chat.UpdatedAt,
+	)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	return nil
+}
+
+func (r *SQLiteRepository) GetTeamChat(ctx context.Context, teamName string) (*models.TeamChat, error) {
+	const op = "SQLite.GetTeamChat"
+
+	query := `
+		SELECT team_name, url, format, COALESCE(assigned_template, ''), COALESCE(reassigned_template, ''),
+			COALESCE(merged_template, ''), updated_at
+		FROM team_chats
+		WHERE team_name = ?
+	`
+	row := r.db.QueryRowContext(ctx, query, teamName)
+
+	var chat models.TeamChat
+	err := row.Scan(
+		&chat.TeamName, &chat.URL, &chat.Format, &chat.Templates.ReviewerAssigned,
+		&chat.Templates.ReviewerReassigned, &chat.Templates.PRMerged, &chat.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, errors.WrapError(op, errors.ErrChatNotFound)
+	}
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	return &chat, nil
+}
+
 func (r *SQLiteRepository) TeamExists(ctx context.Context, teamName string) (bool, error) {
 	const op = "SQLite.TeamExists"
 
@@ -161,7 +732,7 @@ func (r *SQLiteRepository) GetPRsCntByTeam(ctx context.Context, teamName string)
 }
 
 func (r *SQLiteRepository) GetAvgReviewersPerPR(ctx context.Context, teamName string) (float64, error) {
-	const op = "SQLite.GetPRsCntByTeam"
+	const op = "SQLite.GetAvgReviewersPerPR"
 
 	exists, err := r.TeamExists(ctx, teamName)
 	if err != nil {
@@ -173,10 +744,13 @@ func (r *SQLiteRepository) GetAvgReviewersPerPR(ctx context.Context, teamName st
 
 	query := `
 		SELECT 
-			ROUND(
-				CAST(COUNT(prr.user_id) AS FLOAT) / 
-				NULLIF(COUNT(DISTINCT pr.id), 0), 
-				2
+			COALESCE(
+				ROUND(
+					CAST(COUNT(prr.user_id) AS FLOAT) / 
+					NULLIF(COUNT(DISTINCT pr.id), 0), 
+					2
+				), 
+				0
 			) as avg_reviewers_per_pr
 		FROM users u
 		LEFT JOIN pull_requests pr ON u.user_id = pr.author_id
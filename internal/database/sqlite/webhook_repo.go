@@ -0,0 +1,277 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+func (r *SQLiteRepository) CreateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	const op = "SQLite.CreateWebhook"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			return
+		}
+	}()
+
+	query := `INSERT INTO webhooks (id, url, secret, created_at) VALUES (?, ?, ?, ?)`
+	_, err = tx.ExecContext(ctx, query, webhook.ID, webhook.URL, webhook.Secret, webhook.CreatedAt.UTC())
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	for _, event := range webhook.Events {
+		_, err = tx.ExecContext(ctx, `INSERT INTO webhook_events (webhook_id, event) VALUES (?, ?)`, webhook.ID, event)
+		if err != nil {
+			return errors.WrapError(op, err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) GetWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	const op = "SQLite.GetWebhooks"
+
+	query := `
+		SELECT w.id, w.url, w.secret, w.created_at, COALESCE(e.event, '')
+		FROM webhooks w
+		LEFT JOIN webhook_events e ON e.webhook_id = w.id
+		ORDER BY w.created_at, w.id, e.event
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			return
+		}
+	}()
+
+	var webhooks []models.Webhook
+	for rows.Next() {
+		var (
+			webhook models.Webhook
+			event   string
+		)
+		if err := rows.Scan(&webhook.ID, &webhook.URL, &webhook.Secret, &webhook.CreatedAt, &event); err != nil {
+			return nil, errors.WrapError(op, err)
+		}
+
+		// строки одной подписки идут подряд, по одной на событие
+		if n := len(webhooks); n == 0 || webhooks[n-1].ID != webhook.ID {
+			webhooks = append(webhooks, webhook)
+		}
+		if event != "" {
+			last := &webhooks[len(webhooks)-1]
+			last.Events = append(last.Events, event)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	return webhooks, nil
+}
+
+// DeleteWebhook: события и доставки подписки удаляются каскадно.
+func (r *SQLiteRepository) DeleteWebhook(ctx context.Context, id string) error {
+	const op = "SQLite.DeleteWebhook"
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM webhooks WHERE id = ?`, id)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	if rowsAffected == 0 {
+		return errors.WrapError(op, errors.ErrWebhookNotFound)
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) EnqueueDeliveries(ctx context.Context, event string, payload []byte, at time.Time) (int, error) {
+	const op = "SQLite.EnqueueDeliveries"
+
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event, payload, next_attempt_at, created_at)
+		SELECT w.id, ?, ?, ?, ?
+		FROM webhooks w
+		WHERE NOT EXISTS (SELECT 1 FROM webhook_events e WHERE e.webhook_id = w.id)
+			OR EXISTS (SELECT 1 FROM webhook_events e WHERE e.webhook_id = w.id AND e.event = ?)
+		ORDER BY w.created_at, w.id
+	`
+	result, err := r.db.ExecContext(ctx, query, event, string(payload), at.UTC(), at.UTC(), event)
+	if err != nil {
+		return 0, errors.WrapError(op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.WrapError(op, err)
+	}
+	return int(rowsAffected), nil
+}
+
+// ClaimDueDeliveries: транзакция сразу берёт блокировку на запись, поэтому параллельные обработчики
+// не получат одну доставку дважды.
+func (r *SQLiteRepository) ClaimDueDeliveries(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]models.WebhookDelivery, error) {
+	const op = "SQLite.ClaimDueDeliveries"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			return
+		}
+	}()
+
+	query := `
+		SELECT d.id, d.webhook_id, d.event, d.payload, d.status, d.attempts, d.created_at, w.url, w.secret
+		FROM webhook_deliveries d
+		JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.status = 'pending' AND d.next_attempt_at <= ?
+		ORDER BY d.next_attempt_at, d.id
+		LIMIT ?
+	`
+	rows, err := tx.QueryContext(ctx, query, now.UTC(), limit)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			return
+		}
+	}()
+
+	var deliveries []models.WebhookDelivery
+	for rows.Next() {
+		var delivery models.WebhookDelivery
+		err := rows.Scan(
+			&delivery.ID, &delivery.WebhookID, &delivery.Event, &delivery.Payload, &delivery.Status,
+			&delivery.Attempts, &delivery.CreatedAt, &delivery.URL, &delivery.Secret,
+		)
+		if err != nil {
+			return nil, errors.WrapError(op, err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	leasedUntil := now.Add(lease).UTC()
+	for i := range deliveries {
+		_, err := tx.ExecContext(ctx, `UPDATE webhook_deliveries SET next_attempt_at = ? WHERE id = ?`, leasedUntil, deliveries[i].ID)
+		if err != nil {
+			return nil, errors.WrapError(op, err)
+		}
+		deliveries[i].NextAttemptAt = leasedUntil
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	return deliveries, nil
+}
+
+// SaveDeliveryAttempt ничего не записывает, если подписку за это время удалили.
+func (r *SQLiteRepository) SaveDeliveryAttempt(ctx context.Context, delivery *models.WebhookDelivery) error {
+	const op = "SQLite.SaveDeliveryAttempt"
+
+	query := `
+		UPDATE webhook_deliveries
+		SET status = ?, attempts = ?, response_status = NULLIF(?, 0), last_error = NULLIF(?, ''),
+			next_attempt_at = ?, delivered_at = ?
+		WHERE id = ?
+	`
+	var deliveredAt any
+	if delivery.DeliveredAt != nil {
+		deliveredAt = delivery.DeliveredAt.UTC()
+	}
+	_, err := r.db.ExecContext(ctx, query,
+		delivery.Status, delivery.Attempts, delivery.ResponseStatus, delivery.LastError,
+		delivery.NextAttemptAt.UTC(), deliveredAt, delivery.ID,
+	)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) GetWebhookDeliveries(ctx context.Context, webhookID string, limit int) ([]models.WebhookDelivery, error) {
+	const op = "SQLite.GetWebhookDeliveries"
+
+	var exists int
+	err := r.db.QueryRowContext(ctx, `SELECT 1 FROM webhooks WHERE id = ?`, webhookID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return nil, errors.WrapError(op, errors.ErrWebhookNotFound)
+	}
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	query := `
+		SELECT id, webhook_id, event, payload, status, attempts, COALESCE(response_status, 0),
+			COALESCE(last_error, ''), next_attempt_at, delivered_at, created_at
+		FROM webhook_deliveries
+		WHERE webhook_id = ?
+		ORDER BY id DESC
+		LIMIT ?
+	`
+	rows, err := r.db.QueryContext(ctx, query, webhookID, limit)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			return
+		}
+	}()
+
+	var deliveries []models.WebhookDelivery
+	for rows.Next() {
+		var delivery models.WebhookDelivery
+		err := rows.Scan(
+			&delivery.ID, &delivery.WebhookID, &delivery.Event, &delivery.Payload, &delivery.Status,
+			&delivery.Attempts, &delivery.ResponseStatus, &delivery.LastError, &delivery.NextAttemptAt,
+			&delivery.DeliveredAt, &delivery.CreatedAt,
+		)
+		if err != nil {
+			return nil, errors.WrapError(op, err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,200 @@
+package email
+
+import (
+	"net"
+	"net/textproto"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// FakeMessage: строки в Data разделены \n.
+type FakeMessage struct {
+	From string
+	To   []string
+	Data []byte
+}
+
+// FakeServer - SMTP-сервер в процессе для тестов и демо. Понимает только команды, нужные для отправки
+// без STARTTLS и AUTH.
+type FakeServer struct {
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	rejects  map[string]string
+	messages []FakeMessage
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+}
+
+func NewFakeServer() (*FakeServer, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &FakeServer{
+		listener: listener,
+		conns:    make(map[net.Conn]struct{}),
+		rejects:  make(map[string]string),
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+func (s *FakeServer) Addr() *net.TCPAddr {
+	return s.listener.Addr().(*net.TCPAddr)
+}
+
+func (s *FakeServer) Messages() []FakeMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.messages)
+}
+
+// RejectWith задаёт ответ, которым сервер отклонит команду MAIL, RCPT или DATA следующих писем.
+// DATA отклоняется после приёма текста, пустой ответ снова принимает команду.
+func (s *FakeServer) RejectWith(command, reply string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rejects[command] = reply
+}
+
+func (s *FakeServer) Close() error {
+	err := s.listener.Close()
+
+	s.mu.Lock()
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+// private methods
+
+func (s *FakeServer) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.mu.Unlock()
+		}()
+	}
+}
+
+func (s *FakeServer) handle(conn net.Conn) {
+	text := textproto.NewConn(conn)
+	defer func() {
+		if err := text.Close(); err != nil {
+			return
+		}
+	}()
+
+	if err := text.PrintfLine("220 localhost fake SMTP ready"); err != nil {
+		return
+	}
+
+	var message FakeMessage
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+
+		var reply string
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			reply = "250 localhost"
+		case "MAIL":
+			reply = s.rejection("MAIL")
+			if reply == "" {
+				message = FakeMessage{From: address(arg)}
+				reply = "250 OK"
+			}
+		case "RCPT":
+			reply = "503 MAIL first"
+			if message.From == "" {
+				break
+			}
+			reply = s.rejection("RCPT")
+			if reply == "" {
+				message.To = append(message.To, address(arg))
+				reply = "250 OK"
+			}
+		case "DATA":
+			if len(message.To) == 0 {
+				reply = "503 RCPT first"
+				break
+			}
+			if err := text.PrintfLine("354 end data with <CR><LF>.<CR><LF>"); err != nil {
+				return
+			}
+			message.Data, err = text.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			reply = s.rejection("DATA")
+			if reply == "" {
+				s.record(message)
+				reply = "250 OK"
+			}
+			message = FakeMessage{}
+		case "RSET":
+			message = FakeMessage{}
+			reply = "250 OK"
+		case "NOOP":
+			reply = "250 OK"
+		case "QUIT":
+			_ = text.PrintfLine("221 bye")
+			return
+		default:
+			reply = "502 command not implemented"
+		}
+
+		if err := text.PrintfLine("%s", reply); err != nil {
+			return
+		}
+	}
+}
+
+func (s *FakeServer) rejection(command string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.rejects[command]
+}
+
+func (s *FakeServer) record(message FakeMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages = append(s.messages, message)
+}
+
+// address достаёт адрес из аргумента MAIL или RCPT, например FROM:<a@b> BODY=8BITMIME.
+func address(arg string) string {
+	_, value, _ := strings.Cut(arg, ":")
+	value, _, _ = strings.Cut(strings.TrimSpace(value), " ")
+	return strings.Trim(value, "<>")
+}
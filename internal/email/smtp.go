@@ -0,0 +1,107 @@
+// Package email отправляет письма уведомлений через SMTP-сервер.
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"pr-review/internal/config"
+)
+
+var ErrNoTLS = errors.New("SMTP server does not support STARTTLS, refusing to send credentials")
+
+// SMTPSender передаёт учётные данные только после STARTTLS: без него письмо не отправляется.
+type SMTPSender struct {
+	host     string
+	addr     string
+	username string
+	password string
+	from     string
+	timeout  time.Duration
+}
+
+func NewSMTPSender(cfg *config.EmailConfig) *SMTPSender {
+	return &SMTPSender{
+		host:     cfg.Host,
+		addr:     net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		username: cfg.Username,
+		password: cfg.Password,
+		from:     cfg.From,
+		timeout:  cfg.Timeout,
+	}
+}
+
+func (s *SMTPSender) Send(ctx context.Context, to string, message []byte) error {
+	// в конверте нужен адрес без имени отправителя
+	from := s.from
+	if address, err := mail.ParseAddress(s.from); err == nil {
+		from = address.Address
+	}
+
+	dialer := net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return err
+	}
+
+	// net/smtp не принимает контекст, поэтому весь обмен ограничен сроком соединения
+	deadline := time.Now().Add(s.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer func() {
+		if err := client.Close(); err != nil {
+			return
+		}
+	}()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.host, MinVersion: tls.VersionTLS12}); err != nil {
+			return err
+		}
+	}
+	if s.username != "" {
+		if _, ok := client.TLSConnectionState(); !ok {
+			return ErrNoTLS
+		}
+		if err := client.Auth(smtp.PlainAuth("", s.username, s.password, s.host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+
+	data, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := data.Write(message); err != nil {
+		return err
+	}
+	if err := data.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
@@ -0,0 +1,40 @@
+package email_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"pr-review/internal/config"
+	"pr-review/internal/email"
+)
+
+// Без STARTTLS учётные данные не отправляются, и письмо не уходит.
+func TestSMTPSenderRequiresTLSForAuth(t *testing.T) {
+	server, err := email.NewFakeServer()
+	if err != nil {
+		t.Fatalf("start fake SMTP server: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := server.Close(); err != nil {
+			t.Errorf("close fake SMTP server: %v", err)
+		}
+	})
+
+	sender := email.NewSMTPSender(&config.EmailConfig{
+		Host:         server.Addr().IP.String(),
+		Port:         server.Addr().Port,
+		Username:     "pr-review",
+		Password:     "secret",
+		From:         "pr-review@example.com",
+		OutboxConfig: config.OutboxConfig{Timeout: 5 * time.Second},
+	})
+
+	err = sender.Send(t.Context(), "u2@example.com", []byte("Subject: test\r\n\r\nbody\r\n"))
+	if !errors.Is(err, email.ErrNoTLS) {
+		t.Fatalf("expected ErrNoTLS, got %v", err)
+	}
+	if messages := server.Messages(); len(messages) != 0 {
+		t.Fatalf("expected no messages, got %d", len(messages))
+	}
+}
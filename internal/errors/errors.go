@@ -13,10 +13,46 @@ var (
 	ErrPRExists     = errors.New("pull request already exists")
 	ErrUserExists   = errors.New("user with this name or id already exists")
 	ErrPRMerged     = errors.New("pull request already merged")
+	ErrPRClosed     = errors.New("pull request is closed")
+	ErrPRDraft      = errors.New("pull request is a draft")
 	ErrNotAssigned  = errors.New("reviewer is not assigned to this PR")
 	ErrNoCandidate  = errors.New("no active replacement candidate in team")
+	ErrNotMember    = errors.New("user is not a member of this team")
+
+	ErrInvalidReviewersCount = errors.New("reviewers count violates team policy")
+	ErrCodeOwnersNotFound    = errors.New("team has no code owners")
+	ErrInvalidCodeOwners     = errors.New("invalid code owners file")
+	ErrNoOwnerCandidate      = errors.New("no active code owner available for review")
+	ErrInvalidReviewState    = errors.New("invalid review state")
+	ErrMergeBlocked          = errors.New("merge blocked by team policy")
+	ErrTeamArchived          = errors.New("team is archived")
+	ErrTeamHasOpenPRs        = errors.New("team has open pull requests")
+	ErrInvalidAbsence        = errors.New("absence must end after it starts and not in the past")
+	ErrReviewersAtCapacity   = errors.New("reviewer candidates are at their open reviews limit")
+	ErrPoolNotFound          = errors.New("reviewer pool not found")
+	ErrPoolExists            = errors.New("reviewer pool already exists")
+	ErrInvalidFallback       = errors.New("invalid team fallback")
+	ErrReviewerUnavailable   = errors.New("reviewer is no longer available for assignment")
+	ErrWebhookNotFound       = errors.New("webhook not found")
+	ErrInvalidWebhook        = errors.New("invalid webhook")
+	ErrLoginTaken            = errors.New("login is already linked to another user")
+	ErrAccountNotLinked      = errors.New("login is not linked to any user")
+	ErrInvalidSignature      = errors.New("invalid webhook signature")
+	ErrInvalidPayload        = errors.New("invalid webhook payload")
+	ErrSyncTaskNotFound      = errors.New("failed sync task not found")
+	ErrChatNotFound          = errors.New("team has no chat configured")
+	ErrInvalidChat           = errors.New("invalid team chat")
+	ErrEmailNotFound         = errors.New("user has no email configured")
 )
 
 func WrapError(op string, err error) error {
 	return fmt.Errorf("%s: %w", op, err)
 }
+
+func Is(err, target error) bool {
+	return errors.Is(err, target)
+}
+
+func As(err error, target any) bool {
+	return errors.As(err, target)
+}
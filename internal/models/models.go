@@ -2,6 +2,33 @@ package models
 
 import "time"
 
+const (
+	DefaultRequiredReviewers = 2
+	MaxReviewersCount        = 10
+)
+
+type PRStatus string
+
+const (
+	PRStatusOpen   PRStatus = "OPEN"
+	PRStatusMerged PRStatus = "MERGED"
+	PRStatusClosed PRStatus = "CLOSED"
+)
+
+type ReviewState string
+
+const (
+	ReviewStatePending          ReviewState = "PENDING"
+	ReviewStateApproved         ReviewState = "APPROVED"
+	ReviewStateChangesRequested ReviewState = "CHANGES_REQUESTED"
+	ReviewStateCommented        ReviewState = "COMMENTED"
+)
+
+const (
+	CodeOwnersPrefer  = "prefer"
+	CodeOwnersRequire = "require"
+)
+
 type TeamMember struct {
 	UserID   string
 	Username string
@@ -9,27 +36,400 @@ type TeamMember struct {
 }
 
 type User struct {
-	TeamName string
+	// MaxOpenReviews - личный лимит открытых ревью, nil - действует лимит команды
+	MaxOpenReviews *int
+	TeamName       string
 	TeamMember
 }
 
 type Team struct {
+	// ArchivedAt задан у команды, удалённой с сохранением истории PR
+	ArchivedAt *time.Time
+	// MaxOpenReviews - лимит открытых ревью для участников без личного, nil - без лимита
+	MaxOpenReviews *int
+	Name           string
+	Members        []TeamMember
+	// Fallbacks - откуда по порядку добирать ревьюверов, если своей команды не хватает
+	Fallbacks         []ReviewerFallback
+	MergePolicy       MergePolicy
+	RequiredReviewers int
+}
+
+const (
+	TeamDeleted  = "deleted"
+	TeamArchived = "archived"
+)
+
+const (
+	FallbackTeam = "team"
+	FallbackPool = "pool"
+)
+
+type ReviewerFallback struct {
+	Kind string
+	Name string
+}
+
+type FallbackReviewer struct {
+	UserID string
+	ReviewerFallback
+}
+
+type ReviewerPool struct {
 	Name    string
-	Members []TeamMember
+	Members []string
+}
+
+// TeamDeletion: команда без истории PR удаляется целиком, иначе архивируется.
+type TeamDeletion struct {
+	TeamName  string
+	Result    string
+	ClosedPRs []string
+}
+
+type MergePolicy struct {
+	// RequiredApprovals - сколько APPROVED нужно для merge, 0 - без проверки
+	RequiredApprovals       int
+	BlockOnChangesRequested bool
+	ForbidSelfApproval      bool
+	RequireOwnerApproval    bool
+}
+
+// MergePolicyUpdate: правила с nil остаются прежними.
+type MergePolicyUpdate struct {
+	RequiredApprovals       *int
+	BlockOnChangesRequested *bool
+	ForbidSelfApproval      *bool
+	RequireOwnerApproval    *bool
+}
+
+const (
+	MergeRuleOpen               = "pr_open"
+	MergeRuleMinApprovals       = "min_approvals"
+	MergeRuleNoChangesRequested = "no_changes_requested"
+	MergeRuleNoSelfApproval     = "no_self_approval"
+	MergeRuleOwnerApproved      = "owner_approved"
+)
+
+type MergeCheck struct {
+	Rule    string
+	Message string
+	Passed  bool
+}
+
+type Mergeability struct {
+	PullRequestID string
+	Checks        []MergeCheck
+	Mergeable     bool
 }
 
 type PullRequestShort struct {
 	ID       string
 	Name     string
 	AuthorID string
-	Status   string
+	Status   PRStatus
+	IsDraft  bool
+}
+
+type CreatePROptions struct {
+	// ReviewersCount переопределяет число ревьюверов из настроек команды
+	ReviewersCount *int
+	Provider       string
+	ChangedFiles   []string
 }
 
 type PullRequest struct {
 	CreatedAt time.Time
 	MergedAt  *time.Time
+	ClosedAt  *time.Time
+	// ReviewersCount нужно черновику, чтобы назначить ревьюверов в readyForReview
+	ReviewersCount *int
+	// Provider пустой у PR, созданных через API
+	Provider string
 	PullRequestShort
 	AssignedReviewers []string
+	ChangedFiles      []string
+	Reviews           []Review
+	// SkippedAtCapacity и FallbackReviewers заполняются только в ответе на назначение и не хранятся
+	SkippedAtCapacity []string
+	FallbackReviewers []FallbackReviewer
+}
+
+type Review struct {
+	AssignedAt *time.Time
+	ReviewedAt *time.Time
+	UserID     string
+	State      ReviewState
+}
+
+type PREventType string
+
+const (
+	PREventCreated          PREventType = "created"
+	PREventReviewerAssigned PREventType = "reviewer_assigned"
+	PREventReviewerRemoved  PREventType = "reviewer_removed"
+	PREventReviewSubmitted  PREventType = "review_submitted"
+	PREventReadyForReview   PREventType = "ready_for_review"
+	PREventConvertedToDraft PREventType = "converted_to_draft"
+	PREventMerged           PREventType = "merged"
+	PREventClosed           PREventType = "closed"
+	PREventReopened         PREventType = "reopened"
+)
+
+const (
+	ReasonManual       = "manual"
+	ReasonLeftTeam     = "left_team"
+	ReasonTeamChanged  = "team_changed"
+	ReasonDeactivated  = "deactivated"
+	ReasonAbsent       = "absent"
+	ReasonUnavailable  = "unavailable"
+	ReasonTeamArchived = "team_archived"
+)
+
+// PREvent пишется в той же транзакции, что и само изменение.
+type PREvent struct {
+	CreatedAt     time.Time
+	Type          PREventType
+	PullRequestID string
+	// UserID - автор для created, ревьювер для событий назначения и вердикта
+	UserID string
+	// RelatedUserID - при замене: для reviewer_removed тот, кто заменил, для reviewer_assigned тот, кого заменили
+	RelatedUserID string
+	// Reason - почему ревьювера сняли или назначили взамен, для closed - почему PR закрыт автоматически
+	Reason string
+	State  ReviewState
+	ID     int64
+}
+
+type ReviewAssignment struct {
+	AssignedAt *time.Time
+	ReviewedAt *time.Time
+	State      ReviewState
+	PullRequestShort
+}
+
+type Reassignment struct {
+	Fallback      *ReviewerFallback
+	PullRequestID string
+	OldUserID     string
+	NewUserID     string
+}
+
+// ReassignReport: в NoCandidate попадают PR, где ревьювер остался прежним.
+type ReassignReport struct {
+	Reassigned  []Reassignment
+	NoCandidate []string
+}
+
+type MembershipChange struct {
+	User *User
+	ReassignReport
+}
+
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+)
+
+// UserAccount: у пользователя не больше одной записи на провайдера.
+type UserAccount struct {
+	Provider string
+	// Login хранится в нижнем регистре: логины провайдеров к регистру не чувствительны
+	Login  string
+	UserID string
+}
+
+// IntegrationResult: пустой Action означает, что событие проигнорировано.
+type IntegrationResult struct {
+	Action        string
+	PullRequestID string
+}
+
+const (
+	IntegrationCreated          = "created"
+	IntegrationMerged           = "merged"
+	IntegrationClosed           = "closed"
+	IntegrationReopened         = "reopened"
+	IntegrationReadyForReview   = "ready_for_review"
+	IntegrationConvertedToDraft = "converted_to_draft"
+)
+
+type CodeHostPR struct {
+	Repository string
+	Number     int
+}
+
+const (
+	SyncRequestReviewer = "request_reviewer"
+	SyncRemoveReviewer  = "remove_reviewer"
+	SyncComment         = "comment"
+)
+
+const (
+	SyncPending = "pending"
+	SyncDone    = "done"
+	SyncFailed  = "failed"
+)
+
+type SyncTask struct {
+	CreatedAt     time.Time
+	NextAttemptAt time.Time
+	CompletedAt   *time.Time
+	PullRequestID string
+	Provider      string
+	Action        string
+	UserID        string
+	Body          string
+	Status        string
+	LastError     string
+	ID            int64
+	Attempts      int
+}
+
+type Absence struct {
+	StartsAt    time.Time
+	EndsAt      time.Time
+	ProcessedAt *time.Time
+	UserID      string
+}
+
+// CodeOwners: в режиме prefer владельцы назначаются первыми, в require без владельца PR не создаётся.
+type CodeOwners struct {
+	UpdatedAt time.Time
+	TeamName  string
+	Content   string
+	Mode      string
+}
+
+const (
+	WebhookEventPRCreated          = "pr.created"
+	WebhookEventReviewerAssigned   = "reviewer.assigned"
+	WebhookEventReviewerReassigned = "reviewer.reassigned"
+	WebhookEventReviewerRemoved    = "reviewer.removed"
+	WebhookEventPRMerged           = "pr.merged"
+	WebhookEventUserDeactivated    = "user.deactivated"
+)
+
+var WebhookEvents = []string{
+	WebhookEventPRCreated,
+	WebhookEventReviewerAssigned,
+	WebhookEventReviewerReassigned,
+	WebhookEventReviewerRemoved,
+	WebhookEventPRMerged,
+	WebhookEventUserDeactivated,
+}
+
+// Webhook: пустой Events - подписка на все события.
+type Webhook struct {
+	CreatedAt time.Time
+	ID        string
+	URL       string
+	Secret    string
+	Events    []string
+}
+
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+type WebhookDelivery struct {
+	CreatedAt     time.Time
+	NextAttemptAt time.Time
+	DeliveredAt   *time.Time
+	WebhookID     string
+	Event         string
+	Status        string
+	LastError     string
+	// URL и Secret подписки заполняются только у доставок, выданных на отправку
+	URL            string
+	Secret         string
+	Payload        []byte
+	ID             int64
+	Attempts       int
+	ResponseStatus int
+}
+
+const (
+	ChatFormatSlack      = "slack"
+	ChatFormatMattermost = "mattermost"
+)
+
+// ChatTemplates: пустой шаблон - стандартное сообщение для формата чата.
+type ChatTemplates struct {
+	ReviewerAssigned   string
+	ReviewerReassigned string
+	PRMerged           string
+}
+
+type TeamChat struct {
+	UpdatedAt time.Time
+	TeamName  string
+	URL       string
+	Format    string
+	Templates ChatTemplates
+}
+
+const (
+	ChatPending = "pending"
+	ChatSent    = "sent"
+	ChatFailed  = "failed"
+)
+
+// ChatMessage: URL берётся из настроек чата на момент события.
+type ChatMessage struct {
+	CreatedAt     time.Time
+	NextAttemptAt time.Time
+	SentAt        *time.Time
+	TeamName      string
+	Event         string
+	URL           string
+	Status        string
+	LastError     string
+	Payload       []byte
+	ID            int64
+	Attempts      int
+}
+
+const (
+	EmailImmediate = "immediate"
+	EmailDigest    = "digest"
+)
+
+type EmailSettings struct {
+	UserID string
+	Email  string
+	Mode   string
+}
+
+const (
+	EmailPending = "pending"
+	EmailSent    = "sent"
+	EmailFailed  = "failed"
+)
+
+// EmailMessage: Message - письмо целиком, с заголовками.
+type EmailMessage struct {
+	CreatedAt     time.Time
+	NextAttemptAt time.Time
+	SentAt        *time.Time
+	UserID        string
+	Recipient     string
+	Subject       string
+	Status        string
+	LastError     string
+	Message       []byte
+	ID            int64
+	Attempts      int
+}
+
+type ReviewerCandidate struct {
+	LastAssignedAt *time.Time
+	// MaxOpenReviews - действующий лимит: личный или, если его нет, командный
+	MaxOpenReviews *int
+	UserID         string
+	OpenReviews    int
 }
 
 type UserStats struct {
@@ -38,6 +438,7 @@ type UserStats struct {
 	TeamName      string
 	OpenReviews   int
 	MergedReviews int
+	ClosedReviews int
 	CreatedPRs    int
 }
 
@@ -55,6 +456,8 @@ type TotalStats struct {
 	ActiveUsers       int     `json:"active_users"`
 	TotalPRs          int     `json:"total_prs"`
 	OpenPRs           int     `json:"open_prs"`
+	DraftPRs          int     `json:"draft_prs"`
 	MergedPRs         int     `json:"merged_prs"`
+	ClosedPRs         int     `json:"closed_prs"`
 	AvgReviewersPerPR float64 `json:"avg_reviewers_per_pr"`
 }
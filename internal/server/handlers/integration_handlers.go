@@ -0,0 +1,329 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	serviceErrors "pr-review/internal/errors"
+	"pr-review/internal/models"
+	"pr-review/internal/server/response"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+const maxIntegrationBody = 25 << 20
+
+type GitHubService interface {
+	HandleWebhook(ctx context.Context, event, signature string, body []byte) (*models.IntegrationResult, error)
+}
+
+type GitLabService interface {
+	HandleWebhook(ctx context.Context, event, token string, body []byte) (*models.IntegrationResult, error)
+}
+
+type SyncService interface {
+	Tasks(ctx context.Context, status string) ([]models.SyncTask, error)
+	Retry(ctx context.Context, id int64) error
+}
+
+type IntegrationHandler struct {
+	logger *slog.Logger
+	github GitHubService
+	gitlab GitLabService
+	sync   SyncService
+}
+
+func NewIntegrationHandler(logger *slog.Logger, github GitHubService, gitlab GitLabService, sync SyncService) *IntegrationHandler {
+	return &IntegrationHandler{
+		logger: logger,
+		github: github,
+		gitlab: gitlab,
+		sync:   sync,
+	}
+}
+
+// POST /integrations/github/webhook
+func (h *IntegrationHandler) GitHub(w http.ResponseWriter, r *http.Request) {
+	const op = "IntegrationHandlers.GitHub"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+		slog.String("delivery", r.Header.Get("X-GitHub-Delivery")),
+	)
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIntegrationBody))
+	if err != nil {
+		log.Error("Failed to read request body", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "wrong request format"))
+		return
+	}
+
+	event := r.Header.Get("X-GitHub-Event")
+	result, err := h.github.HandleWebhook(r.Context(), event, r.Header.Get("X-Hub-Signature-256"), body)
+	if err != nil {
+		renderIntegrationError(w, r, log, err)
+		return
+	}
+
+	renderIntegrationResult(w, r, event, result)
+}
+
+// POST /integrations/gitlab/webhook
+func (h *IntegrationHandler) GitLab(w http.ResponseWriter, r *http.Request) {
+	const op = "IntegrationHandlers.GitLab"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+		slog.String("delivery", r.Header.Get("X-Gitlab-Event-UUID")),
+	)
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIntegrationBody))
+	if err != nil {
+		log.Error("Failed to read request body", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "wrong request format"))
+		return
+	}
+
+	event := r.Header.Get("X-Gitlab-Event")
+	result, err := h.gitlab.HandleWebhook(r.Context(), event, r.Header.Get("X-Gitlab-Token"), body)
+	if err != nil {
+		renderIntegrationError(w, r, log, err)
+		return
+	}
+
+	renderIntegrationResult(w, r, event, result)
+}
+
+// GET /integrations/sync
+func (h *IntegrationHandler) SyncTasks(w http.ResponseWriter, r *http.Request) {
+	const op = "IntegrationHandlers.SyncTasks"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", models.SyncPending, models.SyncDone, models.SyncFailed:
+	default:
+		log.Error("Invalid status query parameter", "status", status)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "status must be one of pending, done, failed"))
+		return
+	}
+
+	tasks, err := h.sync.Tasks(r.Context(), status)
+	if err != nil {
+		log.Error("Failed to get sync tasks", "error", err, "status", status)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to get sync tasks"))
+		return
+	}
+
+	type SyncTaskItem struct {
+		CreatedAt     time.Time  `json:"created_at"`
+		NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+		CompletedAt   *time.Time `json:"completed_at,omitempty"`
+		PullRequestID string     `json:"pull_request_id"`
+		Provider      string     `json:"provider"`
+		Action        string     `json:"action"`
+		UserID        string     `json:"user_id,omitempty"`
+		Body          string     `json:"body,omitempty"`
+		Status        string     `json:"status"`
+		LastError     string     `json:"last_error,omitempty"`
+		ID            int64      `json:"task_id"`
+		Attempts      int        `json:"attempts"`
+	}
+
+	items := make([]SyncTaskItem, 0, len(tasks))
+	for _, task := range tasks {
+		item := SyncTaskItem{
+			CreatedAt:     task.CreatedAt,
+			CompletedAt:   task.CompletedAt,
+			PullRequestID: task.PullRequestID,
+			Provider:      task.Provider,
+			Action:        task.Action,
+			UserID:        task.UserID,
+			Body:          task.Body,
+			Status:        task.Status,
+			LastError:     task.LastError,
+			ID:            task.ID,
+			Attempts:      task.Attempts,
+		}
+		// время следующей попытки имеет смысл только у ожидающих задач
+		if task.Status == models.SyncPending {
+			item.NextAttemptAt = &task.NextAttemptAt
+		}
+		items = append(items, item)
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, struct {
+		Tasks []SyncTaskItem `json:"tasks"`
+	}{
+		Tasks: items,
+	})
+}
+
+// POST /integrations/sync/retry
+func (h *IntegrationHandler) RetrySync(w http.ResponseWriter, r *http.Request) {
+	const op = "IntegrationHandlers.RetrySync"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req struct {
+		ID int64 `json:"task_id" validate:"required"`
+	}
+
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Error("Failed to decode request body", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "wrong request format"))
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		log.Error("Request validation failed", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("VALIDATION_ERROR", "wrong request format"))
+		return
+	}
+
+	err := h.sync.Retry(r.Context(), req.ID)
+	if errors.Is(err, serviceErrors.ErrSyncTaskNotFound) {
+		log.Error("Failed sync task not found", "error", err, "task_id", req.ID)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("failed sync task not found"))
+		return
+	}
+	if err != nil {
+		log.Error("Failed to retry sync task", "error", err, "task_id", req.ID)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to retry sync task"))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, struct {
+		Status string `json:"status"`
+		ID     int64  `json:"task_id"`
+	}{
+		Status: models.SyncPending,
+		ID:     req.ID,
+	})
+}
+
+// private methods
+
+// renderIntegrationResult: пропущенное событие - тоже успех, иначе код-хостинг будет повторять доставку.
+func renderIntegrationResult(w http.ResponseWriter, r *http.Request, event string, result *models.IntegrationResult) {
+	action := result.Action
+	if action == "" {
+		action = "ignored"
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, struct {
+		Event         string `json:"event"`
+		Action        string `json:"action"`
+		PullRequestID string `json:"pull_request_id,omitempty"`
+	}{
+		Event:         event,
+		Action:        action,
+		PullRequestID: result.PullRequestID,
+	})
+}
+
+func renderIntegrationError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) {
+	if errors.Is(err, serviceErrors.ErrInvalidSignature) {
+		log.Error("Invalid webhook signature", "error", err)
+		render.Status(r, http.StatusUnauthorized)
+		render.JSON(w, r, response.INVALID_SIGNATURE())
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrInvalidPayload) {
+		log.Error("Invalid webhook payload", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "malformed pull request event"))
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrAccountNotLinked) {
+		log.Error("PR author login is not linked", "error", err)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("pull request author login is not linked to any user"))
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrUserNotFound) || errors.Is(err, serviceErrors.ErrTeamNotFound) {
+		log.Error("PR author not found", "error", err)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("pull request author not found"))
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrNotMember) {
+		log.Error("PR author is not a member of the repository team", "error", err)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("pull request author is not a member of the project team"))
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrPRMerged) {
+		log.Error("PR is merged", "error", err)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.PR_MERGED())
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrPRClosed) {
+		log.Error("PR is closed", "error", err)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.PR_CLOSED())
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrPRDraft) {
+		log.Error("PR is a draft", "error", err)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.PR_DRAFT())
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrMergeBlocked) {
+		log.Error("Merge blocked by team policy", "error", err)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.MERGE_BLOCKED("merge blocked by team policy"))
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrTeamArchived) {
+		log.Error("Author team is archived", "error", err)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.TEAM_ARCHIVED())
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrNoOwnerCandidate) {
+		log.Error("No code owner available", "error", err)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.NO_OWNER_CANDIDATE())
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrReviewersAtCapacity) {
+		log.Error("Reviewer candidates at capacity", "error", err)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.REVIEWERS_AT_CAPACITY())
+		return
+	}
+
+	log.Error("Failed to handle webhook", "error", err)
+	render.Status(r, http.StatusInternalServerError)
+	render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to handle webhook"))
+}
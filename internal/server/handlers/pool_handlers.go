@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	serviceErrors "pr-review/internal/errors"
+	"pr-review/internal/models"
+	"pr-review/internal/server/response"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+type PoolService interface {
+	CreatePool(ctx context.Context, pool *models.ReviewerPool) (*models.ReviewerPool, error)
+	GetPool(ctx context.Context, name string) (*models.ReviewerPool, error)
+	SetMembers(ctx context.Context, pool *models.ReviewerPool) (*models.ReviewerPool, error)
+}
+
+type PoolHandler struct {
+	logger  *slog.Logger
+	service PoolService
+}
+
+func NewPoolHandler(logger *slog.Logger, s PoolService) *PoolHandler {
+	return &PoolHandler{
+		logger:  logger,
+		service: s,
+	}
+}
+
+// POST /pool/add
+func (h *PoolHandler) Add(w http.ResponseWriter, r *http.Request) {
+	const op = "PoolHandlers.Add"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req struct {
+		Name    string   `json:"pool_name" validate:"required"`
+		Members []string `json:"members" validate:"dive,required"`
+	}
+
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Error("Failed to decode request body", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "wrong request format"))
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		log.Error("Request validation failed", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("VALIDATION_ERROR", "wrong request format"))
+		return
+	}
+
+	pool, err := h.service.CreatePool(r.Context(), &models.ReviewerPool{Name: req.Name, Members: req.Members})
+	if errors.Is(err, serviceErrors.ErrPoolExists) {
+		log.Error("Reviewer pool already exists", "error", err, "pool_name", req.Name)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.POOL_EXISTS())
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrUserNotFound) {
+		log.Error("Pool member not found", "error", err, "pool_name", req.Name)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("user not found"))
+		return
+	}
+	if err != nil {
+		log.Error("Failed to create reviewer pool", "error", err, "pool_name", req.Name)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to create reviewer pool"))
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, poolResponse(pool))
+}
+
+// GET /pool/get
+func (h *PoolHandler) Get(w http.ResponseWriter, r *http.Request) {
+	const op = "PoolHandlers.Get"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	name := r.URL.Query().Get("pool_name")
+	if name == "" {
+		log.Error("pool_name query parameter is required")
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "pool_name query parameter is required"))
+		return
+	}
+
+	pool, err := h.service.GetPool(r.Context(), name)
+	if errors.Is(err, serviceErrors.ErrPoolNotFound) {
+		log.Error("Reviewer pool not found", "error", err, "pool_name", name)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("pool not found"))
+		return
+	}
+	if err != nil {
+		log.Error("Failed to get reviewer pool", "error", err, "pool_name", name)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to get reviewer pool"))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, poolResponse(pool))
+}
+
+// POST /pool/setMembers
+func (h *PoolHandler) SetMembers(w http.ResponseWriter, r *http.Request) {
+	const op = "PoolHandlers.SetMembers"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req struct {
+		Name    string   `json:"pool_name" validate:"required"`
+		Members []string `json:"members" validate:"dive,required"`
+	}
+
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Error("Failed to decode request body", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "wrong request format"))
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		log.Error("Request validation failed", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("VALIDATION_ERROR", "wrong request format"))
+		return
+	}
+
+	pool, err := h.service.SetMembers(r.Context(), &models.ReviewerPool{Name: req.Name, Members: req.Members})
+	if errors.Is(err, serviceErrors.ErrPoolNotFound) {
+		log.Error("Reviewer pool not found", "error", err, "pool_name", req.Name)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("pool not found"))
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrUserNotFound) {
+		log.Error("Pool member not found", "error", err, "pool_name", req.Name)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("user not found"))
+		return
+	}
+	if err != nil {
+		log.Error("Failed to set reviewer pool members", "error", err, "pool_name", req.Name)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to set reviewer pool members"))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, poolResponse(pool))
+}
+
+func poolResponse(pool *models.ReviewerPool) any {
+	type PoolItem struct {
+		Name    string   `json:"pool_name"`
+		Members []string `json:"members"`
+	}
+
+	members := pool.Members
+	if members == nil {
+		members = []string{}
+	}
+
+	return struct {
+		Pool PoolItem `json:"pool"`
+	}{
+		Pool: PoolItem{
+			Name:    pool.Name,
+			Members: members,
+		},
+	}
+}
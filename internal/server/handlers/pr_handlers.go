@@ -17,9 +17,17 @@ import (
 )
 
 type PRService interface {
-	CreatePR(ctx context.Context, pr *models.PullRequestShort) (*models.PullRequest, error)
+	CreatePR(ctx context.Context, pr *models.PullRequestShort, opts models.CreatePROptions) (*models.PullRequest, error)
 	MergePR(ctx context.Context, prID string) (*models.PullRequest, error)
-	ReassignReviewer(ctx context.Context, prID, oldUserID string) (*models.PullRequest, *string, error)
+	RecordMerge(ctx context.Context, prID string) (*models.PullRequest, error)
+	Mergeability(ctx context.Context, prID string) (*models.Mergeability, error)
+	ReadyForReview(ctx context.Context, prID string) (*models.PullRequest, error)
+	ConvertToDraft(ctx context.Context, prID string) (*models.PullRequest, error)
+	SubmitReview(ctx context.Context, prID, userID string, state models.ReviewState) (*models.PullRequest, error)
+	ClosePR(ctx context.Context, prID string) (*models.PullRequest, error)
+	ReopenPR(ctx context.Context, prID string) (*models.PullRequest, error)
+	ReassignReviewer(ctx context.Context, prID, oldUserID, reason string) (*models.PullRequest, *string, error)
+	Timeline(ctx context.Context, prID string) ([]models.PREvent, error)
 }
 
 type PRHandler struct {
@@ -44,9 +52,12 @@ func (h *PRHandler) Create(w http.ResponseWriter, r *http.Request) {
 	)
 
 	var req struct {
-		ID       string `json:"pull_request_id" validate:"required"`
-		Name     string `json:"pull_request_name" validate:"required"`
-		AuthorID string `json:"author_id" validate:"required"`
+		ReviewersCount *int     `json:"reviewers_count" validate:"omitempty,min=0"`
+		ID             string   `json:"pull_request_id" validate:"required"`
+		Name           string   `json:"pull_request_name" validate:"required"`
+		AuthorID       string   `json:"author_id" validate:"required"`
+		ChangedFiles   []string `json:"changed_files" validate:"dive,required"`
+		IsDraft        bool     `json:"is_draft"`
 	}
 
 	if err := render.DecodeJSON(r.Body, &req); err != nil {
@@ -68,21 +79,62 @@ func (h *PRHandler) Create(w http.ResponseWriter, r *http.Request) {
 		ID:       req.ID,
 		Name:     req.Name,
 		AuthorID: req.AuthorID,
-		Status:   "OPEN",
+		Status:   models.PRStatusOpen,
+		IsDraft:  req.IsDraft,
 	}
-	pr, err := h.service.CreatePR(r.Context(), prShort)
+	opts := models.CreatePROptions{
+		ReviewersCount: req.ReviewersCount,
+		ChangedFiles:   req.ChangedFiles,
+	}
+	pr, err := h.service.CreatePR(r.Context(), prShort, opts)
 	if errors.Is(err, serviceErrors.ErrUserNotFound) {
 		log.Error("Author not found", "error", err, "author_id", req.AuthorID)
 		render.Status(r, http.StatusNotFound)
 		render.JSON(w, r, response.NOT_FOUND("author not found"))
 		return
 	}
+	if errors.Is(err, serviceErrors.ErrTeamNotFound) {
+		log.Error("Author team not found", "error", err, "author_id", req.AuthorID)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("team not found"))
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrTeamArchived) {
+		log.Error("Author team is archived", "error", err, "author_id", req.AuthorID)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.TEAM_ARCHIVED())
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrInvalidReviewersCount) {
+		log.Error("Invalid reviewers count", "error", err, "reviewers_count", req.ReviewersCount)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.INVALID_REVIEWERS_COUNT())
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrNoOwnerCandidate) {
+		log.Error("No code owner available", "error", err, "prID", req.ID)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.NO_OWNER_CANDIDATE())
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrReviewersAtCapacity) {
+		log.Error("Reviewer candidates at capacity", "error", err, "prID", req.ID)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.REVIEWERS_AT_CAPACITY())
+		return
+	}
 	if errors.Is(err, serviceErrors.ErrPRExists) {
 		log.Error("PR already exists", "error", err, "prID", req.ID)
 		render.Status(r, http.StatusConflict)
 		render.JSON(w, r, response.PR_EXISTS())
 		return
 	}
+	if errors.Is(err, serviceErrors.ErrReviewerUnavailable) {
+		log.Error("Selected reviewers became unavailable", "error", err, "prID", req.ID)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.REVIEWER_UNAVAILABLE())
+		return
+	}
 	if err != nil {
 		log.Error("Failed to create PR", "error", err, "prID", req.ID)
 		render.Status(r, http.StatusInternalServerError)
@@ -91,11 +143,14 @@ func (h *PRHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	type PRItem struct {
-		ID                string   `json:"pull_request_id" validate:"required"`
-		Name              string   `json:"pull_request_name" validate:"required"`
-		AuthorID          string   `json:"author_id" validate:"required"`
-		Status            string   `json:"status" validate:"required"`
-		AssignedReviewers []string `json:"assigned_reviewers"`
+		ID                string                 `json:"pull_request_id" validate:"required"`
+		Name              string                 `json:"pull_request_name" validate:"required"`
+		AuthorID          string                 `json:"author_id" validate:"required"`
+		Status            string                 `json:"status" validate:"required"`
+		AssignedReviewers []string               `json:"assigned_reviewers"`
+		SkippedAtCapacity []string               `json:"skipped_at_capacity,omitempty"`
+		FallbackReviewers []FallbackReviewerItem `json:"fallback_reviewers,omitempty"`
+		IsDraft           bool                   `json:"is_draft"`
 	}
 
 	res := struct {
@@ -105,8 +160,11 @@ func (h *PRHandler) Create(w http.ResponseWriter, r *http.Request) {
 			ID:                pr.ID,
 			Name:              pr.Name,
 			AuthorID:          pr.AuthorID,
-			Status:            pr.Status,
+			Status:            string(pr.Status),
 			AssignedReviewers: pr.AssignedReviewers,
+			SkippedAtCapacity: pr.SkippedAtCapacity,
+			FallbackReviewers: fallbackReviewerItems(pr.FallbackReviewers),
+			IsDraft:           pr.IsDraft,
 		},
 	}
 
@@ -149,6 +207,24 @@ func (h *PRHandler) Merge(w http.ResponseWriter, r *http.Request) {
 		render.JSON(w, r, response.NOT_FOUND("pull request not found"))
 		return
 	}
+	if errors.Is(err, serviceErrors.ErrPRClosed) {
+		log.Error("PR is closed", "error", err, "prID", req.ID)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.PR_CLOSED())
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrPRDraft) {
+		log.Error("PR is a draft", "error", err, "prID", req.ID)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.PR_DRAFT())
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrMergeBlocked) {
+		log.Error("Merge blocked by team policy", "error", err, "prID", req.ID)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.MERGE_BLOCKED(errors.Unwrap(err).Error()))
+		return
+	}
 	if err != nil {
 		log.Error("Failed to merge PR", "error", err, "prID", req.ID)
 		render.Status(r, http.StatusInternalServerError)
@@ -172,7 +248,7 @@ func (h *PRHandler) Merge(w http.ResponseWriter, r *http.Request) {
 			ID:                pr.ID,
 			Name:              pr.Name,
 			AuthorID:          pr.AuthorID,
-			Status:            pr.Status,
+			Status:            string(pr.Status),
 			AssignedReviewers: pr.AssignedReviewers,
 			MetgedAt:          pr.MergedAt,
 		},
@@ -182,6 +258,616 @@ func (h *PRHandler) Merge(w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, res)
 }
 
+// GET /pullRequest/mergeability
+func (h *PRHandler) Mergeability(w http.ResponseWriter, r *http.Request) {
+	const op = "PRHandler.Mergeability"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		log.Error("pull_request_id query parameter is required")
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "pull_request_id query parameter is required"))
+		return
+	}
+
+	mergeability, err := h.service.Mergeability(r.Context(), prID)
+	if errors.Is(err, serviceErrors.ErrPRNotFound) {
+		log.Error("PR not found", "error", err, "prID", prID)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("pull request not found"))
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrPRMerged) {
+		log.Error("PR already merged", "error", err, "prID", prID)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.PR_MERGED())
+		return
+	}
+	if err != nil {
+		log.Error("Failed to evaluate mergeability", "error", err, "prID", prID)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to evaluate mergeability"))
+		return
+	}
+
+	type CheckItem struct {
+		Rule    string `json:"rule" validate:"required"`
+		Message string `json:"message"`
+		Passed  bool   `json:"passed"`
+	}
+
+	res := struct {
+		PullRequestID string      `json:"pull_request_id" validate:"required"`
+		Checks        []CheckItem `json:"checks" validate:"dive"`
+		Mergeable     bool        `json:"mergeable"`
+	}{
+		PullRequestID: mergeability.PullRequestID,
+		Mergeable:     mergeability.Mergeable,
+	}
+
+	for _, check := range mergeability.Checks {
+		res.Checks = append(res.Checks, CheckItem{
+			Rule:    check.Rule,
+			Message: check.Message,
+			Passed:  check.Passed,
+		})
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, res)
+}
+
+// GET /pullRequest/timeline
+func (h *PRHandler) Timeline(w http.ResponseWriter, r *http.Request) {
+	const op = "PRHandler.Timeline"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		log.Error("pull_request_id query parameter is required")
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "pull_request_id query parameter is required"))
+		return
+	}
+
+	events, err := h.service.Timeline(r.Context(), prID)
+	if errors.Is(err, serviceErrors.ErrPRNotFound) {
+		log.Error("PR not found", "error", err, "prID", prID)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("pull request not found"))
+		return
+	}
+	if err != nil {
+		log.Error("Failed to get PR timeline", "error", err, "prID", prID)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to get pull request timeline"))
+		return
+	}
+
+	type EventItem struct {
+		CreatedAt     time.Time `json:"created_at"`
+		Type          string    `json:"type" validate:"required"`
+		UserID        string    `json:"user_id,omitempty"`
+		RelatedUserID string    `json:"related_user_id,omitempty"`
+		Reason        string    `json:"reason,omitempty"`
+		State         string    `json:"state,omitempty"`
+		ID            int64     `json:"id"`
+	}
+
+	res := struct {
+		PullRequestID string      `json:"pull_request_id" validate:"required"`
+		Events        []EventItem `json:"events" validate:"dive"`
+	}{
+		PullRequestID: prID,
+		Events:        make([]EventItem, 0, len(events)),
+	}
+
+	for _, event := range events {
+		res.Events = append(res.Events, EventItem{
+			CreatedAt:     event.CreatedAt,
+			Type:          string(event.Type),
+			UserID:        event.UserID,
+			RelatedUserID: event.RelatedUserID,
+			Reason:        event.Reason,
+			State:         string(event.State),
+			ID:            event.ID,
+		})
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, res)
+}
+
+// POST /pullRequest/readyForReview
+func (h *PRHandler) ReadyForReview(w http.ResponseWriter, r *http.Request) {
+	const op = "PRHandler.ReadyForReview"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req struct {
+		ID string `json:"pull_request_id" validate:"required"`
+	}
+
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Error("Failed to decode request body", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "wrong request format"))
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		log.Error("Request validation failed", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("VALIDATION_ERROR", "wrong request format"))
+		return
+	}
+
+	pr, err := h.service.ReadyForReview(r.Context(), req.ID)
+	if errors.Is(err, serviceErrors.ErrPRNotFound) {
+		log.Error("PR not found", "error", err, "prID", req.ID)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("pull request not found"))
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrUserNotFound) {
+		log.Error("Author not found", "error", err, "prID", req.ID)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("author not found"))
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrTeamNotFound) {
+		log.Error("Author team not found", "error", err, "prID", req.ID)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("team not found"))
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrTeamArchived) {
+		log.Error("Author team is archived", "error", err, "prID", req.ID)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.TEAM_ARCHIVED())
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrPRMerged) {
+		log.Error("PR already merged", "error", err, "prID", req.ID)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.PR_MERGED())
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrPRClosed) {
+		log.Error("PR is closed", "error", err, "prID", req.ID)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.PR_CLOSED())
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrInvalidReviewersCount) {
+		log.Error("Invalid reviewers count", "error", err, "prID", req.ID)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.INVALID_REVIEWERS_COUNT())
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrNoOwnerCandidate) {
+		log.Error("No code owner available", "error", err, "prID", req.ID)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.NO_OWNER_CANDIDATE())
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrReviewersAtCapacity) {
+		log.Error("Reviewer candidates at capacity", "error", err, "prID", req.ID)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.REVIEWERS_AT_CAPACITY())
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrReviewerUnavailable) {
+		log.Error("Selected reviewers became unavailable", "error", err, "prID", req.ID)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.REVIEWER_UNAVAILABLE())
+		return
+	}
+	if err != nil {
+		log.Error("Failed to mark PR ready for review", "error", err, "prID", req.ID)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to mark pull request ready for review"))
+		return
+	}
+
+	type PRItem struct {
+		ID                string                 `json:"pull_request_id" validate:"required"`
+		Name              string                 `json:"pull_request_name" validate:"required"`
+		AuthorID          string                 `json:"author_id" validate:"required"`
+		Status            string                 `json:"status" validate:"required"`
+		AssignedReviewers []string               `json:"assigned_reviewers" validate:"required"`
+		SkippedAtCapacity []string               `json:"skipped_at_capacity,omitempty"`
+		FallbackReviewers []FallbackReviewerItem `json:"fallback_reviewers,omitempty"`
+		IsDraft           bool                   `json:"is_draft"`
+	}
+
+	res := struct {
+		PullRequest PRItem `json:"pr" validate:"required"`
+	}{
+		PullRequest: PRItem{
+			ID:                pr.ID,
+			Name:              pr.Name,
+			AuthorID:          pr.AuthorID,
+			Status:            string(pr.Status),
+			AssignedReviewers: pr.AssignedReviewers,
+			SkippedAtCapacity: pr.SkippedAtCapacity,
+			FallbackReviewers: fallbackReviewerItems(pr.FallbackReviewers),
+			IsDraft:           pr.IsDraft,
+		},
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, res)
+}
+
+// POST /pullRequest/convertToDraft
+func (h *PRHandler) ConvertToDraft(w http.ResponseWriter, r *http.Request) {
+	const op = "PRHandler.ConvertToDraft"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req struct {
+		ID string `json:"pull_request_id" validate:"required"`
+	}
+
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Error("Failed to decode request body", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "wrong request format"))
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		log.Error("Request validation failed", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("VALIDATION_ERROR", "wrong request format"))
+		return
+	}
+
+	pr, err := h.service.ConvertToDraft(r.Context(), req.ID)
+	if errors.Is(err, serviceErrors.ErrPRNotFound) {
+		log.Error("PR not found", "error", err, "prID", req.ID)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("pull request not found"))
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrPRMerged) {
+		log.Error("PR already merged", "error", err, "prID", req.ID)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.PR_MERGED())
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrPRClosed) {
+		log.Error("PR is closed", "error", err, "prID", req.ID)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.PR_CLOSED())
+		return
+	}
+	if err != nil {
+		log.Error("Failed to convert PR to draft", "error", err, "prID", req.ID)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to convert pull request to draft"))
+		return
+	}
+
+	type PRItem struct {
+		ID                string   `json:"pull_request_id" validate:"required"`
+		Name              string   `json:"pull_request_name" validate:"required"`
+		AuthorID          string   `json:"author_id" validate:"required"`
+		Status            string   `json:"status" validate:"required"`
+		AssignedReviewers []string `json:"assigned_reviewers" validate:"required"`
+		IsDraft           bool     `json:"is_draft"`
+	}
+
+	res := struct {
+		PullRequest PRItem `json:"pr" validate:"required"`
+	}{
+		PullRequest: PRItem{
+			ID:                pr.ID,
+			Name:              pr.Name,
+			AuthorID:          pr.AuthorID,
+			Status:            string(pr.Status),
+			AssignedReviewers: pr.AssignedReviewers,
+			IsDraft:           pr.IsDraft,
+		},
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, res)
+}
+
+// POST /pullRequest/review
+func (h *PRHandler) Review(w http.ResponseWriter, r *http.Request) {
+	const op = "PRHandler.Review"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req struct {
+		PullRequestID string `json:"pull_request_id" validate:"required"`
+		UserID        string `json:"user_id" validate:"required"`
+		State         string `json:"state" validate:"required,oneof=APPROVED CHANGES_REQUESTED COMMENTED"`
+	}
+
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Error("Failed to decode request body", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "wrong request format"))
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		log.Error("Request validation failed", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("VALIDATION_ERROR", "wrong request format"))
+		return
+	}
+
+	pr, err := h.service.SubmitReview(r.Context(), req.PullRequestID, req.UserID, models.ReviewState(req.State))
+	if errors.Is(err, serviceErrors.ErrPRNotFound) {
+		log.Error("PR not found", "error", err, "prID", req.PullRequestID)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("pull request not found"))
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrUserNotFound) {
+		log.Error("Reviewer not found", "error", err, "user_id", req.UserID, "prID", req.PullRequestID)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("reviewer not found"))
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrInvalidReviewState) {
+		log.Error("Invalid review state", "error", err, "state", req.State)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("VALIDATION_ERROR", "wrong request format"))
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrPRMerged) {
+		log.Error("PR already merged", "error", err, "prID", req.PullRequestID)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.PR_MERGED())
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrPRClosed) {
+		log.Error("PR is closed", "error", err, "prID", req.PullRequestID)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.PR_CLOSED())
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrNotAssigned) {
+		log.Error("Reviewer not assigned to this PR", "error", err, "user_id", req.UserID, "prID", req.PullRequestID)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.NOT_ASSIGNED())
+		return
+	}
+	if err != nil {
+		log.Error("Failed to submit review", "error", err, "prID", req.PullRequestID, "user_id", req.UserID)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to submit review"))
+		return
+	}
+
+	type ReviewItem struct {
+		ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+		UserID     string     `json:"user_id" validate:"required"`
+		State      string     `json:"state" validate:"required"`
+	}
+
+	type PRItem struct {
+		ID                string       `json:"pull_request_id" validate:"required"`
+		Name              string       `json:"pull_request_name" validate:"required"`
+		AuthorID          string       `json:"author_id" validate:"required"`
+		Status            string       `json:"status" validate:"required"`
+		AssignedReviewers []string     `json:"assigned_reviewers" validate:"required"`
+		Reviews           []ReviewItem `json:"reviews" validate:"required,dive"`
+	}
+
+	res := struct {
+		PullRequest PRItem `json:"pr" validate:"required"`
+	}{
+		PullRequest: PRItem{
+			ID:                pr.ID,
+			Name:              pr.Name,
+			AuthorID:          pr.AuthorID,
+			Status:            string(pr.Status),
+			AssignedReviewers: pr.AssignedReviewers,
+		},
+	}
+
+	for _, review := range pr.Reviews {
+		res.PullRequest.Reviews = append(res.PullRequest.Reviews, ReviewItem{
+			UserID:     review.UserID,
+			State:      string(review.State),
+			ReviewedAt: review.ReviewedAt,
+		})
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, res)
+}
+
+// POST /pullRequest/close
+func (h *PRHandler) Close(w http.ResponseWriter, r *http.Request) {
+	const op = "PRHandler.Close"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req struct {
+		ID string `json:"pull_request_id" validate:"required"`
+	}
+
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Error("Failed to decode request body", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "wrong request format"))
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		log.Error("Request validation failed", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("VALIDATION_ERROR", "wrong request format"))
+		return
+	}
+
+	pr, err := h.service.ClosePR(r.Context(), req.ID)
+	if errors.Is(err, serviceErrors.ErrPRNotFound) {
+		log.Error("PR not found", "error", err, "prID", req.ID)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("pull request not found"))
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrPRMerged) {
+		log.Error("PR already merged", "error", err, "prID", req.ID)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.PR_MERGED())
+		return
+	}
+	if err != nil {
+		log.Error("Failed to close PR", "error", err, "prID", req.ID)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to close pull request"))
+		return
+	}
+
+	type PRItem struct {
+		ClosedAt          *time.Time `json:"closed_at" validate:"required"`
+		ID                string     `json:"pull_request_id" validate:"required"`
+		Name              string     `json:"pull_request_name" validate:"required"`
+		AuthorID          string     `json:"author_id" validate:"required"`
+		Status            string     `json:"status" validate:"required"`
+		AssignedReviewers []string   `json:"assigned_reviewers" validate:"required"`
+	}
+
+	res := struct {
+		PullRequest PRItem `json:"pr" validate:"required"`
+	}{
+		PullRequest: PRItem{
+			ID:                pr.ID,
+			Name:              pr.Name,
+			AuthorID:          pr.AuthorID,
+			Status:            string(pr.Status),
+			AssignedReviewers: pr.AssignedReviewers,
+			ClosedAt:          pr.ClosedAt,
+		},
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, res)
+}
+
+// POST /pullRequest/reopen
+func (h *PRHandler) Reopen(w http.ResponseWriter, r *http.Request) {
+	const op = "PRHandler.Reopen"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req struct {
+		ID string `json:"pull_request_id" validate:"required"`
+	}
+
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Error("Failed to decode request body", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "wrong request format"))
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		log.Error("Request validation failed", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("VALIDATION_ERROR", "wrong request format"))
+		return
+	}
+
+	pr, err := h.service.ReopenPR(r.Context(), req.ID)
+	if errors.Is(err, serviceErrors.ErrPRNotFound) {
+		log.Error("PR not found", "error", err, "prID", req.ID)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("pull request not found"))
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrPRMerged) {
+		log.Error("PR already merged", "error", err, "prID", req.ID)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.PR_MERGED())
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrReviewersAtCapacity) {
+		log.Error("Reviewer candidates at capacity", "error", err, "prID", req.ID)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.REVIEWERS_AT_CAPACITY())
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrReviewerUnavailable) {
+		log.Error("Selected reviewers became unavailable", "error", err, "prID", req.ID)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.REVIEWER_UNAVAILABLE())
+		return
+	}
+	if err != nil {
+		log.Error("Failed to reopen PR", "error", err, "prID", req.ID)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to reopen pull request"))
+		return
+	}
+
+	type PRItem struct {
+		ID                string                 `json:"pull_request_id" validate:"required"`
+		Name              string                 `json:"pull_request_name" validate:"required"`
+		AuthorID          string                 `json:"author_id" validate:"required"`
+		Status            string                 `json:"status" validate:"required"`
+		AssignedReviewers []string               `json:"assigned_reviewers" validate:"required"`
+		SkippedAtCapacity []string               `json:"skipped_at_capacity,omitempty"`
+		FallbackReviewers []FallbackReviewerItem `json:"fallback_reviewers,omitempty"`
+	}
+
+	res := struct {
+		PullRequest PRItem `json:"pr" validate:"required"`
+	}{
+		PullRequest: PRItem{
+			ID:                pr.ID,
+			Name:              pr.Name,
+			AuthorID:          pr.AuthorID,
+			Status:            string(pr.Status),
+			AssignedReviewers: pr.AssignedReviewers,
+			SkippedAtCapacity: pr.SkippedAtCapacity,
+			FallbackReviewers: fallbackReviewerItems(pr.FallbackReviewers),
+		},
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, res)
+}
+
 // POST /pullRequest/reassign
 func (h *PRHandler) Reassign(w http.ResponseWriter, r *http.Request) {
 	const op = "PRHandler.Reassign"
@@ -211,7 +897,7 @@ func (h *PRHandler) Reassign(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pr, newUserID, err := h.service.ReassignReviewer(r.Context(), req.PullRequestID, req.OldUserID)
+	pr, newUserID, err := h.service.ReassignReviewer(r.Context(), req.PullRequestID, req.OldUserID, models.ReasonManual)
 	if errors.Is(err, serviceErrors.ErrPRNotFound) {
 		log.Error("PR not found", "error", err, "prID", req.PullRequestID)
 		render.Status(r, http.StatusNotFound)
@@ -230,6 +916,12 @@ func (h *PRHandler) Reassign(w http.ResponseWriter, r *http.Request) {
 		render.JSON(w, r, response.PR_MERGED())
 		return
 	}
+	if errors.Is(err, serviceErrors.ErrPRClosed) {
+		log.Error("PR is closed", "error", err, "prID", req.PullRequestID)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.PR_CLOSED())
+		return
+	}
 	if errors.Is(err, serviceErrors.ErrNotAssigned) {
 		log.Error("Reviewer not assigned to this PR", "error", err, "old_user_id", req.OldUserID, "prID", req.PullRequestID)
 		render.Status(r, http.StatusConflict)
@@ -242,6 +934,18 @@ func (h *PRHandler) Reassign(w http.ResponseWriter, r *http.Request) {
 		render.JSON(w, r, response.NO_CANDIDATE())
 		return
 	}
+	if errors.Is(err, serviceErrors.ErrReviewersAtCapacity) {
+		log.Error("Replacement candidates at capacity", "error", err, "old_user_id", req.OldUserID, "prID", req.PullRequestID)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.REVIEWERS_AT_CAPACITY())
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrReviewerUnavailable) {
+		log.Error("Selected reviewers became unavailable", "error", err, "prID", req.PullRequestID)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.REVIEWER_UNAVAILABLE())
+		return
+	}
 	if err != nil {
 		log.Error("Failed to reassign reviewer", "error", err, "prID", req.PullRequestID, "old_user_id", req.OldUserID)
 		render.Status(r, http.StatusInternalServerError)
@@ -250,11 +954,12 @@ func (h *PRHandler) Reassign(w http.ResponseWriter, r *http.Request) {
 	}
 
 	type PRItem struct {
-		ID                string   `json:"pull_request_id" validate:"required"`
-		Name              string   `json:"pull_request_name" validate:"required"`
-		AuthorID          string   `json:"author_id" validate:"required"`
-		Status            string   `json:"status" validate:"required"`
-		AssignedReviewers []string `json:"assigned_reviewers" validate:"required"`
+		ID                string                 `json:"pull_request_id" validate:"required"`
+		Name              string                 `json:"pull_request_name" validate:"required"`
+		AuthorID          string                 `json:"author_id" validate:"required"`
+		Status            string                 `json:"status" validate:"required"`
+		AssignedReviewers []string               `json:"assigned_reviewers" validate:"required"`
+		FallbackReviewers []FallbackReviewerItem `json:"fallback_reviewers,omitempty"`
 	}
 
 	res := struct {
@@ -265,8 +970,9 @@ func (h *PRHandler) Reassign(w http.ResponseWriter, r *http.Request) {
 			ID:                pr.ID,
 			Name:              pr.Name,
 			AuthorID:          pr.AuthorID,
-			Status:            pr.Status,
+			Status:            string(pr.Status),
 			AssignedReviewers: pr.AssignedReviewers,
+			FallbackReviewers: fallbackReviewerItems(pr.FallbackReviewers),
 		},
 		NewUserID: *newUserID,
 	}
@@ -69,6 +69,7 @@ func (h *StatsHandler) User(w http.ResponseWriter, r *http.Request) {
 		TeamName      string `json:"team_name" validate:"required"`
 		OpenReviews   int    `json:"open_assignments" validate:"required"`
 		MergedReviews int    `json:"merged_assignments" validate:"required"`
+		ClosedReviews int    `json:"closed_assignments" validate:"required"`
 		CreatedPRs    int    `json:"created_prs" validate:"required"`
 	}
 
@@ -81,6 +82,7 @@ func (h *StatsHandler) User(w http.ResponseWriter, r *http.Request) {
 			TeamName:      stats.TeamName,
 			OpenReviews:   stats.OpenReviews,
 			MergedReviews: stats.MergedReviews,
+			ClosedReviews: stats.ClosedReviews,
 			CreatedPRs:    stats.CreatedPRs,
 		},
 	}
@@ -167,7 +169,9 @@ func (h *StatsHandler) Total(w http.ResponseWriter, r *http.Request) {
 		ActiveUsers       int     `json:"active_users" validate:"required"`
 		TotalPRs          int     `json:"total_prs" validate:"required"`
 		OpenPRs           int     `json:"open_prs" validate:"required"`
+		DraftPRs          int     `json:"draft_prs" validate:"required"`
 		MergedPRs         int     `json:"merged_prs" validate:"required"`
+		ClosedPRs         int     `json:"closed_prs" validate:"required"`
 		AvgReviewersPerPR float64 `json:"avg_reviewers_per_pr" validate:"required"`
 	}
 
@@ -180,7 +184,9 @@ func (h *StatsHandler) Total(w http.ResponseWriter, r *http.Request) {
 			ActiveUsers:       stats.ActiveUsers,
 			TotalPRs:          stats.TotalPRs,
 			OpenPRs:           stats.OpenPRs,
+			DraftPRs:          stats.DraftPRs,
 			MergedPRs:         stats.MergedPRs,
+			ClosedPRs:         stats.ClosedPRs,
 			AvgReviewersPerPR: stats.AvgReviewersPerPR,
 		},
 	}
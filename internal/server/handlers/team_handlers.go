@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"time"
 
 	serviceErrors "pr-review/internal/errors"
 	"pr-review/internal/models"
@@ -18,6 +19,18 @@ import (
 type TeamService interface {
 	CreateTeam(ctx context.Context, team *models.Team) (*models.Team, error)
 	GetTeam(ctx context.Context, teamName string) (*models.Team, error)
+	AddMember(ctx context.Context, teamName string, member *models.TeamMember) (*models.Team, error)
+	RemoveMember(ctx context.Context, teamName, userID string) (*models.MembershipChange, error)
+	DeactivateUsers(ctx context.Context, teamName string, userIDs []string) ([]*models.MembershipChange, error)
+	DeleteTeam(ctx context.Context, teamName string, force bool) (*models.TeamDeletion, error)
+	RenameTeam(ctx context.Context, oldName, newName string) (*models.Team, error)
+	SetMergePolicy(ctx context.Context, teamName string, update *models.MergePolicyUpdate) (*models.Team, error)
+	SetMaxOpenReviews(ctx context.Context, teamName string, limit *int) (*models.Team, error)
+	SetFallbacks(ctx context.Context, teamName string, fallbacks []models.ReviewerFallback) (*models.Team, error)
+	SetCodeOwners(ctx context.Context, codeOwners *models.CodeOwners) (*models.CodeOwners, error)
+	GetCodeOwners(ctx context.Context, teamName string) (*models.CodeOwners, error)
+	SetChat(ctx context.Context, chat *models.TeamChat) (*models.TeamChat, error)
+	GetChat(ctx context.Context, teamName string) (*models.TeamChat, error)
 }
 
 type TeamHandler struct {
@@ -48,8 +61,14 @@ func (h *TeamHandler) Add(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		Name    string       `json:"team_name" validate:"required"`
-		Members []MemberItem `json:"members" validate:"dive"`
+		RequiredReviewers       *int         `json:"required_reviewers" validate:"omitempty,min=0"`
+		RequiredApprovals       *int         `json:"required_approvals" validate:"omitempty,min=0"`
+		BlockOnChangesRequested *bool        `json:"block_on_changes_requested"`
+		ForbidSelfApproval      *bool        `json:"forbid_self_approval"`
+		RequireOwnerApproval    *bool        `json:"require_owner_approval"`
+		MaxOpenReviews          *int         `json:"max_open_reviews" validate:"omitempty,min=0"`
+		Name                    string       `json:"team_name" validate:"required"`
+		Members                 []MemberItem `json:"members" validate:"dive"`
 	}
 
 	if err := render.DecodeJSON(r.Body, &req); err != nil {
@@ -77,8 +96,28 @@ func (h *TeamHandler) Add(w http.ResponseWriter, r *http.Request) {
 		members = append(members, member)
 	}
 	team := &models.Team{
-		Name:    req.Name,
-		Members: members,
+		Name:              req.Name,
+		Members:           members,
+		MaxOpenReviews:    req.MaxOpenReviews,
+		RequiredReviewers: models.DefaultRequiredReviewers,
+		MergePolicy: models.MergePolicy{
+			ForbidSelfApproval: true,
+		},
+	}
+	if req.RequiredReviewers != nil {
+		team.RequiredReviewers = *req.RequiredReviewers
+	}
+	if req.RequiredApprovals != nil {
+		team.MergePolicy.RequiredApprovals = *req.RequiredApprovals
+	}
+	if req.BlockOnChangesRequested != nil {
+		team.MergePolicy.BlockOnChangesRequested = *req.BlockOnChangesRequested
+	}
+	if req.ForbidSelfApproval != nil {
+		team.MergePolicy.ForbidSelfApproval = *req.ForbidSelfApproval
+	}
+	if req.RequireOwnerApproval != nil {
+		team.MergePolicy.RequireOwnerApproval = *req.RequireOwnerApproval
 	}
 
 	createdTeam, err := h.service.CreateTeam(r.Context(), team)
@@ -94,6 +133,12 @@ func (h *TeamHandler) Add(w http.ResponseWriter, r *http.Request) {
 		render.JSON(w, r, response.USER_EXISTS())
 		return
 	}
+	if errors.Is(err, serviceErrors.ErrInvalidReviewersCount) {
+		log.Error("Invalid required reviewers count", "error", err, "team_name", team.Name)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.INVALID_REVIEWERS_COUNT())
+		return
+	}
 	if err != nil {
 		log.Error("Failed to create team", "error", err, "team_name", team.Name)
 		render.Status(r, http.StatusInternalServerError)
@@ -102,15 +147,21 @@ func (h *TeamHandler) Add(w http.ResponseWriter, r *http.Request) {
 	}
 
 	type TeamItem struct {
-		Name    string       `json:"team_name" validate:"required"`
-		Members []MemberItem `json:"members,omitempty" validate:"dive"`
+		MaxOpenReviews    *int         `json:"max_open_reviews,omitempty"`
+		Name              string       `json:"team_name" validate:"required"`
+		Members           []MemberItem `json:"members,omitempty" validate:"dive"`
+		RequiredReviewers int          `json:"required_reviewers"`
+		MergePolicyItem
 	}
 
 	res := struct {
 		Team TeamItem `json:"team" validate:"required"`
 	}{
 		Team: TeamItem{
-			Name: createdTeam.Name,
+			MaxOpenReviews:    createdTeam.MaxOpenReviews,
+			Name:              createdTeam.Name,
+			RequiredReviewers: createdTeam.RequiredReviewers,
+			MergePolicyItem:   mergePolicyItem(&createdTeam.MergePolicy),
 		},
 	}
 
@@ -165,10 +216,113 @@ func (h *TeamHandler) Get(w http.ResponseWriter, r *http.Request) {
 	}
 
 	res := struct {
+		ArchivedAt        *time.Time     `json:"archived_at,omitempty"`
+		MaxOpenReviews    *int           `json:"max_open_reviews,omitempty"`
+		Name              string         `json:"team_name" validate:"required"`
+		Members           []MemberItem   `json:"members,omitempty" validate:"dive"`
+		Fallbacks         []FallbackItem `json:"fallbacks,omitempty"`
+		RequiredReviewers int            `json:"required_reviewers"`
+		MergePolicyItem
+	}{
+		ArchivedAt:        team.ArchivedAt,
+		MaxOpenReviews:    team.MaxOpenReviews,
+		Fallbacks:         fallbackItems(team.Fallbacks),
+		Name:              team.Name,
+		RequiredReviewers: team.RequiredReviewers,
+		MergePolicyItem:   mergePolicyItem(&team.MergePolicy),
+	}
+
+	for _, m := range team.Members {
+		member := MemberItem{
+			UserID:   m.UserID,
+			Username: m.Username,
+			IsActive: m.IsActive,
+		}
+		res.Members = append(res.Members, member)
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, res)
+}
+
+// POST /team/addMember
+func (h *TeamHandler) AddMember(w http.ResponseWriter, r *http.Request) {
+	const op = "TeamHandlers.AddMember"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req struct {
+		TeamName string `json:"team_name" validate:"required"`
+		UserID   string `json:"user_id" validate:"required"`
+		Username string `json:"username" validate:"required"`
+		IsActive bool   `json:"is_active"`
+	}
+
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Error("Failed to decode request body", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "wrong request format"))
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		log.Error("Request validation failed", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("VALIDATION_ERROR", "wrong request format"))
+		return
+	}
+
+	member := &models.TeamMember{
+		UserID:   req.UserID,
+		Username: req.Username,
+		IsActive: req.IsActive,
+	}
+
+	team, err := h.service.AddMember(r.Context(), req.TeamName, member)
+	if errors.Is(err, serviceErrors.ErrTeamNotFound) {
+		log.Error("Team not found", "error", err, "team_name", req.TeamName)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("team not found"))
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrTeamArchived) {
+		log.Error("Team is archived", "error", err, "team_name", req.TeamName)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.TEAM_ARCHIVED())
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrUserExists) {
+		log.Error("User already exists", "error", err, "team_name", req.TeamName, "user_id", req.UserID)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.USER_EXISTS())
+		return
+	}
+	if err != nil {
+		log.Error("Failed to add team member", "error", err, "team_name", req.TeamName, "user_id", req.UserID)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to add team member"))
+		return
+	}
+
+	type MemberItem struct {
+		UserID   string `json:"user_id" validate:"required"`
+		Username string `json:"username" validate:"required"`
+		IsActive bool   `json:"is_active"`
+	}
+
+	type TeamItem struct {
 		Name    string       `json:"team_name" validate:"required"`
 		Members []MemberItem `json:"members,omitempty" validate:"dive"`
+	}
+
+	res := struct {
+		Team TeamItem `json:"team" validate:"required"`
 	}{
-		Name: team.Name,
+		Team: TeamItem{Name: team.Name},
 	}
 
 	for _, m := range team.Members {
@@ -177,9 +331,793 @@ func (h *TeamHandler) Get(w http.ResponseWriter, r *http.Request) {
 			Username: m.Username,
 			IsActive: m.IsActive,
 		}
-		res.Members = append(res.Members, member)
+		res.Team.Members = append(res.Team.Members, member)
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, res)
+}
+
+// POST /team/removeMember
+func (h *TeamHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	const op = "TeamHandlers.RemoveMember"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req struct {
+		TeamName string `json:"team_name" validate:"required"`
+		UserID   string `json:"user_id" validate:"required"`
+	}
+
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Error("Failed to decode request body", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "wrong request format"))
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		log.Error("Request validation failed", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("VALIDATION_ERROR", "wrong request format"))
+		return
+	}
+
+	change, err := h.service.RemoveMember(r.Context(), req.TeamName, req.UserID)
+	if errors.Is(err, serviceErrors.ErrTeamNotFound) {
+		log.Error("Team not found", "error", err, "team_name", req.TeamName)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("team not found"))
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrUserNotFound) {
+		log.Error("User not found", "error", err, "user_id", req.UserID)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("user not found"))
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrNotMember) {
+		log.Error("User is not a team member", "error", err, "team_name", req.TeamName, "user_id", req.UserID)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("user is not a member of this team"))
+		return
 	}
+	if err != nil {
+		log.Error("Failed to remove team member", "error", err, "team_name", req.TeamName, "user_id", req.UserID)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to remove team member"))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, membershipChangeResponse(change))
+}
+
+// POST /team/deactivateUsers
+func (h *TeamHandler) DeactivateUsers(w http.ResponseWriter, r *http.Request) {
+	const op = "TeamHandlers.DeactivateUsers"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req struct {
+		TeamName string   `json:"team_name" validate:"required"`
+		UserIDs  []string `json:"user_ids" validate:"required,min=1,dive,required"`
+	}
+
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Error("Failed to decode request body", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "wrong request format"))
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		log.Error("Request validation failed", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("VALIDATION_ERROR", "wrong request format"))
+		return
+	}
+
+	changes, err := h.service.DeactivateUsers(r.Context(), req.TeamName, req.UserIDs)
+	if errors.Is(err, serviceErrors.ErrTeamNotFound) {
+		log.Error("Team not found", "error", err, "team_name", req.TeamName)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("team not found"))
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrUserNotFound) {
+		log.Error("User not found", "error", err, "user_ids", req.UserIDs)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("user not found"))
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrNotMember) {
+		log.Error("User is not a team member", "error", err, "team_name", req.TeamName, "user_ids", req.UserIDs)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("user is not a member of this team"))
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrReviewerUnavailable) {
+		log.Error("Selected reviewers became unavailable", "error", err, "team_name", req.TeamName, "user_ids", req.UserIDs)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.REVIEWER_UNAVAILABLE())
+		return
+	}
+	if err != nil {
+		log.Error("Failed to deactivate users", "error", err, "team_name", req.TeamName, "user_ids", req.UserIDs)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to deactivate users"))
+		return
+	}
+
+	res := struct {
+		TeamName string `json:"team_name" validate:"required"`
+		Users    []any  `json:"users"`
+	}{
+		TeamName: req.TeamName,
+		Users:    make([]any, 0, len(changes)),
+	}
+	for _, change := range changes {
+		res.Users = append(res.Users, membershipChangeResponse(change))
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, res)
+}
+
+// POST /team/delete
+func (h *TeamHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	const op = "TeamHandlers.Delete"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req struct {
+		TeamName string `json:"team_name" validate:"required"`
+		Force    bool   `json:"force"`
+	}
+
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Error("Failed to decode request body", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "wrong request format"))
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		log.Error("Request validation failed", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("VALIDATION_ERROR", "wrong request format"))
+		return
+	}
+
+	deletion, err := h.service.DeleteTeam(r.Context(), req.TeamName, req.Force)
+	if errors.Is(err, serviceErrors.ErrTeamNotFound) {
+		log.Error("Team not found", "error", err, "team_name", req.TeamName)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("team not found"))
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrTeamHasOpenPRs) {
+		log.Error("Team has open pull requests", "error", err, "team_name", req.TeamName)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.TEAM_HAS_OPEN_PRS())
+		return
+	}
+	if err != nil {
+		log.Error("Failed to delete team", "error", err, "team_name", req.TeamName)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to delete team"))
+		return
+	}
+
+	res := struct {
+		TeamName  string   `json:"team_name" validate:"required"`
+		Result    string   `json:"result" validate:"required"`
+		ClosedPRs []string `json:"closed_pull_requests"`
+	}{
+		TeamName:  deletion.TeamName,
+		Result:    deletion.Result,
+		ClosedPRs: make([]string, 0, len(deletion.ClosedPRs)),
+	}
+	res.ClosedPRs = append(res.ClosedPRs, deletion.ClosedPRs...)
 
 	render.Status(r, http.StatusOK)
 	render.JSON(w, r, res)
 }
+
+// POST /team/rename
+func (h *TeamHandler) Rename(w http.ResponseWriter, r *http.Request) {
+	const op = "TeamHandlers.Rename"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req struct {
+		TeamName    string `json:"team_name" validate:"required"`
+		NewTeamName string `json:"new_team_name" validate:"required"`
+	}
+
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Error("Failed to decode request body", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "wrong request format"))
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		log.Error("Request validation failed", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("VALIDATION_ERROR", "wrong request format"))
+		return
+	}
+
+	team, err := h.service.RenameTeam(r.Context(), req.TeamName, req.NewTeamName)
+	if errors.Is(err, serviceErrors.ErrTeamNotFound) {
+		log.Error("Team not found", "error", err, "team_name", req.TeamName)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("team not found"))
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrTeamExists) {
+		log.Error("Team already exists", "error", err, "team_name", req.NewTeamName)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.TEAM_EXISTS())
+		return
+	}
+	if err != nil {
+		log.Error("Failed to rename team", "error", err, "team_name", req.TeamName, "new_team_name", req.NewTeamName)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to rename team"))
+		return
+	}
+
+	type MemberItem struct {
+		UserID   string `json:"user_id" validate:"required"`
+		Username string `json:"username" validate:"required"`
+		IsActive bool   `json:"is_active"`
+	}
+
+	type TeamItem struct {
+		Name    string       `json:"team_name" validate:"required"`
+		Members []MemberItem `json:"members,omitempty" validate:"dive"`
+	}
+
+	res := struct {
+		Team TeamItem `json:"team" validate:"required"`
+	}{
+		Team: TeamItem{Name: team.Name},
+	}
+
+	for _, m := range team.Members {
+		member := MemberItem{
+			UserID:   m.UserID,
+			Username: m.Username,
+			IsActive: m.IsActive,
+		}
+		res.Team.Members = append(res.Team.Members, member)
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, res)
+}
+
+// POST /team/setMergePolicy
+func (h *TeamHandler) SetMergePolicy(w http.ResponseWriter, r *http.Request) {
+	const op = "TeamHandlers.SetMergePolicy"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	// Не переданные правила остаются прежними
+	var req struct {
+		RequiredApprovals       *int   `json:"required_approvals" validate:"omitempty,min=0"`
+		BlockOnChangesRequested *bool  `json:"block_on_changes_requested"`
+		ForbidSelfApproval      *bool  `json:"forbid_self_approval"`
+		RequireOwnerApproval    *bool  `json:"require_owner_approval"`
+		TeamName                string `json:"team_name" validate:"required"`
+	}
+
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Error("Failed to decode request body", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "wrong request format"))
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		log.Error("Request validation failed", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("VALIDATION_ERROR", "wrong request format"))
+		return
+	}
+
+	update := &models.MergePolicyUpdate{
+		RequiredApprovals:       req.RequiredApprovals,
+		BlockOnChangesRequested: req.BlockOnChangesRequested,
+		ForbidSelfApproval:      req.ForbidSelfApproval,
+		RequireOwnerApproval:    req.RequireOwnerApproval,
+	}
+
+	team, err := h.service.SetMergePolicy(r.Context(), req.TeamName, update)
+	if errors.Is(err, serviceErrors.ErrTeamNotFound) {
+		log.Error("Team not found", "error", err, "team_name", req.TeamName)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("team not found"))
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrInvalidReviewersCount) {
+		log.Error("Invalid required approvals count", "error", err, "team_name", req.TeamName)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.INVALID_REVIEWERS_COUNT())
+		return
+	}
+	if err != nil {
+		log.Error("Failed to set merge policy", "error", err, "team_name", req.TeamName)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to set merge policy"))
+		return
+	}
+
+	res := struct {
+		TeamName string `json:"team_name" validate:"required"`
+		MergePolicyItem
+	}{
+		TeamName:        team.Name,
+		MergePolicyItem: mergePolicyItem(&team.MergePolicy),
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, res)
+}
+
+// POST /team/setMaxOpenReviews
+func (h *TeamHandler) SetMaxOpenReviews(w http.ResponseWriter, r *http.Request) {
+	const op = "TeamHandlers.SetMaxOpenReviews"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req struct {
+		MaxOpenReviews *int   `json:"max_open_reviews" validate:"omitempty,min=0"`
+		TeamName       string `json:"team_name" validate:"required"`
+	}
+
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Error("Failed to decode request body", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "wrong request format"))
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		log.Error("Request validation failed", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("VALIDATION_ERROR", "wrong request format"))
+		return
+	}
+
+	team, err := h.service.SetMaxOpenReviews(r.Context(), req.TeamName, req.MaxOpenReviews)
+	if errors.Is(err, serviceErrors.ErrTeamNotFound) {
+		log.Error("Team not found", "error", err, "team_name", req.TeamName)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("team not found"))
+		return
+	}
+	if err != nil {
+		log.Error("Failed to set team max open reviews", "error", err, "team_name", req.TeamName)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to set team max open reviews"))
+		return
+	}
+
+	res := struct {
+		MaxOpenReviews *int   `json:"max_open_reviews"`
+		TeamName       string `json:"team_name" validate:"required"`
+	}{
+		MaxOpenReviews: team.MaxOpenReviews,
+		TeamName:       team.Name,
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, res)
+}
+
+// POST /team/setFallbacks
+func (h *TeamHandler) SetFallbacks(w http.ResponseWriter, r *http.Request) {
+	const op = "TeamHandlers.SetFallbacks"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req struct {
+		TeamName  string         `json:"team_name" validate:"required"`
+		Fallbacks []FallbackItem `json:"fallbacks" validate:"dive"`
+	}
+
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Error("Failed to decode request body", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "wrong request format"))
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		log.Error("Request validation failed", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("VALIDATION_ERROR", "wrong request format"))
+		return
+	}
+
+	fallbacks := make([]models.ReviewerFallback, 0, len(req.Fallbacks))
+	for _, item := range req.Fallbacks {
+		fallbacks = append(fallbacks, models.ReviewerFallback{Kind: item.Kind, Name: item.Name})
+	}
+
+	team, err := h.service.SetFallbacks(r.Context(), req.TeamName, fallbacks)
+	if errors.Is(err, serviceErrors.ErrInvalidFallback) {
+		log.Error("Invalid team fallbacks", "error", err, "team_name", req.TeamName)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.INVALID_FALLBACK(errors.Unwrap(err).Error()))
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrTeamNotFound) {
+		log.Error("Team not found", "error", err, "team_name", req.TeamName)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("team not found"))
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrPoolNotFound) {
+		log.Error("Fallback pool not found", "error", err, "team_name", req.TeamName)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("pool not found"))
+		return
+	}
+	if err != nil {
+		log.Error("Failed to set team fallbacks", "error", err, "team_name", req.TeamName)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to set team fallbacks"))
+		return
+	}
+
+	res := struct {
+		TeamName  string         `json:"team_name" validate:"required"`
+		Fallbacks []FallbackItem `json:"fallbacks"`
+	}{
+		TeamName:  team.Name,
+		Fallbacks: make([]FallbackItem, 0, len(team.Fallbacks)),
+	}
+	res.Fallbacks = append(res.Fallbacks, fallbackItems(team.Fallbacks)...)
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, res)
+}
+
+// POST /team/setCodeOwners
+func (h *TeamHandler) SetCodeOwners(w http.ResponseWriter, r *http.Request) {
+	const op = "TeamHandlers.SetCodeOwners"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req struct {
+		TeamName string `json:"team_name" validate:"required"`
+		Content  string `json:"content"`
+		Mode     string `json:"mode" validate:"omitempty,oneof=prefer require"`
+	}
+
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Error("Failed to decode request body", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "wrong request format"))
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		log.Error("Request validation failed", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("VALIDATION_ERROR", "wrong request format"))
+		return
+	}
+
+	codeOwners := &models.CodeOwners{
+		TeamName: req.TeamName,
+		Content:  req.Content,
+		Mode:     req.Mode,
+	}
+	if codeOwners.Mode == "" {
+		codeOwners.Mode = models.CodeOwnersPrefer
+	}
+
+	updated, err := h.service.SetCodeOwners(r.Context(), codeOwners)
+	if errors.Is(err, serviceErrors.ErrTeamNotFound) {
+		log.Error("Team not found", "error", err, "team_name", req.TeamName)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("team not found"))
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrInvalidCodeOwners) {
+		log.Error("Invalid code owners file", "error", err, "team_name", req.TeamName)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.INVALID_CODEOWNERS())
+		return
+	}
+	if err != nil {
+		log.Error("Failed to set code owners", "error", err, "team_name", req.TeamName)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to set code owners"))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, codeOwnersResponse(updated))
+}
+
+// GET /team/getCodeOwners
+func (h *TeamHandler) GetCodeOwners(w http.ResponseWriter, r *http.Request) {
+	const op = "TeamHandlers.GetCodeOwners"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		log.Error("team_name query parameter is required")
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "team_name query parameter is required"))
+		return
+	}
+
+	codeOwners, err := h.service.GetCodeOwners(r.Context(), teamName)
+	if errors.Is(err, serviceErrors.ErrCodeOwnersNotFound) {
+		log.Error("Code owners not found", "error", err, "team_name", teamName)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("code owners not found"))
+		return
+	}
+	if err != nil {
+		log.Error("Failed to get code owners", "error", err, "team_name", teamName)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to get code owners"))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, codeOwnersResponse(codeOwners))
+}
+
+func codeOwnersResponse(codeOwners *models.CodeOwners) any {
+	type CodeOwnersItem struct {
+		UpdatedAt time.Time `json:"updated_at"`
+		TeamName  string    `json:"team_name" validate:"required"`
+		Content   string    `json:"content"`
+		Mode      string    `json:"mode" validate:"required"`
+	}
+
+	return struct {
+		CodeOwners CodeOwnersItem `json:"code_owners" validate:"required"`
+	}{
+		CodeOwners: CodeOwnersItem{
+			UpdatedAt: codeOwners.UpdatedAt,
+			TeamName:  codeOwners.TeamName,
+			Content:   codeOwners.Content,
+			Mode:      codeOwners.Mode,
+		},
+	}
+}
+
+// POST /team/setChat
+func (h *TeamHandler) SetChat(w http.ResponseWriter, r *http.Request) {
+	const op = "TeamHandlers.SetChat"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req struct {
+		TeamName  string            `json:"team_name" validate:"required"`
+		URL       string            `json:"url" validate:"required"`
+		Format    string            `json:"format" validate:"omitempty,oneof=slack mattermost"`
+		Templates ChatTemplatesItem `json:"templates"`
+	}
+
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Error("Failed to decode request body", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "wrong request format"))
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		log.Error("Request validation failed", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("VALIDATION_ERROR", "wrong request format"))
+		return
+	}
+
+	chat := &models.TeamChat{
+		TeamName: req.TeamName,
+		URL:      req.URL,
+		Format:   req.Format,
+		Templates: models.ChatTemplates{
+			ReviewerAssigned:   req.Templates.ReviewerAssigned,
+			ReviewerReassigned: req.Templates.ReviewerReassigned,
+			PRMerged:           req.Templates.PRMerged,
+		},
+	}
+	if chat.Format == "" {
+		chat.Format = models.ChatFormatSlack
+	}
+
+	updated, err := h.service.SetChat(r.Context(), chat)
+	if errors.Is(err, serviceErrors.ErrTeamNotFound) {
+		log.Error("Team not found", "error", err, "team_name", req.TeamName)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("team not found"))
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrInvalidChat) {
+		log.Error("Invalid team chat", "error", err, "team_name", req.TeamName)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.INVALID_CHAT(errors.Unwrap(err).Error()))
+		return
+	}
+	if err != nil {
+		log.Error("Failed to set team chat", "error", err, "team_name", req.TeamName)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to set team chat"))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, chatResponse(updated))
+}
+
+// GET /team/getChat
+func (h *TeamHandler) GetChat(w http.ResponseWriter, r *http.Request) {
+	const op = "TeamHandlers.GetChat"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		log.Error("team_name query parameter is required")
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "team_name query parameter is required"))
+		return
+	}
+
+	chat, err := h.service.GetChat(r.Context(), teamName)
+	if errors.Is(err, serviceErrors.ErrChatNotFound) {
+		log.Error("Team chat not found", "error", err, "team_name", teamName)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("team chat not found"))
+		return
+	}
+	if err != nil {
+		log.Error("Failed to get team chat", "error", err, "team_name", teamName)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to get team chat"))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, chatResponse(chat))
+}
+
+type ChatTemplatesItem struct {
+	ReviewerAssigned   string `json:"reviewer_assigned,omitempty"`
+	ReviewerReassigned string `json:"reviewer_reassigned,omitempty"`
+	PRMerged           string `json:"pr_merged,omitempty"`
+}
+
+func chatResponse(chat *models.TeamChat) any {
+	type ChatItem struct {
+		UpdatedAt time.Time         `json:"updated_at"`
+		TeamName  string            `json:"team_name"`
+		URL       string            `json:"url"`
+		Format    string            `json:"format"`
+		Templates ChatTemplatesItem `json:"templates"`
+	}
+
+	return struct {
+		Chat ChatItem `json:"chat"`
+	}{
+		Chat: ChatItem{
+			UpdatedAt: chat.UpdatedAt,
+			TeamName:  chat.TeamName,
+			URL:       chat.URL,
+			Format:    chat.Format,
+			Templates: ChatTemplatesItem{
+				ReviewerAssigned:   chat.Templates.ReviewerAssigned,
+				ReviewerReassigned: chat.Templates.ReviewerReassigned,
+				PRMerged:           chat.Templates.PRMerged,
+			},
+		},
+	}
+}
+
+type MergePolicyItem struct {
+	RequiredApprovals       int  `json:"required_approvals" validate:"min=0"`
+	BlockOnChangesRequested bool `json:"block_on_changes_requested"`
+	ForbidSelfApproval      bool `json:"forbid_self_approval"`
+	RequireOwnerApproval    bool `json:"require_owner_approval"`
+}
+
+func mergePolicyItem(policy *models.MergePolicy) MergePolicyItem {
+	return MergePolicyItem{
+		RequiredApprovals:       policy.RequiredApprovals,
+		BlockOnChangesRequested: policy.BlockOnChangesRequested,
+		ForbidSelfApproval:      policy.ForbidSelfApproval,
+		RequireOwnerApproval:    policy.RequireOwnerApproval,
+	}
+}
+
+type FallbackItem struct {
+	Kind string `json:"kind" validate:"required,oneof=team pool"`
+	Name string `json:"name" validate:"required"`
+}
+
+func fallbackItem(fallback *models.ReviewerFallback) FallbackItem {
+	return FallbackItem{
+		Kind: fallback.Kind,
+		Name: fallback.Name,
+	}
+}
+
+func fallbackItems(fallbacks []models.ReviewerFallback) []FallbackItem {
+	var items []FallbackItem
+	for i := range fallbacks {
+		items = append(items, fallbackItem(&fallbacks[i]))
+	}
+	return items
+}
+
+type FallbackReviewerItem struct {
+	UserID string `json:"user_id"`
+	FallbackItem
+}
+
+func fallbackReviewerItems(reviewers []models.FallbackReviewer) []FallbackReviewerItem {
+	var items []FallbackReviewerItem
+	for i := range reviewers {
+		items = append(items, FallbackReviewerItem{
+			UserID:       reviewers[i].UserID,
+			FallbackItem: fallbackItem(&reviewers[i].ReviewerFallback),
+		})
+	}
+	return items
+}
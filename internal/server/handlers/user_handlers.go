@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"time"
 
 	serviceErrors "pr-review/internal/errors"
 	"pr-review/internal/models"
@@ -17,7 +18,12 @@ import (
 
 type UserService interface {
 	SetUserActive(ctx context.Context, userID string, isActive bool) (*models.User, error)
-	GetUserReviewPRs(ctx context.Context, userID string) ([]*models.PullRequestShort, error)
+	SetMaxOpenReviews(ctx context.Context, userID string, limit *int) (*models.User, error)
+	MoveTeam(ctx context.Context, userID, teamName string) (*models.MembershipChange, error)
+	AddAbsence(ctx context.Context, absence *models.Absence) (*models.Absence, error)
+	LinkAccount(ctx context.Context, account *models.UserAccount) (*models.UserAccount, error)
+	SetEmail(ctx context.Context, settings *models.EmailSettings) (*models.EmailSettings, error)
+	GetUserReviewPRs(ctx context.Context, userID string) ([]*models.ReviewAssignment, error)
 }
 
 type UserHandler struct {
@@ -97,6 +103,344 @@ func (h *UserHandler) SetIsActive(w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, res)
 }
 
+// POST /users/setMaxOpenReviews
+func (h *UserHandler) SetMaxOpenReviews(w http.ResponseWriter, r *http.Request) {
+	const op = "UserHandlers.SetMaxOpenReviews"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req struct {
+		MaxOpenReviews *int   `json:"max_open_reviews" validate:"omitempty,min=0"`
+		UserID         string `json:"user_id" validate:"required"`
+	}
+
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Error("Failed to decode request body", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "wrong request format"))
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		log.Error("Request validation failed", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("VALIDATION_ERROR", "wrong request format"))
+		return
+	}
+
+	user, err := h.service.SetMaxOpenReviews(r.Context(), req.UserID, req.MaxOpenReviews)
+	if errors.Is(err, serviceErrors.ErrUserNotFound) {
+		log.Error("User not found", "error", err, "user_id", req.UserID)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("user not found"))
+		return
+	}
+	if err != nil {
+		log.Error("Failed to set user max open reviews", "error", err, "user_id", req.UserID)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to set user max open reviews"))
+		return
+	}
+
+	type UserItem struct {
+		MaxOpenReviews *int   `json:"max_open_reviews"`
+		UserID         string `json:"user_id" validate:"required"`
+		Username       string `json:"username" validate:"required"`
+		TeamName       string `json:"team_name,omitempty"`
+		IsActive       bool   `json:"is_active"`
+	}
+
+	res := struct {
+		User UserItem `json:"user" validate:"required"`
+	}{
+		User: UserItem{
+			MaxOpenReviews: user.MaxOpenReviews,
+			UserID:         user.UserID,
+			Username:       user.Username,
+			TeamName:       user.TeamName,
+			IsActive:       user.IsActive,
+		},
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, res)
+}
+
+// POST /users/moveTeam
+func (h *UserHandler) MoveTeam(w http.ResponseWriter, r *http.Request) {
+	const op = "UserHandlers.MoveTeam"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req struct {
+		UserID   string `json:"user_id" validate:"required"`
+		TeamName string `json:"team_name" validate:"required"`
+	}
+
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Error("Failed to decode request body", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "wrong request format"))
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		log.Error("Request validation failed", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("VALIDATION_ERROR", "wrong request format"))
+		return
+	}
+
+	change, err := h.service.MoveTeam(r.Context(), req.UserID, req.TeamName)
+	if errors.Is(err, serviceErrors.ErrUserNotFound) {
+		log.Error("User not found", "error", err, "user_id", req.UserID)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("user not found"))
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrTeamNotFound) {
+		log.Error("Team not found", "error", err, "team_name", req.TeamName)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("team not found"))
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrTeamArchived) {
+		log.Error("Team is archived", "error", err, "team_name", req.TeamName)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.TEAM_ARCHIVED())
+		return
+	}
+	if err != nil {
+		log.Error("Failed to move user to team", "error", err, "user_id", req.UserID, "team_name", req.TeamName)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to move user to team"))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, membershipChangeResponse(change))
+}
+
+// POST /users/absence
+func (h *UserHandler) AddAbsence(w http.ResponseWriter, r *http.Request) {
+	const op = "UserHandlers.AddAbsence"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req struct {
+		StartsAt *time.Time `json:"starts_at" validate:"required"`
+		EndsAt   *time.Time `json:"ends_at" validate:"required"`
+		UserID   string     `json:"user_id" validate:"required"`
+	}
+
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Error("Failed to decode request body", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "wrong request format"))
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		log.Error("Request validation failed", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("VALIDATION_ERROR", "wrong request format"))
+		return
+	}
+
+	absence, err := h.service.AddAbsence(r.Context(), &models.Absence{
+		UserID:   req.UserID,
+		StartsAt: *req.StartsAt,
+		EndsAt:   *req.EndsAt,
+	})
+	if errors.Is(err, serviceErrors.ErrInvalidAbsence) {
+		log.Error("Invalid absence period", "error", err, "user_id", req.UserID)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.INVALID_ABSENCE())
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrUserNotFound) {
+		log.Error("User not found", "error", err, "user_id", req.UserID)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("user not found"))
+		return
+	}
+	if err != nil {
+		log.Error("Failed to add absence", "error", err, "user_id", req.UserID)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to add absence"))
+		return
+	}
+
+	type AbsenceItem struct {
+		StartsAt time.Time `json:"starts_at"`
+		EndsAt   time.Time `json:"ends_at"`
+		UserID   string    `json:"user_id" validate:"required"`
+	}
+
+	res := struct {
+		Absence AbsenceItem `json:"absence" validate:"required"`
+	}{
+		Absence: AbsenceItem{
+			StartsAt: absence.StartsAt,
+			EndsAt:   absence.EndsAt,
+			UserID:   absence.UserID,
+		},
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, res)
+}
+
+// POST /users/linkAccount
+func (h *UserHandler) LinkAccount(w http.ResponseWriter, r *http.Request) {
+	const op = "UserHandlers.LinkAccount"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req struct {
+		UserID   string `json:"user_id" validate:"required"`
+		Provider string `json:"provider" validate:"required,oneof=github gitlab"`
+		Login    string `json:"login" validate:"required,max=100"`
+	}
+
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Error("Failed to decode request body", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "wrong request format"))
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		log.Error("Request validation failed", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("VALIDATION_ERROR", "wrong request format"))
+		return
+	}
+
+	account, err := h.service.LinkAccount(r.Context(), &models.UserAccount{
+		Provider: req.Provider,
+		Login:    req.Login,
+		UserID:   req.UserID,
+	})
+	if errors.Is(err, serviceErrors.ErrUserNotFound) {
+		log.Error("User not found", "error", err, "user_id", req.UserID)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("user not found"))
+		return
+	}
+	if errors.Is(err, serviceErrors.ErrLoginTaken) {
+		log.Error("Login is linked to another user", "error", err, "user_id", req.UserID, "login", req.Login)
+		render.Status(r, http.StatusConflict)
+		render.JSON(w, r, response.LOGIN_TAKEN())
+		return
+	}
+	if err != nil {
+		log.Error("Failed to link account", "error", err, "user_id", req.UserID)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to link account"))
+		return
+	}
+
+	type AccountItem struct {
+		UserID   string `json:"user_id"`
+		Provider string `json:"provider"`
+		Login    string `json:"login"`
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, struct {
+		Account AccountItem `json:"account"`
+	}{
+		Account: AccountItem{
+			UserID:   account.UserID,
+			Provider: account.Provider,
+			Login:    account.Login,
+		},
+	})
+}
+
+// POST /users/setEmail
+func (h *UserHandler) SetEmail(w http.ResponseWriter, r *http.Request) {
+	const op = "UserHandlers.SetEmail"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req struct {
+		UserID string `json:"user_id" validate:"required"`
+		Email  string `json:"email" validate:"omitempty,max=255,email"`
+		Mode   string `json:"mode" validate:"omitempty,oneof=immediate digest"`
+	}
+
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Error("Failed to decode request body", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "wrong request format"))
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		log.Error("Request validation failed", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("VALIDATION_ERROR", "wrong request format"))
+		return
+	}
+
+	settings, err := h.service.SetEmail(r.Context(), &models.EmailSettings{
+		UserID: req.UserID,
+		Email:  req.Email,
+		Mode:   req.Mode,
+	})
+	if errors.Is(err, serviceErrors.ErrUserNotFound) {
+		log.Error("User not found", "error", err, "user_id", req.UserID)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("user not found"))
+		return
+	}
+	if err != nil {
+		log.Error("Failed to set email", "error", err, "user_id", req.UserID)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to set email"))
+		return
+	}
+
+	type EmailItem struct {
+		UserID string `json:"user_id"`
+		Email  string `json:"email"`
+		Mode   string `json:"mode"`
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, struct {
+		Email EmailItem `json:"email"`
+	}{
+		Email: EmailItem{
+			UserID: settings.UserID,
+			Email:  settings.Email,
+			Mode:   settings.Mode,
+		},
+	})
+}
+
 // GET /users/getReview
 func (h *UserHandler) GetReview(w http.ResponseWriter, r *http.Request) {
 	const op = "UserHandlers.GetReview"
@@ -129,10 +473,13 @@ func (h *UserHandler) GetReview(w http.ResponseWriter, r *http.Request) {
 	}
 
 	type PRItem struct {
-		ID       string `json:"pull_request_id" validate:"required"`
-		PRName   string `json:"pull_request_name" validate:"required"`
-		AuthorID string `json:"author_id" validate:"required"`
-		Status   string `json:"status" validate:"required"`
+		AssignedAt  *time.Time `json:"assigned_at,omitempty"`
+		ReviewedAt  *time.Time `json:"reviewed_at,omitempty"`
+		ID          string     `json:"pull_request_id" validate:"required"`
+		PRName      string     `json:"pull_request_name" validate:"required"`
+		AuthorID    string     `json:"author_id" validate:"required"`
+		Status      string     `json:"status" validate:"required"`
+		ReviewState string     `json:"review_state" validate:"required"`
 	}
 
 	var res struct {
@@ -143,10 +490,13 @@ func (h *UserHandler) GetReview(w http.ResponseWriter, r *http.Request) {
 	res.UserID = userID
 	for _, pr := range prs {
 		prRes := PRItem{
-			ID:       pr.ID,
-			PRName:   pr.Name,
-			AuthorID: pr.AuthorID,
-			Status:   pr.Status,
+			ID:          pr.ID,
+			PRName:      pr.Name,
+			AuthorID:    pr.AuthorID,
+			Status:      string(pr.Status),
+			ReviewState: string(pr.State),
+			AssignedAt:  pr.AssignedAt,
+			ReviewedAt:  pr.ReviewedAt,
 		}
 		res.PullRequests = append(res.PullRequests, prRes)
 	}
@@ -154,3 +504,50 @@ func (h *UserHandler) GetReview(w http.ResponseWriter, r *http.Request) {
 	render.Status(r, http.StatusOK)
 	render.JSON(w, r, res)
 }
+
+func membershipChangeResponse(change *models.MembershipChange) any {
+	type UserItem struct {
+		UserID   string `json:"user_id" validate:"required"`
+		Username string `json:"username" validate:"required"`
+		TeamName string `json:"team_name,omitempty"`
+		IsActive bool   `json:"is_active"`
+	}
+
+	type ReassignedItem struct {
+		Fallback      *FallbackItem `json:"fallback,omitempty"`
+		PullRequestID string        `json:"pull_request_id" validate:"required"`
+		OldUserID     string        `json:"old_user_id" validate:"required"`
+		NewUserID     string        `json:"replaced_by" validate:"required"`
+	}
+
+	res := struct {
+		User        UserItem         `json:"user" validate:"required"`
+		Reassigned  []ReassignedItem `json:"reassigned"`
+		NoCandidate []string         `json:"no_candidate"`
+	}{
+		User: UserItem{
+			UserID:   change.User.UserID,
+			Username: change.User.Username,
+			TeamName: change.User.TeamName,
+			IsActive: change.User.IsActive,
+		},
+		Reassigned:  make([]ReassignedItem, 0, len(change.Reassigned)),
+		NoCandidate: make([]string, 0, len(change.NoCandidate)),
+	}
+
+	for _, reassignment := range change.Reassigned {
+		item := ReassignedItem{
+			PullRequestID: reassignment.PullRequestID,
+			OldUserID:     reassignment.OldUserID,
+			NewUserID:     reassignment.NewUserID,
+		}
+		if reassignment.Fallback != nil {
+			fallback := fallbackItem(reassignment.Fallback)
+			item.Fallback = &fallback
+		}
+		res.Reassigned = append(res.Reassigned, item)
+	}
+	res.NoCandidate = append(res.NoCandidate, change.NoCandidate...)
+
+	return res
+}
@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	serviceErrors "pr-review/internal/errors"
+	"pr-review/internal/models"
+	"pr-review/internal/server/response"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+type WebhookService interface {
+	Create(ctx context.Context, webhook *models.Webhook) (*models.Webhook, error)
+	List(ctx context.Context) ([]models.Webhook, error)
+	Delete(ctx context.Context, id string) error
+	Deliveries(ctx context.Context, webhookID string) ([]models.WebhookDelivery, error)
+}
+
+type WebhookHandler struct {
+	logger  *slog.Logger
+	service WebhookService
+}
+
+func NewWebhookHandler(logger *slog.Logger, s WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		logger:  logger,
+		service: s,
+	}
+}
+
+// POST /webhooks
+func (h *WebhookHandler) Create(w http.ResponseWriter, r *http.Request) {
+	const op = "WebhookHandlers.Create"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req struct {
+		URL    string   `json:"url" validate:"required"`
+		Secret string   `json:"secret"`
+		Events []string `json:"events" validate:"dive,required"`
+	}
+
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Error("Failed to decode request body", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "wrong request format"))
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		log.Error("Request validation failed", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("VALIDATION_ERROR", "wrong request format"))
+		return
+	}
+
+	webhook, err := h.service.Create(r.Context(), &models.Webhook{URL: req.URL, Secret: req.Secret, Events: req.Events})
+	if errors.Is(err, serviceErrors.ErrInvalidWebhook) {
+		log.Error("Invalid webhook", "error", err, "url", req.URL)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.INVALID_WEBHOOK(errors.Unwrap(err).Error()))
+		return
+	}
+	if err != nil {
+		log.Error("Failed to create webhook", "error", err, "url", req.URL)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to create webhook"))
+		return
+	}
+
+	// секрет отдаётся только при создании
+	item := webhookItem(webhook)
+	item.Secret = webhook.Secret
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, struct {
+		Webhook WebhookItem `json:"webhook"`
+	}{
+		Webhook: item,
+	})
+}
+
+// GET /webhooks
+func (h *WebhookHandler) List(w http.ResponseWriter, r *http.Request) {
+	const op = "WebhookHandlers.List"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	webhooks, err := h.service.List(r.Context())
+	if err != nil {
+		log.Error("Failed to get webhooks", "error", err)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to get webhooks"))
+		return
+	}
+
+	items := make([]WebhookItem, 0, len(webhooks))
+	for i := range webhooks {
+		items = append(items, webhookItem(&webhooks[i]))
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, struct {
+		Webhooks []WebhookItem `json:"webhooks"`
+	}{
+		Webhooks: items,
+	})
+}
+
+// POST /webhooks/delete
+func (h *WebhookHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	const op = "WebhookHandlers.Delete"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req struct {
+		ID string `json:"webhook_id" validate:"required"`
+	}
+
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Error("Failed to decode request body", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "wrong request format"))
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		log.Error("Request validation failed", "error", err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("VALIDATION_ERROR", "wrong request format"))
+		return
+	}
+
+	err := h.service.Delete(r.Context(), req.ID)
+	if errors.Is(err, serviceErrors.ErrWebhookNotFound) {
+		log.Error("Webhook not found", "error", err, "webhook_id", req.ID)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("webhook not found"))
+		return
+	}
+	if err != nil {
+		log.Error("Failed to delete webhook", "error", err, "webhook_id", req.ID)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to delete webhook"))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, struct {
+		ID string `json:"webhook_id"`
+	}{
+		ID: req.ID,
+	})
+}
+
+// GET /webhooks/deliveries
+func (h *WebhookHandler) Deliveries(w http.ResponseWriter, r *http.Request) {
+	const op = "WebhookHandlers.Deliveries"
+
+	log := h.logger.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	webhookID := r.URL.Query().Get("webhook_id")
+	if webhookID == "" {
+		log.Error("webhook_id query parameter is required")
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, response.ERROR("INVALID_REQUEST", "webhook_id query parameter is required"))
+		return
+	}
+
+	deliveries, err := h.service.Deliveries(r.Context(), webhookID)
+	if errors.Is(err, serviceErrors.ErrWebhookNotFound) {
+		log.Error("Webhook not found", "error", err, "webhook_id", webhookID)
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, response.NOT_FOUND("webhook not found"))
+		return
+	}
+	if err != nil {
+		log.Error("Failed to get webhook deliveries", "error", err, "webhook_id", webhookID)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, response.ERROR("INTERNAL_ERROR", "failed to get webhook deliveries"))
+		return
+	}
+
+	type DeliveryItem struct {
+		CreatedAt      time.Time       `json:"created_at"`
+		NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+		DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+		Event          string          `json:"event"`
+		Status         string          `json:"status"`
+		LastError      string          `json:"last_error,omitempty"`
+		Payload        json.RawMessage `json:"payload"`
+		ID             int64           `json:"delivery_id"`
+		Attempts       int             `json:"attempts"`
+		ResponseStatus int             `json:"response_status,omitempty"`
+	}
+
+	items := make([]DeliveryItem, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		item := DeliveryItem{
+			CreatedAt:      delivery.CreatedAt,
+			DeliveredAt:    delivery.DeliveredAt,
+			Event:          delivery.Event,
+			Status:         delivery.Status,
+			LastError:      delivery.LastError,
+			Payload:        delivery.Payload,
+			ID:             delivery.ID,
+			Attempts:       delivery.Attempts,
+			ResponseStatus: delivery.ResponseStatus,
+		}
+		// время следующей попытки имеет смысл только у ожидающих доставок
+		if delivery.Status == models.DeliveryPending {
+			item.NextAttemptAt = &delivery.NextAttemptAt
+		}
+		items = append(items, item)
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, struct {
+		WebhookID  string         `json:"webhook_id"`
+		Deliveries []DeliveryItem `json:"deliveries"`
+	}{
+		WebhookID:  webhookID,
+		Deliveries: items,
+	})
+}
+
+// WebhookItem: Secret заполняется только при создании.
+type WebhookItem struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        string    `json:"webhook_id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"`
+	Events    []string  `json:"events"`
+}
+
+func webhookItem(webhook *models.Webhook) WebhookItem {
+	events := webhook.Events
+	if events == nil {
+		events = []string{}
+	}
+
+	return WebhookItem{
+		CreatedAt: webhook.CreatedAt,
+		ID:        webhook.ID,
+		URL:       webhook.URL,
+		Events:    events,
+	}
+}
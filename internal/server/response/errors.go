@@ -55,6 +55,30 @@ func PR_MERGED() *ErrorResponse {
 	}
 }
 
+func PR_CLOSED() *ErrorResponse {
+	return &ErrorResponse{
+		Error: struct {
+			Code    string `json:"code"`
+			Message string `json:"message,omitempty"`
+		}{
+			Code:    "PR_CLOSED",
+			Message: "pull request is closed",
+		},
+	}
+}
+
+func PR_DRAFT() *ErrorResponse {
+	return &ErrorResponse{
+		Error: struct {
+			Code    string `json:"code"`
+			Message string `json:"message,omitempty"`
+		}{
+			Code:    "PR_DRAFT",
+			Message: "pull request is a draft",
+		},
+	}
+}
+
 func NOT_ASSIGNED() *ErrorResponse {
 	return &ErrorResponse{
 		Error: struct {
@@ -79,6 +103,186 @@ func NO_CANDIDATE() *ErrorResponse {
 	}
 }
 
+func INVALID_REVIEWERS_COUNT() *ErrorResponse {
+	return &ErrorResponse{
+		Error: struct {
+			Code    string `json:"code"`
+			Message string `json:"message,omitempty"`
+		}{
+			Code:    "INVALID_REVIEWERS_COUNT",
+			Message: "reviewers count violates team policy",
+		},
+	}
+}
+
+func INVALID_CODEOWNERS() *ErrorResponse {
+	return &ErrorResponse{
+		Error: struct {
+			Code    string `json:"code"`
+			Message string `json:"message,omitempty"`
+		}{
+			Code:    "INVALID_CODEOWNERS",
+			Message: "invalid code owners file",
+		},
+	}
+}
+
+func NO_OWNER_CANDIDATE() *ErrorResponse {
+	return &ErrorResponse{
+		Error: struct {
+			Code    string `json:"code"`
+			Message string `json:"message,omitempty"`
+		}{
+			Code:    "NO_OWNER_CANDIDATE",
+			Message: "no active code owner available for review",
+		},
+	}
+}
+
+func TEAM_ARCHIVED() *ErrorResponse {
+	return &ErrorResponse{
+		Error: struct {
+			Code    string `json:"code"`
+			Message string `json:"message,omitempty"`
+		}{
+			Code:    "TEAM_ARCHIVED",
+			Message: "team is archived",
+		},
+	}
+}
+
+func TEAM_HAS_OPEN_PRS() *ErrorResponse {
+	return &ErrorResponse{
+		Error: struct {
+			Code    string `json:"code"`
+			Message string `json:"message,omitempty"`
+		}{
+			Code:    "TEAM_HAS_OPEN_PRS",
+			Message: "team has open pull requests, use force to archive it",
+		},
+	}
+}
+
+func INVALID_ABSENCE() *ErrorResponse {
+	return &ErrorResponse{
+		Error: struct {
+			Code    string `json:"code"`
+			Message string `json:"message,omitempty"`
+		}{
+			Code:    "INVALID_ABSENCE",
+			Message: "absence must end after it starts and not in the past",
+		},
+	}
+}
+
+func REVIEWERS_AT_CAPACITY() *ErrorResponse {
+	return &ErrorResponse{
+		Error: struct {
+			Code    string `json:"code"`
+			Message string `json:"message,omitempty"`
+		}{
+			Code:    "REVIEWERS_AT_CAPACITY",
+			Message: "reviewer candidates are at their open reviews limit",
+		},
+	}
+}
+
+func REVIEWER_UNAVAILABLE() *ErrorResponse {
+	return &ErrorResponse{
+		Error: struct {
+			Code    string `json:"code"`
+			Message string `json:"message,omitempty"`
+		}{
+			Code:    "REVIEWER_UNAVAILABLE",
+			Message: "selected reviewers kept changing concurrently, retry the request",
+		},
+	}
+}
+
+func POOL_EXISTS() *ErrorResponse {
+	return &ErrorResponse{
+		Error: struct {
+			Code    string `json:"code"`
+			Message string `json:"message,omitempty"`
+		}{
+			Code:    "POOL_EXISTS",
+			Message: "pool_name already exists",
+		},
+	}
+}
+
+func INVALID_FALLBACK(message string) *ErrorResponse {
+	return &ErrorResponse{
+		Error: struct {
+			Code    string `json:"code"`
+			Message string `json:"message,omitempty"`
+		}{
+			Code:    "INVALID_FALLBACK",
+			Message: message,
+		},
+	}
+}
+
+func INVALID_WEBHOOK(message string) *ErrorResponse {
+	return &ErrorResponse{
+		Error: struct {
+			Code    string `json:"code"`
+			Message string `json:"message,omitempty"`
+		}{
+			Code:    "INVALID_WEBHOOK",
+			Message: message,
+		},
+	}
+}
+
+func INVALID_CHAT(message string) *ErrorResponse {
+	return &ErrorResponse{
+		Error: struct {
+			Code    string `json:"code"`
+			Message string `json:"message,omitempty"`
+		}{
+			Code:    "INVALID_CHAT",
+			Message: message,
+		},
+	}
+}
+
+func MERGE_BLOCKED(message string) *ErrorResponse {
+	return &ErrorResponse{
+		Error: struct {
+			Code    string `json:"code"`
+			Message string `json:"message,omitempty"`
+		}{
+			Code:    "MERGE_BLOCKED",
+			Message: message,
+		},
+	}
+}
+
+func LOGIN_TAKEN() *ErrorResponse {
+	return &ErrorResponse{
+		Error: struct {
+			Code    string `json:"code"`
+			Message string `json:"message,omitempty"`
+		}{
+			Code:    "LOGIN_TAKEN",
+			Message: "login is already linked to another user",
+		},
+	}
+}
+
+func INVALID_SIGNATURE() *ErrorResponse {
+	return &ErrorResponse{
+		Error: struct {
+			Code    string `json:"code"`
+			Message string `json:"message,omitempty"`
+		}{
+			Code:    "INVALID_SIGNATURE",
+			Message: "webhook signature or token does not match the configured secret",
+		},
+	}
+}
+
 func NOT_FOUND(message ...string) *ErrorResponse {
 	if len(message) > 0 {
 		return &ErrorResponse{
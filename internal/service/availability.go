@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+type AbsenceRepository interface {
+	GetUsersWithStartedAbsences(ctx context.Context, at time.Time) ([]string, error)
+	MarkAbsencesProcessed(ctx context.Context, userID string, at time.Time) error
+	GetPRsByReviewer(ctx context.Context, userID string) ([]*models.ReviewAssignment, error)
+}
+
+type AbsenceProcessor struct {
+	logger     *slog.Logger
+	repo       AbsenceRepository
+	reassigner reviewReassigner
+}
+
+func NewAbsenceProcessor(
+	logger *slog.Logger,
+	repo AbsenceRepository,
+	reassigner reviewReassigner,
+) *AbsenceProcessor {
+	return &AbsenceProcessor{
+		logger:     logger,
+		repo:       repo,
+		reassigner: reassigner,
+	}
+}
+
+// ProcessStarted отмечает пользователя обработанным, только когда переданы все его ревью,
+// иначе он обрабатывается снова в следующий запуск.
+func (p *AbsenceProcessor) ProcessStarted(ctx context.Context, now time.Time) error {
+	const op = "AbsenceProcessor.ProcessStarted"
+
+	userIDs, err := p.repo.GetUsersWithStartedAbsences(ctx, now)
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	for _, userID := range userIDs {
+		report, err := reassignReviews(ctx, p.repo, p.reassigner, userID, pendingReviews, models.ReasonAbsent)
+		if err != nil {
+			p.logger.Error("Failed to reassign reviews of absent user", "op", op, "error", err, "userID", userID)
+			continue
+		}
+		if len(report.NoCandidate) > 0 {
+			p.logger.Warn("Some reviews of absent user were not reassigned, will retry",
+				"op", op,
+				"userID", userID,
+				"reassigned", len(report.Reassigned),
+				"noCandidate", report.NoCandidate,
+			)
+			continue
+		}
+
+		err = p.repo.MarkAbsencesProcessed(ctx, userID, now)
+		if err != nil {
+			p.logger.Error("Failed to mark absences processed", "op", op, "error", err, "userID", userID)
+			continue
+		}
+
+		p.logger.Info("Reassigned reviews of absent user",
+			"op", op,
+			"userID", userID,
+			"reassigned", len(report.Reassigned),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"pr-review/internal/models"
+	"pr-review/internal/service"
+)
+
+func TestAbsenceRetriesUnassigned(t *testing.T) {
+	env := newEnv(t)
+	env.seedTeam(t, "backend", "u1", "u2", "u3", "u4")
+	processor := service.NewAbsenceProcessor(env.logger, env.repo, env.prs)
+
+	_, err := env.prs.CreatePR(t.Context(), &models.PullRequestShort{ID: "pr-1", Name: "PR", AuthorID: "u1"}, models.CreatePROptions{})
+	noError(t, err)
+
+	// единственный свободный кандидат упёрся в лимит
+	full := 0
+	noError(t, env.repo.SetUserMaxOpenReviews(t.Context(), "u4", &full))
+
+	now := time.Now().UTC()
+	noError(t, env.repo.AddAbsence(t.Context(), &models.Absence{StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour), UserID: "u2"}))
+	noError(t, processor.ProcessStarted(t.Context(), now))
+	equalStrings(t, "reviewers kept", env.getPR(t, "pr-1").AssignedReviewers, []string{"u2", "u3"})
+
+	// ревью не передано, поэтому отсутствие обрабатывается снова
+	started, err := env.repo.GetUsersWithStartedAbsences(t.Context(), now)
+	noError(t, err)
+	equalStrings(t, "not processed", started, []string{"u2"})
+
+	noError(t, env.repo.SetUserMaxOpenReviews(t.Context(), "u4", nil))
+	noError(t, processor.ProcessStarted(t.Context(), now.Add(time.Minute)))
+	equalStrings(t, "reassigned", env.getPR(t, "pr-1").AssignedReviewers, []string{"u3", "u4"})
+
+	started, err = env.repo.GetUsersWithStartedAbsences(t.Context(), now.Add(time.Minute))
+	noError(t, err)
+	equal(t, "processed", len(started), 0)
+}
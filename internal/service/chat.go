@@ -0,0 +1,301 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	"pr-review/internal/config"
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+type ChatRepository interface {
+	GetTeamChat(ctx context.Context, teamName string) (*models.TeamChat, error)
+	GetUserByID(ctx context.Context, id string) (*models.User, error)
+	EnqueueChatMessage(ctx context.Context, message *models.ChatMessage) error
+	ClaimDueChatMessages(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.ChatMessage, error)
+	SaveChatAttempt(ctx context.Context, message *models.ChatMessage) error
+}
+
+// ChatMessageData - данные шаблона сообщения в чат. Пользователь без username указывается по id.
+type ChatMessageData struct {
+	Event  string
+	Author string
+	// Reviewer для pr.merged пустой
+	Reviewer string
+	// OldReviewer и Reason заданы только при замене ревьювера
+	OldReviewer string
+	Reason      string
+	PullRequest EventPR
+}
+
+// Slack выделяет жирным одной звёздочкой, Mattermost - двумя.
+var defaultChatTemplates = map[string]models.ChatTemplates{
+	models.ChatFormatSlack: {
+		ReviewerAssigned:   "{{.Reviewer}} was assigned to review *{{.PullRequest.Name}}* ({{.PullRequest.ID}}) by {{.Author}}",
+		ReviewerReassigned: "Review of *{{.PullRequest.Name}}* ({{.PullRequest.ID}}) passed from {{.OldReviewer}} to {{.Reviewer}} (reason: {{.Reason}})",
+		PRMerged:           "*{{.PullRequest.Name}}* ({{.PullRequest.ID}}) by {{.Author}} was merged",
+	},
+	models.ChatFormatMattermost: {
+		ReviewerAssigned:   "{{.Reviewer}} was assigned to review **{{.PullRequest.Name}}** ({{.PullRequest.ID}}) by {{.Author}}",
+		ReviewerReassigned: "Review of **{{.PullRequest.Name}}** ({{.PullRequest.ID}}) passed from {{.OldReviewer}} to {{.Reviewer}} (reason: {{.Reason}})",
+		PRMerged:           "**{{.PullRequest.Name}}** ({{.PullRequest.ID}}) by {{.Author}} was merged",
+	},
+}
+
+// slackEscaper экранирует символы, которые Slack считает разметкой ссылок и упоминаний.
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// ChatNotifier формирует сообщения по шаблонам команды при событии, а отправляет их из очереди.
+type ChatNotifier struct {
+	logger *slog.Logger
+	repo   ChatRepository
+	client *http.Client
+	worker *outboxWorker[models.ChatMessage]
+}
+
+func NewChatNotifier(logger *slog.Logger, repo ChatRepository, cfg *config.OutboxConfig) *ChatNotifier {
+	n := &ChatNotifier{
+		logger: logger,
+		repo:   repo,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}
+	n.worker = newOutboxWorker[models.ChatMessage](logger, n, "Chat message", cfg, cfg.Timeout)
+	return n
+}
+
+func (n *ChatNotifier) Publish(ctx context.Context, event string, data any) {
+	const op = "ChatNotifier.Publish"
+
+	var message ChatMessageData
+	switch data := data.(type) {
+	case ReviewerEventData:
+		if event != models.WebhookEventReviewerAssigned && event != models.WebhookEventReviewerReassigned {
+			return
+		}
+		message = ChatMessageData{PullRequest: data.PullRequest, Reviewer: data.ReviewerID, OldReviewer: data.OldReviewerID, Reason: data.Reason}
+	case PREventData:
+		if event != models.WebhookEventPRMerged {
+			return
+		}
+		message = ChatMessageData{PullRequest: data.PullRequest}
+	default:
+		return
+	}
+	message.Event = event
+
+	author, err := n.repo.GetUserByID(ctx, message.PullRequest.AuthorID)
+	if err != nil || author.TeamName == "" {
+		return
+	}
+	chat, err := n.repo.GetTeamChat(ctx, author.TeamName)
+	if errors.Is(err, errors.ErrChatNotFound) {
+		return
+	}
+	if err != nil {
+		n.logger.Error("Failed to get team chat", "op", op, "error", err, "teamName", author.TeamName)
+		return
+	}
+
+	message.Author = author.Username
+	message.Reviewer = n.username(ctx, message.Reviewer)
+	message.OldReviewer = n.username(ctx, message.OldReviewer)
+
+	payload, err := renderChatMessage(chat.Format, chatTemplate(chat, event), &message)
+	if err != nil {
+		n.logger.Error("Failed to render chat message", "op", op, "error", err, "teamName", chat.TeamName, "event", event)
+		return
+	}
+
+	now := time.Now().UTC()
+	err = n.repo.EnqueueChatMessage(ctx, &models.ChatMessage{
+		CreatedAt:     now,
+		NextAttemptAt: now,
+		TeamName:      chat.TeamName,
+		Event:         event,
+		URL:           chat.URL,
+		Status:        models.ChatPending,
+		Payload:       payload,
+	})
+	if err != nil {
+		n.logger.Error("Failed to enqueue chat message", "op", op, "error", err, "teamName", chat.TeamName, "event", event)
+	}
+}
+
+func (n *ChatNotifier) SendDue(ctx context.Context, now time.Time) error {
+	const op = "ChatNotifier.SendDue"
+
+	if err := n.worker.runDue(ctx, now); err != nil {
+		return errors.WrapError(op, err)
+	}
+	return nil
+}
+
+// private methods
+
+func (n *ChatNotifier) claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.ChatMessage, error) {
+	return n.repo.ClaimDueChatMessages(ctx, now, lease, limit)
+}
+
+func (n *ChatNotifier) save(ctx context.Context, message *models.ChatMessage) error {
+	return n.repo.SaveChatAttempt(ctx, message)
+}
+
+func (n *ChatNotifier) attempts(message *models.ChatMessage) int {
+	return message.Attempts
+}
+
+func (n *ChatNotifier) record(message *models.ChatMessage, result *outboxAttempt) {
+	message.Attempts = result.attempts
+	message.LastError = ""
+	if result.err != nil {
+		message.LastError = result.err.Error()
+	}
+
+	switch result.status {
+	case outboxDone:
+		message.Status = models.ChatSent
+		message.SentAt = &result.at
+	case outboxFailed:
+		message.Status = models.ChatFailed
+	default:
+		message.Status = models.ChatPending
+		message.NextAttemptAt = result.nextAttemptAt
+	}
+}
+
+func (n *ChatNotifier) logAttrs(message *models.ChatMessage) []any {
+	return []any{"messageID", message.ID, "teamName", message.TeamName}
+}
+
+func (n *ChatNotifier) send(ctx context.Context, message *models.ChatMessage) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, message.URL, bytes.NewReader(message.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			return
+		}
+	}()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	return fmt.Errorf("chat responded %s: %s", resp.Status, bytes.TrimSpace(body))
+}
+
+func (n *ChatNotifier) username(ctx context.Context, userID string) string {
+	if userID == "" {
+		return ""
+	}
+	user, err := n.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return userID
+	}
+	return user.Username
+}
+
+func validateTeamChat(chat *models.TeamChat) error {
+	target, err := url.Parse(chat.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http(s) URL", errors.ErrInvalidChat)
+	}
+	if _, ok := defaultChatTemplates[chat.Format]; !ok {
+		return fmt.Errorf("%w: unknown format %q", errors.ErrInvalidChat, chat.Format)
+	}
+
+	sample := &ChatMessageData{
+		Author:      "author",
+		Reviewer:    "reviewer",
+		OldReviewer: "old-reviewer",
+		Reason:      models.ReasonManual,
+		PullRequest: EventPR{ID: "pr-1", Name: "Pull request", AuthorID: "u1", Status: string(models.PRStatusOpen)},
+	}
+	for _, event := range []string{models.WebhookEventReviewerAssigned, models.WebhookEventReviewerReassigned, models.WebhookEventPRMerged} {
+		sample.Event = event
+		if _, err := renderChatMessage(chat.Format, chatTemplate(chat, event), sample); err != nil {
+			return fmt.Errorf("%w: %s template: %v", errors.ErrInvalidChat, event, err)
+		}
+	}
+
+	return nil
+}
+
+func chatTemplate(chat *models.TeamChat, event string) string {
+	templates := []models.ChatTemplates{chat.Templates, defaultChatTemplates[chat.Format]}
+	for _, t := range templates {
+		var text string
+		switch event {
+		case models.WebhookEventReviewerAssigned:
+			text = t.ReviewerAssigned
+		case models.WebhookEventReviewerReassigned:
+			text = t.ReviewerReassigned
+		case models.WebhookEventPRMerged:
+			text = t.PRMerged
+		}
+		if text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
+// renderChatMessage экранирует данные для Slack, чтобы название PR не стало разметкой.
+func renderChatMessage(format, text string, data *ChatMessageData) ([]byte, error) {
+	tmpl, err := template.New("message").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	escaped := *data
+	if format == models.ChatFormatSlack {
+		escaped.Author = slackEscaper.Replace(data.Author)
+		escaped.Reviewer = slackEscaper.Replace(data.Reviewer)
+		escaped.OldReviewer = slackEscaper.Replace(data.OldReviewer)
+		escaped.PullRequest.ID = slackEscaper.Replace(data.PullRequest.ID)
+		escaped.PullRequest.Name = slackEscaper.Replace(data.PullRequest.Name)
+	}
+
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, &escaped); err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case models.ChatFormatSlack:
+		return json.Marshal(struct {
+			Text string `json:"text"`
+		}{
+			Text: buf.String(),
+		})
+	case models.ChatFormatMattermost:
+		// props не показываются в канале, но доступны интеграциям Mattermost
+		return json.Marshal(struct {
+			Props map[string]string `json:"props"`
+			Text  string            `json:"text"`
+		}{
+			Props: map[string]string{"event": data.Event, "pull_request_id": data.PullRequest.ID},
+			Text:  buf.String(),
+		})
+	default:
+		return nil, fmt.Errorf("unknown chat format %q", format)
+	}
+}
@@ -0,0 +1,122 @@
+package service_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"pr-review/internal/config"
+	"pr-review/internal/models"
+	"pr-review/internal/service"
+)
+
+var chatConfig = config.OutboxConfig{
+	Timeout:     time.Second,
+	BackoffBase: time.Minute,
+	BackoffMax:  time.Hour,
+	MaxAttempts: 3,
+	BatchSize:   10,
+}
+
+// chatPayload - тело запроса к incoming webhook Slack или Mattermost.
+type chatPayload struct {
+	Props map[string]string `json:"props"`
+	Text  string            `json:"text"`
+}
+
+// newChatEnv направляет события сервиса в ChatNotifier и подключает к команде
+// чат-заглушку в формате format.
+func newChatEnv(t *testing.T, rcv *receiver, format string, templates models.ChatTemplates) (*testEnv, *service.ChatNotifier) {
+	t.Helper()
+
+	env := newEnv(t)
+	env.seedTeam(t, "backend", "u1", "u2", "u3")
+	notifier := service.NewChatNotifier(env.logger, env.repo, &chatConfig)
+	env.events.forward = notifier
+
+	_, err := env.teams.SetChat(t.Context(), &models.TeamChat{
+		TeamName:  "backend",
+		URL:       rcv.server.URL,
+		Format:    format,
+		Templates: templates,
+	})
+	noError(t, err)
+	return env, notifier
+}
+
+// chatPayloads разбирает тела запросов, пришедших в чат.
+func chatPayloads(t *testing.T, rcv *receiver) []chatPayload {
+	t.Helper()
+
+	var payloads []chatPayload
+	for _, got := range rcv.deliveries() {
+		equal(t, "content type", got.header.Get("Content-Type"), "application/json")
+
+		var payload chatPayload
+		noError(t, json.Unmarshal(got.body, &payload))
+		payloads = append(payloads, payload)
+	}
+	return payloads
+}
+
+func TestChatSlack(t *testing.T) {
+	rcv := newReceiver(t)
+	env, notifier := newChatEnv(t, rcv, models.ChatFormatSlack, models.ChatTemplates{})
+
+	// разметка Slack в названии PR экранируется
+	_, err := env.prs.CreatePR(t.Context(), &models.PullRequestShort{ID: "pr-1", Name: "Drop <!channel> & co", AuthorID: "u1"}, models.CreatePROptions{})
+	noError(t, err)
+	noError(t, notifier.SendDue(t.Context(), time.Now().UTC()))
+
+	payloads := chatPayloads(t, rcv)
+	equal(t, "messages", len(payloads), 2)
+	equal(t, "assigned", payloads[0].Text, "u2-name was assigned to review *Drop &lt;!channel&gt; &amp; co* (pr-1) by u1-name")
+	equal(t, "second assigned", payloads[1].Text, "u3-name was assigned to review *Drop &lt;!channel&gt; &amp; co* (pr-1) by u1-name")
+	equal(t, "slack props", payloads[0].Props == nil, true)
+
+	_, err = env.prs.RecordMerge(t.Context(), "pr-1")
+	noError(t, err)
+	noError(t, notifier.SendDue(t.Context(), time.Now().UTC()))
+
+	payloads = chatPayloads(t, rcv)
+	equal(t, "messages after merge", len(payloads), 3)
+	equal(t, "merged", payloads[2].Text, "*Drop &lt;!channel&gt; &amp; co* (pr-1) by u1-name was merged")
+}
+
+func TestChatMattermostRetry(t *testing.T) {
+	rcv := newReceiver(t, http.StatusBadGateway)
+	env, notifier := newChatEnv(t, rcv, models.ChatFormatMattermost, models.ChatTemplates{
+		ReviewerAssigned: "{{.Reviewer}} reviews {{.PullRequest.Name}}",
+	})
+
+	_, err := env.prs.CreatePR(t.Context(), &models.PullRequestShort{ID: "pr-1", Name: "Add <cache>", AuthorID: "u1"}, models.CreatePROptions{})
+	noError(t, err)
+
+	// первое сообщение не доставлено, второе не ждёт его
+	start := time.Now().UTC()
+	noError(t, notifier.SendDue(t.Context(), start))
+	payloads := chatPayloads(t, rcv)
+	equal(t, "attempts", len(payloads), 2)
+	equal(t, "delivered", payloads[1].Text, "u3-name reviews Add <cache>")
+	equal(t, "event", payloads[1].Props["event"], models.WebhookEventReviewerAssigned)
+	equal(t, "pr", payloads[1].Props["pull_request_id"], "pr-1")
+
+	// до конца паузы повтора нет
+	noError(t, notifier.SendDue(t.Context(), start.Add(chatConfig.BackoffBase/2)))
+	equal(t, "before backoff", len(rcv.deliveries()), 2)
+
+	noError(t, notifier.SendDue(t.Context(), start.Add(chatConfig.BackoffBase+time.Minute)))
+	payloads = chatPayloads(t, rcv)
+	equal(t, "retried", len(payloads), 3)
+	equal(t, "retried text", payloads[2].Text, payloads[0].Text)
+	equal(t, "retried assigned", payloads[2].Text, "u2-name reviews Add <cache>")
+
+	// шаблон merge не задан, используется стандартный Mattermost
+	_, err = env.prs.RecordMerge(t.Context(), "pr-1")
+	noError(t, err)
+	noError(t, notifier.SendDue(t.Context(), time.Now().UTC()))
+	payloads = chatPayloads(t, rcv)
+	equal(t, "merged", payloads[3].Text, "**Add <cache>** (pr-1) by u1-name was merged")
+	equal(t, "merged event", payloads[3].Props["event"], models.WebhookEventPRMerged)
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"pr-review/internal/codeowners"
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+// pickReviewers выбирает сначала владельцев изменённых файлов, затем команду и её запасные источники.
+func (s *prService) pickReviewers(ctx context.Context, author *models.User, changedFiles, assigned []string, count int) (*reviewerPick, error) {
+	const op = "prService.pickReviewers"
+
+	exclude := append([]string{author.UserID}, assigned...)
+	pick := &reviewerPick{}
+
+	owners, mode, err := s.resolveOwners(ctx, author.TeamName, changedFiles)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	if len(owners) > 0 && count > 0 {
+		candidates, err := s.repo.GetReviewerCandidatesByIDs(ctx, owners)
+		if err != nil {
+			return nil, errors.WrapError(op, err)
+		}
+		candidates = excludeCandidates(candidates, exclude)
+
+		pick.selected, pick.atCapacity = s.selectors.SelectOwners(author.TeamName, candidates, count)
+		if len(pick.selected) == 0 && mode == models.CodeOwnersRequire {
+			return nil, errors.WrapError(op, errors.ErrNoOwnerCandidate)
+		}
+	}
+
+	if len(pick.selected) < count {
+		rest, err := s.selectReviewers(ctx, author.TeamName, append(exclude, pick.selected...), count-len(pick.selected))
+		if err != nil {
+			return nil, errors.WrapError(op, err)
+		}
+		pick.selected = append(pick.selected, rest.selected...)
+		pick.atCapacity = uniqueStrings(append(pick.atCapacity, rest.atCapacity...))
+		pick.fallback = rest.fallback
+	}
+
+	if err := s.checkCapacity(pick, count, false); err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	return pick, nil
+}
+
+// resolveOwners раскрывает владельца вида "org/team" в участников команды team.
+func (s *prService) resolveOwners(ctx context.Context, teamName string, changedFiles []string) ([]string, string, error) {
+	const op = "prService.resolveOwners"
+
+	if len(changedFiles) == 0 {
+		return nil, "", nil
+	}
+
+	codeOwners, err := s.repo.GetTeamCodeOwners(ctx, teamName)
+	if errors.Is(err, errors.ErrCodeOwnersNotFound) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", errors.WrapError(op, err)
+	}
+
+	ruleset, err := codeowners.Parse(codeOwners.Content)
+	if err != nil {
+		return nil, "", errors.WrapError(op, err)
+	}
+
+	var userIDs []string
+	for _, owner := range ruleset.OwnersOf(changedFiles) {
+		i := strings.LastIndex(owner, "/")
+		if i < 0 {
+			userIDs = append(userIDs, owner)
+			continue
+		}
+
+		ownerTeam, err := s.repo.GetTeamByName(ctx, owner[i+1:])
+		if errors.Is(err, errors.ErrTeamNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, "", errors.WrapError(op, err)
+		}
+		for _, member := range ownerTeam.Members {
+			userIDs = append(userIDs, member.UserID)
+		}
+	}
+
+	return uniqueStrings(userIDs), codeOwners.Mode, nil
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var unique []string
+	for _, v := range values {
+		if v != "" && !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"pr-review/internal/config"
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+const syncTasksLimit = 100
+
+type SyncRepository interface {
+	EnqueueSyncTasks(ctx context.Context, tasks []models.SyncTask) error
+	// ClaimDueSyncTasks выдаёт у каждого PR только самую старую задачу pending
+	ClaimDueSyncTasks(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.SyncTask, error)
+	SaveSyncAttempt(ctx context.Context, task *models.SyncTask) error
+	GetSyncTasks(ctx context.Context, status string, limit int) ([]models.SyncTask, error)
+	RetrySyncTask(ctx context.Context, id int64, at time.Time) error
+	GetUserLogin(ctx context.Context, provider, userID string) (string, error)
+}
+
+// CodeHostClient повторяет изменения PR на код-хостинге.
+type CodeHostClient interface {
+	RequestReviewers(ctx context.Context, pr models.CodeHostPR, logins []string) error
+	RemoveReviewers(ctx context.Context, pr models.CodeHostPR, logins []string) error
+	Comment(ctx context.Context, pr models.CodeHostPR, body string) error
+}
+
+// CodeHostSync переносит назначения ревьюверов на код-хостинг, откуда пришёл PR.
+// Задачи одного PR выполняются по очереди, иначе снятие и назначение при замене
+// могли бы примениться в обратном порядке.
+type CodeHostSync struct {
+	logger  *slog.Logger
+	repo    SyncRepository
+	clients map[string]CodeHostClient
+	worker  *outboxWorker[models.SyncTask]
+}
+
+func NewCodeHostSync(
+	logger *slog.Logger,
+	repo SyncRepository,
+	clients map[string]CodeHostClient,
+	cfg *config.OutboxConfig,
+) *CodeHostSync {
+	s := &CodeHostSync{
+		logger:  logger,
+		repo:    repo,
+		clients: clients,
+	}
+	// у GitLab до трёх запросов на задачу
+	s.worker = newOutboxWorker[models.SyncTask](logger, s, "Code host sync", cfg, 3*cfg.Timeout)
+	return s
+}
+
+func (s *CodeHostSync) Publish(ctx context.Context, event string, data any) {
+	const op = "CodeHostSync.Publish"
+
+	reviewer, ok := data.(ReviewerEventData)
+	if !ok {
+		return
+	}
+	provider := reviewer.PullRequest.Provider
+	if _, ok := codeHostPR(provider, reviewer.PullRequest.ID); !ok {
+		return
+	}
+	if _, ok := s.clients[provider]; !ok {
+		return
+	}
+
+	now := time.Now().UTC()
+	task := func(action, userID, body string) models.SyncTask {
+		return models.SyncTask{
+			CreatedAt:     now,
+			NextAttemptAt: now,
+			PullRequestID: reviewer.PullRequest.ID,
+			Provider:      provider,
+			Action:        action,
+			UserID:        userID,
+			Body:          body,
+			Status:        models.SyncPending,
+		}
+	}
+
+	var tasks []models.SyncTask
+	switch event {
+	case models.WebhookEventReviewerAssigned:
+		tasks = append(tasks, task(models.SyncRequestReviewer, reviewer.ReviewerID, ""))
+	case models.WebhookEventReviewerRemoved:
+		tasks = append(tasks, task(models.SyncRemoveReviewer, reviewer.ReviewerID, ""))
+	case models.WebhookEventReviewerReassigned:
+		body := fmt.Sprintf("Reviewer %s was replaced by %s (reason: %s).",
+			s.mention(ctx, provider, reviewer.OldReviewerID), s.mention(ctx, provider, reviewer.ReviewerID), reviewer.Reason)
+		tasks = append(tasks,
+			task(models.SyncRemoveReviewer, reviewer.OldReviewerID, ""),
+			task(models.SyncRequestReviewer, reviewer.ReviewerID, ""),
+			task(models.SyncComment, "", body),
+		)
+	default:
+		return
+	}
+
+	if err := s.repo.EnqueueSyncTasks(ctx, tasks); err != nil {
+		s.logger.Error("Failed to enqueue code host sync", "op", op, "error", err, "event", event, "prID", reviewer.PullRequest.ID)
+	}
+}
+
+func (s *CodeHostSync) SyncDue(ctx context.Context, now time.Time) error {
+	const op = "CodeHostSync.SyncDue"
+
+	if err := s.worker.runDue(ctx, now); err != nil {
+		return errors.WrapError(op, err)
+	}
+	return nil
+}
+
+func (s *CodeHostSync) Tasks(ctx context.Context, status string) ([]models.SyncTask, error) {
+	const op = "CodeHostSync.Tasks"
+
+	tasks, err := s.repo.GetSyncTasks(ctx, status, syncTasksLimit)
+	if err != nil {
+		s.logger.Error("Failed to get sync tasks", "op", op, "error", err, "status", status)
+		return nil, errors.WrapError(op, err)
+	}
+
+	return tasks, nil
+}
+
+func (s *CodeHostSync) Retry(ctx context.Context, id int64) error {
+	const op = "CodeHostSync.Retry"
+
+	if err := s.repo.RetrySyncTask(ctx, id, time.Now().UTC()); err != nil {
+		s.logger.Error("Failed to retry sync task", "op", op, "error", err, "taskID", id)
+		return errors.WrapError(op, err)
+	}
+
+	return nil
+}
+
+// private methods
+
+func (s *CodeHostSync) claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.SyncTask, error) {
+	return s.repo.ClaimDueSyncTasks(ctx, now, lease, limit)
+}
+
+func (s *CodeHostSync) save(ctx context.Context, task *models.SyncTask) error {
+	return s.repo.SaveSyncAttempt(ctx, task)
+}
+
+func (s *CodeHostSync) attempts(task *models.SyncTask) int {
+	return task.Attempts
+}
+
+func (s *CodeHostSync) record(task *models.SyncTask, result *outboxAttempt) {
+	task.Attempts = result.attempts
+	task.LastError = ""
+	if result.err != nil {
+		task.LastError = result.err.Error()
+	}
+
+	switch result.status {
+	case outboxDone:
+		task.Status = models.SyncDone
+		task.CompletedAt = &result.at
+	case outboxFailed:
+		task.Status = models.SyncFailed
+	default:
+		task.Status = models.SyncPending
+		task.NextAttemptAt = result.nextAttemptAt
+	}
+}
+
+func (s *CodeHostSync) logAttrs(task *models.SyncTask) []any {
+	return []any{"taskID", task.ID, "prID", task.PullRequestID, "action", task.Action}
+}
+
+// send сразу завершает задачу failed, если у ревьювера нет связанной учётной записи: повторы её не дадут.
+func (s *CodeHostSync) send(ctx context.Context, task *models.SyncTask) error {
+	client, ok := s.clients[task.Provider]
+	if !ok {
+		return fmt.Errorf("no %s client configured", task.Provider)
+	}
+	pr, ok := codeHostPR(task.Provider, task.PullRequestID)
+	if !ok {
+		return fmt.Errorf("pull request %q is not from a code host", task.PullRequestID)
+	}
+
+	switch task.Action {
+	case models.SyncRequestReviewer, models.SyncRemoveReviewer:
+		login, err := s.repo.GetUserLogin(ctx, task.Provider, task.UserID)
+		if errors.Is(err, errors.ErrAccountNotLinked) {
+			return finalError{fmt.Errorf("reviewer %s: %w", task.UserID, err)}
+		}
+		if err != nil {
+			return fmt.Errorf("reviewer %s: %w", task.UserID, err)
+		}
+		if task.Action == models.SyncRequestReviewer {
+			return client.RequestReviewers(ctx, pr, []string{login})
+		}
+		return client.RemoveReviewers(ctx, pr, []string{login})
+	case models.SyncComment:
+		return client.Comment(ctx, pr, task.Body)
+	default:
+		return fmt.Errorf("unknown sync action %q", task.Action)
+	}
+}
+
+func (s *CodeHostSync) mention(ctx context.Context, provider, userID string) string {
+	login, err := s.repo.GetUserLogin(ctx, provider, userID)
+	if err != nil {
+		return userID
+	}
+	return "@" + login
+}
@@ -0,0 +1,172 @@
+package service_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"pr-review/internal/codehost"
+	"pr-review/internal/config"
+	"pr-review/internal/models"
+	"pr-review/internal/service"
+)
+
+// newSyncEnv направляет события сервиса в CodeHostSync с клиентом-заглушкой GitHub.
+// Все участники backend связаны с GitHub.
+func newSyncEnv(t *testing.T) (*testEnv, *service.CodeHostSync, *codehost.Fake) {
+	t.Helper()
+
+	env := newEnv(t)
+	env.seedTeam(t, "backend", "u1", "u2", "u3", "u4")
+	env.link(t, models.ProviderGitHub, "alice-dev", "u1")
+	env.link(t, models.ProviderGitHub, "bob", "u2")
+	env.link(t, models.ProviderGitHub, "carol", "u3")
+	env.link(t, models.ProviderGitHub, "dave", "u4")
+
+	fake := codehost.NewFake()
+	sync := service.NewCodeHostSync(
+		env.logger,
+		env.repo,
+		map[string]service.CodeHostClient{models.ProviderGitHub: fake},
+		&config.OutboxConfig{
+			Timeout:     time.Second,
+			BackoffBase: time.Minute,
+			BackoffMax:  time.Hour,
+			MaxAttempts: 3,
+			BatchSize:   10,
+		},
+	)
+	env.events.forward = sync
+	return env, sync, fake
+}
+
+// syncOnce выполняет один проход обработчика.
+func syncOnce(t *testing.T, sync *service.CodeHostSync) {
+	t.Helper()
+
+	noError(t, sync.SyncDue(t.Context(), time.Now().UTC()))
+}
+
+// wantCalls сверяет вызовы заглушки, начиная с from, в виде "метод логины|текст".
+func wantCalls(t *testing.T, fake *codehost.Fake, from int, want ...string) {
+	t.Helper()
+
+	var got []string
+	for _, call := range fake.Calls()[from:] {
+		equal(t, "pr", call.PR, models.CodeHostPR{Repository: "acme/api", Number: 7})
+		if call.Method == "Comment" {
+			got = append(got, call.Method)
+			continue
+		}
+		got = append(got, call.Method+" "+strings.Join(call.Logins, ","))
+	}
+	equalStrings(t, "calls", got, want)
+}
+
+func TestCodeHostSyncReassignInOrder(t *testing.T) {
+	env, sync, fake := newSyncEnv(t)
+	github := service.NewGitHubService(env.logger, env.repo, env.prs, &config.GitHubConfig{WebhookSecret: gitHubSecret})
+
+	_, err := deliverGitHub(t, github, "opened.json")
+	noError(t, err)
+	equalStrings(t, "reviewers", env.getPR(t, "acme/api#7").AssignedReviewers, []string{"u2", "u3"})
+	syncOnce(t, sync)
+	wantCalls(t, fake, 0, "RequestReviewers bob", "RequestReviewers carol")
+
+	_, _, err = env.prs.ReassignReviewer(t.Context(), "acme/api#7", "u2", models.ReasonManual)
+	noError(t, err)
+
+	// снятие, назначение и комментарий выполняются в том порядке, в каком поставлены
+	syncOnce(t, sync)
+	wantCalls(t, fake, 2, "RemoveReviewers bob", "RequestReviewers dave", "Comment")
+	equal(t, "comment", fake.Calls()[4].Body, "Reviewer @bob was replaced by @dave (reason: manual).")
+}
+
+func TestCodeHostSyncWaitsForFailedTask(t *testing.T) {
+	env, sync, fake := newSyncEnv(t)
+	github := service.NewGitHubService(env.logger, env.repo, env.prs, &config.GitHubConfig{WebhookSecret: gitHubSecret})
+
+	_, err := deliverGitHub(t, github, "opened.json")
+	noError(t, err)
+
+	// первая задача ждёт повтора, вторая не обгоняет её
+	fake.FailWith(errors.New("github: 502 Bad Gateway"))
+	syncOnce(t, sync)
+	fake.FailWith(nil)
+	syncOnce(t, sync)
+	wantCalls(t, fake, 0, "RequestReviewers bob")
+
+	noError(t, sync.SyncDue(t.Context(), time.Now().UTC().Add(2*time.Minute)))
+	wantCalls(t, fake, 0, "RequestReviewers bob", "RequestReviewers bob", "RequestReviewers carol")
+}
+
+// Ответ GitHub не 2xx оставляет задачу в очереди на повтор, а не проваливает её.
+func TestCodeHostSyncRetriesErrorStatus(t *testing.T) {
+	env := newEnv(t)
+	env.seedTeam(t, "backend", "u1", "u2")
+	env.link(t, models.ProviderGitHub, "alice-dev", "u1")
+	env.link(t, models.ProviderGitHub, "bob", "u2")
+
+	rcv := newReceiver(t, http.StatusBadGateway)
+	client := codehost.NewGitHubClient(&config.GitHubConfig{Token: "ghp-test", APIURL: rcv.server.URL}, rcv.server.Client())
+	sync := service.NewCodeHostSync(env.logger, env.repo, map[string]service.CodeHostClient{models.ProviderGitHub: client}, &chatConfig)
+	env.events.forward = sync
+	github := service.NewGitHubService(env.logger, env.repo, env.prs, &config.GitHubConfig{WebhookSecret: gitHubSecret})
+
+	_, err := deliverGitHub(t, github, "opened.json")
+	noError(t, err)
+	syncOnce(t, sync)
+
+	tasks, err := sync.Tasks(t.Context(), models.SyncPending)
+	noError(t, err)
+	equal(t, "pending tasks", len(tasks), 1)
+	equal(t, "attempts", tasks[0].Attempts, 1)
+	if !strings.Contains(tasks[0].LastError, "502") {
+		t.Fatalf("last error %q does not mention 502", tasks[0].LastError)
+	}
+
+	noError(t, sync.SyncDue(t.Context(), time.Now().UTC().Add(2*time.Minute)))
+	tasks, err = sync.Tasks(t.Context(), models.SyncDone)
+	noError(t, err)
+	equal(t, "done tasks", len(tasks), 1)
+	equal(t, "requests", len(rcv.deliveries()), 2)
+}
+
+func TestCodeHostSyncReopenRemovesInactive(t *testing.T) {
+	env, sync, fake := newSyncEnv(t)
+	github := service.NewGitHubService(env.logger, env.repo, env.prs, &config.GitHubConfig{WebhookSecret: gitHubSecret})
+
+	_, err := deliverGitHub(t, github, "opened.json")
+	noError(t, err)
+	syncOnce(t, sync)
+	_, err = deliverGitHub(t, github, "closed.json")
+	noError(t, err)
+
+	noError(t, env.repo.SetUserActive(t.Context(), "u2", false))
+	pr, err := env.prs.ReopenPR(t.Context(), "acme/api#7")
+	noError(t, err)
+	equalStrings(t, "reviewers", pr.AssignedReviewers, []string{"u3", "u4"})
+
+	syncOnce(t, sync)
+	wantCalls(t, fake, 2, "RemoveReviewers bob", "RequestReviewers dave")
+}
+
+func TestCodeHostSyncSkipsAPIPR(t *testing.T) {
+	env, sync, fake := newSyncEnv(t)
+
+	// id похож на PR GitHub, но PR создан через API, а не интеграцией
+	_, err := env.prs.CreatePR(t.Context(), &models.PullRequestShort{
+		ID:       "acme/api#7",
+		Name:     "Add rate limiter",
+		AuthorID: "u1",
+	}, models.CreatePROptions{})
+	noError(t, err)
+
+	tasks, err := sync.Tasks(t.Context(), "")
+	noError(t, err)
+	equal(t, "tasks", len(tasks), 0)
+	syncOnce(t, sync)
+	equal(t, "calls", len(fake.Calls()), 0)
+}
@@ -0,0 +1,340 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	htmltemplate "html/template"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"strings"
+	"text/template"
+	"time"
+
+	"pr-review/internal/config"
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+type EmailRepository interface {
+	GetUserByID(ctx context.Context, id string) (*models.User, error)
+	GetUserEmail(ctx context.Context, userID string) (*models.EmailSettings, error)
+	GetPRsByReviewer(ctx context.Context, userID string) ([]*models.ReviewAssignment, error)
+	// ClaimDigestRecipients сразу отмечает сводку за day разосланной
+	ClaimDigestRecipients(ctx context.Context, day time.Time) ([]models.EmailSettings, error)
+	EnqueueEmail(ctx context.Context, message *models.EmailMessage) error
+	ClaimDueEmails(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.EmailMessage, error)
+	SaveEmailAttempt(ctx context.Context, message *models.EmailMessage) error
+}
+
+type EmailSender interface {
+	Send(ctx context.Context, to string, message []byte) error
+}
+
+// ReviewEmailData - данные письма о назначении. Пользователь без username указывается по id.
+type ReviewEmailData struct {
+	Reviewer string
+	Author   string
+	// OldReviewer и Reason заданы только при замене ревьювера
+	OldReviewer string
+	Reason      string
+	PullRequest EventPR
+}
+
+type DigestEmailData struct {
+	Reviewer     string
+	PullRequests []DigestPR
+}
+
+type DigestPR struct {
+	AssignedAt *time.Time
+	ID         string
+	Name       string
+	Author     string
+}
+
+var (
+	reviewEmailText = template.Must(template.New("review").Parse(`Hi {{.Reviewer}},
+
+{{if .OldReviewer -}}
+Review of "{{.PullRequest.Name}}" ({{.PullRequest.ID}}) by {{.Author}} was passed to you from {{.OldReviewer}} (reason: {{.Reason}}).
+{{- else -}}
+You were assigned to review "{{.PullRequest.Name}}" ({{.PullRequest.ID}}) by {{.Author}}.
+{{- end}}
+`))
+	reviewEmailHTML = htmltemplate.Must(htmltemplate.New("review").Parse(`<p>Hi {{.Reviewer}},</p>
+<p>
+{{- if .OldReviewer -}}
+Review of <b>{{.PullRequest.Name}}</b> ({{.PullRequest.ID}}) by {{.Author}} was passed to you from {{.OldReviewer}} (reason: {{.Reason}}).
+{{- else -}}
+You were assigned to review <b>{{.PullRequest.Name}}</b> ({{.PullRequest.ID}}) by {{.Author}}.
+{{- end -}}
+</p>
+`))
+	digestEmailText = template.Must(template.New("digest").Parse(`Hi {{.Reviewer}},
+
+Pull requests waiting for your review:
+{{- range .PullRequests}}
+- {{.Name}} ({{.ID}}) by {{.Author}}{{with .AssignedAt}}, assigned {{.Format "2006-01-02"}}{{end}}
+{{- end}}
+`))
+	digestEmailHTML = htmltemplate.Must(htmltemplate.New("digest").Parse(`<p>Hi {{.Reviewer}},</p>
+<p>Pull requests waiting for your review:</p>
+<ul>
+{{- range .PullRequests}}
+<li><b>{{.Name}}</b> ({{.ID}}) by {{.Author}}{{with .AssignedAt}}, assigned {{.Format "2006-01-02"}}{{end}}</li>
+{{- end}}
+</ul>
+`))
+)
+
+// EmailNotifier пишет ревьюверу при каждом назначении (immediate) или раз в день сводкой (digest).
+type EmailNotifier struct {
+	logger *slog.Logger
+	repo   EmailRepository
+	sender EmailSender
+	worker *outboxWorker[models.EmailMessage]
+	cfg    config.EmailConfig
+}
+
+func NewEmailNotifier(logger *slog.Logger, repo EmailRepository, sender EmailSender, cfg *config.EmailConfig) *EmailNotifier {
+	n := &EmailNotifier{
+		logger: logger,
+		repo:   repo,
+		sender: sender,
+		cfg:    *cfg,
+	}
+	n.worker = newOutboxWorker[models.EmailMessage](logger, n, "Email", &cfg.OutboxConfig, cfg.Timeout)
+	return n
+}
+
+func (n *EmailNotifier) Publish(ctx context.Context, event string, data any) {
+	const op = "EmailNotifier.Publish"
+
+	reviewer, ok := data.(ReviewerEventData)
+	if !ok || (event != models.WebhookEventReviewerAssigned && event != models.WebhookEventReviewerReassigned) {
+		return
+	}
+
+	settings, err := n.repo.GetUserEmail(ctx, reviewer.ReviewerID)
+	if errors.Is(err, errors.ErrEmailNotFound) || errors.Is(err, errors.ErrUserNotFound) {
+		return
+	}
+	if err != nil {
+		n.logger.Error("Failed to get user email", "op", op, "error", err, "userID", reviewer.ReviewerID)
+		return
+	}
+	if settings.Mode != models.EmailImmediate {
+		return
+	}
+
+	message := &ReviewEmailData{
+		Reviewer:    n.username(ctx, reviewer.ReviewerID),
+		Author:      n.username(ctx, reviewer.PullRequest.AuthorID),
+		OldReviewer: n.username(ctx, reviewer.OldReviewerID),
+		Reason:      reviewer.Reason,
+		PullRequest: reviewer.PullRequest,
+	}
+	subject := "Review requested: " + reviewer.PullRequest.Name
+
+	if err := n.enqueue(ctx, settings, subject, reviewEmailText, reviewEmailHTML, message); err != nil {
+		n.logger.Error("Failed to enqueue email", "op", op, "error", err, "userID", settings.UserID, "event", event)
+	}
+}
+
+// SendDigests отмечает пользователя до постановки сводки в очередь, поэтому при ошибке
+// сводка за этот день пропускается, а не дублируется.
+func (n *EmailNotifier) SendDigests(ctx context.Context, now time.Time) error {
+	const op = "EmailNotifier.SendDigests"
+
+	recipients, err := n.repo.ClaimDigestRecipients(ctx, digestDay(now, n.cfg.DigestHour))
+	if err != nil {
+		return errors.WrapError(op, err)
+	}
+
+	for i := range recipients {
+		if err := n.enqueueDigest(ctx, &recipients[i]); err != nil {
+			n.logger.Error("Failed to enqueue digest", "op", op, "error", err, "userID", recipients[i].UserID)
+		}
+	}
+	return nil
+}
+
+func (n *EmailNotifier) SendDue(ctx context.Context, now time.Time) error {
+	const op = "EmailNotifier.SendDue"
+
+	if err := n.worker.runDue(ctx, now); err != nil {
+		return errors.WrapError(op, err)
+	}
+	return nil
+}
+
+// private methods
+
+func (n *EmailNotifier) enqueueDigest(ctx context.Context, settings *models.EmailSettings) error {
+	assignments, err := n.repo.GetPRsByReviewer(ctx, settings.UserID)
+	if err != nil {
+		return err
+	}
+
+	digest := &DigestEmailData{Reviewer: n.username(ctx, settings.UserID)}
+	for _, assignment := range assignments {
+		if assignment.Status != models.PRStatusOpen || assignment.State != models.ReviewStatePending {
+			continue
+		}
+		digest.PullRequests = append(digest.PullRequests, DigestPR{
+			AssignedAt: assignment.AssignedAt,
+			ID:         assignment.ID,
+			Name:       assignment.Name,
+			Author:     n.username(ctx, assignment.AuthorID),
+		})
+	}
+	if len(digest.PullRequests) == 0 {
+		return nil
+	}
+
+	subject := fmt.Sprintf("%d pull requests waiting for your review", len(digest.PullRequests))
+	if len(digest.PullRequests) == 1 {
+		subject = "1 pull request waiting for your review"
+	}
+	return n.enqueue(ctx, settings, subject, digestEmailText, digestEmailHTML, digest)
+}
+
+func (n *EmailNotifier) enqueue(
+	ctx context.Context,
+	settings *models.EmailSettings,
+	subject string,
+	text *template.Template,
+	html *htmltemplate.Template,
+	data any,
+) error {
+	var textBody, htmlBody strings.Builder
+	if err := text.Execute(&textBody, data); err != nil {
+		return err
+	}
+	if err := html.Execute(&htmlBody, data); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	message, err := buildEmail(n.cfg.From, settings.Email, subject, now, textBody.String(), htmlBody.String())
+	if err != nil {
+		return err
+	}
+
+	return n.repo.EnqueueEmail(ctx, &models.EmailMessage{
+		CreatedAt:     now,
+		NextAttemptAt: now,
+		UserID:        settings.UserID,
+		Recipient:     settings.Email,
+		Subject:       subject,
+		Status:        models.EmailPending,
+		Message:       message,
+	})
+}
+
+func (n *EmailNotifier) claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.EmailMessage, error) {
+	return n.repo.ClaimDueEmails(ctx, now, lease, limit)
+}
+
+func (n *EmailNotifier) send(ctx context.Context, message *models.EmailMessage) error {
+	return n.sender.Send(ctx, message.Recipient, message.Message)
+}
+
+func (n *EmailNotifier) save(ctx context.Context, message *models.EmailMessage) error {
+	return n.repo.SaveEmailAttempt(ctx, message)
+}
+
+func (n *EmailNotifier) attempts(message *models.EmailMessage) int {
+	return message.Attempts
+}
+
+func (n *EmailNotifier) record(message *models.EmailMessage, result *outboxAttempt) {
+	message.Attempts = result.attempts
+	message.LastError = ""
+	if result.err != nil {
+		message.LastError = result.err.Error()
+	}
+
+	switch result.status {
+	case outboxDone:
+		message.Status = models.EmailSent
+		message.SentAt = &result.at
+	case outboxFailed:
+		message.Status = models.EmailFailed
+	default:
+		message.Status = models.EmailPending
+		message.NextAttemptAt = result.nextAttemptAt
+	}
+}
+
+func (n *EmailNotifier) logAttrs(message *models.EmailMessage) []any {
+	return []any{"messageID", message.ID, "userID", message.UserID}
+}
+
+func (n *EmailNotifier) username(ctx context.Context, userID string) string {
+	if userID == "" {
+		return ""
+	}
+	user, err := n.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return userID
+	}
+	return user.Username
+}
+
+// digestDay - сегодня в hour UTC или, если этот час ещё не наступил, вчера.
+func digestDay(now time.Time, hour int) time.Time {
+	now = now.UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, time.UTC)
+	if now.Before(day) {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
+
+// buildEmail кодирует тему, поэтому перевод строки в названии PR не попадёт в заголовки.
+func buildEmail(from, to, subject string, date time.Time, text, html string) ([]byte, error) {
+	var buf bytes.Buffer
+	body := multipart.NewWriter(&buf)
+
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", to)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", body.Boundary())
+
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{contentType: "text/plain; charset=utf-8", content: text},
+		{contentType: "text/html; charset=utf-8", content: html},
+	}
+	for _, part := range parts {
+		writer, err := body.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		encoder := quotedprintable.NewWriter(writer)
+		if _, err := encoder.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,134 @@
+package service_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"pr-review/internal/config"
+	"pr-review/internal/email"
+	"pr-review/internal/models"
+	"pr-review/internal/service"
+)
+
+// newEmailEnv направляет события сервиса в EmailNotifier, который отправляет письма
+// на SMTP-заглушку. u2 получает письма сразу, u3 - сводкой.
+func newEmailEnv(t *testing.T) (*testEnv, *service.EmailNotifier, *email.FakeServer) {
+	t.Helper()
+
+	server, err := email.NewFakeServer()
+	noError(t, err)
+	t.Cleanup(func() {
+		if err := server.Close(); err != nil {
+			t.Errorf("close fake SMTP server: %v", err)
+		}
+	})
+
+	cfg := &config.EmailConfig{
+		Host: server.Addr().IP.String(),
+		Port: server.Addr().Port,
+		From: "PR Review <pr-review@example.com>",
+		OutboxConfig: config.OutboxConfig{
+			Timeout:     5 * time.Second,
+			BackoffBase: time.Minute,
+			BackoffMax:  time.Hour,
+			MaxAttempts: 3,
+			BatchSize:   10,
+		},
+	}
+
+	env := newEnv(t)
+	env.seedTeam(t, "backend", "u1", "u2", "u3")
+	noError(t, env.repo.SetUserEmail(t.Context(), &models.EmailSettings{UserID: "u2", Email: "u2@example.com", Mode: models.EmailImmediate}))
+	noError(t, env.repo.SetUserEmail(t.Context(), &models.EmailSettings{UserID: "u3", Email: "u3@example.com", Mode: models.EmailDigest}))
+
+	notifier := service.NewEmailNotifier(env.logger, env.repo, email.NewSMTPSender(cfg), cfg)
+	env.events.forward = notifier
+	return env, notifier, server
+}
+
+// wantMessage сверяет конверт письма и проверяет, что в нём есть каждая из строк want.
+func wantMessage(t *testing.T, message email.FakeMessage, to string, want ...string) {
+	t.Helper()
+
+	equal(t, "from", message.From, "pr-review@example.com")
+	equalStrings(t, "to", message.To, []string{to})
+	for _, line := range want {
+		if !bytes.Contains(message.Data, []byte(line)) {
+			t.Fatalf("message to %s: %q not found in\n%s", to, line, message.Data)
+		}
+	}
+}
+
+func TestEmailImmediate(t *testing.T) {
+	env, notifier, server := newEmailEnv(t)
+
+	_, err := env.prs.CreatePR(t.Context(), &models.PullRequestShort{ID: "pr-1", Name: "Add cache", AuthorID: "u1"}, models.CreatePROptions{})
+	noError(t, err)
+	noError(t, notifier.SendDue(t.Context(), time.Now().UTC()))
+
+	// u3 выбрал сводку и сразу письмо не получает
+	messages := server.Messages()
+	equal(t, "messages", len(messages), 1)
+	wantMessage(t, messages[0], "u2@example.com",
+		"From: PR Review <pr-review@example.com>",
+		"To: u2@example.com",
+		"Subject: Review requested: Add cache",
+		"Content-Type: multipart/alternative",
+		`You were assigned to review "Add cache" (pr-1) by u1-name.`,
+		"<b>Add cache</b>",
+	)
+}
+
+// Отказ сервера на любой команде письма ставит его на повтор.
+func TestEmailRetry(t *testing.T) {
+	for _, command := range []string{"MAIL", "RCPT", "DATA"} {
+		t.Run(command, func(t *testing.T) {
+			env, notifier, server := newEmailEnv(t)
+
+			server.RejectWith(command, "451 try again later")
+			_, err := env.prs.CreatePR(t.Context(), &models.PullRequestShort{ID: "pr-1", Name: "Add cache", AuthorID: "u1"}, models.CreatePROptions{})
+			noError(t, err)
+
+			start := time.Now().UTC()
+			noError(t, notifier.SendDue(t.Context(), start))
+			equal(t, "rejected", len(server.Messages()), 0)
+
+			// до конца паузы повтора нет, даже если сервер снова принимает письма
+			server.RejectWith(command, "")
+			noError(t, notifier.SendDue(t.Context(), start.Add(30*time.Second)))
+			equal(t, "before backoff", len(server.Messages()), 0)
+
+			noError(t, notifier.SendDue(t.Context(), start.Add(2*time.Minute)))
+			messages := server.Messages()
+			equal(t, "retried", len(messages), 1)
+			wantMessage(t, messages[0], "u2@example.com", "Subject: Review requested: Add cache")
+
+			noError(t, notifier.SendDue(t.Context(), start.Add(time.Hour)))
+			equal(t, "sent once", len(server.Messages()), 1)
+		})
+	}
+}
+
+func TestEmailDigest(t *testing.T) {
+	env, notifier, server := newEmailEnv(t)
+
+	_, err := env.prs.CreatePR(t.Context(), &models.PullRequestShort{ID: "pr-1", Name: "Add cache", AuthorID: "u1"}, models.CreatePROptions{})
+	noError(t, err)
+
+	now := time.Now().UTC()
+	noError(t, notifier.SendDigests(t.Context(), now))
+	noError(t, notifier.SendDue(t.Context(), time.Now().UTC()))
+
+	messages := server.Messages()
+	equal(t, "messages", len(messages), 2)
+	wantMessage(t, messages[1], "u3@example.com",
+		"Subject: 1 pull request waiting for your review",
+		"- Add cache (pr-1) by u1-name",
+	)
+
+	// сводка за день рассылается один раз
+	noError(t, notifier.SendDigests(t.Context(), now.Add(time.Minute)))
+	noError(t, notifier.SendDue(t.Context(), time.Now().UTC().Add(time.Minute)))
+	equal(t, "digest once", len(server.Messages()), 2)
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"pr-review/internal/models"
+)
+
+// EventPublisher: ошибка публикации не отменяет изменение, поэтому реализации только логируют её.
+type EventPublisher interface {
+	Publish(ctx context.Context, event string, data any)
+}
+
+type EventPublishers []EventPublisher
+
+func (p EventPublishers) Publish(ctx context.Context, event string, data any) {
+	for _, publisher := range p {
+		publisher.Publish(ctx, event, data)
+	}
+}
+
+type EventPR struct {
+	MergedAt          *time.Time `json:"merged_at,omitempty"`
+	ID                string     `json:"pull_request_id"`
+	Name              string     `json:"pull_request_name"`
+	AuthorID          string     `json:"author_id"`
+	Status            string     `json:"status"`
+	Provider          string     `json:"provider,omitempty"`
+	AssignedReviewers []string   `json:"assigned_reviewers"`
+	IsDraft           bool       `json:"is_draft"`
+}
+
+type PREventData struct {
+	PullRequest EventPR `json:"pull_request"`
+}
+
+// ReviewerEventData: OldReviewerID задаётся только при замене, Reason - при замене и снятии.
+type ReviewerEventData struct {
+	ReviewerID    string  `json:"reviewer_id"`
+	OldReviewerID string  `json:"old_reviewer_id,omitempty"`
+	Reason        string  `json:"reason,omitempty"`
+	PullRequest   EventPR `json:"pull_request"`
+}
+
+type UserEventData struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	TeamName string `json:"team_name"`
+}
+
+func eventPR(pr *models.PullRequest) EventPR {
+	reviewers := pr.AssignedReviewers
+	if reviewers == nil {
+		reviewers = []string{}
+	}
+	return EventPR{
+		MergedAt:          pr.MergedAt,
+		ID:                pr.ID,
+		Name:              pr.Name,
+		AuthorID:          pr.AuthorID,
+		Status:            string(pr.Status),
+		Provider:          pr.Provider,
+		AssignedReviewers: reviewers,
+		IsDraft:           pr.IsDraft,
+	}
+}
+
+func publishAssigned(ctx context.Context, events EventPublisher, pr *models.PullRequest, reviewers []string) {
+	for _, reviewerID := range reviewers {
+		events.Publish(ctx, models.WebhookEventReviewerAssigned, ReviewerEventData{
+			ReviewerID:  reviewerID,
+			PullRequest: eventPR(pr),
+		})
+	}
+}
+
+func publishReassigned(ctx context.Context, events EventPublisher, pr *models.PullRequest, oldUserID, newUserID, reason string) {
+	events.Publish(ctx, models.WebhookEventReviewerReassigned, ReviewerEventData{
+		ReviewerID:    newUserID,
+		OldReviewerID: oldUserID,
+		Reason:        reason,
+		PullRequest:   eventPR(pr),
+	})
+}
+
+func publishRemoved(ctx context.Context, events EventPublisher, pr *models.PullRequest, reviewers []string, reason string) {
+	for _, reviewerID := range reviewers {
+		events.Publish(ctx, models.WebhookEventReviewerRemoved, ReviewerEventData{
+			ReviewerID:  reviewerID,
+			Reason:      reason,
+			PullRequest: eventPR(pr),
+		})
+	}
+}
+
+func publishDeactivated(ctx context.Context, events EventPublisher, user *models.User) {
+	events.Publish(ctx, models.WebhookEventUserDeactivated, UserEventData{
+		UserID:   user.UserID,
+		Username: user.Username,
+		TeamName: user.TeamName,
+	})
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+type candidateRepository interface {
+	GetReviewerCandidates(ctx context.Context, teamName string) ([]models.ReviewerCandidate, error)
+	GetReviewerCandidatesByIDs(ctx context.Context, userIDs []string) ([]models.ReviewerCandidate, error)
+	GetReviewerPool(ctx context.Context, name string) (*models.ReviewerPool, error)
+	GetTeamByName(ctx context.Context, name string) (*models.Team, error)
+}
+
+type reviewerPick struct {
+	selected   []string
+	atCapacity []string
+	fallback   []models.FallbackReviewer
+}
+
+func fallbackCandidates(ctx context.Context, repo candidateRepository, fallback models.ReviewerFallback) ([]models.ReviewerCandidate, error) {
+	const op = "service.fallbackCandidates"
+
+	if fallback.Kind == models.FallbackTeam {
+		candidates, err := teamCandidates(ctx, repo, fallback.Name)
+		if err != nil {
+			return nil, errors.WrapError(op, err)
+		}
+		return candidates, nil
+	}
+
+	pool, err := repo.GetReviewerPool(ctx, fallback.Name)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	if len(pool.Members) == 0 {
+		return nil, nil
+	}
+
+	candidates, err := repo.GetReviewerCandidatesByIDs(ctx, pool.Members)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	return candidates, nil
+}
+
+// teamCandidates: архивная или удалённая команда ревьюверов не даёт, даже если кого-то
+// из её участников снова активировали.
+func teamCandidates(ctx context.Context, repo candidateRepository, teamName string) ([]models.ReviewerCandidate, error) {
+	const op = "service.teamCandidates"
+
+	team, err := repo.GetTeamByName(ctx, teamName)
+	if errors.Is(err, errors.ErrTeamNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	if team.ArchivedAt != nil {
+		return nil, nil
+	}
+
+	candidates, err := repo.GetReviewerCandidates(ctx, teamName)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	return candidates, nil
+}
+
+// fallbackSelectorKey: запасная команда выбирается своей стратегией, пул - стратегией команды автора.
+func fallbackSelectorKey(teamName string, fallback models.ReviewerFallback) string {
+	if fallback.Kind == models.FallbackTeam {
+		return fallback.Name
+	}
+	return teamName
+}
+
+func fallbackOf(fallbacks []models.FallbackReviewer, userID string) *models.ReviewerFallback {
+	for i := range fallbacks {
+		if fallbacks[i].UserID == userID {
+			return &fallbacks[i].ReviewerFallback
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"pr-review/internal/config"
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+type gitHubPullRequestEvent struct {
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Action      string `json:"action"`
+	PullRequest struct {
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		Title  string `json:"title"`
+		Number int    `json:"number"`
+		Draft  bool   `json:"draft"`
+		Merged bool   `json:"merged"`
+	} `json:"pull_request"`
+}
+
+type GitHubService struct {
+	logger   *slog.Logger
+	accounts AccountRepository
+	prs      prLifecycle
+	secret   string
+}
+
+func NewGitHubService(
+	logger *slog.Logger,
+	accounts AccountRepository,
+	prs prLifecycle,
+	cfg *config.GitHubConfig,
+) *GitHubService {
+	return &GitHubService{
+		logger:   logger,
+		accounts: accounts,
+		prs:      prs,
+		secret:   cfg.WebhookSecret,
+	}
+}
+
+// HandleWebhook пропускает события кроме pull_request и неизвестные действия.
+func (s *GitHubService) HandleWebhook(ctx context.Context, event, signature string, body []byte) (*models.IntegrationResult, error) {
+	const op = "GitHubService.HandleWebhook"
+
+	// без секрета подпись подделать тривиально, поэтому такие доставки не принимаются
+	if s.secret == "" || !hmac.Equal([]byte(signature), []byte(Sign(body, s.secret))) {
+		return nil, errors.WrapError(op, errors.ErrInvalidSignature)
+	}
+	if event != "pull_request" {
+		return &models.IntegrationResult{}, nil
+	}
+
+	var payload gitHubPullRequestEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, errors.WrapError(op, fmt.Errorf("%w: %w", errors.ErrInvalidPayload, err))
+	}
+	if payload.Repository.FullName == "" || payload.PullRequest.Number == 0 {
+		return nil, errors.WrapError(op, fmt.Errorf("%w: repository and pull request number are required", errors.ErrInvalidPayload))
+	}
+
+	result, err := applyCodeHostEvent(ctx, s.logger, s.accounts, s.prs, models.ProviderGitHub, gitHubEvent(&payload))
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	return result, nil
+}
+
+// private methods
+
+// gitHubEvent: PR сервиса получает id вида owner/repo#number.
+func gitHubEvent(payload *gitHubPullRequestEvent) *codeHostEvent {
+	event := &codeHostEvent{
+		PullRequestID: fmt.Sprintf("%s#%d", payload.Repository.FullName, payload.PullRequest.Number),
+		Title:         payload.PullRequest.Title,
+		AuthorLogin:   normalizeLogin(payload.PullRequest.User.Login),
+		IsDraft:       payload.PullRequest.Draft,
+	}
+
+	switch payload.Action {
+	case "opened":
+		event.Action = models.IntegrationCreated
+	case "reopened":
+		event.Action = models.IntegrationReopened
+	case "ready_for_review":
+		event.Action = models.IntegrationReadyForReview
+	case "converted_to_draft":
+		event.Action = models.IntegrationConvertedToDraft
+	case "closed":
+		event.Action = models.IntegrationClosed
+		if payload.PullRequest.Merged {
+			event.Action = models.IntegrationMerged
+		}
+	}
+	return event
+}
@@ -0,0 +1,109 @@
+package service_test
+
+import (
+	"testing"
+
+	"pr-review/internal/config"
+	serviceErrors "pr-review/internal/errors"
+	"pr-review/internal/models"
+	"pr-review/internal/service"
+)
+
+const gitHubSecret = "webhook-secret"
+
+func newGitHubEnv(t *testing.T) (*testEnv, *service.GitHubService) {
+	t.Helper()
+
+	env := newEnv(t)
+	env.seedTeam(t, "backend", "u1", "u2", "u3")
+	env.link(t, models.ProviderGitHub, "alice-dev", "u1")
+	return env, service.NewGitHubService(env.logger, env.repo, env.prs, &config.GitHubConfig{WebhookSecret: gitHubSecret})
+}
+
+// deliverGitHub подписывает записанную доставку так же, как GitHub.
+func deliverGitHub(t *testing.T, s *service.GitHubService, name string) (*models.IntegrationResult, error) {
+	t.Helper()
+
+	body := fixture(t, "github/"+name)
+	return s.HandleWebhook(t.Context(), "pull_request", service.Sign(body, gitHubSecret), body)
+}
+
+func TestGitHubOpened(t *testing.T) {
+	env, github := newGitHubEnv(t)
+
+	result, err := deliverGitHub(t, github, "opened.json")
+	noError(t, err)
+	equal(t, "action", result.Action, models.IntegrationCreated)
+	equal(t, "pr id", result.PullRequestID, "acme/api#7")
+
+	pr := env.getPR(t, "acme/api#7")
+	equal(t, "author", pr.AuthorID, "u1")
+	equal(t, "name", pr.Name, "Add rate limiter")
+	equalStrings(t, "reviewers", pr.AssignedReviewers, []string{"u2", "u3"})
+
+	// повторная доставка уже созданного PR - не ошибка
+	result, err = deliverGitHub(t, github, "opened.json")
+	noError(t, err)
+	equal(t, "repeated action", result.Action, "")
+}
+
+func TestGitHubReadyForReview(t *testing.T) {
+	env, github := newGitHubEnv(t)
+
+	_, err := env.prs.CreatePR(t.Context(), &models.PullRequestShort{
+		ID: "acme/api#9", Name: "Split config loader", AuthorID: "u1", IsDraft: true,
+	}, models.CreatePROptions{})
+	noError(t, err)
+
+	result, err := deliverGitHub(t, github, "ready_for_review.json")
+	noError(t, err)
+	equal(t, "action", result.Action, models.IntegrationReadyForReview)
+
+	pr := env.getPR(t, "acme/api#9")
+	equal(t, "draft", pr.IsDraft, false)
+	equalStrings(t, "reviewers", pr.AssignedReviewers, []string{"u2", "u3"})
+}
+
+// Merge на GitHub уже случился, поэтому политика команды его не блокирует.
+func TestGitHubClosedMerged(t *testing.T) {
+	env, github := newGitHubEnv(t)
+	noError(t, env.repo.SetTeamMergePolicy(t.Context(), "backend", &models.MergePolicy{RequiredApprovals: 1}))
+
+	_, err := deliverGitHub(t, github, "opened.json")
+	noError(t, err)
+	_, err = env.prs.MergePR(t.Context(), "acme/api#7")
+	wantError(t, err, serviceErrors.ErrMergeBlocked)
+
+	result, err := deliverGitHub(t, github, "closed_merged.json")
+	noError(t, err)
+	equal(t, "action", result.Action, models.IntegrationMerged)
+	equal(t, "status", env.getPR(t, "acme/api#7").Status, models.PRStatusMerged)
+	equal(t, "merged events", env.events.count(models.WebhookEventPRMerged), 1)
+
+	// повтор доставки и опоздавшее закрытие не ошибки и ничего не меняют
+	_, err = deliverGitHub(t, github, "closed_merged.json")
+	noError(t, err)
+	result, err = deliverGitHub(t, github, "closed.json")
+	noError(t, err)
+	equal(t, "late close action", result.Action, "")
+	equal(t, "status after close", env.getPR(t, "acme/api#7").Status, models.PRStatusMerged)
+	equal(t, "merged events after repeat", env.events.count(models.WebhookEventPRMerged), 1)
+}
+
+func TestGitHubUnknownLogin(t *testing.T) {
+	env, github := newGitHubEnv(t)
+
+	_, err := deliverGitHub(t, github, "opened_unknown_login.json")
+	wantError(t, err, serviceErrors.ErrAccountNotLinked)
+
+	_, err = env.repo.GetPRByID(t.Context(), "acme/api#8")
+	wantError(t, err, serviceErrors.ErrPRNotFound)
+}
+
+func TestGitHubSignature(t *testing.T) {
+	_, github := newGitHubEnv(t)
+
+	body := fixture(t, "github/opened.json")
+	_, err := github.HandleWebhook(t.Context(), "pull_request", service.Sign(body, "other-secret"), body)
+	wantError(t, err, serviceErrors.ErrInvalidSignature)
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"pr-review/internal/config"
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+type gitLabMergeRequestEvent struct {
+	// Changes - что изменилось при action update, нужно только снятие черновика
+	Changes struct {
+		Draft *gitLabChange `json:"draft"`
+	} `json:"changes"`
+	User struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	Project struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectKind       string `json:"object_kind"`
+	ObjectAttributes struct {
+		Title  string `json:"title"`
+		Action string `json:"action"`
+		IID    int    `json:"iid"`
+		Draft  bool   `json:"draft"`
+	} `json:"object_attributes"`
+}
+
+type gitLabChange struct {
+	Previous bool `json:"previous"`
+	Current  bool `json:"current"`
+}
+
+// GitLabService обрабатывает только проекты, для которых задана команда-владелец.
+type GitLabService struct {
+	logger       *slog.Logger
+	accounts     AccountRepository
+	prs          prLifecycle
+	projectTeams map[string]string
+	token        string
+}
+
+func NewGitLabService(
+	logger *slog.Logger,
+	accounts AccountRepository,
+	prs prLifecycle,
+	cfg *config.GitLabConfig,
+) *GitLabService {
+	return &GitLabService{
+		logger:       logger,
+		accounts:     accounts,
+		prs:          prs,
+		projectTeams: cfg.ProjectTeams,
+		token:        cfg.WebhookToken,
+	}
+}
+
+func (s *GitLabService) HandleWebhook(ctx context.Context, event, token string, body []byte) (*models.IntegrationResult, error) {
+	const op = "GitLabService.HandleWebhook"
+
+	if s.token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+		return nil, errors.WrapError(op, errors.ErrInvalidSignature)
+	}
+	if event != "Merge Request Hook" {
+		return &models.IntegrationResult{}, nil
+	}
+
+	var payload gitLabMergeRequestEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, errors.WrapError(op, fmt.Errorf("%w: %w", errors.ErrInvalidPayload, err))
+	}
+	if payload.ObjectKind != "merge_request" || payload.Project.PathWithNamespace == "" || payload.ObjectAttributes.IID == 0 {
+		return nil, errors.WrapError(op, fmt.Errorf("%w: project and merge request iid are required", errors.ErrInvalidPayload))
+	}
+
+	mr := gitLabEvent(&payload)
+	teamName, ok := s.projectTeams[payload.Project.PathWithNamespace]
+	if !ok {
+		s.logger.Info("Skipping event of unconfigured GitLab project", "op", op, "project", payload.Project.PathWithNamespace)
+		return &models.IntegrationResult{PullRequestID: mr.PullRequestID}, nil
+	}
+	mr.TeamName = teamName
+
+	result, err := applyCodeHostEvent(ctx, s.logger, s.accounts, s.prs, models.ProviderGitLab, mr)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	return result, nil
+}
+
+// private methods
+
+// gitLabEvent: PR сервиса получает id вида group/project!iid.
+func gitLabEvent(payload *gitLabMergeRequestEvent) *codeHostEvent {
+	attrs := payload.ObjectAttributes
+	event := &codeHostEvent{
+		PullRequestID: fmt.Sprintf("%s!%d", payload.Project.PathWithNamespace, attrs.IID),
+		Title:         attrs.Title,
+		AuthorLogin:   normalizeLogin(payload.User.Username),
+		IsDraft:       attrs.Draft,
+	}
+
+	switch attrs.Action {
+	case "open":
+		event.Action = models.IntegrationCreated
+	case "reopen":
+		event.Action = models.IntegrationReopened
+	case "merge":
+		// MR уже влит в GitLab, поэтому он только отмечается влитым, без политики merge
+		event.Action = models.IntegrationMerged
+	case "close":
+		event.Action = models.IntegrationClosed
+	case "update":
+		if draft := payload.Changes.Draft; draft != nil && draft.Previous != draft.Current {
+			event.Action = models.IntegrationReadyForReview
+			if draft.Current {
+				event.Action = models.IntegrationConvertedToDraft
+			}
+		}
+	}
+	return event
+}
@@ -0,0 +1,150 @@
+package service_test
+
+import (
+	"testing"
+
+	"pr-review/internal/config"
+	serviceErrors "pr-review/internal/errors"
+	"pr-review/internal/models"
+	"pr-review/internal/service"
+)
+
+const gitLabToken = "webhook-token"
+
+// newGitLabEnv настраивает два проекта разных команд, bob состоит в backend.
+func newGitLabEnv(t *testing.T) (*testEnv, *service.GitLabService) {
+	t.Helper()
+
+	env := newEnv(t)
+	env.seedTeam(t, "backend", "u1", "u2", "u3")
+	env.seedTeam(t, "frontend", "f1", "f2")
+	env.link(t, models.ProviderGitLab, "bob", "u1")
+	return env, service.NewGitLabService(env.logger, env.repo, env.prs, &config.GitLabConfig{
+		ProjectTeams: map[string]string{"platform/api": "backend", "platform/web": "frontend"},
+		WebhookToken: gitLabToken,
+	})
+}
+
+func deliverGitLab(t *testing.T, s *service.GitLabService, name string) (*models.IntegrationResult, error) {
+	t.Helper()
+
+	return s.HandleWebhook(t.Context(), "Merge Request Hook", gitLabToken, fixture(t, "gitlab/"+name))
+}
+
+func TestGitLabOpen(t *testing.T) {
+	env, gitlab := newGitLabEnv(t)
+
+	result, err := deliverGitLab(t, gitlab, "open.json")
+	noError(t, err)
+	equal(t, "action", result.Action, models.IntegrationCreated)
+	equal(t, "pr id", result.PullRequestID, "platform/api!12")
+
+	pr := env.getPR(t, "platform/api!12")
+	equal(t, "author", pr.AuthorID, "u1")
+	equalStrings(t, "reviewers", pr.AssignedReviewers, []string{"u2", "u3"})
+
+	// правка заголовка ничего не меняет
+	result, err = deliverGitLab(t, gitlab, "update_title.json")
+	noError(t, err)
+	equal(t, "update action", result.Action, "")
+}
+
+func TestGitLabReadyForReview(t *testing.T) {
+	env, gitlab := newGitLabEnv(t)
+
+	_, err := deliverGitLab(t, gitlab, "open_draft.json")
+	noError(t, err)
+	draft := env.getPR(t, "platform/api!13")
+	equal(t, "draft", draft.IsDraft, true)
+	equal(t, "draft reviewers", len(draft.AssignedReviewers), 0)
+
+	result, err := deliverGitLab(t, gitlab, "update_ready.json")
+	noError(t, err)
+	equal(t, "action", result.Action, models.IntegrationReadyForReview)
+
+	pr := env.getPR(t, "platform/api!13")
+	equal(t, "ready draft", pr.IsDraft, false)
+	equalStrings(t, "ready reviewers", pr.AssignedReviewers, []string{"u2", "u3"})
+}
+
+func TestGitLabConvertToDraft(t *testing.T) {
+	env, gitlab := newGitLabEnv(t)
+
+	_, err := deliverGitLab(t, gitlab, "open.json")
+	noError(t, err)
+
+	result, err := deliverGitLab(t, gitlab, "update_draft.json")
+	noError(t, err)
+	equal(t, "action", result.Action, models.IntegrationConvertedToDraft)
+
+	pr := env.getPR(t, "platform/api!12")
+	equal(t, "draft", pr.IsDraft, true)
+	equalStrings(t, "draft reviewers", pr.AssignedReviewers, []string{"u2", "u3"})
+}
+
+// MR влит в GitLab вопреки политике команды: PR всё равно отмечается влитым.
+func TestGitLabMerge(t *testing.T) {
+	env, gitlab := newGitLabEnv(t)
+	noError(t, env.repo.SetTeamMergePolicy(t.Context(), "backend", &models.MergePolicy{
+		RequiredApprovals:       1,
+		BlockOnChangesRequested: true,
+	}))
+
+	_, err := deliverGitLab(t, gitlab, "open.json")
+	noError(t, err)
+	_, err = env.prs.SubmitReview(t.Context(), "platform/api!12", "u2", models.ReviewStateChangesRequested)
+	noError(t, err)
+
+	result, err := deliverGitLab(t, gitlab, "merge.json")
+	noError(t, err)
+	equal(t, "action", result.Action, models.IntegrationMerged)
+	equal(t, "status", env.getPR(t, "platform/api!12").Status, models.PRStatusMerged)
+
+	_, err = deliverGitLab(t, gitlab, "merge.json")
+	noError(t, err)
+	result, err = deliverGitLab(t, gitlab, "reopen.json")
+	noError(t, err)
+	equal(t, "late reopen action", result.Action, "")
+	equal(t, "merged events", env.events.count(models.WebhookEventPRMerged), 1)
+}
+
+func TestGitLabCloseReopen(t *testing.T) {
+	env, gitlab := newGitLabEnv(t)
+
+	_, err := deliverGitLab(t, gitlab, "open.json")
+	noError(t, err)
+
+	result, err := deliverGitLab(t, gitlab, "close.json")
+	noError(t, err)
+	equal(t, "close action", result.Action, models.IntegrationClosed)
+	equal(t, "closed status", env.getPR(t, "platform/api!12").Status, models.PRStatusClosed)
+
+	result, err = deliverGitLab(t, gitlab, "reopen.json")
+	noError(t, err)
+	equal(t, "reopen action", result.Action, models.IntegrationReopened)
+	equal(t, "reopened status", env.getPR(t, "platform/api!12").Status, models.PRStatusOpen)
+}
+
+func TestGitLabProjectTeams(t *testing.T) {
+	env, gitlab := newGitLabEnv(t)
+
+	// ревьюверы берутся из команды автора, поэтому автор должен состоять в команде проекта
+	_, err := deliverGitLab(t, gitlab, "open_web.json")
+	wantError(t, err, serviceErrors.ErrNotMember)
+
+	result, err := deliverGitLab(t, gitlab, "open_unconfigured.json")
+	noError(t, err)
+	equal(t, "unconfigured action", result.Action, "")
+	_, err = env.repo.GetPRByID(t.Context(), "sandbox/scratch!5")
+	wantError(t, err, serviceErrors.ErrPRNotFound)
+
+	_, err = deliverGitLab(t, gitlab, "open_unknown_login.json")
+	wantError(t, err, serviceErrors.ErrAccountNotLinked)
+}
+
+func TestGitLabToken(t *testing.T) {
+	_, gitlab := newGitLabEnv(t)
+
+	_, err := gitlab.HandleWebhook(t.Context(), "Merge Request Hook", "other-token", fixture(t, "gitlab/open.json"))
+	wantError(t, err, serviceErrors.ErrInvalidSignature)
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+type AccountRepository interface {
+	GetUserIDByLogin(ctx context.Context, provider, login string) (string, error)
+	GetUserByID(ctx context.Context, id string) (*models.User, error)
+}
+
+type prLifecycle interface {
+	CreatePR(ctx context.Context, pr *models.PullRequestShort, opts models.CreatePROptions) (*models.PullRequest, error)
+	RecordMerge(ctx context.Context, prID string) (*models.PullRequest, error)
+	ClosePR(ctx context.Context, prID string) (*models.PullRequest, error)
+	ReopenPR(ctx context.Context, prID string) (*models.PullRequest, error)
+	ReadyForReview(ctx context.Context, prID string) (*models.PullRequest, error)
+	ConvertToDraft(ctx context.Context, prID string) (*models.PullRequest, error)
+}
+
+// codeHostEvent: Title, AuthorLogin, TeamName и IsDraft нужны только для создания.
+type codeHostEvent struct {
+	Action        string
+	PullRequestID string
+	Title         string
+	AuthorLogin   string
+	// TeamName: если задана, автор должен в ней состоять
+	TeamName string
+	IsDraft  bool
+}
+
+// applyCodeHostEvent пропускает повторные и опоздавшие события, а не возвращает ошибку:
+// код-хостинги повторяют доставку при ошибке. Merge на код-хостинге уже случился,
+// поэтому политика merge не проверяется.
+func applyCodeHostEvent(
+	ctx context.Context,
+	logger *slog.Logger,
+	accounts AccountRepository,
+	prs prLifecycle,
+	provider string,
+	event *codeHostEvent,
+) (*models.IntegrationResult, error) {
+	const op = "service.applyCodeHostEvent"
+
+	var err error
+	switch event.Action {
+	case models.IntegrationCreated:
+		var authorID string
+		authorID, err = accounts.GetUserIDByLogin(ctx, provider, event.AuthorLogin)
+		if err != nil {
+			logger.Error("Failed to map PR author", "op", op, "error", err, "provider", provider, "login", event.AuthorLogin)
+			return nil, errors.WrapError(op, err)
+		}
+		if err = checkTeam(ctx, accounts, authorID, event.TeamName); err != nil {
+			logger.Error("PR author does not belong to repository team", "op", op, "error", err,
+				"provider", provider, "userID", authorID, "teamName", event.TeamName)
+			return nil, errors.WrapError(op, err)
+		}
+		_, err = prs.CreatePR(ctx, &models.PullRequestShort{
+			ID:       event.PullRequestID,
+			Name:     event.Title,
+			AuthorID: authorID,
+			IsDraft:  event.IsDraft,
+		}, models.CreatePROptions{Provider: provider})
+		if errors.Is(err, errors.ErrPRExists) {
+			return &models.IntegrationResult{PullRequestID: event.PullRequestID}, nil
+		}
+	case models.IntegrationMerged:
+		_, err = prs.RecordMerge(ctx, event.PullRequestID)
+	case models.IntegrationClosed:
+		_, err = prs.ClosePR(ctx, event.PullRequestID)
+	case models.IntegrationReopened:
+		_, err = prs.ReopenPR(ctx, event.PullRequestID)
+	case models.IntegrationReadyForReview:
+		_, err = prs.ReadyForReview(ctx, event.PullRequestID)
+	case models.IntegrationConvertedToDraft:
+		_, err = prs.ConvertToDraft(ctx, event.PullRequestID)
+	default:
+		return &models.IntegrationResult{PullRequestID: event.PullRequestID}, nil
+	}
+
+	if errors.Is(err, errors.ErrPRNotFound) {
+		return &models.IntegrationResult{PullRequestID: event.PullRequestID}, nil
+	}
+	if errors.Is(err, errors.ErrPRMerged) || errors.Is(err, errors.ErrPRClosed) {
+		logger.Warn("Skipping code host event for finished PR", "op", op, "error", err,
+			"provider", provider, "action", event.Action, "prID", event.PullRequestID)
+		return &models.IntegrationResult{PullRequestID: event.PullRequestID}, nil
+	}
+	if err != nil {
+		logger.Error("Failed to apply code host event", "op", op, "error", err,
+			"provider", provider, "action", event.Action, "prID", event.PullRequestID)
+		return nil, errors.WrapError(op, err)
+	}
+
+	return &models.IntegrationResult{Action: event.Action, PullRequestID: event.PullRequestID}, nil
+}
+
+// codeHostPR разбирает owner/repo#number у GitHub и group/project!iid у GitLab.
+func codeHostPR(provider, prID string) (models.CodeHostPR, bool) {
+	var separator string
+	switch provider {
+	case models.ProviderGitHub:
+		separator = "#"
+	case models.ProviderGitLab:
+		separator = "!"
+	default:
+		return models.CodeHostPR{}, false
+	}
+
+	repository, number, found := strings.Cut(prID, separator)
+	if !found || !strings.Contains(repository, "/") {
+		return models.CodeHostPR{}, false
+	}
+	n, err := strconv.Atoi(number)
+	if err != nil || n <= 0 {
+		return models.CodeHostPR{}, false
+	}
+	return models.CodeHostPR{Repository: repository, Number: n}, true
+}
+
+func checkTeam(ctx context.Context, accounts AccountRepository, userID, teamName string) error {
+	if teamName == "" {
+		return nil
+	}
+
+	user, err := accounts.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.TeamName != teamName {
+		return errors.ErrNotMember
+	}
+	return nil
+}
+
+func normalizeLogin(login string) string {
+	return strings.ToLower(strings.TrimSpace(login))
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+type reviewReassigner interface {
+	ReassignReviewer(ctx context.Context, prID, oldUserID, reason string) (*models.PullRequest, *string, error)
+}
+
+type reviewLister interface {
+	GetPRsByReviewer(ctx context.Context, userID string) ([]*models.ReviewAssignment, error)
+}
+
+type reviewFilter func(assignment *models.ReviewAssignment) bool
+
+func openReviews(assignment *models.ReviewAssignment) bool {
+	return assignment.Status == models.PRStatusOpen
+}
+
+func pendingReviews(assignment *models.ReviewAssignment) bool {
+	return assignment.Status == models.PRStatusOpen && assignment.State == models.ReviewStatePending
+}
+
+// reassignReviews: PR, для которых замены нет, попадают в отчёт, а не прерывают остальные переназначения.
+func reassignReviews(
+	ctx context.Context,
+	repo reviewLister,
+	reassigner reviewReassigner,
+	userID string,
+	filter reviewFilter,
+	reason string,
+) (*models.ReassignReport, error) {
+	const op = "service.reassignReviews"
+
+	assignments, err := repo.GetPRsByReviewer(ctx, userID)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	report := &models.ReassignReport{}
+	for _, assignment := range assignments {
+		if !filter(assignment) {
+			continue
+		}
+
+		pr, newUserID, err := reassigner.ReassignReviewer(ctx, assignment.ID, userID, reason)
+		if errors.Is(err, errors.ErrNoCandidate) || errors.Is(err, errors.ErrReviewersAtCapacity) {
+			report.NoCandidate = append(report.NoCandidate, assignment.ID)
+			continue
+		}
+		if err != nil {
+			return report, errors.WrapError(op, err)
+		}
+
+		report.Reassigned = append(report.Reassigned, models.Reassignment{
+			Fallback:      fallbackOf(pr.FallbackReviewers, *newUserID),
+			PullRequestID: assignment.ID,
+			OldUserID:     userID,
+			NewUserID:     *newUserID,
+		})
+	}
+
+	return report, nil
+}
+
+type planCache struct {
+	candidates map[models.ReviewerFallback][]models.ReviewerCandidate
+	fallbacks  map[string][]models.ReviewerFallback
+	planned    map[string]int
+}
+
+// planReassignments учитывает уже запланированные назначения в нагрузке кандидатов, чтобы
+// least-loaded не отдал всё одному и лимит открытых ревью не был превышен.
+func (s *teamService) planReassignments(ctx context.Context, changes []*models.MembershipChange) ([]models.Reassignment, error) {
+	const op = "teamService.planReassignments"
+
+	leaving := make([]string, 0, len(changes))
+	for _, change := range changes {
+		leaving = append(leaving, change.User.UserID)
+	}
+
+	cache := &planCache{
+		candidates: make(map[models.ReviewerFallback][]models.ReviewerCandidate),
+		fallbacks:  make(map[string][]models.ReviewerFallback),
+		planned:    make(map[string]int),
+	}
+	addedToPR := make(map[string][]string)
+
+	var reassignments []models.Reassignment
+	for _, change := range changes {
+		userID := change.User.UserID
+
+		assignments, err := s.repo.GetPRsByReviewer(ctx, userID)
+		if err != nil {
+			return nil, errors.WrapError(op, err)
+		}
+
+		for _, assignment := range assignments {
+			if assignment.Status != models.PRStatusOpen {
+				continue
+			}
+
+			pr, err := s.repo.GetPRByID(ctx, assignment.ID)
+			if err != nil {
+				return nil, errors.WrapError(op, err)
+			}
+			author, err := s.repo.GetUserByID(ctx, pr.AuthorID)
+			if err != nil {
+				return nil, errors.WrapError(op, err)
+			}
+
+			exclude := append([]string{author.UserID}, pr.AssignedReviewers...)
+			exclude = append(exclude, leaving...)
+			exclude = append(exclude, addedToPR[pr.ID]...)
+
+			newUserID, fallback, err := s.planReplacement(ctx, cache, author.TeamName, exclude)
+			if err != nil {
+				return nil, errors.WrapError(op, err)
+			}
+			if newUserID == "" {
+				change.NoCandidate = append(change.NoCandidate, pr.ID)
+				continue
+			}
+
+			reassignment := models.Reassignment{
+				Fallback:      fallback,
+				PullRequestID: pr.ID,
+				OldUserID:     userID,
+				NewUserID:     newUserID,
+			}
+			reassignments = append(reassignments, reassignment)
+			change.Reassigned = append(change.Reassigned, reassignment)
+			cache.planned[newUserID]++
+			addedToPR[pr.ID] = append(addedToPR[pr.ID], newUserID)
+		}
+	}
+
+	return reassignments, nil
+}
+
+// planReplacement: пустой user_id означает, что замены нет.
+func (s *teamService) planReplacement(
+	ctx context.Context,
+	cache *planCache,
+	teamName string,
+	exclude []string,
+) (string, *models.ReviewerFallback, error) {
+	const op = "teamService.planReplacement"
+
+	fallbacks, ok := cache.fallbacks[teamName]
+	if !ok {
+		var err error
+		fallbacks, err = s.repo.GetTeamFallbacks(ctx, teamName)
+		if err != nil {
+			return "", nil, errors.WrapError(op, err)
+		}
+		cache.fallbacks[teamName] = fallbacks
+	}
+	sources := append([]models.ReviewerFallback{{Kind: models.FallbackTeam, Name: teamName}}, fallbacks...)
+
+	for i, source := range sources {
+		candidates, ok := cache.candidates[source]
+		if !ok {
+			var err error
+			candidates, err = fallbackCandidates(ctx, s.repo, source)
+			if err != nil {
+				return "", nil, errors.WrapError(op, err)
+			}
+			cache.candidates[source] = candidates
+		}
+
+		available := excludeCandidates(candidates, exclude)
+		for j := range available {
+			available[j].OpenReviews += cache.planned[available[j].UserID]
+		}
+
+		selected, _ := s.selectors.Select(fallbackSelectorKey(teamName, source), available, 1)
+		if len(selected) == 0 {
+			continue
+		}
+		if i == 0 {
+			return selected[0], nil, nil
+		}
+		return selected[0], &source, nil
+	}
+
+	return "", nil, nil
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+type mergeInput struct {
+	pr *models.PullRequest
+	// owners - владельцы изменённых файлов без автора PR
+	owners []string
+	policy models.MergePolicy
+}
+
+// mergeRule: выключенное в политике правило возвращает nil.
+type mergeRule func(in *mergeInput) *models.MergeCheck
+
+var mergeRules = []mergeRule{
+	checkOpen,
+	checkMinApprovals,
+	checkNoChangesRequested,
+	checkNoSelfApproval,
+	checkOwnerApproved,
+}
+
+func (s *prService) mergeInput(ctx context.Context, pr *models.PullRequest) (*mergeInput, error) {
+	const op = "prService.mergeInput"
+
+	author, err := s.repo.GetUserByID(ctx, pr.AuthorID)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	team, err := s.repo.GetTeamByName(ctx, author.TeamName)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	in := &mergeInput{
+		pr:     pr,
+		policy: team.MergePolicy,
+	}
+	if in.policy.RequireOwnerApproval {
+		owners, _, err := s.resolveOwners(ctx, author.TeamName, pr.ChangedFiles)
+		if err != nil {
+			return nil, errors.WrapError(op, err)
+		}
+		in.owners = slices.DeleteFunc(owners, func(userID string) bool { return userID == pr.AuthorID })
+	}
+
+	return in, nil
+}
+
+func evaluateRules(in *mergeInput) *models.Mergeability {
+	result := &models.Mergeability{
+		PullRequestID: in.pr.ID,
+		Mergeable:     true,
+	}
+	for _, rule := range mergeRules {
+		check := rule(in)
+		if check == nil {
+			continue
+		}
+		result.Checks = append(result.Checks, *check)
+		if !check.Passed {
+			result.Mergeable = false
+		}
+	}
+
+	return result
+}
+
+func mergeBlockedError(result *models.Mergeability) error {
+	var failed []string
+	for _, check := range result.Checks {
+		if !check.Passed {
+			failed = append(failed, check.Rule)
+		}
+	}
+	return fmt.Errorf("%w: %s", errors.ErrMergeBlocked, strings.Join(failed, ", "))
+}
+
+func checkOpen(in *mergeInput) *models.MergeCheck {
+	check := &models.MergeCheck{Rule: models.MergeRuleOpen, Passed: true, Message: "pull request is open"}
+	switch {
+	case in.pr.Status == models.PRStatusClosed:
+		check.Passed, check.Message = false, "pull request is closed"
+	case in.pr.IsDraft:
+		check.Passed, check.Message = false, "pull request is a draft"
+	}
+	return check
+}
+
+func checkMinApprovals(in *mergeInput) *models.MergeCheck {
+	if in.policy.RequiredApprovals == 0 {
+		return nil
+	}
+
+	approvals := 0
+	for _, review := range in.pr.Reviews {
+		if review.State != models.ReviewStateApproved {
+			continue
+		}
+		if in.policy.ForbidSelfApproval && review.UserID == in.pr.AuthorID {
+			continue
+		}
+		approvals++
+	}
+
+	return &models.MergeCheck{
+		Rule:    models.MergeRuleMinApprovals,
+		Passed:  approvals >= in.policy.RequiredApprovals,
+		Message: fmt.Sprintf("%d of %d required approvals", approvals, in.policy.RequiredApprovals),
+	}
+}
+
+func checkNoChangesRequested(in *mergeInput) *models.MergeCheck {
+	if !in.policy.BlockOnChangesRequested {
+		return nil
+	}
+
+	var requested []string
+	for _, review := range in.pr.Reviews {
+		if review.State == models.ReviewStateChangesRequested {
+			requested = append(requested, review.UserID)
+		}
+	}
+
+	check := &models.MergeCheck{Rule: models.MergeRuleNoChangesRequested, Passed: len(requested) == 0}
+	if check.Passed {
+		check.Message = "no outstanding change requests"
+	} else {
+		check.Message = "changes requested by " + strings.Join(requested, ", ")
+	}
+	return check
+}
+
+func checkNoSelfApproval(in *mergeInput) *models.MergeCheck {
+	if !in.policy.ForbidSelfApproval {
+		return nil
+	}
+
+	for _, review := range in.pr.Reviews {
+		if review.UserID == in.pr.AuthorID && review.State == models.ReviewStateApproved {
+			return &models.MergeCheck{Rule: models.MergeRuleNoSelfApproval, Passed: false, Message: "author approved own pull request"}
+		}
+	}
+	return &models.MergeCheck{Rule: models.MergeRuleNoSelfApproval, Passed: true, Message: "author did not approve own pull request"}
+}
+
+func checkOwnerApproved(in *mergeInput) *models.MergeCheck {
+	if !in.policy.RequireOwnerApproval {
+		return nil
+	}
+
+	// Без владельцев правило не к чему применить
+	if len(in.owners) == 0 {
+		return &models.MergeCheck{Rule: models.MergeRuleOwnerApproved, Passed: true, Message: "no code owners for changed files"}
+	}
+
+	for _, review := range in.pr.Reviews {
+		if review.State == models.ReviewStateApproved && slices.Contains(in.owners, review.UserID) {
+			return &models.MergeCheck{Rule: models.MergeRuleOwnerApproved, Passed: true, Message: "approved by code owner " + review.UserID}
+		}
+	}
+	return &models.MergeCheck{Rule: models.MergeRuleOwnerApproved, Passed: false, Message: "no approval from code owners"}
+}
@@ -0,0 +1,111 @@
+package service_test
+
+import (
+	"testing"
+
+	serviceErrors "pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+// failedRules - правила, не пропускающие PR к merge.
+func failedRules(mergeability *models.Mergeability) []string {
+	var failed []string
+	for _, check := range mergeability.Checks {
+		if !check.Passed {
+			failed = append(failed, check.Rule)
+		}
+	}
+	return failed
+}
+
+func TestMergePolicy(t *testing.T) {
+	env := newEnv(t)
+	env.seedTeam(t, "backend", "u1", "u2", "u3")
+	noError(t, env.repo.SetTeamMergePolicy(t.Context(), "backend", &models.MergePolicy{
+		RequiredApprovals:       1,
+		BlockOnChangesRequested: true,
+		ForbidSelfApproval:      true,
+	}))
+	_, err := env.prs.CreatePR(t.Context(), &models.PullRequestShort{ID: "pr-1", Name: "PR", AuthorID: "u1"}, models.CreatePROptions{})
+	noError(t, err)
+
+	_, err = env.prs.MergePR(t.Context(), "pr-1")
+	wantError(t, err, serviceErrors.ErrMergeBlocked)
+	mergeability, err := env.prs.Mergeability(t.Context(), "pr-1")
+	noError(t, err)
+	equal(t, "mergeable", mergeability.Mergeable, false)
+	equalStrings(t, "failed", failedRules(mergeability), []string{models.MergeRuleMinApprovals})
+	equal(t, "approvals message", mergeability.Checks[1].Message, "0 of 1 required approvals")
+
+	// одобрения хватает, но запрошенные изменения блокируют merge
+	_, err = env.prs.SubmitReview(t.Context(), "pr-1", "u2", models.ReviewStateApproved)
+	noError(t, err)
+	_, err = env.prs.SubmitReview(t.Context(), "pr-1", "u3", models.ReviewStateChangesRequested)
+	noError(t, err)
+	_, err = env.prs.MergePR(t.Context(), "pr-1")
+	wantError(t, err, serviceErrors.ErrMergeBlocked)
+	mergeability, err = env.prs.Mergeability(t.Context(), "pr-1")
+	noError(t, err)
+	equalStrings(t, "failed after review", failedRules(mergeability), []string{models.MergeRuleNoChangesRequested})
+
+	_, err = env.prs.SubmitReview(t.Context(), "pr-1", "u3", models.ReviewStateApproved)
+	noError(t, err)
+	pr, err := env.prs.MergePR(t.Context(), "pr-1")
+	noError(t, err)
+	equal(t, "status", pr.Status, models.PRStatusMerged)
+	equal(t, "merged events", env.events.count(models.WebhookEventPRMerged), 1)
+
+	// повторный merge возвращает PR без нового события
+	pr, err = env.prs.MergePR(t.Context(), "pr-1")
+	noError(t, err)
+	equal(t, "status again", pr.Status, models.PRStatusMerged)
+	equal(t, "merged events again", env.events.count(models.WebhookEventPRMerged), 1)
+}
+
+func TestMergePolicyOwnerApproval(t *testing.T) {
+	env := newEnv(t)
+	env.seedTeam(t, "backend", "u1", "u2", "u3", "u4")
+	noError(t, env.repo.SetTeamMergePolicy(t.Context(), "backend", &models.MergePolicy{RequireOwnerApproval: true}))
+	noError(t, env.repo.SetTeamCodeOwners(t.Context(), &models.CodeOwners{
+		TeamName: "backend",
+		Content:  "*.go @u4\n",
+		Mode:     models.CodeOwnersPrefer,
+	}))
+	_, err := env.prs.CreatePR(t.Context(), &models.PullRequestShort{ID: "pr-1", Name: "PR", AuthorID: "u1"}, models.CreatePROptions{
+		ChangedFiles: []string{"main.go"},
+	})
+	noError(t, err)
+
+	// одобрение не владельца не в счёт
+	_, err = env.prs.SubmitReview(t.Context(), "pr-1", "u2", models.ReviewStateApproved)
+	noError(t, err)
+	_, err = env.prs.MergePR(t.Context(), "pr-1")
+	wantError(t, err, serviceErrors.ErrMergeBlocked)
+
+	_, err = env.prs.SubmitReview(t.Context(), "pr-1", "u4", models.ReviewStateApproved)
+	noError(t, err)
+	mergeability, err := env.prs.Mergeability(t.Context(), "pr-1")
+	noError(t, err)
+	equal(t, "mergeable", mergeability.Mergeable, true)
+	_, err = env.prs.MergePR(t.Context(), "pr-1")
+	noError(t, err)
+}
+
+func TestMergeDraftAndClosed(t *testing.T) {
+	env := newEnv(t)
+	env.seedTeam(t, "backend", "u1", "u2", "u3")
+
+	_, err := env.prs.CreatePR(t.Context(), &models.PullRequestShort{ID: "pr-draft", Name: "PR", AuthorID: "u1", IsDraft: true}, models.CreatePROptions{})
+	noError(t, err)
+	_, err = env.prs.MergePR(t.Context(), "pr-draft")
+	wantError(t, err, serviceErrors.ErrPRDraft)
+
+	_, err = env.prs.CreatePR(t.Context(), &models.PullRequestShort{ID: "pr-closed", Name: "PR", AuthorID: "u1"}, models.CreatePROptions{})
+	noError(t, err)
+	_, err = env.prs.ClosePR(t.Context(), "pr-closed")
+	noError(t, err)
+	_, err = env.prs.MergePR(t.Context(), "pr-closed")
+	wantError(t, err, serviceErrors.ErrPRClosed)
+
+	equal(t, "merged events", env.events.count(models.WebhookEventPRMerged), 0)
+}
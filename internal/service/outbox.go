@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"pr-review/internal/config"
+	"pr-review/internal/errors"
+)
+
+// Итог попытки, очередь переводит его в свои статусы
+const (
+	outboxDone = iota + 1
+	outboxPending
+	outboxFailed
+)
+
+// outbox - очередь с повторами, которую разбирает outboxWorker.
+type outbox[T any] interface {
+	// claim откладывает выданные записи на lease, чтобы их не взял другой обработчик
+	claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]T, error)
+	send(ctx context.Context, item *T) error
+	save(ctx context.Context, item *T) error
+	attempts(item *T) int
+	record(item *T, result *outboxAttempt)
+	logAttrs(item *T) []any
+}
+
+type outboxAttempt struct {
+	at time.Time
+	// nextAttemptAt задан только у статуса outboxPending
+	nextAttemptAt time.Time
+	err           error
+	attempts      int
+	status        int
+}
+
+// finalError - ошибка, которую повтор не исправит.
+type finalError struct {
+	error
+}
+
+func (e finalError) Unwrap() error {
+	return e.error
+}
+
+// outboxWorker повторяет неудачные попытки с экспоненциальной паузой, пока не исчерпано MaxAttempts.
+type outboxWorker[T any] struct {
+	logger *slog.Logger
+	queue  outbox[T]
+	name   string
+	cfg    config.OutboxConfig
+	// lease покрывает попытки всей выборки, которые выполняются по очереди
+	lease time.Duration
+}
+
+func newOutboxWorker[T any](
+	logger *slog.Logger,
+	queue outbox[T],
+	name string,
+	cfg *config.OutboxConfig,
+	perItem time.Duration,
+) *outboxWorker[T] {
+	return &outboxWorker[T]{
+		logger: logger,
+		queue:  queue,
+		name:   name,
+		cfg:    *cfg,
+		lease:  time.Duration(cfg.BatchSize+1) * perItem,
+	}
+}
+
+// runDue повторяет выборку, пока она не пуста: очередь может выдавать следующую запись только после предыдущей.
+func (w *outboxWorker[T]) runDue(ctx context.Context, now time.Time) error {
+	const op = "outboxWorker.runDue"
+
+	for {
+		items, err := w.queue.claim(ctx, now, w.lease, w.cfg.BatchSize)
+		if err != nil {
+			return errors.WrapError(op, err)
+		}
+
+		for i := range items {
+			item := &items[i]
+			w.attempt(ctx, item)
+
+			if err := w.queue.save(ctx, item); err != nil {
+				w.logger.Error("Failed to save "+w.name, append([]any{"op", op, "error", err}, w.queue.logAttrs(item)...)...)
+			}
+		}
+
+		if len(items) == 0 || ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// private methods
+
+func (w *outboxWorker[T]) attempt(ctx context.Context, item *T) {
+	const op = "outboxWorker.attempt"
+
+	result := &outboxAttempt{attempts: w.queue.attempts(item) + 1}
+	result.err = w.queue.send(ctx, item)
+	result.at = time.Now().UTC()
+
+	var final finalError
+	switch {
+	case result.err == nil:
+		result.status = outboxDone
+	case errors.As(result.err, &final) || result.attempts >= w.cfg.MaxAttempts:
+		result.status = outboxFailed
+		w.logger.Warn(w.name+" failed", append([]any{
+			"op", op,
+			"error", result.err,
+			"attempts", result.attempts,
+		}, w.queue.logAttrs(item)...)...)
+	default:
+		result.status = outboxPending
+		result.nextAttemptAt = result.at.Add(backoff(w.cfg.BackoffBase, w.cfg.BackoffMax, result.attempts))
+	}
+
+	w.queue.record(item, result)
+}
+
+func backoff(base, maxDelay time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxDelay)
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+	"pr-review/internal/server/handlers"
+)
+
+type PoolRepository interface {
+	CreateReviewerPool(ctx context.Context, pool *models.ReviewerPool) error
+	GetReviewerPool(ctx context.Context, name string) (*models.ReviewerPool, error)
+	SetReviewerPoolMembers(ctx context.Context, pool *models.ReviewerPool) error
+}
+
+type poolService struct {
+	logger *slog.Logger
+	repo   PoolRepository
+}
+
+func NewPoolService(
+	logger *slog.Logger,
+	repo PoolRepository,
+) handlers.PoolService {
+	return &poolService{
+		logger: logger,
+		repo:   repo,
+	}
+}
+
+func (s *poolService) CreatePool(ctx context.Context, pool *models.ReviewerPool) (*models.ReviewerPool, error) {
+	const op = "poolService.CreatePool"
+
+	pool.Members = uniqueStrings(pool.Members)
+	err := s.repo.CreateReviewerPool(ctx, pool)
+	if err != nil {
+		s.logger.Error("Failed to create reviewer pool", "op", op, "error", err, "poolName", pool.Name)
+		return nil, errors.WrapError(op, err)
+	}
+
+	createdPool, err := s.repo.GetReviewerPool(ctx, pool.Name)
+	if err != nil {
+		s.logger.Error("Failed to get created reviewer pool", "op", op, "error", err, "poolName", pool.Name)
+		return nil, errors.WrapError(op, err)
+	}
+
+	return createdPool, nil
+}
+
+func (s *poolService) GetPool(ctx context.Context, name string) (*models.ReviewerPool, error) {
+	const op = "poolService.GetPool"
+
+	pool, err := s.repo.GetReviewerPool(ctx, name)
+	if err != nil {
+		s.logger.Error("Failed to get reviewer pool", "op", op, "error", err, "poolName", name)
+		return nil, errors.WrapError(op, err)
+	}
+
+	return pool, nil
+}
+
+// SetMembers не меняет уже назначенные из пула ревью.
+func (s *poolService) SetMembers(ctx context.Context, pool *models.ReviewerPool) (*models.ReviewerPool, error) {
+	const op = "poolService.SetMembers"
+
+	pool.Members = uniqueStrings(pool.Members)
+	err := s.repo.SetReviewerPoolMembers(ctx, pool)
+	if err != nil {
+		s.logger.Error("Failed to set reviewer pool members", "op", op, "error", err, "poolName", pool.Name)
+		return nil, errors.WrapError(op, err)
+	}
+
+	updatedPool, err := s.repo.GetReviewerPool(ctx, pool.Name)
+	if err != nil {
+		s.logger.Error("Failed to get updated reviewer pool", "op", op, "error", err, "poolName", pool.Name)
+		return nil, errors.WrapError(op, err)
+	}
+
+	return updatedPool, nil
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"log/slog"
+	"slices"
 	"time"
 
 	"pr-review/internal/errors"
@@ -11,31 +12,91 @@ import (
 )
 
 type PRRepository interface {
-	CreatePR(ctx context.Context, pr *models.PullRequestShort) error
+	CreatePR(ctx context.Context, pr *models.PullRequest) error
 	GetPRByID(ctx context.Context, id string) (*models.PullRequest, error)
-	MergePR(ctx context.Context, prID string, mergedAt time.Time) error
-	ReassignReviewer(ctx context.Context, prID, oldUserID string) (*string, error)
+	// guard вызывается в той же транзакции с PR, который до конца merge никто не изменит
+	MergePR(ctx context.Context, prID string, mergedAt time.Time, guard func(pr *models.PullRequest) error) error
+	ClosePR(ctx context.Context, prID string, closedAt time.Time) error
+	MarkReadyForReview(ctx context.Context, prID string, reviewers []string) error
+	MarkDraft(ctx context.Context, prID string) error
+	ReopenPR(ctx context.Context, prID string, removed, added []string) error
+	ReassignReviewer(ctx context.Context, prID, oldUserID, newUserID, reason string) error
+	GetPREvents(ctx context.Context, prID string) ([]models.PREvent, error)
+	SubmitReview(ctx context.Context, prID, userID string, state models.ReviewState, reviewedAt time.Time) error
+	GetUserByID(ctx context.Context, id string) (*models.User, error)
+	GetTeamByName(ctx context.Context, name string) (*models.Team, error)
+	GetReviewerCandidates(ctx context.Context, teamName string) ([]models.ReviewerCandidate, error)
+	GetReviewerCandidatesByIDs(ctx context.Context, userIDs []string) ([]models.ReviewerCandidate, error)
+	GetTeamFallbacks(ctx context.Context, teamName string) ([]models.ReviewerFallback, error)
+	GetReviewerPool(ctx context.Context, name string) (*models.ReviewerPool, error)
+	GetTeamCodeOwners(ctx context.Context, teamName string) (*models.CodeOwners, error)
 }
 
 type prService struct {
-	logger *slog.Logger
-	repo   PRRepository
+	logger    *slog.Logger
+	repo      PRRepository
+	selectors *TeamSelectors
+	events    EventPublisher
 }
 
 func NewPRService(
 	logger *slog.Logger,
 	repo PRRepository,
+	selectors *TeamSelectors,
+	events EventPublisher,
 ) handlers.PRService {
 	return &prService{
-		logger: logger,
-		repo:   repo,
+		logger:    logger,
+		repo:      repo,
+		selectors: selectors,
+		events:    events,
 	}
 }
 
-func (s *prService) CreatePR(ctx context.Context, pr *models.PullRequestShort) (*models.PullRequest, error) {
+func (s *prService) CreatePR(ctx context.Context, pr *models.PullRequestShort, opts models.CreatePROptions) (*models.PullRequest, error) {
 	const op = "prService.CreatePR"
 
-	err := s.repo.CreatePR(ctx, pr)
+	author, err := s.repo.GetUserByID(ctx, pr.AuthorID)
+	if err != nil {
+		s.logger.Error("Failed to get PR author", "op", op, "error", err, "prID", pr.ID, "authorID", pr.AuthorID)
+		return nil, errors.WrapError(op, err)
+	}
+
+	team, err := s.repo.GetTeamByName(ctx, author.TeamName)
+	if err != nil {
+		s.logger.Error("Failed to get author team", "op", op, "error", err, "prID", pr.ID, "teamName", author.TeamName)
+		return nil, errors.WrapError(op, err)
+	}
+	// у архивной команды нет активных участников, PR её авторов не заводятся
+	if team.ArchivedAt != nil {
+		return nil, errors.WrapError(op, errors.ErrTeamArchived)
+	}
+
+	reviewersCount, err := resolveReviewersCount(team, opts.ReviewersCount)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	newPR := &models.PullRequest{
+		PullRequestShort: *pr,
+		ReviewersCount:   opts.ReviewersCount,
+		Provider:         opts.Provider,
+		ChangedFiles:     uniqueStrings(opts.ChangedFiles),
+	}
+
+	// Черновику ревьюверы назначаются позже, в ReadyForReview
+	pick := &reviewerPick{}
+	err = retryAssignment(func() error {
+		if !pr.IsDraft {
+			pick, err = s.pickReviewers(ctx, author, newPR.ChangedFiles, nil, reviewersCount)
+			if err != nil {
+				s.logger.Error("Failed to select reviewers", "op", op, "error", err, "prID", pr.ID)
+				return err
+			}
+			newPR.AssignedReviewers = pick.selected
+		}
+		return s.repo.CreatePR(ctx, newPR)
+	})
 	if err != nil {
 		s.logger.Error("Failed to create PR", "op", op, "error", err, "prID", pr.ID)
 		return nil, errors.WrapError(op, err)
@@ -46,6 +107,11 @@ func (s *prService) CreatePR(ctx context.Context, pr *models.PullRequestShort) (
 		s.logger.Error("Failed to get created PR", "op", op, "error", err, "prID", pr.ID)
 		return nil, errors.WrapError(op, err)
 	}
+	createdPR.SkippedAtCapacity = pick.atCapacity
+	createdPR.FallbackReviewers = pick.fallback
+
+	s.events.Publish(ctx, models.WebhookEventPRCreated, PREventData{PullRequest: eventPR(createdPR)})
+	publishAssigned(ctx, s.events, createdPR, createdPR.AssignedReviewers)
 
 	return createdPR, nil
 }
@@ -53,7 +119,42 @@ func (s *prService) CreatePR(ctx context.Context, pr *models.PullRequestShort) (
 func (s *prService) MergePR(ctx context.Context, prID string) (*models.PullRequest, error) {
 	const op = "prService.MergePR"
 
-	err := s.repo.MergePR(ctx, prID, time.Now())
+	pr, err := s.repo.GetPRByID(ctx, prID)
+	if err != nil {
+		s.logger.Error("Failed to get PR", "op", op, "error", err, "prID", prID)
+		return nil, errors.WrapError(op, err)
+	}
+	switch pr.Status {
+	case models.PRStatusMerged:
+		return pr, nil
+	case models.PRStatusClosed:
+		return nil, errors.WrapError(op, errors.ErrPRClosed)
+	}
+	if pr.IsDraft {
+		return nil, errors.WrapError(op, errors.ErrPRDraft)
+	}
+
+	in, err := s.mergeInput(ctx, pr)
+	if err != nil {
+		s.logger.Error("Failed to evaluate merge policy", "op", op, "error", err, "prID", prID)
+		return nil, errors.WrapError(op, err)
+	}
+
+	// Вердикты могли измениться после чтения PR, поэтому политика проверяется
+	// ещё раз по PR, заблокированному на время merge
+	err = s.repo.MergePR(ctx, prID, time.Now(), func(locked *models.PullRequest) error {
+		if locked.IsDraft {
+			return errors.ErrPRDraft
+		}
+		in.pr = locked
+		if mergeability := evaluateRules(in); !mergeability.Mergeable {
+			return mergeBlockedError(mergeability)
+		}
+		return nil
+	})
+	if errors.Is(err, errors.ErrPRDraft) || errors.Is(err, errors.ErrMergeBlocked) {
+		return nil, errors.WrapError(op, err)
+	}
 	if err != nil {
 		s.logger.Error("Failed to merge PR", "op", op, "error", err, "prID", prID)
 		return nil, errors.WrapError(op, err)
@@ -65,13 +166,381 @@ func (s *prService) MergePR(ctx context.Context, prID string) (*models.PullReque
 		return nil, errors.WrapError(op, err)
 	}
 
+	s.events.Publish(ctx, models.WebhookEventPRMerged, PREventData{PullRequest: eventPR(mergedPR)})
+
 	return mergedPR, nil
 }
 
-func (s *prService) ReassignReviewer(ctx context.Context, prID, oldUserID string) (*models.PullRequest, *string, error) {
+// RecordMerge не проверяет политику merge и черновик: merge уже сделан на код-хостинге.
+func (s *prService) RecordMerge(ctx context.Context, prID string) (*models.PullRequest, error) {
+	const op = "prService.RecordMerge"
+
+	pr, err := s.repo.GetPRByID(ctx, prID)
+	if err != nil {
+		s.logger.Error("Failed to get PR", "op", op, "error", err, "prID", prID)
+		return nil, errors.WrapError(op, err)
+	}
+	if pr.Status == models.PRStatusMerged {
+		return pr, nil
+	}
+
+	err = s.repo.MergePR(ctx, prID, time.Now(), nil)
+	if err != nil {
+		s.logger.Error("Failed to record merge", "op", op, "error", err, "prID", prID)
+		return nil, errors.WrapError(op, err)
+	}
+
+	mergedPR, err := s.repo.GetPRByID(ctx, prID)
+	if err != nil {
+		s.logger.Error("Failed to get merged PR", "op", op, "error", err, "prID", prID)
+		return nil, errors.WrapError(op, err)
+	}
+
+	s.events.Publish(ctx, models.WebhookEventPRMerged, PREventData{PullRequest: eventPR(mergedPR)})
+
+	return mergedPR, nil
+}
+
+func (s *prService) Mergeability(ctx context.Context, prID string) (*models.Mergeability, error) {
+	const op = "prService.Mergeability"
+
+	pr, err := s.repo.GetPRByID(ctx, prID)
+	if err != nil {
+		s.logger.Error("Failed to get PR", "op", op, "error", err, "prID", prID)
+		return nil, errors.WrapError(op, err)
+	}
+	if pr.Status == models.PRStatusMerged {
+		return nil, errors.WrapError(op, errors.ErrPRMerged)
+	}
+
+	in, err := s.mergeInput(ctx, pr)
+	if err != nil {
+		s.logger.Error("Failed to evaluate merge policy", "op", op, "error", err, "prID", prID)
+		return nil, errors.WrapError(op, err)
+	}
+
+	return evaluateRules(in), nil
+}
+
+func (s *prService) Timeline(ctx context.Context, prID string) ([]models.PREvent, error) {
+	const op = "prService.Timeline"
+
+	events, err := s.repo.GetPREvents(ctx, prID)
+	if err != nil {
+		s.logger.Error("Failed to get PR events", "op", op, "error", err, "prID", prID)
+		return nil, errors.WrapError(op, err)
+	}
+
+	return events, nil
+}
+
+func (s *prService) ReadyForReview(ctx context.Context, prID string) (*models.PullRequest, error) {
+	const op = "prService.ReadyForReview"
+
+	pr, err := s.repo.GetPRByID(ctx, prID)
+	if err != nil {
+		s.logger.Error("Failed to get PR", "op", op, "error", err, "prID", prID)
+		return nil, errors.WrapError(op, err)
+	}
+	switch pr.Status {
+	case models.PRStatusMerged:
+		return nil, errors.WrapError(op, errors.ErrPRMerged)
+	case models.PRStatusClosed:
+		return nil, errors.WrapError(op, errors.ErrPRClosed)
+	}
+	if !pr.IsDraft {
+		return pr, nil
+	}
+
+	author, err := s.repo.GetUserByID(ctx, pr.AuthorID)
+	if err != nil {
+		s.logger.Error("Failed to get PR author", "op", op, "error", err, "prID", prID, "authorID", pr.AuthorID)
+		return nil, errors.WrapError(op, err)
+	}
+
+	team, err := s.repo.GetTeamByName(ctx, author.TeamName)
+	if err != nil {
+		s.logger.Error("Failed to get author team", "op", op, "error", err, "prID", prID, "teamName", author.TeamName)
+		return nil, errors.WrapError(op, err)
+	}
+	if team.ArchivedAt != nil {
+		return nil, errors.WrapError(op, errors.ErrTeamArchived)
+	}
+
+	// Политика команды могла измениться, пока PR был черновиком
+	reviewersCount, err := resolveReviewersCount(team, pr.ReviewersCount)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	// Ревьюверы, назначенные до возврата PR в черновики, остаются, добираются недостающие
+	missing := max(reviewersCount-len(pr.AssignedReviewers), 0)
+
+	var pick *reviewerPick
+	err = retryAssignment(func() error {
+		pick, err = s.pickReviewers(ctx, author, pr.ChangedFiles, pr.AssignedReviewers, missing)
+		if err != nil {
+			s.logger.Error("Failed to select reviewers", "op", op, "error", err, "prID", prID)
+			return err
+		}
+		return s.repo.MarkReadyForReview(ctx, prID, pick.selected)
+	})
+	if err != nil {
+		s.logger.Error("Failed to mark PR ready for review", "op", op, "error", err, "prID", prID)
+		return nil, errors.WrapError(op, err)
+	}
+
+	readyPR, err := s.repo.GetPRByID(ctx, prID)
+	if err != nil {
+		s.logger.Error("Failed to get ready PR", "op", op, "error", err, "prID", prID)
+		return nil, errors.WrapError(op, err)
+	}
+	readyPR.SkippedAtCapacity = pick.atCapacity
+	readyPR.FallbackReviewers = pick.fallback
+
+	publishAssigned(ctx, s.events, readyPR, pick.selected)
+
+	return readyPR, nil
+}
+
+// ConvertToDraft оставляет назначенных ревьюверов, но их ревью не учитываются в нагрузке.
+func (s *prService) ConvertToDraft(ctx context.Context, prID string) (*models.PullRequest, error) {
+	const op = "prService.ConvertToDraft"
+
+	pr, err := s.repo.GetPRByID(ctx, prID)
+	if err != nil {
+		s.logger.Error("Failed to get PR", "op", op, "error", err, "prID", prID)
+		return nil, errors.WrapError(op, err)
+	}
+	switch pr.Status {
+	case models.PRStatusMerged:
+		return nil, errors.WrapError(op, errors.ErrPRMerged)
+	case models.PRStatusClosed:
+		return nil, errors.WrapError(op, errors.ErrPRClosed)
+	}
+	if pr.IsDraft {
+		return pr, nil
+	}
+
+	if err := s.repo.MarkDraft(ctx, prID); err != nil {
+		s.logger.Error("Failed to convert PR to draft", "op", op, "error", err, "prID", prID)
+		return nil, errors.WrapError(op, err)
+	}
+
+	draftPR, err := s.repo.GetPRByID(ctx, prID)
+	if err != nil {
+		s.logger.Error("Failed to get draft PR", "op", op, "error", err, "prID", prID)
+		return nil, errors.WrapError(op, err)
+	}
+
+	return draftPR, nil
+}
+
+// SubmitReview: повторная отправка заменяет прошлый вердикт.
+func (s *prService) SubmitReview(ctx context.Context, prID, userID string, state models.ReviewState) (*models.PullRequest, error) {
+	const op = "prService.SubmitReview"
+
+	switch state {
+	case models.ReviewStateApproved, models.ReviewStateChangesRequested, models.ReviewStateCommented:
+	default:
+		return nil, errors.WrapError(op, errors.ErrInvalidReviewState)
+	}
+
+	pr, err := s.repo.GetPRByID(ctx, prID)
+	if err != nil {
+		s.logger.Error("Failed to get PR", "op", op, "error", err, "prID", prID)
+		return nil, errors.WrapError(op, err)
+	}
+	switch pr.Status {
+	case models.PRStatusMerged:
+		return nil, errors.WrapError(op, errors.ErrPRMerged)
+	case models.PRStatusClosed:
+		return nil, errors.WrapError(op, errors.ErrPRClosed)
+	}
+
+	if _, err := s.repo.GetUserByID(ctx, userID); err != nil {
+		s.logger.Error("Failed to get reviewer", "op", op, "error", err, "userID", userID)
+		return nil, errors.WrapError(op, err)
+	}
+	if !slices.Contains(pr.AssignedReviewers, userID) {
+		return nil, errors.WrapError(op, errors.ErrNotAssigned)
+	}
+
+	err = s.repo.SubmitReview(ctx, prID, userID, state, time.Now())
+	if err != nil {
+		s.logger.Error("Failed to submit review", "op", op, "error", err, "prID", prID, "userID", userID)
+		return nil, errors.WrapError(op, err)
+	}
+
+	updatedPR, err := s.repo.GetPRByID(ctx, prID)
+	if err != nil {
+		s.logger.Error("Failed to get updated PR", "op", op, "error", err, "prID", prID)
+		return nil, errors.WrapError(op, err)
+	}
+
+	return updatedPR, nil
+}
+
+func (s *prService) ClosePR(ctx context.Context, prID string) (*models.PullRequest, error) {
+	const op = "prService.ClosePR"
+
+	pr, err := s.repo.GetPRByID(ctx, prID)
+	if err != nil {
+		s.logger.Error("Failed to get PR", "op", op, "error", err, "prID", prID)
+		return nil, errors.WrapError(op, err)
+	}
+	switch pr.Status {
+	case models.PRStatusMerged:
+		return nil, errors.WrapError(op, errors.ErrPRMerged)
+	case models.PRStatusClosed:
+		return pr, nil
+	}
+
+	err = s.repo.ClosePR(ctx, prID, time.Now())
+	if err != nil {
+		s.logger.Error("Failed to close PR", "op", op, "error", err, "prID", prID)
+		return nil, errors.WrapError(op, err)
+	}
+
+	closedPR, err := s.repo.GetPRByID(ctx, prID)
+	if err != nil {
+		s.logger.Error("Failed to get closed PR", "op", op, "error", err, "prID", prID)
+		return nil, errors.WrapError(op, err)
+	}
+
+	return closedPR, nil
+}
+
+// ReopenPR заменяет неактивных ревьюверов новыми из команды автора, если есть кого назначить.
+func (s *prService) ReopenPR(ctx context.Context, prID string) (*models.PullRequest, error) {
+	const op = "prService.ReopenPR"
+
+	pr, err := s.repo.GetPRByID(ctx, prID)
+	if err != nil {
+		s.logger.Error("Failed to get PR", "op", op, "error", err, "prID", prID)
+		return nil, errors.WrapError(op, err)
+	}
+	switch pr.Status {
+	case models.PRStatusMerged:
+		return nil, errors.WrapError(op, errors.ErrPRMerged)
+	case models.PRStatusOpen:
+		return pr, nil
+	}
+
+	var removed []string
+	for _, reviewerID := range pr.AssignedReviewers {
+		reviewer, err := s.repo.GetUserByID(ctx, reviewerID)
+		if err != nil {
+			s.logger.Error("Failed to get reviewer", "op", op, "error", err, "prID", prID, "reviewerID", reviewerID)
+			return nil, errors.WrapError(op, err)
+		}
+		if !reviewer.IsActive {
+			removed = append(removed, reviewerID)
+		}
+	}
+
+	var author *models.User
+	if len(removed) > 0 {
+		author, err = s.repo.GetUserByID(ctx, pr.AuthorID)
+		if err != nil {
+			s.logger.Error("Failed to get PR author", "op", op, "error", err, "prID", prID, "authorID", pr.AuthorID)
+			return nil, errors.WrapError(op, err)
+		}
+	}
+
+	pick := &reviewerPick{}
+	err = retryAssignment(func() error {
+		if author != nil {
+			exclude := append([]string{author.UserID}, pr.AssignedReviewers...)
+			pick, err = s.selectReviewers(ctx, author.TeamName, exclude, len(removed))
+			if err != nil {
+				s.logger.Error("Failed to select reviewers", "op", op, "error", err, "prID", prID)
+				return err
+			}
+			if err := s.checkCapacity(pick, len(removed), false); err != nil {
+				return err
+			}
+		}
+		return s.repo.ReopenPR(ctx, prID, removed, pick.selected)
+	})
+	if err != nil {
+		s.logger.Error("Failed to reopen PR", "op", op, "error", err, "prID", prID)
+		return nil, errors.WrapError(op, err)
+	}
+
+	reopenedPR, err := s.repo.GetPRByID(ctx, prID)
+	if err != nil {
+		s.logger.Error("Failed to get reopened PR", "op", op, "error", err, "prID", prID)
+		return nil, errors.WrapError(op, err)
+	}
+	reopenedPR.SkippedAtCapacity = pick.atCapacity
+	reopenedPR.FallbackReviewers = pick.fallback
+
+	publishRemoved(ctx, s.events, reopenedPR, removed, models.ReasonUnavailable)
+	publishAssigned(ctx, s.events, reopenedPR, pick.selected)
+
+	return reopenedPR, nil
+}
+
+func (s *prService) ReassignReviewer(ctx context.Context, prID, oldUserID, reason string) (*models.PullRequest, *string, error) {
 	const op = "prService.ReassignReviewer"
 
-	newUserID, err := s.repo.ReassignReviewer(ctx, prID, oldUserID)
+	pr, err := s.repo.GetPRByID(ctx, prID)
+	if err != nil {
+		s.logger.Error("Failed to get PR", "op", op, "error", err, "prID", prID)
+		return nil, nil, errors.WrapError(op, err)
+	}
+	switch pr.Status {
+	case models.PRStatusMerged:
+		return nil, nil, errors.WrapError(op, errors.ErrPRMerged)
+	case models.PRStatusClosed:
+		return nil, nil, errors.WrapError(op, errors.ErrPRClosed)
+	}
+
+	if _, err := s.repo.GetUserByID(ctx, oldUserID); err != nil {
+		s.logger.Error("Failed to get reviewer", "op", op, "error", err, "oldUserID", oldUserID)
+		return nil, nil, errors.WrapError(op, err)
+	}
+	if !slices.Contains(pr.AssignedReviewers, oldUserID) {
+		return nil, nil, errors.WrapError(op, errors.ErrNotAssigned)
+	}
+
+	author, err := s.repo.GetUserByID(ctx, pr.AuthorID)
+	if err != nil {
+		s.logger.Error("Failed to get PR author", "op", op, "error", err, "prID", prID, "authorID", pr.AuthorID)
+		return nil, nil, errors.WrapError(op, err)
+	}
+
+	// Выбранного могли назначить на этот PR параллельно, поэтому при повторе
+	// исключаются ревьюверы, назначенные на момент подбора
+	var (
+		pick      *reviewerPick
+		newUserID string
+	)
+	err = retryAssignment(func() error {
+		pick, err = s.selectReviewers(ctx, author.TeamName, append([]string{author.UserID}, pr.AssignedReviewers...), 1)
+		if err != nil {
+			s.logger.Error("Failed to select reviewer", "op", op, "error", err, "prID", prID)
+			return err
+		}
+		if err := s.checkCapacity(pick, 1, true); err != nil {
+			return err
+		}
+		if len(pick.selected) == 0 {
+			return errors.ErrNoCandidate
+		}
+		newUserID = pick.selected[0]
+
+		err = s.repo.ReassignReviewer(ctx, prID, oldUserID, newUserID, reason)
+		if errors.Is(err, errors.ErrReviewerUnavailable) {
+			pr, err = s.repo.GetPRByID(ctx, prID)
+			if err != nil {
+				return err
+			}
+			return errors.ErrReviewerUnavailable
+		}
+		return err
+	})
 	if err != nil {
 		s.logger.Error("Failed to reassign reviewer", "op", op, "error", err, "prID", prID, "oldUserID", oldUserID)
 		return nil, nil, errors.WrapError(op, err)
@@ -82,6 +551,89 @@ func (s *prService) ReassignReviewer(ctx context.Context, prID, oldUserID string
 		s.logger.Error("Failed to get updated PR", "op", op, "error", err, "prID", prID)
 		return nil, nil, errors.WrapError(op, err)
 	}
+	updatedPR.FallbackReviewers = pick.fallback
+
+	publishReassigned(ctx, s.events, updatedPR, oldUserID, newUserID, reason)
 
-	return updatedPR, newUserID, nil
+	return updatedPR, &newUserID, nil
+}
+
+// selectReviewers добирает недостающих из запасных источников команды по порядку.
+func (s *prService) selectReviewers(ctx context.Context, teamName string, exclude []string, count int) (*reviewerPick, error) {
+	const op = "prService.selectReviewers"
+
+	candidates, err := teamCandidates(ctx, s.repo, teamName)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	candidates = excludeCandidates(candidates, exclude)
+
+	pick := &reviewerPick{}
+	pick.selected, pick.atCapacity = s.selectors.Select(teamName, candidates, count)
+	if len(pick.selected) >= count {
+		return pick, nil
+	}
+
+	fallbacks, err := s.repo.GetTeamFallbacks(ctx, teamName)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	for _, fallback := range fallbacks {
+		if len(pick.selected) >= count {
+			break
+		}
+
+		candidates, err := fallbackCandidates(ctx, s.repo, fallback)
+		if err != nil {
+			return nil, errors.WrapError(op, err)
+		}
+		candidates = excludeCandidates(candidates, append(slices.Clone(exclude), pick.selected...))
+
+		selected, atCapacity := s.selectors.Select(fallbackSelectorKey(teamName, fallback), candidates, count-len(pick.selected))
+		for _, userID := range selected {
+			pick.fallback = append(pick.fallback, models.FallbackReviewer{UserID: userID, ReviewerFallback: fallback})
+		}
+		pick.selected = append(pick.selected, selected...)
+		pick.atCapacity = append(pick.atCapacity, atCapacity...)
+	}
+	pick.atCapacity = uniqueStrings(pick.atCapacity)
+
+	return pick, nil
+}
+
+// checkCapacity: замену ревьювера (required) нельзя назначить частично, поэтому для неё
+// нехватка - всегда ошибка.
+func (s *prService) checkCapacity(pick *reviewerPick, count int, required bool) error {
+	if len(pick.selected) >= count || len(pick.atCapacity) == 0 {
+		return nil
+	}
+	if required || s.selectors.FailAtCapacity() {
+		return errors.ErrReviewersAtCapacity
+	}
+	return nil
+}
+
+const maxAssignAttempts = 3
+
+// retryAssignment повторяет подбор, пока хранилище отклоняет выбранных как недоступных.
+func retryAssignment(assign func() error) error {
+	var err error
+	for range maxAssignAttempts {
+		err = assign()
+		if !errors.Is(err, errors.ErrReviewerUnavailable) {
+			return err
+		}
+	}
+	return err
+}
+
+// resolveReviewersCount: можно попросить больше ревьюверов, чем требует команда, но не меньше.
+func resolveReviewersCount(team *models.Team, override *int) (int, error) {
+	if override == nil {
+		return team.RequiredReviewers, nil
+	}
+	if *override < team.RequiredReviewers || *override > models.MaxReviewersCount {
+		return 0, errors.ErrInvalidReviewersCount
+	}
+	return *override, nil
 }
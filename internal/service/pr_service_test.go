@@ -0,0 +1,229 @@
+package service_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	serviceErrors "pr-review/internal/errors"
+	"pr-review/internal/models"
+	"pr-review/internal/service"
+)
+
+// archive архивирует команду и снова активирует reactivated, как если бы
+// администратор вернул пользователя, не переводя его в другую команду.
+func (e *testEnv) archive(t *testing.T, teamName string, reactivated ...string) {
+	t.Helper()
+
+	noError(t, e.repo.ArchiveTeam(t.Context(), teamName, time.Now(), nil))
+	for _, userID := range reactivated {
+		noError(t, e.repo.SetUserActive(t.Context(), userID, true))
+	}
+}
+
+func TestCreatePRArchivedTeam(t *testing.T) {
+	env := newEnv(t)
+	env.seedTeam(t, "legacy", "l1", "l2")
+	env.archive(t, "legacy", "l1", "l2")
+
+	_, err := env.prs.CreatePR(t.Context(), &models.PullRequestShort{ID: "pr-1", Name: "PR", AuthorID: "l1"}, models.CreatePROptions{})
+	wantError(t, err, serviceErrors.ErrTeamArchived)
+	_, err = env.repo.GetPRByID(t.Context(), "pr-1")
+	wantError(t, err, serviceErrors.ErrPRNotFound)
+}
+
+func TestReadyForReviewArchivedTeam(t *testing.T) {
+	env := newEnv(t)
+	env.seedTeam(t, "legacy", "l1", "l2")
+	_, err := env.prs.CreatePR(t.Context(), &models.PullRequestShort{ID: "pr-1", Name: "PR", AuthorID: "l1", IsDraft: true}, models.CreatePROptions{})
+	noError(t, err)
+	env.archive(t, "legacy", "l2")
+
+	_, err = env.prs.ReadyForReview(t.Context(), "pr-1")
+	wantError(t, err, serviceErrors.ErrTeamArchived)
+	equal(t, "draft", env.getPR(t, "pr-1").IsDraft, true)
+}
+
+func TestFallbackSkipsArchivedTeam(t *testing.T) {
+	env := newEnv(t)
+	env.seedTeam(t, "backend", "u1")
+	env.seedTeam(t, "legacy", "l1")
+	env.seedTeam(t, "platform", "p1")
+	noError(t, env.repo.SetTeamFallbacks(t.Context(), "backend", []models.ReviewerFallback{
+		{Kind: models.FallbackTeam, Name: "legacy"},
+		{Kind: models.FallbackTeam, Name: "platform"},
+	}))
+	env.archive(t, "legacy", "l1")
+
+	pr, err := env.prs.CreatePR(t.Context(), &models.PullRequestShort{ID: "pr-1", Name: "PR", AuthorID: "u1"}, models.CreatePROptions{})
+	noError(t, err)
+	equalStrings(t, "reviewers", pr.AssignedReviewers, []string{"p1"})
+}
+
+func TestCreatePRRoundRobin(t *testing.T) {
+	env := newEnv(t)
+	env.seedTeam(t, "backend", "u1", "u2", "u3", "u4")
+
+	// очередь команды общая для всех авторов и продолжается после последнего назначенного
+	want := []struct {
+		id, author string
+		reviewers  []string
+	}{
+		{id: "pr-1", author: "u1", reviewers: []string{"u2", "u3"}},
+		{id: "pr-2", author: "u1", reviewers: []string{"u2", "u4"}},
+		{id: "pr-3", author: "u2", reviewers: []string{"u3", "u4"}},
+	}
+	for _, w := range want {
+		pr, err := env.prs.CreatePR(t.Context(), &models.PullRequestShort{ID: w.id, Name: "PR", AuthorID: w.author}, models.CreatePROptions{})
+		noError(t, err)
+		equalStrings(t, w.id+" reviewers", pr.AssignedReviewers, w.reviewers)
+		equal(t, w.id+" status", pr.Status, models.PRStatusOpen)
+	}
+	equal(t, "created events", env.events.count(models.WebhookEventPRCreated), 3)
+	equal(t, "assigned events", env.events.count(models.WebhookEventReviewerAssigned), 6)
+
+	// неактивные не назначаются
+	noError(t, env.repo.SetUserActive(t.Context(), "u2", false))
+	pr, err := env.prs.CreatePR(t.Context(), &models.PullRequestShort{ID: "pr-4", Name: "PR", AuthorID: "u1"}, models.CreatePROptions{})
+	noError(t, err)
+	equalStrings(t, "without inactive", pr.AssignedReviewers, []string{"u3", "u4"})
+
+	_, err = env.prs.CreatePR(t.Context(), &models.PullRequestShort{ID: "pr-1", Name: "PR", AuthorID: "u1"}, models.CreatePROptions{})
+	wantError(t, err, serviceErrors.ErrPRExists)
+}
+
+// Ревьюверы, назначенные до возврата в черновики, остаются, при готовности добирается недостающий.
+func TestConvertToDraft(t *testing.T) {
+	env := newEnv(t)
+	env.seedTeam(t, "backend", "u1", "u2")
+
+	_, err := env.prs.CreatePR(t.Context(), &models.PullRequestShort{ID: "pr-1", Name: "PR", AuthorID: "u1"}, models.CreatePROptions{})
+	noError(t, err)
+
+	pr, err := env.prs.ConvertToDraft(t.Context(), "pr-1")
+	noError(t, err)
+	equal(t, "draft", pr.IsDraft, true)
+	equalStrings(t, "draft reviewers", pr.AssignedReviewers, []string{"u2"})
+
+	_, err = env.teams.AddMember(t.Context(), "backend", &models.TeamMember{UserID: "u3", Username: "u3-name", IsActive: true})
+	noError(t, err)
+
+	pr, err = env.prs.ReadyForReview(t.Context(), "pr-1")
+	noError(t, err)
+	equal(t, "ready", pr.IsDraft, false)
+	equalStrings(t, "ready reviewers", pr.AssignedReviewers, []string{"u2", "u3"})
+	equal(t, "assigned events", env.events.count(models.WebhookEventReviewerAssigned), 2)
+
+	_, err = env.prs.ClosePR(t.Context(), "pr-1")
+	noError(t, err)
+	_, err = env.prs.ConvertToDraft(t.Context(), "pr-1")
+	wantError(t, err, serviceErrors.ErrPRClosed)
+}
+
+func TestCreatePRFewerCandidates(t *testing.T) {
+	env := newEnv(t)
+	env.seedTeam(t, "backend", "u1", "u2")
+
+	pr, err := env.prs.CreatePR(t.Context(), &models.PullRequestShort{ID: "pr-1", Name: "PR", AuthorID: "u1"}, models.CreatePROptions{})
+	noError(t, err)
+	equalStrings(t, "reviewers", pr.AssignedReviewers, []string{"u2"})
+
+	// лимит открытых ревью: при assign-fewer PR создаётся без упёршихся в него
+	limit := 1
+	noError(t, env.repo.SetUserMaxOpenReviews(t.Context(), "u2", &limit))
+	pr, err = env.prs.CreatePR(t.Context(), &models.PullRequestShort{ID: "pr-2", Name: "PR", AuthorID: "u1"}, models.CreatePROptions{})
+	noError(t, err)
+	equal(t, "reviewers at capacity", len(pr.AssignedReviewers), 0)
+	equalStrings(t, "skipped", pr.SkippedAtCapacity, []string{"u2"})
+}
+
+func TestCreatePRCodeOwnersFirst(t *testing.T) {
+	env := newEnv(t)
+	env.seedTeam(t, "backend", "u1", "u2", "u3", "u4")
+	noError(t, env.repo.SetTeamCodeOwners(t.Context(), &models.CodeOwners{
+		TeamName: "backend",
+		Content:  "*.sql @u2\n",
+		Mode:     models.CodeOwnersPrefer,
+	}))
+
+	pr, err := env.prs.CreatePR(t.Context(), &models.PullRequestShort{ID: "pr-1", Name: "PR", AuthorID: "u1"}, models.CreatePROptions{})
+	noError(t, err)
+	equalStrings(t, "reviewers", pr.AssignedReviewers, []string{"u2", "u3"})
+
+	// владелец назначается первым, а очередь команды продолжается после u3,
+	// а не после выбранного из владельцев u2
+	pr, err = env.prs.CreatePR(t.Context(), &models.PullRequestShort{ID: "pr-2", Name: "PR", AuthorID: "u1"}, models.CreatePROptions{
+		ChangedFiles: []string{"migrations/001_initial_schema.up.sql", "main.go"},
+	})
+	noError(t, err)
+	equalStrings(t, "owner first", pr.AssignedReviewers, []string{"u2", "u4"})
+}
+
+func TestReassignReviewer(t *testing.T) {
+	env := newEnv(t)
+	env.seedTeam(t, "backend", "u1", "u2", "u3", "u4")
+	_, err := env.prs.CreatePR(t.Context(), &models.PullRequestShort{ID: "pr-1", Name: "PR", AuthorID: "u1"}, models.CreatePROptions{})
+	noError(t, err)
+
+	// замена выбирается среди тех, кто ещё не назначен, и не автор
+	pr, newUserID, err := env.prs.ReassignReviewer(t.Context(), "pr-1", "u2", models.ReasonManual)
+	noError(t, err)
+	equal(t, "new reviewer", *newUserID, "u4")
+	equal(t, "reviewers", len(pr.AssignedReviewers), 2)
+	equal(t, "old removed", slices.Contains(pr.AssignedReviewers, "u2"), false)
+	equal(t, "new assigned", slices.Contains(pr.AssignedReviewers, "u4"), true)
+	equal(t, "reassigned events", env.events.count(models.WebhookEventReviewerReassigned), 1)
+
+	_, _, err = env.prs.ReassignReviewer(t.Context(), "pr-1", "u2", models.ReasonManual)
+	wantError(t, err, serviceErrors.ErrNotAssigned)
+
+	_, err = env.prs.RecordMerge(t.Context(), "pr-1")
+	noError(t, err)
+	_, _, err = env.prs.ReassignReviewer(t.Context(), "pr-1", "u4", models.ReasonManual)
+	wantError(t, err, serviceErrors.ErrPRMerged)
+}
+
+func TestReassignReviewerNoCandidate(t *testing.T) {
+	env := newEnv(t)
+	env.seedTeam(t, "backend", "u1", "u2", "u3")
+	_, err := env.prs.CreatePR(t.Context(), &models.PullRequestShort{ID: "pr-1", Name: "PR", AuthorID: "u1"}, models.CreatePROptions{})
+	noError(t, err)
+
+	// в команде из трёх все, кроме автора, уже назначены
+	_, _, err = env.prs.ReassignReviewer(t.Context(), "pr-1", "u2", models.ReasonManual)
+	wantError(t, err, serviceErrors.ErrNoCandidate)
+
+	pr := env.getPR(t, "pr-1")
+	equalStrings(t, "reviewers kept", pr.AssignedReviewers, []string{"u2", "u3"})
+	equal(t, "reassigned events", env.events.count(models.WebhookEventReviewerReassigned), 0)
+}
+
+func TestReopenPRAtCapacity(t *testing.T) {
+	for _, atCapacity := range []string{service.AtCapacityAssignFewer, service.AtCapacityFail} {
+		t.Run(atCapacity, func(t *testing.T) {
+			env := newEnvAtCapacity(t, atCapacity)
+			env.seedTeam(t, "backend", "u1", "u2", "u3", "u4")
+			limit := 1
+			noError(t, env.repo.SetTeamMaxOpenReviews(t.Context(), "backend", &limit))
+
+			_, err := env.prs.CreatePR(t.Context(), &models.PullRequestShort{ID: "pr-1", Name: "PR", AuthorID: "u1"}, models.CreatePROptions{})
+			noError(t, err)
+			_, err = env.prs.ClosePR(t.Context(), "pr-1")
+			noError(t, err)
+			_, err = env.prs.CreatePR(t.Context(), &models.PullRequestShort{ID: "pr-2", Name: "PR", AuthorID: "u1"}, models.CreatePROptions{})
+			noError(t, err)
+
+			// u2 заменить некем: u4 уже упёрся в лимит
+			noError(t, env.repo.SetUserActive(t.Context(), "u2", false))
+			pr, err := env.prs.ReopenPR(t.Context(), "pr-1")
+			if atCapacity == service.AtCapacityFail {
+				wantError(t, err, serviceErrors.ErrReviewersAtCapacity)
+				equal(t, "status", env.getPR(t, "pr-1").Status, models.PRStatusClosed)
+				return
+			}
+			noError(t, err)
+			equalStrings(t, "reviewers", pr.AssignedReviewers, []string{"u3"})
+			equalStrings(t, "skipped", pr.SkippedAtCapacity, []string{"u4"})
+		})
+	}
+}
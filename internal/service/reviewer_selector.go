@@ -0,0 +1,273 @@
+package service
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"pr-review/internal/config"
+	"pr-review/internal/models"
+)
+
+const (
+	StrategyRandom      = "random"
+	StrategyRoundRobin  = "round-robin"
+	StrategyLeastLoaded = "least-loaded"
+	StrategyWeighted    = "weighted"
+
+	TieBreakRandom       = "random"
+	TieBreakLastAssigned = "last-assigned"
+
+	AtCapacityAssignFewer = "assign-fewer"
+	AtCapacityFail        = "fail"
+)
+
+// ReviewerSelector получает уже отфильтрованных кандидатов и возвращает не больше count user_id.
+type ReviewerSelector interface {
+	Select(teamName string, candidates []models.ReviewerCandidate, count int) []string
+}
+
+func NewReviewerSelector(strategy string, cfg *config.ReviewConfig) (ReviewerSelector, error) {
+	switch strategy {
+	case StrategyRandom:
+		return &randomSelector{}, nil
+	case StrategyRoundRobin:
+		return &roundRobinSelector{last: make(map[string]string)}, nil
+	case StrategyLeastLoaded:
+		switch cfg.TieBreak {
+		case TieBreakRandom, TieBreakLastAssigned:
+			return &leastLoadedSelector{tieBreak: cfg.TieBreak}, nil
+		default:
+			return nil, fmt.Errorf("unknown least-loaded tie-break %q", cfg.TieBreak)
+		}
+	case StrategyWeighted:
+		return &weightedSelector{weights: cfg.Weights}, nil
+	default:
+		return nil, fmt.Errorf("unknown reviewer selection strategy %q", strategy)
+	}
+}
+
+type TeamSelectors struct {
+	defaultSelector ReviewerSelector
+	teamSelectors   map[string]ReviewerSelector
+	atCapacity      string
+}
+
+func NewTeamSelectors(cfg *config.ReviewConfig) (*TeamSelectors, error) {
+	const op = "NewTeamSelectors"
+
+	switch cfg.AtCapacity {
+	case AtCapacityAssignFewer, AtCapacityFail:
+	default:
+		return nil, fmt.Errorf("%s: unknown at-capacity behaviour %q", op, cfg.AtCapacity)
+	}
+
+	defaultSelector, err := NewReviewerSelector(cfg.Strategy, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Команды с одинаковой стратегией делят один экземпляр селектора,
+	// состояние round-robin всё равно ведётся по имени команды.
+	byStrategy := map[string]ReviewerSelector{cfg.Strategy: defaultSelector}
+	teamSelectors := make(map[string]ReviewerSelector, len(cfg.TeamStrategies))
+	for teamName, strategy := range cfg.TeamStrategies {
+		selector, ok := byStrategy[strategy]
+		if !ok {
+			selector, err = NewReviewerSelector(strategy, cfg)
+			if err != nil {
+				return nil, fmt.Errorf("%s: team %s: %w", op, teamName, err)
+			}
+			byStrategy[strategy] = selector
+		}
+		teamSelectors[teamName] = selector
+	}
+
+	return &TeamSelectors{
+		defaultSelector: defaultSelector,
+		teamSelectors:   teamSelectors,
+		atCapacity:      cfg.AtCapacity,
+	}, nil
+}
+
+func (s *TeamSelectors) ForTeam(teamName string) ReviewerSelector {
+	if selector, ok := s.teamSelectors[teamName]; ok {
+		return selector
+	}
+	return s.defaultSelector
+}
+
+// Select возвращает вторым значением кандидатов, отброшенных из-за лимита.
+func (s *TeamSelectors) Select(teamName string, candidates []models.ReviewerCandidate, count int) ([]string, []string) {
+	return s.selectKeyed(teamName, teamName, candidates, count)
+}
+
+// SelectOwners ведёт для владельцев свою очередь round-robin, чтобы не сдвигать очередь команды.
+func (s *TeamSelectors) SelectOwners(teamName string, candidates []models.ReviewerCandidate, count int) ([]string, []string) {
+	return s.selectKeyed(teamName, "codeowners:"+teamName, candidates, count)
+}
+
+func (s *TeamSelectors) FailAtCapacity() bool {
+	return s.atCapacity == AtCapacityFail
+}
+
+func (s *TeamSelectors) selectKeyed(teamName, key string, candidates []models.ReviewerCandidate, count int) ([]string, []string) {
+	available := make([]models.ReviewerCandidate, 0, len(candidates))
+	var atCapacity []string
+	for _, c := range candidates {
+		if c.MaxOpenReviews != nil && c.OpenReviews >= *c.MaxOpenReviews {
+			atCapacity = append(atCapacity, c.UserID)
+			continue
+		}
+		available = append(available, c)
+	}
+
+	return s.ForTeam(teamName).Select(key, available, count), atCapacity
+}
+
+type randomSelector struct{}
+
+func (s *randomSelector) Select(_ string, candidates []models.ReviewerCandidate, count int) []string {
+	ids := candidateIDs(candidates)
+	rand.Shuffle(len(ids), func(i, j int) {
+		ids[i], ids[j] = ids[j], ids[i]
+	})
+
+	return ids[:min(count, len(ids))]
+}
+
+type roundRobinSelector struct {
+	last map[string]string
+	mu   sync.Mutex
+}
+
+func (s *roundRobinSelector) Select(teamName string, candidates []models.ReviewerCandidate, count int) []string {
+	ids := candidateIDs(candidates)
+	n := min(count, len(ids))
+	if n == 0 {
+		return nil
+	}
+	sort.Strings(ids)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Продолжаем с первого user_id после последнего назначенного,
+	// так очередь переживает добавление и удаление участников.
+	last := s.last[teamName]
+	start := sort.Search(len(ids), func(i int) bool { return ids[i] > last })
+
+	selected := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		selected = append(selected, ids[(start+i)%len(ids)])
+	}
+	s.last[teamName] = selected[n-1]
+
+	return selected
+}
+
+// leastLoadedSelector при равной нагрузке (last-assigned) ставит первыми тех, кому дольше
+// всего ничего не назначали.
+type leastLoadedSelector struct {
+	tieBreak string
+}
+
+func (s *leastLoadedSelector) Select(_ string, candidates []models.ReviewerCandidate, count int) []string {
+	sorted := make([]models.ReviewerCandidate, len(candidates))
+	copy(sorted, candidates)
+
+	// Перемешиваем до стабильной сортировки, чтобы при полном равенстве
+	// не выбирать всегда одних и тех же.
+	rand.Shuffle(len(sorted), func(i, j int) {
+		sorted[i], sorted[j] = sorted[j], sorted[i]
+	})
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].OpenReviews != sorted[j].OpenReviews {
+			return sorted[i].OpenReviews < sorted[j].OpenReviews
+		}
+		if s.tieBreak == TieBreakLastAssigned {
+			return assignedEarlier(sorted[i].LastAssignedAt, sorted[j].LastAssignedAt)
+		}
+		return false
+	})
+
+	return candidateIDs(sorted)[:min(count, len(sorted))]
+}
+
+type weightedSelector struct {
+	weights map[string]int
+}
+
+func (s *weightedSelector) Select(_ string, candidates []models.ReviewerCandidate, count int) []string {
+	type weighted struct {
+		userID string
+		weight int
+	}
+
+	pool := make([]weighted, 0, len(candidates))
+	total := 0
+	for _, c := range candidates {
+		weight, ok := s.weights[c.UserID]
+		if !ok {
+			weight = 1
+		}
+		// Вес 0 означает, что пользователя не назначаем вовсе
+		if weight <= 0 {
+			continue
+		}
+		pool = append(pool, weighted{userID: c.UserID, weight: weight})
+		total += weight
+	}
+
+	var selected []string
+	for len(selected) < count && len(pool) > 0 {
+		target := rand.Intn(total)
+		for i, w := range pool {
+			if target < w.weight {
+				selected = append(selected, w.userID)
+				total -= w.weight
+				pool = append(pool[:i], pool[i+1:]...)
+				break
+			}
+			target -= w.weight
+		}
+	}
+
+	return selected
+}
+
+func assignedEarlier(a, b *time.Time) bool {
+	switch {
+	case a == nil:
+		return b != nil
+	case b == nil:
+		return false
+	default:
+		return a.Before(*b)
+	}
+}
+
+func candidateIDs(candidates []models.ReviewerCandidate) []string {
+	ids := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		ids = append(ids, c.UserID)
+	}
+	return ids
+}
+
+func excludeCandidates(candidates []models.ReviewerCandidate, exclude []string) []models.ReviewerCandidate {
+	excludeMap := make(map[string]bool, len(exclude))
+	for _, userID := range exclude {
+		excludeMap[userID] = true
+	}
+
+	filtered := make([]models.ReviewerCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		if !excludeMap[c.UserID] {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
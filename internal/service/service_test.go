@@ -0,0 +1,151 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+
+	"pr-review/internal/config"
+	"pr-review/internal/database/memory"
+	"pr-review/internal/models"
+	"pr-review/internal/server/handlers"
+	"pr-review/internal/service"
+)
+
+// testEnv - сервисы PR и команд поверх пустого хранилища в памяти. events записывает
+// опубликованные события по порядку.
+type testEnv struct {
+	repo   *memory.MemoryRepository
+	prs    handlers.PRService
+	teams  handlers.TeamService
+	events *recordedEvents
+	logger *slog.Logger
+}
+
+// newEnv выбирает ревьюверов по round-robin, чтобы выбор был предсказуем.
+func newEnv(t *testing.T) *testEnv {
+	t.Helper()
+	return newEnvAtCapacity(t, service.AtCapacityAssignFewer)
+}
+
+// newEnvAtCapacity - то же, что newEnv, с заданным поведением при лимитах открытых ревью.
+func newEnvAtCapacity(t *testing.T, atCapacity string) *testEnv {
+	t.Helper()
+
+	selectors, err := service.NewTeamSelectors(&config.ReviewConfig{
+		Strategy:   service.StrategyRoundRobin,
+		TieBreak:   service.TieBreakLastAssigned,
+		AtCapacity: atCapacity,
+	})
+	noError(t, err)
+
+	env := &testEnv{
+		repo:   memory.New(),
+		events: &recordedEvents{},
+		logger: slog.New(slog.DiscardHandler),
+	}
+	env.prs = service.NewPRService(env.logger, env.repo, selectors, env.events)
+	env.teams = service.NewTeamService(env.logger, env.repo, env.prs, selectors, env.events)
+	return env
+}
+
+// seedTeam создаёт команду из активных участников.
+func (e *testEnv) seedTeam(t *testing.T, name string, userIDs ...string) {
+	t.Helper()
+
+	team := &models.Team{Name: name, RequiredReviewers: models.DefaultRequiredReviewers}
+	for _, userID := range userIDs {
+		team.Members = append(team.Members, models.TeamMember{UserID: userID, Username: userID + "-name", IsActive: true})
+	}
+	noError(t, e.repo.CreateTeam(t.Context(), team))
+}
+
+func (e *testEnv) link(t *testing.T, provider, login, userID string) {
+	t.Helper()
+
+	noError(t, e.repo.LinkUserAccount(t.Context(), &models.UserAccount{Provider: provider, Login: login, UserID: userID}))
+}
+
+func (e *testEnv) getPR(t *testing.T, id string) *models.PullRequest {
+	t.Helper()
+
+	pr, err := e.repo.GetPRByID(t.Context(), id)
+	noError(t, err)
+	return pr
+}
+
+// recordedEvents запоминает имена опубликованных событий и передаёт их в forward, если он задан.
+type recordedEvents struct {
+	forward service.EventPublisher
+	names   []string
+	mu      sync.Mutex
+}
+
+func (r *recordedEvents) Publish(ctx context.Context, event string, data any) {
+	r.mu.Lock()
+	r.names = append(r.names, event)
+	r.mu.Unlock()
+
+	if r.forward != nil {
+		r.forward.Publish(ctx, event, data)
+	}
+}
+
+func (r *recordedEvents) count(event string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := 0
+	for _, name := range r.names {
+		if name == event {
+			n++
+		}
+	}
+	return n
+}
+
+// fixture читает записанное тело доставки из testdata.
+func fixture(t *testing.T, name string) []byte {
+	t.Helper()
+
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	noError(t, err)
+	return body
+}
+
+func noError(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func wantError(t *testing.T, err, target error) {
+	t.Helper()
+
+	if !errors.Is(err, target) {
+		t.Fatalf("got error %v, want %v", err, target)
+	}
+}
+
+func equal[T comparable](t *testing.T, what string, got, want T) {
+	t.Helper()
+
+	if got != want {
+		t.Fatalf("%s: got %v, want %v", what, got, want)
+	}
+}
+
+func equalStrings(t *testing.T, what string, got, want []string) {
+	t.Helper()
+
+	if !slices.Equal(got, want) {
+		t.Fatalf("%s: got %v, want %v", what, got, want)
+	}
+}
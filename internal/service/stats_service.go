@@ -11,7 +11,7 @@ import (
 
 type StatsRepository interface {
 	GetUserByID(ctx context.Context, id string) (*models.User, error)
-	GetPRsByReviewer(ctx context.Context, userID string) ([]*models.PullRequestShort, error)
+	GetPRsByReviewer(ctx context.Context, userID string) ([]*models.ReviewAssignment, error)
 	GetPRsCntByAuthor(ctx context.Context, userID string) (int, error)
 	GetTeamByName(ctx context.Context, name string) (*models.Team, error)
 	GetPRsCntByTeam(ctx context.Context, teamName string) (int, error)
@@ -58,10 +58,12 @@ func (s *statsService) GetUserStats(ctx context.Context, userID string) (*models
 
 	for _, pr := range prs {
 		switch pr.Status {
-		case "OPEN":
+		case models.PRStatusOpen:
 			stats.OpenReviews++
-		case "MERGED":
+		case models.PRStatusMerged:
 			stats.MergedReviews++
+		case models.PRStatusClosed:
+			stats.ClosedReviews++
 		}
 	}
 
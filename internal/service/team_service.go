@@ -2,8 +2,11 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"time"
 
+	"pr-review/internal/codeowners"
 	"pr-review/internal/errors"
 	"pr-review/internal/models"
 	"pr-review/internal/server/handlers"
@@ -12,28 +15,66 @@ import (
 type TeamRepository interface {
 	CreateTeam(ctx context.Context, team *models.Team) error
 	GetTeamByName(ctx context.Context, name string) (*models.Team, error)
+	AddTeamMember(ctx context.Context, teamName string, member *models.TeamMember) error
+	RemoveTeamMember(ctx context.Context, teamName, userID string, reassignments []models.Reassignment) error
+	RenameTeam(ctx context.Context, oldName, newName string) error
+	GetOpenPRIDsByTeam(ctx context.Context, teamName string) ([]string, error)
+	TeamHasHistory(ctx context.Context, teamName string) (bool, error)
+	ArchiveTeam(ctx context.Context, teamName string, archivedAt time.Time, closePRs []string) error
+	DeleteTeam(ctx context.Context, teamName string) error
+	GetUserByID(ctx context.Context, id string) (*models.User, error)
+	GetPRsByReviewer(ctx context.Context, userID string) ([]*models.ReviewAssignment, error)
+	GetPRByID(ctx context.Context, id string) (*models.PullRequest, error)
+	GetReviewerCandidates(ctx context.Context, teamName string) ([]models.ReviewerCandidate, error)
+	GetReviewerCandidatesByIDs(ctx context.Context, userIDs []string) ([]models.ReviewerCandidate, error)
+	GetReviewerPool(ctx context.Context, name string) (*models.ReviewerPool, error)
+	GetTeamFallbacks(ctx context.Context, teamName string) ([]models.ReviewerFallback, error)
+	SetTeamFallbacks(ctx context.Context, teamName string, fallbacks []models.ReviewerFallback) error
+	DeactivateUsers(ctx context.Context, userIDs []string, reassignments []models.Reassignment) error
 	GetPRsCntByTeam(ctx context.Context, teamName string) (int, error)
 	GetAvgReviewersPerPR(ctx context.Context, teamName string) (float64, error)
+	SetTeamMergePolicy(ctx context.Context, teamName string, policy *models.MergePolicy) error
+	SetTeamMaxOpenReviews(ctx context.Context, teamName string, limit *int) error
+	SetTeamCodeOwners(ctx context.Context, codeOwners *models.CodeOwners) error
+	GetTeamCodeOwners(ctx context.Context, teamName string) (*models.CodeOwners, error)
+	SetTeamChat(ctx context.Context, chat *models.TeamChat) error
+	GetTeamChat(ctx context.Context, teamName string) (*models.TeamChat, error)
 }
 
 type teamService struct {
-	logger *slog.Logger
-	repo   TeamRepository
+	logger     *slog.Logger
+	repo       TeamRepository
+	reassigner reviewReassigner
+	selectors  *TeamSelectors
+	events     EventPublisher
 }
 
 func NewTeamService(
 	logger *slog.Logger,
 	repo TeamRepository,
+	reassigner reviewReassigner,
+	selectors *TeamSelectors,
+	events EventPublisher,
 ) handlers.TeamService {
 	return &teamService{
-		logger: logger,
-		repo:   repo,
+		logger:     logger,
+		repo:       repo,
+		reassigner: reassigner,
+		selectors:  selectors,
+		events:     events,
 	}
 }
 
 func (s *teamService) CreateTeam(ctx context.Context, team *models.Team) (*models.Team, error) {
 	const op = "teamService.CreateTeam"
 
+	if team.RequiredReviewers < 0 || team.RequiredReviewers > models.MaxReviewersCount {
+		return nil, errors.WrapError(op, errors.ErrInvalidReviewersCount)
+	}
+	if err := validateMergePolicy(team.RequiredReviewers, &team.MergePolicy); err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
 	err := s.repo.CreateTeam(ctx, team)
 	if err != nil {
 		s.logger.Error("Failed to create team", "op", op, "error", err, "teamName", team.Name)
@@ -60,3 +101,379 @@ func (s *teamService) GetTeam(ctx context.Context, teamName string) (*models.Tea
 
 	return team, nil
 }
+
+func (s *teamService) AddMember(ctx context.Context, teamName string, member *models.TeamMember) (*models.Team, error) {
+	const op = "teamService.AddMember"
+
+	team, err := s.repo.GetTeamByName(ctx, teamName)
+	if err != nil {
+		s.logger.Error("Failed to get team", "op", op, "error", err, "teamName", teamName)
+		return nil, errors.WrapError(op, err)
+	}
+	if team.ArchivedAt != nil {
+		return nil, errors.WrapError(op, errors.ErrTeamArchived)
+	}
+
+	err = s.repo.AddTeamMember(ctx, teamName, member)
+	if err != nil {
+		s.logger.Error("Failed to add team member", "op", op, "error", err, "teamName", teamName, "userID", member.UserID)
+		return nil, errors.WrapError(op, err)
+	}
+
+	team, err = s.repo.GetTeamByName(ctx, teamName)
+	if err != nil {
+		s.logger.Error("Failed to get updated team", "op", op, "error", err, "teamName", teamName)
+		return nil, errors.WrapError(op, err)
+	}
+
+	return team, nil
+}
+
+// RemoveMember записывает исключение и переназначения одной транзакцией.
+func (s *teamService) RemoveMember(ctx context.Context, teamName, userID string) (*models.MembershipChange, error) {
+	const op = "teamService.RemoveMember"
+
+	if _, err := s.repo.GetTeamByName(ctx, teamName); err != nil {
+		s.logger.Error("Failed to get team", "op", op, "error", err, "teamName", teamName)
+		return nil, errors.WrapError(op, err)
+	}
+
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		s.logger.Error("Failed to get user", "op", op, "error", err, "userID", userID)
+		return nil, errors.WrapError(op, err)
+	}
+	change := &models.MembershipChange{User: user}
+
+	var reassignments []models.Reassignment
+	err = retryAssignment(func() error {
+		change.ReassignReport = models.ReassignReport{}
+		var err error
+		reassignments, err = s.planReassignments(ctx, []*models.MembershipChange{change})
+		if err != nil {
+			s.logger.Error("Failed to plan reassignments", "op", op, "error", err, "userID", userID)
+			return err
+		}
+		return s.repo.RemoveTeamMember(ctx, teamName, userID, reassignments)
+	})
+	if err != nil {
+		s.logger.Error("Failed to remove team member", "op", op, "error", err, "teamName", teamName, "userID", userID)
+		return nil, errors.WrapError(op, err)
+	}
+
+	change.User.TeamName = ""
+	s.publishReassignments(ctx, reassignments, models.ReasonLeftTeam)
+
+	return change, nil
+}
+
+// DeactivateUsers: PR без замены остаются за прежним ревьювером.
+func (s *teamService) DeactivateUsers(ctx context.Context, teamName string, userIDs []string) ([]*models.MembershipChange, error) {
+	const op = "teamService.DeactivateUsers"
+
+	userIDs = uniqueStrings(userIDs)
+
+	if _, err := s.repo.GetTeamByName(ctx, teamName); err != nil {
+		s.logger.Error("Failed to get team", "op", op, "error", err, "teamName", teamName)
+		return nil, errors.WrapError(op, err)
+	}
+
+	changes := make([]*models.MembershipChange, 0, len(userIDs))
+	for _, userID := range userIDs {
+		user, err := s.repo.GetUserByID(ctx, userID)
+		if err != nil {
+			s.logger.Error("Failed to get user", "op", op, "error", err, "userID", userID)
+			return nil, errors.WrapError(op, err)
+		}
+		if user.TeamName != teamName {
+			return nil, errors.WrapError(op, errors.ErrNotMember)
+		}
+		changes = append(changes, &models.MembershipChange{User: user})
+	}
+
+	var reassignments []models.Reassignment
+	err := retryAssignment(func() error {
+		for _, change := range changes {
+			change.ReassignReport = models.ReassignReport{}
+		}
+		var err error
+		reassignments, err = s.planReassignments(ctx, changes)
+		if err != nil {
+			s.logger.Error("Failed to plan reassignments", "op", op, "error", err, "teamName", teamName)
+			return err
+		}
+		return s.repo.DeactivateUsers(ctx, userIDs, reassignments)
+	})
+	if err != nil {
+		s.logger.Error("Failed to deactivate users", "op", op, "error", err, "teamName", teamName)
+		return nil, errors.WrapError(op, err)
+	}
+
+	for _, change := range changes {
+		change.User.IsActive = false
+		publishDeactivated(ctx, s.events, change.User)
+	}
+	s.publishReassignments(ctx, reassignments, models.ReasonDeactivated)
+
+	return changes, nil
+}
+
+// DeleteTeam: команда с открытыми PR удаляется только с force, команда с историей архивируется.
+func (s *teamService) DeleteTeam(ctx context.Context, teamName string, force bool) (*models.TeamDeletion, error) {
+	const op = "teamService.DeleteTeam"
+
+	team, err := s.repo.GetTeamByName(ctx, teamName)
+	if err != nil {
+		s.logger.Error("Failed to get team", "op", op, "error", err, "teamName", teamName)
+		return nil, errors.WrapError(op, err)
+	}
+	if team.ArchivedAt != nil {
+		return &models.TeamDeletion{TeamName: teamName, Result: models.TeamArchived}, nil
+	}
+
+	openPRs, err := s.repo.GetOpenPRIDsByTeam(ctx, teamName)
+	if err != nil {
+		s.logger.Error("Failed to get open team PRs", "op", op, "error", err, "teamName", teamName)
+		return nil, errors.WrapError(op, err)
+	}
+	if len(openPRs) > 0 && !force {
+		return nil, errors.WrapError(op, errors.ErrTeamHasOpenPRs)
+	}
+
+	hasHistory, err := s.repo.TeamHasHistory(ctx, teamName)
+	if err != nil {
+		s.logger.Error("Failed to check team history", "op", op, "error", err, "teamName", teamName)
+		return nil, errors.WrapError(op, err)
+	}
+
+	if !hasHistory {
+		err = s.repo.DeleteTeam(ctx, teamName)
+		if err != nil {
+			s.logger.Error("Failed to delete team", "op", op, "error", err, "teamName", teamName)
+			return nil, errors.WrapError(op, err)
+		}
+		return &models.TeamDeletion{TeamName: teamName, Result: models.TeamDeleted}, nil
+	}
+
+	err = s.repo.ArchiveTeam(ctx, teamName, time.Now(), openPRs)
+	if err != nil {
+		s.logger.Error("Failed to archive team", "op", op, "error", err, "teamName", teamName)
+		return nil, errors.WrapError(op, err)
+	}
+
+	return &models.TeamDeletion{TeamName: teamName, Result: models.TeamArchived, ClosedPRs: openPRs}, nil
+}
+
+func (s *teamService) RenameTeam(ctx context.Context, oldName, newName string) (*models.Team, error) {
+	const op = "teamService.RenameTeam"
+
+	if oldName == newName {
+		return s.GetTeam(ctx, oldName)
+	}
+
+	err := s.repo.RenameTeam(ctx, oldName, newName)
+	if err != nil {
+		s.logger.Error("Failed to rename team", "op", op, "error", err, "teamName", oldName, "newTeamName", newName)
+		return nil, errors.WrapError(op, err)
+	}
+
+	team, err := s.repo.GetTeamByName(ctx, newName)
+	if err != nil {
+		s.logger.Error("Failed to get renamed team", "op", op, "error", err, "teamName", newName)
+		return nil, errors.WrapError(op, err)
+	}
+
+	return team, nil
+}
+
+// SetMergePolicy сохраняет правила, не заданные в update.
+func (s *teamService) SetMergePolicy(ctx context.Context, teamName string, update *models.MergePolicyUpdate) (*models.Team, error) {
+	const op = "teamService.SetMergePolicy"
+
+	team, err := s.repo.GetTeamByName(ctx, teamName)
+	if err != nil {
+		s.logger.Error("Failed to get team", "op", op, "error", err, "teamName", teamName)
+		return nil, errors.WrapError(op, err)
+	}
+
+	policy := team.MergePolicy
+	if update.RequiredApprovals != nil {
+		policy.RequiredApprovals = *update.RequiredApprovals
+	}
+	if update.BlockOnChangesRequested != nil {
+		policy.BlockOnChangesRequested = *update.BlockOnChangesRequested
+	}
+	if update.ForbidSelfApproval != nil {
+		policy.ForbidSelfApproval = *update.ForbidSelfApproval
+	}
+	if update.RequireOwnerApproval != nil {
+		policy.RequireOwnerApproval = *update.RequireOwnerApproval
+	}
+
+	if err := validateMergePolicy(team.RequiredReviewers, &policy); err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	err = s.repo.SetTeamMergePolicy(ctx, teamName, &policy)
+	if err != nil {
+		s.logger.Error("Failed to set merge policy", "op", op, "error", err, "teamName", teamName)
+		return nil, errors.WrapError(op, err)
+	}
+
+	team.MergePolicy = policy
+	return team, nil
+}
+
+func (s *teamService) SetMaxOpenReviews(ctx context.Context, teamName string, limit *int) (*models.Team, error) {
+	const op = "teamService.SetMaxOpenReviews"
+
+	err := s.repo.SetTeamMaxOpenReviews(ctx, teamName, limit)
+	if err != nil {
+		s.logger.Error("Failed to set team max open reviews", "op", op, "error", err, "teamName", teamName)
+		return nil, errors.WrapError(op, err)
+	}
+
+	team, err := s.repo.GetTeamByName(ctx, teamName)
+	if err != nil {
+		s.logger.Error("Failed to get updated team", "op", op, "error", err, "teamName", teamName)
+		return nil, errors.WrapError(op, err)
+	}
+
+	return team, nil
+}
+
+func (s *teamService) SetFallbacks(ctx context.Context, teamName string, fallbacks []models.ReviewerFallback) (*models.Team, error) {
+	const op = "teamService.SetFallbacks"
+
+	seen := make(map[models.ReviewerFallback]bool, len(fallbacks))
+	for _, fallback := range fallbacks {
+		switch {
+		case fallback.Kind != models.FallbackTeam && fallback.Kind != models.FallbackPool:
+			return nil, errors.WrapError(op, fmt.Errorf("%w: unknown kind %q", errors.ErrInvalidFallback, fallback.Kind))
+		case fallback.Kind == models.FallbackTeam && fallback.Name == teamName:
+			return nil, errors.WrapError(op, fmt.Errorf("%w: team cannot fall back to itself", errors.ErrInvalidFallback))
+		case seen[fallback]:
+			return nil, errors.WrapError(op, fmt.Errorf("%w: %s %q listed twice", errors.ErrInvalidFallback, fallback.Kind, fallback.Name))
+		}
+		seen[fallback] = true
+
+		var err error
+		if fallback.Kind == models.FallbackTeam {
+			_, err = s.repo.GetTeamByName(ctx, fallback.Name)
+		} else {
+			_, err = s.repo.GetReviewerPool(ctx, fallback.Name)
+		}
+		if err != nil {
+			s.logger.Error("Failed to get fallback source", "op", op, "error", err, "teamName", teamName, "fallback", fallback.Name)
+			return nil, errors.WrapError(op, err)
+		}
+	}
+
+	err := s.repo.SetTeamFallbacks(ctx, teamName, fallbacks)
+	if err != nil {
+		s.logger.Error("Failed to set team fallbacks", "op", op, "error", err, "teamName", teamName)
+		return nil, errors.WrapError(op, err)
+	}
+
+	team, err := s.repo.GetTeamByName(ctx, teamName)
+	if err != nil {
+		s.logger.Error("Failed to get updated team", "op", op, "error", err, "teamName", teamName)
+		return nil, errors.WrapError(op, err)
+	}
+
+	return team, nil
+}
+
+func (s *teamService) SetCodeOwners(ctx context.Context, codeOwners *models.CodeOwners) (*models.CodeOwners, error) {
+	const op = "teamService.SetCodeOwners"
+
+	if codeOwners.Mode != models.CodeOwnersPrefer && codeOwners.Mode != models.CodeOwnersRequire {
+		return nil, errors.WrapError(op, fmt.Errorf("%w: unknown mode %q", errors.ErrInvalidCodeOwners, codeOwners.Mode))
+	}
+	if _, err := codeowners.Parse(codeOwners.Content); err != nil {
+		return nil, errors.WrapError(op, fmt.Errorf("%w: %v", errors.ErrInvalidCodeOwners, err))
+	}
+
+	codeOwners.UpdatedAt = time.Now()
+	err := s.repo.SetTeamCodeOwners(ctx, codeOwners)
+	if err != nil {
+		s.logger.Error("Failed to set code owners", "op", op, "error", err, "teamName", codeOwners.TeamName)
+		return nil, errors.WrapError(op, err)
+	}
+
+	updated, err := s.repo.GetTeamCodeOwners(ctx, codeOwners.TeamName)
+	if err != nil {
+		s.logger.Error("Failed to get updated code owners", "op", op, "error", err, "teamName", codeOwners.TeamName)
+		return nil, errors.WrapError(op, err)
+	}
+
+	return updated, nil
+}
+
+func (s *teamService) GetCodeOwners(ctx context.Context, teamName string) (*models.CodeOwners, error) {
+	const op = "teamService.GetCodeOwners"
+
+	codeOwners, err := s.repo.GetTeamCodeOwners(ctx, teamName)
+	if err != nil {
+		s.logger.Error("Failed to get code owners", "op", op, "error", err, "teamName", teamName)
+		return nil, errors.WrapError(op, err)
+	}
+
+	return codeOwners, nil
+}
+
+func (s *teamService) SetChat(ctx context.Context, chat *models.TeamChat) (*models.TeamChat, error) {
+	const op = "teamService.SetChat"
+
+	if err := validateTeamChat(chat); err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	chat.UpdatedAt = time.Now()
+	err := s.repo.SetTeamChat(ctx, chat)
+	if err != nil {
+		s.logger.Error("Failed to set team chat", "op", op, "error", err, "teamName", chat.TeamName)
+		return nil, errors.WrapError(op, err)
+	}
+
+	updated, err := s.repo.GetTeamChat(ctx, chat.TeamName)
+	if err != nil {
+		s.logger.Error("Failed to get updated team chat", "op", op, "error", err, "teamName", chat.TeamName)
+		return nil, errors.WrapError(op, err)
+	}
+
+	return updated, nil
+}
+
+func (s *teamService) GetChat(ctx context.Context, teamName string) (*models.TeamChat, error) {
+	const op = "teamService.GetChat"
+
+	chat, err := s.repo.GetTeamChat(ctx, teamName)
+	if err != nil {
+		s.logger.Error("Failed to get team chat", "op", op, "error", err, "teamName", teamName)
+		return nil, errors.WrapError(op, err)
+	}
+
+	return chat, nil
+}
+
+// validateMergePolicy: одобрений не может требоваться больше, чем назначается ревьюверов.
+func validateMergePolicy(requiredReviewers int, policy *models.MergePolicy) error {
+	if policy.RequiredApprovals < 0 || policy.RequiredApprovals > requiredReviewers {
+		return errors.ErrInvalidReviewersCount
+	}
+	return nil
+}
+
+func (s *teamService) publishReassignments(ctx context.Context, reassignments []models.Reassignment, reason string) {
+	const op = "teamService.publishReassignments"
+
+	for _, reassignment := range reassignments {
+		pr, err := s.repo.GetPRByID(ctx, reassignment.PullRequestID)
+		if err != nil {
+			s.logger.Error("Failed to get reassigned PR", "op", op, "error", err, "prID", reassignment.PullRequestID)
+			continue
+		}
+		publishReassigned(ctx, s.events, pr, reassignment.OldUserID, reassignment.NewUserID, reason)
+	}
+}
@@ -0,0 +1,56 @@
+package service_test
+
+import (
+	"testing"
+
+	"pr-review/internal/models"
+)
+
+func TestSetMergePolicyPartial(t *testing.T) {
+	env := newEnv(t)
+	env.seedTeam(t, "backend", "u1", "u2", "u3")
+	noError(t, env.repo.SetTeamMergePolicy(t.Context(), "backend", &models.MergePolicy{
+		RequiredApprovals:  1,
+		ForbidSelfApproval: true,
+	}))
+
+	approvals := 2
+	team, err := env.teams.SetMergePolicy(t.Context(), "backend", &models.MergePolicyUpdate{RequiredApprovals: &approvals})
+	noError(t, err)
+
+	want := models.MergePolicy{RequiredApprovals: 2, ForbidSelfApproval: true}
+	equal(t, "returned policy", team.MergePolicy, want)
+	stored, err := env.repo.GetTeamByName(t.Context(), "backend")
+	noError(t, err)
+	equal(t, "stored policy", stored.MergePolicy, want)
+
+	off := false
+	team, err = env.teams.SetMergePolicy(t.Context(), "backend", &models.MergePolicyUpdate{ForbidSelfApproval: &off})
+	noError(t, err)
+	equal(t, "policy after toggle", team.MergePolicy, models.MergePolicy{RequiredApprovals: 2})
+}
+
+func TestRemoveMember(t *testing.T) {
+	env := newEnv(t)
+	env.seedTeam(t, "backend", "u1", "u2", "u3", "u4")
+	_, err := env.prs.CreatePR(t.Context(), &models.PullRequestShort{ID: "pr-1", Name: "PR", AuthorID: "u1"}, models.CreatePROptions{})
+	noError(t, err)
+	_, err = env.prs.CreatePR(t.Context(), &models.PullRequestShort{ID: "pr-2", Name: "PR", AuthorID: "u3"}, models.CreatePROptions{})
+	noError(t, err)
+	equalStrings(t, "pr-2 reviewers", env.getPR(t, "pr-2").AssignedReviewers, []string{"u1", "u4"})
+
+	change, err := env.teams.RemoveMember(t.Context(), "backend", "u4")
+	noError(t, err)
+	equal(t, "team", change.User.TeamName, "")
+	equal(t, "reassigned", len(change.Reassigned), 1)
+	equal(t, "new reviewer", change.Reassigned[0].NewUserID, "u2")
+	equal(t, "no candidate", len(change.NoCandidate), 0)
+	equalStrings(t, "pr-2 reviewers", env.getPR(t, "pr-2").AssignedReviewers, []string{"u1", "u2"})
+	equal(t, "reassigned events", env.events.count(models.WebhookEventReviewerReassigned), 1)
+
+	// замены нет: автор и второй ревьювер не подходят, ревью остаётся за исключённым
+	change, err = env.teams.RemoveMember(t.Context(), "backend", "u3")
+	noError(t, err)
+	equalStrings(t, "no candidate", change.NoCandidate, []string{"pr-1"})
+	equalStrings(t, "pr-1 reviewers", env.getPR(t, "pr-1").AssignedReviewers, []string{"u2", "u3"})
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"log/slog"
+	"time"
 
 	"pr-review/internal/errors"
 	"pr-review/internal/models"
@@ -12,22 +13,34 @@ import (
 type UserRepository interface {
 	SetUserActive(ctx context.Context, userID string, isActive bool) error
 	GetUserByID(ctx context.Context, id string) (*models.User, error)
-	GetPRsByReviewer(ctx context.Context, userID string) ([]*models.PullRequestShort, error)
+	SetUserTeam(ctx context.Context, userID, teamName string) error
+	SetUserMaxOpenReviews(ctx context.Context, userID string, limit *int) error
+	AddAbsence(ctx context.Context, absence *models.Absence) error
+	LinkUserAccount(ctx context.Context, account *models.UserAccount) error
+	SetUserEmail(ctx context.Context, settings *models.EmailSettings) error
+	GetTeamByName(ctx context.Context, name string) (*models.Team, error)
+	GetPRsByReviewer(ctx context.Context, userID string) ([]*models.ReviewAssignment, error)
 	GetPRsCntByAuthor(ctx context.Context, userID string) (int, error)
 }
 
 type userService struct {
-	logger *slog.Logger
-	repo   UserRepository
+	logger     *slog.Logger
+	repo       UserRepository
+	reassigner reviewReassigner
+	events     EventPublisher
 }
 
 func NewUserService(
 	logger *slog.Logger,
 	repo UserRepository,
+	reassigner reviewReassigner,
+	events EventPublisher,
 ) handlers.UserService {
 	return &userService{
-		logger: logger,
-		repo:   repo,
+		logger:     logger,
+		repo:       repo,
+		reassigner: reassigner,
+		events:     events,
 	}
 }
 
@@ -48,10 +61,131 @@ func (s *userService) SetUserActive(ctx context.Context, userID string, isActive
 		return nil, err
 	}
 
+	if !isActive {
+		publishDeactivated(ctx, s.events, user)
+	}
+
 	return user, nil
 }
 
-func (s *userService) GetUserReviewPRs(ctx context.Context, userID string) ([]*models.PullRequestShort, error) {
+func (s *userService) SetMaxOpenReviews(ctx context.Context, userID string, limit *int) (*models.User, error) {
+	const op = "userService.SetMaxOpenReviews"
+
+	err := s.repo.SetUserMaxOpenReviews(ctx, userID, limit)
+	if err != nil {
+		err = errors.WrapError(op, err)
+		s.logger.Error("Failed to set user max open reviews", "error", err, "userID", userID)
+		return nil, err
+	}
+
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		err = errors.WrapError(op, err)
+		s.logger.Error("Failed to get updated user", "error", err, "userID", userID)
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// MoveTeam передаёт открытые ревью в прежней команде другим её участникам.
+func (s *userService) MoveTeam(ctx context.Context, userID, teamName string) (*models.MembershipChange, error) {
+	const op = "userService.MoveTeam"
+
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		err = errors.WrapError(op, err)
+		s.logger.Error("Failed to get user", "error", err, "userID", userID)
+		return nil, err
+	}
+	if user.TeamName == teamName {
+		return &models.MembershipChange{User: user}, nil
+	}
+
+	team, err := s.repo.GetTeamByName(ctx, teamName)
+	if err != nil {
+		err = errors.WrapError(op, err)
+		s.logger.Error("Failed to get team", "error", err, "teamName", teamName)
+		return nil, err
+	}
+	if team.ArchivedAt != nil {
+		return nil, errors.WrapError(op, errors.ErrTeamArchived)
+	}
+
+	err = s.repo.SetUserTeam(ctx, userID, teamName)
+	if err != nil {
+		err = errors.WrapError(op, err)
+		s.logger.Error("Failed to set user team", "error", err, "userID", userID, "teamName", teamName)
+		return nil, err
+	}
+
+	report, err := reassignReviews(ctx, s.repo, s.reassigner, userID, openReviews, models.ReasonTeamChanged)
+	if err != nil {
+		err = errors.WrapError(op, err)
+		s.logger.Error("Failed to reassign open reviews", "error", err, "userID", userID)
+		return nil, err
+	}
+
+	user.TeamName = teamName
+	return &models.MembershipChange{User: user, ReassignReport: *report}, nil
+}
+
+func (s *userService) AddAbsence(ctx context.Context, absence *models.Absence) (*models.Absence, error) {
+	const op = "userService.AddAbsence"
+
+	if !absence.EndsAt.After(absence.StartsAt) || !absence.EndsAt.After(time.Now()) {
+		return nil, errors.WrapError(op, errors.ErrInvalidAbsence)
+	}
+
+	err := s.repo.AddAbsence(ctx, absence)
+	if err != nil {
+		err = errors.WrapError(op, err)
+		s.logger.Error("Failed to add absence", "error", err, "userID", absence.UserID)
+		return nil, err
+	}
+
+	return absence, nil
+}
+
+func (s *userService) LinkAccount(ctx context.Context, account *models.UserAccount) (*models.UserAccount, error) {
+	const op = "userService.LinkAccount"
+
+	linked := *account
+	linked.Login = normalizeLogin(account.Login)
+
+	err := s.repo.LinkUserAccount(ctx, &linked)
+	if err != nil {
+		err = errors.WrapError(op, err)
+		s.logger.Error("Failed to link user account", "error", err, "userID", account.UserID, "provider", account.Provider)
+		return nil, err
+	}
+
+	return &linked, nil
+}
+
+// SetEmail: без режима письма приходят на каждое назначение.
+func (s *userService) SetEmail(ctx context.Context, settings *models.EmailSettings) (*models.EmailSettings, error) {
+	const op = "userService.SetEmail"
+
+	stored := *settings
+	switch {
+	case stored.Email == "":
+		stored.Mode = ""
+	case stored.Mode == "":
+		stored.Mode = models.EmailImmediate
+	}
+
+	err := s.repo.SetUserEmail(ctx, &stored)
+	if err != nil {
+		err = errors.WrapError(op, err)
+		s.logger.Error("Failed to set user email", "error", err, "userID", settings.UserID)
+		return nil, err
+	}
+
+	return &stored, nil
+}
+
+func (s *userService) GetUserReviewPRs(ctx context.Context, userID string) ([]*models.ReviewAssignment, error) {
 	const op = "userService.GetUserReviewPRs"
 
 	prs, err := s.repo.GetPRsByReviewer(ctx, userID)
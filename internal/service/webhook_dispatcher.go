@@ -0,0 +1,127 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"pr-review/internal/config"
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+// Подпись - HMAC-SHA256 тела на секрете подписки в hex с префиксом sha256=.
+const (
+	HeaderWebhookSignature = "X-Webhook-Signature"
+	HeaderWebhookEvent     = "X-Webhook-Event"
+	HeaderWebhookDelivery  = "X-Webhook-Delivery"
+)
+
+const maxErrorBody = 512
+
+// WebhookDispatcher считает доставкой ответ 2xx, иначе попытка повторяется.
+type WebhookDispatcher struct {
+	repo   WebhookRepository
+	client *http.Client
+	worker *outboxWorker[models.WebhookDelivery]
+}
+
+func NewWebhookDispatcher(logger *slog.Logger, repo WebhookRepository, cfg *config.OutboxConfig) *WebhookDispatcher {
+	d := &WebhookDispatcher{
+		repo:   repo,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}
+	d.worker = newOutboxWorker[models.WebhookDelivery](logger, d, "Webhook delivery", cfg, cfg.Timeout)
+	return d
+}
+
+func (d *WebhookDispatcher) DeliverDue(ctx context.Context, now time.Time) error {
+	const op = "WebhookDispatcher.DeliverDue"
+
+	if err := d.worker.runDue(ctx, now); err != nil {
+		return errors.WrapError(op, err)
+	}
+	return nil
+}
+
+func Sign(payload []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// private methods
+
+func (d *WebhookDispatcher) claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	return d.repo.ClaimDueDeliveries(ctx, now, lease, limit)
+}
+
+func (d *WebhookDispatcher) save(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return d.repo.SaveDeliveryAttempt(ctx, delivery)
+}
+
+func (d *WebhookDispatcher) attempts(delivery *models.WebhookDelivery) int {
+	return delivery.Attempts
+}
+
+func (d *WebhookDispatcher) record(delivery *models.WebhookDelivery, result *outboxAttempt) {
+	delivery.Attempts = result.attempts
+	delivery.LastError = ""
+	if result.err != nil {
+		delivery.LastError = result.err.Error()
+	}
+
+	switch result.status {
+	case outboxDone:
+		delivery.Status = models.DeliveryDelivered
+		delivery.DeliveredAt = &result.at
+	case outboxFailed:
+		delivery.Status = models.DeliveryFailed
+	default:
+		delivery.Status = models.DeliveryPending
+		delivery.NextAttemptAt = result.nextAttemptAt
+	}
+}
+
+func (d *WebhookDispatcher) logAttrs(delivery *models.WebhookDelivery) []any {
+	return []any{"deliveryID", delivery.ID, "webhookID", delivery.WebhookID}
+}
+
+func (d *WebhookDispatcher) send(ctx context.Context, delivery *models.WebhookDelivery) error {
+	delivery.ResponseStatus = 0
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderWebhookEvent, delivery.Event)
+	req.Header.Set(HeaderWebhookDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderWebhookSignature, Sign(delivery.Payload, delivery.Secret))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			return
+		}
+	}()
+
+	delivery.ResponseStatus = resp.StatusCode
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	return fmt.Errorf("receiver responded %s: %s", resp.Status, bytes.TrimSpace(body))
+}
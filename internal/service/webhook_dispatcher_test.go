@@ -0,0 +1,216 @@
+package service_test
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"pr-review/internal/config"
+	"pr-review/internal/database/memory"
+	"pr-review/internal/models"
+	"pr-review/internal/service"
+)
+
+const webhookSecret = "receiver-secret"
+
+var webhookConfig = config.OutboxConfig{
+	Timeout:     time.Second,
+	BackoffBase: time.Minute,
+	BackoffMax:  3 * time.Minute,
+	MaxAttempts: 4,
+	BatchSize:   10,
+}
+
+// receivedDelivery - запрос, пришедший получателю.
+type receivedDelivery struct {
+	header http.Header
+	body   []byte
+}
+
+// receiver - получатель вебхуков, отвечающий статусами из statuses по очереди,
+// а когда они кончились - 200.
+type receiver struct {
+	server   *httptest.Server
+	received []receivedDelivery
+	statuses []int
+	mu       sync.Mutex
+}
+
+func newReceiver(t *testing.T, statuses ...int) *receiver {
+	t.Helper()
+
+	r := &receiver{statuses: statuses}
+	r.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		r.mu.Lock()
+		r.received = append(r.received, receivedDelivery{header: req.Header.Clone(), body: body})
+		status := http.StatusOK
+		if len(r.statuses) > 0 {
+			status, r.statuses = r.statuses[0], r.statuses[1:]
+		}
+		r.mu.Unlock()
+
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, http.StatusText(status))
+	}))
+	t.Cleanup(r.server.Close)
+	return r
+}
+
+func (r *receiver) deliveries() []receivedDelivery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]receivedDelivery(nil), r.received...)
+}
+
+// newWebhookEnv подписывает получателя на pr.merged и публикует одно событие.
+func newWebhookEnv(t *testing.T, rcv *receiver) (*service.WebhookDispatcher, *service.WebhookService, string) {
+	t.Helper()
+
+	repo := memory.New()
+	logger := slog.New(slog.DiscardHandler)
+	webhooks := service.NewWebhookService(logger, repo)
+	webhook, err := webhooks.Create(t.Context(), &models.Webhook{
+		URL:    rcv.server.URL,
+		Secret: webhookSecret,
+		Events: []string{models.WebhookEventPRMerged},
+	})
+	noError(t, err)
+
+	// событие без подписки не доставляется
+	webhooks.Publish(t.Context(), models.WebhookEventPRCreated, map[string]string{"pull_request_id": "pr-1"})
+	webhooks.Publish(t.Context(), models.WebhookEventPRMerged, map[string]string{"pull_request_id": "pr-1"})
+
+	cfg := webhookConfig
+	return service.NewWebhookDispatcher(logger, repo, &cfg), webhooks, webhook.ID
+}
+
+func TestWebhookDispatcherSigned(t *testing.T) {
+	rcv := newReceiver(t)
+	dispatcher, webhooks, webhookID := newWebhookEnv(t, rcv)
+
+	noError(t, dispatcher.DeliverDue(t.Context(), time.Now().UTC()))
+
+	received := rcv.deliveries()
+	equal(t, "requests", len(received), 1)
+	got := received[0]
+	equal(t, "signature", got.header.Get(service.HeaderWebhookSignature), service.Sign(got.body, webhookSecret))
+	equal(t, "event header", got.header.Get(service.HeaderWebhookEvent), models.WebhookEventPRMerged)
+	equal(t, "content type", got.header.Get("Content-Type"), "application/json")
+
+	var envelope struct {
+		Data  map[string]string `json:"data"`
+		ID    string            `json:"id"`
+		Event string            `json:"event"`
+	}
+	noError(t, json.Unmarshal(got.body, &envelope))
+	equal(t, "event", envelope.Event, models.WebhookEventPRMerged)
+	equal(t, "data", envelope.Data["pull_request_id"], "pr-1")
+	equal(t, "event id", envelope.ID != "", true)
+
+	deliveries, err := webhooks.Deliveries(t.Context(), webhookID)
+	noError(t, err)
+	equal(t, "deliveries", len(deliveries), 1)
+	equal(t, "delivery header", got.header.Get(service.HeaderWebhookDelivery), strconv.FormatInt(deliveries[0].ID, 10))
+	equal(t, "status", deliveries[0].Status, models.DeliveryDelivered)
+	equal(t, "response", deliveries[0].ResponseStatus, http.StatusOK)
+	equal(t, "attempts", deliveries[0].Attempts, 1)
+
+	// доставленное не отправляется повторно
+	noError(t, dispatcher.DeliverDue(t.Context(), time.Now().UTC().Add(time.Hour)))
+	equal(t, "requests after delivery", len(rcv.deliveries()), 1)
+}
+
+func TestWebhookDispatcherRetries(t *testing.T) {
+	rcv := newReceiver(t, http.StatusInternalServerError, http.StatusBadGateway)
+	dispatcher, webhooks, webhookID := newWebhookEnv(t, rcv)
+
+	delivery := func() models.WebhookDelivery {
+		t.Helper()
+
+		deliveries, err := webhooks.Deliveries(t.Context(), webhookID)
+		noError(t, err)
+		equal(t, "deliveries", len(deliveries), 1)
+		return deliveries[0]
+	}
+
+	start := time.Now().UTC()
+	noError(t, dispatcher.DeliverDue(t.Context(), start))
+	first := delivery()
+	equal(t, "first status", first.Status, models.DeliveryPending)
+	equal(t, "first response", first.ResponseStatus, http.StatusInternalServerError)
+	equal(t, "first error", strings.Contains(first.LastError, "Internal Server Error"), true)
+	equalDelay(t, "first backoff", first.NextAttemptAt, start, webhookConfig.BackoffBase)
+
+	// до конца паузы повтора нет
+	noError(t, dispatcher.DeliverDue(t.Context(), first.NextAttemptAt.Add(-time.Second)))
+	equal(t, "requests before backoff", len(rcv.deliveries()), 1)
+
+	retried := time.Now().UTC()
+	noError(t, dispatcher.DeliverDue(t.Context(), first.NextAttemptAt))
+	second := delivery()
+	equal(t, "second attempts", second.Attempts, 2)
+	equal(t, "second response", second.ResponseStatus, http.StatusBadGateway)
+	// пауза удваивается
+	equalDelay(t, "second backoff", second.NextAttemptAt, retried, 2*webhookConfig.BackoffBase)
+
+	noError(t, dispatcher.DeliverDue(t.Context(), second.NextAttemptAt))
+	third := delivery()
+	equal(t, "third status", third.Status, models.DeliveryDelivered)
+	equal(t, "third attempts", third.Attempts, 3)
+	equal(t, "third error", third.LastError, "")
+
+	// каждая попытка подписана одинаково
+	received := rcv.deliveries()
+	equal(t, "requests", len(received), 3)
+	for _, got := range received {
+		equal(t, "retry signature", got.header.Get(service.HeaderWebhookSignature), service.Sign(received[0].body, webhookSecret))
+	}
+}
+
+func TestWebhookDispatcherExhausted(t *testing.T) {
+	statuses := make([]int, webhookConfig.MaxAttempts+1)
+	for i := range statuses {
+		statuses[i] = http.StatusServiceUnavailable
+	}
+	rcv := newReceiver(t, statuses...)
+	dispatcher, webhooks, webhookID := newWebhookEnv(t, rcv)
+
+	at := time.Now().UTC()
+	for range webhookConfig.MaxAttempts {
+		noError(t, dispatcher.DeliverDue(t.Context(), at))
+		at = at.Add(webhookConfig.BackoffMax + time.Second)
+	}
+
+	deliveries, err := webhooks.Deliveries(t.Context(), webhookID)
+	noError(t, err)
+	equal(t, "status", deliveries[0].Status, models.DeliveryFailed)
+	equal(t, "attempts", deliveries[0].Attempts, webhookConfig.MaxAttempts)
+
+	// исчерпанная доставка больше не отправляется
+	noError(t, dispatcher.DeliverDue(t.Context(), at.Add(time.Hour)))
+	equal(t, "requests", len(rcv.deliveries()), webhookConfig.MaxAttempts)
+}
+
+// equalDelay сверяет срок следующей попытки, назначенной после from, с точностью
+// до времени, прошедшего в самом тесте.
+func equalDelay(t *testing.T, name string, next, from time.Time, want time.Duration) {
+	t.Helper()
+
+	if got := next.Sub(from); got < want || got > want+5*time.Second {
+		t.Fatalf("%s: got %s, want %s", name, got, want)
+	}
+}
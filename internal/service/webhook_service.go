@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"time"
+
+	"pr-review/internal/errors"
+	"pr-review/internal/models"
+)
+
+const deliveriesLimit = 100
+
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, webhook *models.Webhook) error
+	GetWebhooks(ctx context.Context) ([]models.Webhook, error)
+	DeleteWebhook(ctx context.Context, id string) error
+	EnqueueDeliveries(ctx context.Context, event string, payload []byte, at time.Time) (int, error)
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error)
+	SaveDeliveryAttempt(ctx context.Context, delivery *models.WebhookDelivery) error
+	GetWebhookDeliveries(ctx context.Context, webhookID string, limit int) ([]models.WebhookDelivery, error)
+}
+
+type webhookEnvelope struct {
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+	ID         string    `json:"id"`
+	Event      string    `json:"event"`
+}
+
+// WebhookService ставит события в очередь, отправляет их WebhookDispatcher.
+type WebhookService struct {
+	logger *slog.Logger
+	repo   WebhookRepository
+}
+
+func NewWebhookService(logger *slog.Logger, repo WebhookRepository) *WebhookService {
+	return &WebhookService{
+		logger: logger,
+		repo:   repo,
+	}
+}
+
+// Create возвращает сгенерированный секрет только один раз, позже получить его нельзя.
+func (s *WebhookService) Create(ctx context.Context, webhook *models.Webhook) (*models.Webhook, error) {
+	const op = "WebhookService.Create"
+
+	if err := validateWebhook(webhook); err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+
+	id, err := randomHex(16)
+	if err != nil {
+		return nil, errors.WrapError(op, err)
+	}
+	if webhook.Secret == "" {
+		webhook.Secret, err = randomHex(32)
+		if err != nil {
+			return nil, errors.WrapError(op, err)
+		}
+	}
+
+	webhook.ID = id
+	webhook.Events = uniqueStrings(webhook.Events)
+	webhook.CreatedAt = time.Now().UTC()
+
+	if err := s.repo.CreateWebhook(ctx, webhook); err != nil {
+		s.logger.Error("Failed to create webhook", "op", op, "error", err, "url", webhook.URL)
+		return nil, errors.WrapError(op, err)
+	}
+
+	return webhook, nil
+}
+
+func (s *WebhookService) List(ctx context.Context) ([]models.Webhook, error) {
+	const op = "WebhookService.List"
+
+	webhooks, err := s.repo.GetWebhooks(ctx)
+	if err != nil {
+		s.logger.Error("Failed to get webhooks", "op", op, "error", err)
+		return nil, errors.WrapError(op, err)
+	}
+
+	return webhooks, nil
+}
+
+func (s *WebhookService) Delete(ctx context.Context, id string) error {
+	const op = "WebhookService.Delete"
+
+	if err := s.repo.DeleteWebhook(ctx, id); err != nil {
+		s.logger.Error("Failed to delete webhook", "op", op, "error", err, "webhookID", id)
+		return errors.WrapError(op, err)
+	}
+
+	return nil
+}
+
+func (s *WebhookService) Deliveries(ctx context.Context, webhookID string) ([]models.WebhookDelivery, error) {
+	const op = "WebhookService.Deliveries"
+
+	deliveries, err := s.repo.GetWebhookDeliveries(ctx, webhookID, deliveriesLimit)
+	if err != nil {
+		s.logger.Error("Failed to get webhook deliveries", "op", op, "error", err, "webhookID", webhookID)
+		return nil, errors.WrapError(op, err)
+	}
+
+	return deliveries, nil
+}
+
+func (s *WebhookService) Publish(ctx context.Context, event string, data any) {
+	const op = "WebhookService.Publish"
+
+	id, err := randomHex(16)
+	if err != nil {
+		s.logger.Error("Failed to generate event id", "op", op, "error", err, "event", event)
+		return
+	}
+
+	occurredAt := time.Now().UTC()
+	payload, err := json.Marshal(webhookEnvelope{ID: id, Event: event, OccurredAt: occurredAt, Data: data})
+	if err != nil {
+		s.logger.Error("Failed to marshal webhook payload", "op", op, "error", err, "event", event)
+		return
+	}
+
+	count, err := s.repo.EnqueueDeliveries(ctx, event, payload, occurredAt)
+	if err != nil {
+		s.logger.Error("Failed to enqueue webhook deliveries", "op", op, "error", err, "event", event)
+		return
+	}
+	if count > 0 {
+		s.logger.Debug("Enqueued webhook deliveries", "op", op, "event", event, "eventID", id, "deliveries", count)
+	}
+}
+
+// private methods
+
+func validateWebhook(webhook *models.Webhook) error {
+	target, err := url.Parse(webhook.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http(s) URL", errors.ErrInvalidWebhook)
+	}
+
+	for _, event := range webhook.Events {
+		if !slices.Contains(models.WebhookEvents, event) {
+			return fmt.Errorf("%w: unknown event %q", errors.ErrInvalidWebhook, event)
+		}
+	}
+
+	return nil
+}
+
+func randomHex(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
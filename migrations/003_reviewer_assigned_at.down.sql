@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_pr_reviewers_user_assigned;
+
+ALTER TABLE pr_reviewers DROP COLUMN assigned_at;
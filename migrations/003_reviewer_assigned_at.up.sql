@@ -0,0 +1,3 @@
+ALTER TABLE pr_reviewers ADD COLUMN assigned_at TIMESTAMP WITH TIME ZONE DEFAULT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_pr_reviewers_user_assigned ON pr_reviewers(user_id, assigned_at);
@@ -0,0 +1 @@
+ALTER TABLE teams ADD COLUMN required_reviewers INTEGER NOT NULL DEFAULT 2;
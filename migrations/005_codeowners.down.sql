@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS pr_files;
+DROP TABLE IF EXISTS team_codeowners;
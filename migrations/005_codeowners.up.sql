@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS team_codeowners (
+    team_name VARCHAR(100) PRIMARY KEY,
+    content TEXT NOT NULL,
+    mode VARCHAR(20) NOT NULL DEFAULT 'prefer',
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (team_name) REFERENCES teams(name) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS pr_files (
+    pr_id VARCHAR(100) NOT NULL,
+    path VARCHAR(1024) NOT NULL,
+    PRIMARY KEY (pr_id, path),
+    FOREIGN KEY (pr_id) REFERENCES pull_requests(id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+ALTER TABLE pull_requests DROP COLUMN reviewers_count;
+ALTER TABLE pull_requests DROP COLUMN is_draft;
@@ -0,0 +1,2 @@
+ALTER TABLE pull_requests ADD COLUMN is_draft BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE pull_requests ADD COLUMN reviewers_count INTEGER DEFAULT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE teams DROP COLUMN required_approvals;
+ALTER TABLE pr_reviewers DROP COLUMN reviewed_at;
+ALTER TABLE pr_reviewers DROP COLUMN state;
@@ -0,0 +1,3 @@
+ALTER TABLE pr_reviewers ADD COLUMN state VARCHAR(20) NOT NULL DEFAULT 'PENDING';
+ALTER TABLE pr_reviewers ADD COLUMN reviewed_at TIMESTAMP WITH TIME ZONE DEFAULT NULL;
+ALTER TABLE teams ADD COLUMN required_approvals INTEGER NOT NULL DEFAULT 0;
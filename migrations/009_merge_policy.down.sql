@@ -0,0 +1,3 @@
+ALTER TABLE teams DROP COLUMN require_owner_approval;
+ALTER TABLE teams DROP COLUMN forbid_self_approval;
+ALTER TABLE teams DROP COLUMN block_on_changes_requested;
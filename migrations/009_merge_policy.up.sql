@@ -0,0 +1,3 @@
+ALTER TABLE teams ADD COLUMN block_on_changes_requested BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE teams ADD COLUMN forbid_self_approval BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE teams ADD COLUMN require_owner_approval BOOLEAN NOT NULL DEFAULT FALSE;
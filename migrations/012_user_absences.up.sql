@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS user_absences (
+    user_id VARCHAR(100) NOT NULL,
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    processed_at TIMESTAMP WITH TIME ZONE DEFAULT NULL,
+    PRIMARY KEY (user_id, starts_at),
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_absences_period ON user_absences(starts_at, ends_at);
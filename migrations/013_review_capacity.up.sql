@@ -0,0 +1,2 @@
+ALTER TABLE users ADD COLUMN max_open_reviews INTEGER DEFAULT NULL CHECK (max_open_reviews >= 0);
+ALTER TABLE teams ADD COLUMN max_open_reviews INTEGER DEFAULT NULL CHECK (max_open_reviews >= 0);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS team_fallbacks;
+DROP TABLE IF EXISTS reviewer_pool_members;
+DROP TABLE IF EXISTS reviewer_pools;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS reviewer_pools (
+    name VARCHAR(100) PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS reviewer_pool_members (
+    pool_name VARCHAR(100) NOT NULL,
+    user_id VARCHAR(100) NOT NULL,
+    PRIMARY KEY (pool_name, user_id),
+    FOREIGN KEY (pool_name) REFERENCES reviewer_pools(name) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS team_fallbacks (
+    team_name VARCHAR(100) NOT NULL,
+    position INTEGER NOT NULL,
+    kind VARCHAR(10) NOT NULL CHECK (kind IN ('team', 'pool')),
+    source_name VARCHAR(100) NOT NULL,
+    PRIMARY KEY (team_name, position),
+    FOREIGN KEY (team_name) REFERENCES teams(name) ON DELETE CASCADE
+);
@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS pr_events (
+    id BIGSERIAL PRIMARY KEY,
+    pr_id VARCHAR(100) NOT NULL,
+    type VARCHAR(30) NOT NULL,
+    user_id VARCHAR(100) DEFAULT NULL,
+    related_user_id VARCHAR(100) DEFAULT NULL,
+    reason VARCHAR(30) DEFAULT NULL,
+    state VARCHAR(20) DEFAULT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (pr_id) REFERENCES pull_requests(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_pr_events_pr ON pr_events(pr_id, id);
+
+-- история существующих PR восстанавливается из текущего состояния
+INSERT INTO pr_events (pr_id, type, user_id, created_at)
+SELECT id, 'created', author_id, created_at FROM pull_requests ORDER BY created_at, id;
+
+INSERT INTO pr_events (pr_id, type, user_id, created_at)
+SELECT prr.pr_id, 'reviewer_assigned', prr.user_id, COALESCE(prr.assigned_at, pr.created_at)
+FROM pr_reviewers prr
+JOIN pull_requests pr ON pr.id = prr.pr_id
+ORDER BY COALESCE(prr.assigned_at, pr.created_at), prr.pr_id, prr.user_id;
+
+INSERT INTO pr_events (pr_id, type, user_id, state, created_at)
+SELECT pr_id, 'review_submitted', user_id, state, reviewed_at
+FROM pr_reviewers
+WHERE reviewed_at IS NOT NULL
+ORDER BY reviewed_at, pr_id, user_id;
+
+INSERT INTO pr_events (pr_id, type, created_at)
+SELECT id, 'merged', merged_at FROM pull_requests WHERE status = 'MERGED' AND merged_at IS NOT NULL ORDER BY merged_at, id;
+
+INSERT INTO pr_events (pr_id, type, created_at)
+SELECT id, 'closed', closed_at FROM pull_requests WHERE status = 'CLOSED' AND closed_at IS NOT NULL ORDER BY closed_at, id;
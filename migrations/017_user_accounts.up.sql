@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS user_accounts (
+    provider VARCHAR(20) NOT NULL,
+    login VARCHAR(100) NOT NULL,
+    user_id VARCHAR(100) NOT NULL,
+    PRIMARY KEY (provider, login),
+    UNIQUE (provider, user_id),
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
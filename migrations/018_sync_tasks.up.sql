@@ -0,0 +1,19 @@
+-- изменения PR, которые нужно повторить на код-хостинге
+CREATE TABLE IF NOT EXISTS sync_tasks (
+    id BIGSERIAL PRIMARY KEY,
+    pr_id VARCHAR(100) NOT NULL,
+    provider VARCHAR(20) NOT NULL,
+    action VARCHAR(30) NOT NULL,
+    user_id VARCHAR(100) DEFAULT NULL,
+    body TEXT DEFAULT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT DEFAULT NULL,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    completed_at TIMESTAMP WITH TIME ZONE DEFAULT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (action IN ('request_reviewer', 'remove_reviewer', 'comment')),
+    CHECK (status IN ('pending', 'done', 'failed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_sync_tasks_due ON sync_tasks(status, next_attempt_at);
@@ -0,0 +1,29 @@
+-- incoming webhook чата команды, NULL в шаблоне - стандартное сообщение
+CREATE TABLE IF NOT EXISTS team_chats (
+    team_name VARCHAR(100) PRIMARY KEY,
+    url TEXT NOT NULL,
+    format VARCHAR(20) NOT NULL,
+    assigned_template TEXT DEFAULT NULL,
+    reassigned_template TEXT DEFAULT NULL,
+    merged_template TEXT DEFAULT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (team_name) REFERENCES teams(name) ON DELETE CASCADE,
+    CHECK (format IN ('slack', 'mattermost'))
+);
+
+CREATE TABLE IF NOT EXISTS chat_messages (
+    id BIGSERIAL PRIMARY KEY,
+    team_name VARCHAR(100) NOT NULL,
+    event VARCHAR(50) NOT NULL,
+    url TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT DEFAULT NULL,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    sent_at TIMESTAMP WITH TIME ZONE DEFAULT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (status IN ('pending', 'sent', 'failed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_messages_due ON chat_messages(status, next_attempt_at);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS email_messages;
+ALTER TABLE users DROP COLUMN digest_sent_at;
+ALTER TABLE users DROP COLUMN email_mode;
+ALTER TABLE users DROP COLUMN email;
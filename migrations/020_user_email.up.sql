@@ -0,0 +1,22 @@
+-- адрес для уведомлений и режим: письмо на каждое назначение или ежедневная сводка
+ALTER TABLE users ADD COLUMN email VARCHAR(255) DEFAULT NULL;
+ALTER TABLE users ADD COLUMN email_mode VARCHAR(20) DEFAULT NULL CHECK (email_mode IN ('immediate', 'digest'));
+-- digest_sent_at - начало последнего дня, за который сводка поставлена в очередь
+ALTER TABLE users ADD COLUMN digest_sent_at TIMESTAMP WITH TIME ZONE DEFAULT NULL;
+
+CREATE TABLE IF NOT EXISTS email_messages (
+    id BIGSERIAL PRIMARY KEY,
+    user_id VARCHAR(100) NOT NULL,
+    recipient VARCHAR(255) NOT NULL,
+    subject TEXT NOT NULL,
+    message TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT DEFAULT NULL,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    sent_at TIMESTAMP WITH TIME ZONE DEFAULT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (status IN ('pending', 'sent', 'failed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_messages_due ON email_messages(status, next_attempt_at);
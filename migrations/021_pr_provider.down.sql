@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_sync_tasks_pr_pending;
+ALTER TABLE pull_requests DROP COLUMN provider;
@@ -0,0 +1,10 @@
+-- код-хостинг, из интеграции с которым создан PR; у PR из API пустой
+ALTER TABLE pull_requests ADD COLUMN provider VARCHAR(20) NOT NULL DEFAULT '';
+
+-- PR, по которым уже есть задачи синхронизации, пришли из интеграции
+UPDATE pull_requests
+SET provider = (SELECT MIN(s.provider) FROM sync_tasks s WHERE s.pr_id = pull_requests.id)
+WHERE EXISTS (SELECT 1 FROM sync_tasks s WHERE s.pr_id = pull_requests.id);
+
+-- задачи одного PR выполняются по очереди, начиная с самой старой
+CREATE INDEX IF NOT EXISTS idx_sync_tasks_pr_pending ON sync_tasks(pr_id, id) WHERE status = 'pending';
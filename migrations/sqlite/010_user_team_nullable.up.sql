@@ -0,0 +1,16 @@
+CREATE TABLE users_new (
+    user_id VARCHAR(100) PRIMARY KEY,
+    username VARCHAR(100) NOT NULL UNIQUE,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    team_name VARCHAR(100),
+    FOREIGN KEY (team_name) REFERENCES teams(name) ON DELETE CASCADE
+);
+
+INSERT INTO users_new (user_id, username, is_active, team_name)
+SELECT user_id, username, is_active, team_name FROM users;
+
+DROP TABLE users;
+ALTER TABLE users_new RENAME TO users;
+
+CREATE INDEX IF NOT EXISTS idx_users_team_active ON users(team_name, is_active);
+CREATE INDEX IF NOT EXISTS idx_users_active ON users(is_active);